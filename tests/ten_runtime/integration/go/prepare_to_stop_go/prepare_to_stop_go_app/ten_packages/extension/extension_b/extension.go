@@ -10,6 +10,7 @@ package default_extension_go
 
 import (
 	"fmt"
+	"sync"
 
 	ten "ten_framework/ten_runtime"
 )
@@ -17,11 +18,22 @@ import (
 type bExtension struct {
 	ten.DefaultExtension
 	stopChan chan struct{}
+
+	// pauseChan/resumeChan are swapped in under chanMu at the start of
+	// each OnPause/OnResume, the same way Engine re-makes its own
+	// pauseDone/resumeDone per cycle: a real extension pauses and resumes
+	// repeatedly, and a channel closed once must not be reused (or
+	// re-closed) on the next cycle.
+	chanMu     sync.Mutex
+	pauseChan  chan struct{}
+	resumeChan chan struct{}
 }
 
 func NewBExtension(name string) ten.Extension {
 	return &bExtension{
-		stopChan: make(chan struct{}),
+		stopChan:   make(chan struct{}),
+		pauseChan:  make(chan struct{}),
+		resumeChan: make(chan struct{}),
 	}
 }
 
@@ -47,25 +59,131 @@ func (p *bExtension) OnCmd(
 				close(p.stopChan)
 				tenEnv.Log(ten.LogLevelInfo, "Stop command is processed.")
 			})
+		} else if cmdName == "pause" {
+			tenEnv.SendCmd(cmd, func(r ten.TenEnv, cs ten.CmdResult, e error) {
+				r.ReturnResult(cs, nil)
+
+				p.chanMu.Lock()
+				pauseChan := p.pauseChan
+				p.chanMu.Unlock()
+				close(pauseChan)
+				tenEnv.Log(ten.LogLevelInfo, "Pause command is processed.")
+			})
+		} else if cmdName == "resume" {
+			tenEnv.SendCmd(cmd, func(r ten.TenEnv, cs ten.CmdResult, e error) {
+				r.ReturnResult(cs, nil)
+
+				p.chanMu.Lock()
+				resumeChan := p.resumeChan
+				p.chanMu.Unlock()
+				close(resumeChan)
+				tenEnv.Log(ten.LogLevelInfo, "Resume command is processed.")
+			})
+		} else if cmdName == "stream" {
+			// Forward every partial result from the downstream SendCmdEx
+			// straight back to our own caller, preserving is_final so the
+			// caller sees the same number of intermediate results we do.
+			tenEnv.SendCmdEx(
+				cmd,
+				func(r ten.TenEnv, cs ten.CmdResult, isFinal bool, e error) {
+					if e != nil {
+						// e.g. the graceful-shutdown deadline force-
+						// cancelled this in-flight SendCmdEx: cs is nil,
+						// so forward a real error result instead of
+						// propagating nil as if it were success.
+						errResult, _ := ten.NewCmdResultFromError(e, cmd)
+						r.ReturnResultEx(errResult, cmd, true)
+						tenEnv.Log(
+							ten.LogLevelError,
+							"Stream command failed: "+e.Error(),
+						)
+						return
+					}
+
+					r.ReturnResultEx(cs, cmd, isFinal)
+
+					if isFinal {
+						tenEnv.Log(
+							ten.LogLevelInfo,
+							"Stream command is processed.",
+						)
+					}
+				},
+			)
 		} else {
-			cmdResult, _ := ten.NewCmdResult(ten.StatusCodeError, cmd)
-			cmdResult.SetPropertyString("detail", "unknown cmd")
+			err := ten.NewError(ten.ErrUnknownCmd, "unknown cmd: "+cmdName)
+			cmdResult, _ := ten.NewCmdResultFromError(err, cmd)
 			tenEnv.ReturnResult(cmdResult, nil)
 		}
 	}()
 }
 
-func (p *bExtension) OnStop(tenEnv ten.TenEnv) {
+func (p *bExtension) OnPause(tenEnv ten.TenEnv) {
+	p.chanMu.Lock()
+	p.pauseChan = make(chan struct{})
+	pauseChan := p.pauseChan
+	p.chanMu.Unlock()
+
 	go func() {
-		tenEnv.Log(ten.LogLevelDebug, "OnStop")
+		tenEnv.Log(ten.LogLevelDebug, "OnPause")
 
-		// Wait until the stop command is received and processed.
-		<-p.stopChan
+		// Wait until the pause command is received and processed. State
+		// (e.g. model handles, connections) is kept alive; only cmd
+		// delivery is suspended by the runtime while paused (this package
+		// has no Data type yet, so data frames aren't gated).
+		<-pauseChan
 
 		tenEnv.Log(
 			ten.LogLevelInfo,
-			"Stop command processed. Now calling OnStopDone.",
+			"Pause command processed. Now calling OnPauseDone.",
 		)
+		tenEnv.OnPauseDone()
+	}()
+}
+
+func (p *bExtension) OnResume(tenEnv ten.TenEnv) {
+	p.chanMu.Lock()
+	p.resumeChan = make(chan struct{})
+	resumeChan := p.resumeChan
+	p.chanMu.Unlock()
+
+	go func() {
+		tenEnv.Log(ten.LogLevelDebug, "OnResume")
+
+		// Wait until the resume command is received and processed.
+		<-resumeChan
+
+		tenEnv.Log(
+			ten.LogLevelInfo,
+			"Resume command processed. Now calling OnResumeDone.",
+		)
+		tenEnv.OnResumeDone()
+	}()
+}
+
+func (p *bExtension) OnStop(tenEnv ten.TenEnv) {
+	go func() {
+		tenEnv.Log(ten.LogLevelDebug, "OnStop")
+
+		// OnStop can be called from either the running or the paused state,
+		// so it only waits on the stop command, not on pause/resume. If the
+		// stop command never arrives (e.g. the peer crashed), the runtime's
+		// graceful-shutdown deadline fires ShutdownContext() instead of
+		// leaving this goroutine, and the app, hanging forever.
+		select {
+		case <-p.stopChan:
+			tenEnv.Log(
+				ten.LogLevelInfo,
+				"Stop command processed. Now calling OnStopDone.",
+			)
+		case <-tenEnv.ShutdownContext().Done():
+			tenEnv.Log(
+				ten.LogLevelWarn,
+				"Graceful-shutdown deadline expired before the stop command "+
+					"arrived; calling OnStopDone anyway.",
+			)
+		}
+
 		tenEnv.OnStopDone()
 	}()
 }
@@ -74,7 +192,7 @@ func init() {
 	fmt.Println("call init")
 
 	// Register addon
-	err := ten.RegisterAddonAsExtension(
+	_, err := ten.RegisterAddonAsExtension(
 		"extension_b",
 		ten.NewDefaultExtensionAddon(NewBExtension),
 	)
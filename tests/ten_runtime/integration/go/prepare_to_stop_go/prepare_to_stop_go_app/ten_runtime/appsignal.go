@@ -0,0 +1,81 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import "fmt"
+
+// AppSignal identifies an app-level lifecycle event surfaced to every
+// extension via TenEnv.OnAppSignal, distinct from a Cmd -- it carries no
+// payload and isn't part of the graph's normal message flow, the same way
+// a process signal is distinct from anything arriving on its stdin.
+type AppSignal int
+
+const (
+	// AppSignalShuttingDown reports that the app hosting this extension's
+	// graph has begun shutting down. See OnAppSignal's doc comment for
+	// exactly when this fires relative to OnStop.
+	AppSignalShuttingDown AppSignal = iota
+
+	// AppSignalReloadRequested reports that the app has been asked to
+	// reload its configuration (e.g. it caught SIGHUP) without
+	// necessarily shutting down -- an extension that owns a config file
+	// of its own can use this as a cue to re-read it.
+	AppSignalReloadRequested
+)
+
+func (s AppSignal) String() string {
+	switch s {
+	case AppSignalShuttingDown:
+		return "shutting_down"
+	case AppSignalReloadRequested:
+		return "reload_requested"
+	default:
+		return fmt.Sprintf("unknown(%d)", int(s))
+	}
+}
+
+// OnAppSignal registers cb to run whenever the app hosting this
+// extension's graph reports a lifecycle event -- see AppSignal's
+// constants. Every registered callback fires, in registration order, on
+// every SignalApp call; unlike OnPropertyChanged there's no path to
+// filter on, so nothing narrows which callbacks a given signal reaches.
+//
+// Stop calls SignalApp(AppSignalShuttingDown) as the very first thing it
+// does, before Context is cancelled and before OnStop is invoked -- so a
+// callback registered here is always an extension's earliest warning
+// that shutdown has begun, strictly earlier than both of those. That
+// makes it the right place to stop admitting new work of its own accord
+// (e.g. flip a flag an upstream caller checks before it calls DeliverCmd
+// at all) while letting whatever's already in flight finish normally,
+// which is a different, gentler ask than OnStop's "a deadline is ticking,
+// wrap up now." A host that wants to warn extensions with more lead time
+// than Stop's automatic firing gives can call SignalApp directly, any
+// time before it decides to tear the graph down at all; AppSignalReload
+// Requested only ever fires that way, since Stop has no reason to send it.
+func (t *tenEnvImpl) OnAppSignal(cb func(sig AppSignal)) {
+	t.engine.mu.Lock()
+	defer t.engine.mu.Unlock()
+	t.engine.appSignalSubs = append(t.engine.appSignalSubs, cb)
+}
+
+// SignalApp fires sig to every callback registered via TenEnv.OnAppSignal,
+// in registration order, on the calling goroutine. A panic in one
+// callback is recovered and logged without stopping the rest from
+// running, the same as UpdateProperty's notifications.
+func (e *Engine) SignalApp(sig AppSignal) {
+	e.mu.Lock()
+	subs := make([]func(AppSignal), len(e.appSignalSubs))
+	copy(subs, e.appSignalSubs)
+	e.mu.Unlock()
+
+	for _, cb := range subs {
+		e.invokeAppSignalCallback(cb, sig)
+	}
+}
+
+func (e *Engine) invokeAppSignalCallback(cb func(AppSignal), sig AppSignal) {
+	defer e.recoverInto("OnAppSignal", nil)
+	cb(sig)
+}
@@ -0,0 +1,145 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"errors"
+	"sync/atomic"
+)
+
+// StatusCode is the outcome of a Cmd as reported by a CmdResult.
+type StatusCode int
+
+const (
+	StatusCodeOk StatusCode = iota
+	StatusCodeError
+)
+
+// Cmd represents an in-flight command flowing through a graph.
+type Cmd interface {
+	GetName() (string, error)
+
+	// finalized reports whether a final CmdResult has already been
+	// returned for this Cmd, and atomically marks it final when marking
+	// is true. It backs the "exactly one final result per cmd" guarantee
+	// used by ReturnResultEx.
+	finalized(marking bool) (alreadyFinal bool)
+}
+
+type cmdImpl struct {
+	name  string
+	final int32
+}
+
+// NewCmd creates a new outgoing Cmd with the given name.
+func NewCmd(name string) (Cmd, error) {
+	return &cmdImpl{name: name}, nil
+}
+
+func (c *cmdImpl) GetName() (string, error) {
+	return c.name, nil
+}
+
+func (c *cmdImpl) finalized(marking bool) bool {
+	if marking {
+		return !atomic.CompareAndSwapInt32(&c.final, 0, 1)
+	}
+	return atomic.LoadInt32(&c.final) != 0
+}
+
+// CmdResult carries the outcome of a Cmd back to its originator, optionally
+// as one of a series of streamed partial results (see SendCmdEx).
+type CmdResult interface {
+	StatusCode() (StatusCode, error)
+
+	SetPropertyString(path string, value string) error
+	GetPropertyString(path string) (string, error)
+
+	SetPropertyInt(path string, value int64) error
+	GetPropertyInt(path string) (int64, error)
+
+	// IsFinal reports whether this is the terminal result for its Cmd.
+	IsFinal() (bool, error)
+
+	// AsError reconstructs the typed error carried by this CmdResult, if
+	// any. It returns nil when the result does not represent an error.
+	AsError() error
+}
+
+type cmdResultImpl struct {
+	statusCode StatusCode
+	props      map[string]any
+	isFinal    bool
+}
+
+// NewCmdResult creates a final CmdResult with the given status for cmd.
+func NewCmdResult(statusCode StatusCode, cmd Cmd) (CmdResult, error) {
+	return &cmdResultImpl{
+		statusCode: statusCode,
+		props:      map[string]any{},
+		isFinal:    true,
+	}, nil
+}
+
+// reservedErrorProperty is the CmdResult property key under which
+// NewCmdResultFromError serializes a ten.Error's Code/Message/Details/
+// Cause chain, and from which AsError reconstructs it.
+const reservedErrorProperty = "_ten_error"
+
+// NewCmdResultFromError builds a final, StatusCodeError CmdResult for cmd
+// out of err. If err is a ten.Error, its Code/Message/Details/Cause chain
+// is preserved across the property boundary so the receiving side's
+// AsError can reconstruct it for use with errors.Is/errors.As; otherwise
+// only its message survives.
+func NewCmdResultFromError(err error, cmd Cmd) (CmdResult, error) {
+	result, _ := NewCmdResult(StatusCodeError, cmd)
+	impl := result.(*cmdResultImpl)
+	impl.props[reservedErrorProperty] = encodeError(err)
+	impl.SetPropertyString("detail", err.Error())
+	return result, nil
+}
+
+func (r *cmdResultImpl) StatusCode() (StatusCode, error) {
+	return r.statusCode, nil
+}
+
+func (r *cmdResultImpl) SetPropertyString(path string, value string) error {
+	r.props[path] = value
+	return nil
+}
+
+func (r *cmdResultImpl) GetPropertyString(path string) (string, error) {
+	v, _ := r.props[path].(string)
+	return v, nil
+}
+
+func (r *cmdResultImpl) SetPropertyInt(path string, value int64) error {
+	r.props[path] = value
+	return nil
+}
+
+func (r *cmdResultImpl) GetPropertyInt(path string) (int64, error) {
+	v, _ := r.props[path].(int64)
+	return v, nil
+}
+
+func (r *cmdResultImpl) IsFinal() (bool, error) {
+	return r.isFinal, nil
+}
+
+func (r *cmdResultImpl) AsError() error {
+	if r.statusCode != StatusCodeError {
+		return nil
+	}
+	if m, ok := r.props[reservedErrorProperty].(map[string]any); ok {
+		return decodeError(m)
+	}
+	return errors.New(stringProp(r.props, "detail"))
+}
+
+func stringProp(props map[string]any, key string) string {
+	s, _ := props[key].(string)
+	return s
+}
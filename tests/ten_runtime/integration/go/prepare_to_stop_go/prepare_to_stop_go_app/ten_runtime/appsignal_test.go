@@ -0,0 +1,85 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import "testing"
+
+func TestTenEnv_OnAppSignalFiresOnSignalApp(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+
+	var got AppSignal = -1
+	engine.TenEnv().OnAppSignal(func(sig AppSignal) { got = sig })
+
+	engine.SignalApp(AppSignalReloadRequested)
+
+	if got != AppSignalReloadRequested {
+		t.Fatalf("callback got %v, want AppSignalReloadRequested", got)
+	}
+}
+
+func TestTenEnv_OnAppSignalFiresEveryRegisteredCallbackInOrder(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+
+	var order []int
+	engine.TenEnv().OnAppSignal(func(AppSignal) { order = append(order, 1) })
+	engine.TenEnv().OnAppSignal(func(AppSignal) { order = append(order, 2) })
+
+	engine.SignalApp(AppSignalShuttingDown)
+
+	if len(order) != 2 || order[0] != 1 || order[1] != 2 {
+		t.Fatalf("callbacks fired in order %v, want [1 2]", order)
+	}
+}
+
+func TestTenEnv_OnAppSignalRecoversAPanickingCallback(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+
+	ranAfterPanic := false
+	engine.TenEnv().OnAppSignal(func(AppSignal) { panic("boom") })
+	engine.TenEnv().OnAppSignal(func(AppSignal) { ranAfterPanic = true })
+
+	engine.SignalApp(AppSignalShuttingDown)
+
+	if !ranAfterPanic {
+		t.Fatal("a panicking callback prevented a later one from running")
+	}
+}
+
+func TestEngine_StopSignalsShuttingDownBeforeOnStop(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+	engine.Init()
+	engine.Start()
+
+	var order []string
+	engine.TenEnv().OnAppSignal(func(sig AppSignal) {
+		if sig == AppSignalShuttingDown {
+			order = append(order, "signal")
+		}
+	})
+
+	if err := engine.Stop(); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+
+	if len(order) != 1 || order[0] != "signal" {
+		t.Fatalf("OnAppSignal(AppSignalShuttingDown) did not fire during Stop, order = %v", order)
+	}
+}
+
+func TestAppSignal_StringRendersEachKindAndAnUnknownValue(t *testing.T) {
+	cases := []struct {
+		sig  AppSignal
+		want string
+	}{
+		{AppSignalShuttingDown, "shutting_down"},
+		{AppSignalReloadRequested, "reload_requested"},
+		{AppSignal(99), "unknown(99)"},
+	}
+	for _, c := range cases {
+		if got := c.sig.String(); got != c.want {
+			t.Fatalf("AppSignal(%d).String() = %q, want %q", int(c.sig), got, c.want)
+		}
+	}
+}
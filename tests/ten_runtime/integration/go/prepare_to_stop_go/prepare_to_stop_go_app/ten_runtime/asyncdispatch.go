@@ -0,0 +1,37 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+// WithAsyncDispatch opts an Engine into dispatching each OnCmd on a
+// freshly spawned goroutine instead of the caller's own -- normally the
+// runtime's dispatch thread, which every example otherwise has to
+// protect by wrapping its OnCmd body in its own "go func(){...}()" so a
+// slow or blocking handler can't stall it. With this option, extension
+// authors can write straight-line blocking code in OnCmd directly.
+//
+// Ordering implications: without this option, DeliverCmd calls into
+// OnCmd synchronously, so cmds delivered back-to-back on the same
+// goroutine are handled by OnCmd strictly in that order, one at a time.
+// With it enabled, two cmds delivered close together may have their
+// OnCmd calls running concurrently on different goroutines, and there is
+// no guarantee the second's OnCmd doesn't finish (or call
+// TenEnv.ReturnResult) before the first's does. An extension whose
+// correctness depends on processing cmds in delivery order -- e.g. one
+// that mutates state OnCmd reads and writes without its own locking --
+// should leave this option off, or take out its own lock at the top of
+// OnCmd to serialize itself back to front, the same way it would need to
+// if the runtime were multi-threaded.
+//
+// The default is synchronous dispatch, matching every OnCmd
+// implementation in this package's tests and examples.
+//
+// This only covers OnCmd: this package has no Engine-side delivery path
+// for OnData at all yet (nothing here calls Extension.OnData), so there
+// is nothing for this option to change there.
+func WithAsyncDispatch(enabled bool) EngineOption {
+	return func(e *Engine) {
+		e.asyncDispatch = enabled
+	}
+}
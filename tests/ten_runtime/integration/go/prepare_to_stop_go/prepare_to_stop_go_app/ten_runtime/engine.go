@@ -0,0 +1,413 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// runState is the lifecycle state of a single Extension instance as driven
+// by an Engine.
+type runState int
+
+const (
+	stateInit runState = iota
+	stateStarting
+	stateRunning
+	statePausing
+	statePaused
+	stateResuming
+	stateStopping
+	stateStopped
+)
+
+var (
+	// ErrInvalidState is returned when a lifecycle transition is requested
+	// from a state that doesn't support it (e.g. Pause() while stopped).
+	ErrInvalidState = errors.New("ten: invalid state transition")
+
+	// ErrExtensionPaused is returned by DeliverCmd when the extension is
+	// paused and the Engine is configured to reject rather than queue.
+	ErrExtensionPaused = errors.New("ten: extension is paused")
+
+	// ErrCmdAlreadyFinal is returned by ReturnResultEx when a final result
+	// was already returned for the given Cmd.
+	ErrCmdAlreadyFinal = errors.New("ten: cmd already has a final result")
+
+	// ErrShutdownPreempted is returned by Pause/Resume when Stop takes over
+	// the extension mid-transition, e.g. the "pause"/"resume" cmd never
+	// arrives because its peer crashed -- the same failure this package
+	// guards OnStop against, just reachable through Pause/Resume instead.
+	ErrShutdownPreempted = errors.New("ten: pause/resume preempted by shutdown")
+)
+
+// Engine drives a single Extension instance through its lifecycle
+// (init/start/pause/resume/stop) and delivers commands to it, playing the
+// role that the native ten_runtime engine plays for a real graph node.
+type Engine struct {
+	mu     sync.Mutex
+	state  runState
+	ext    Extension
+	tenEnv *tenEnvImpl
+
+	initDone, startDone, stopDone chan struct{}
+	pauseDone, resumeDone         chan struct{}
+	stopDoneOnce                  sync.Once
+
+	// stopPreempt is closed by Stop when it takes over from a Pause/Resume
+	// call stuck in statePausing/stateResuming, waking the blocked call so
+	// it can return ErrShutdownPreempted instead of hanging forever.
+	stopPreempt chan struct{}
+
+	// queueWhilePaused controls what DeliverCmd does with cmds that arrive
+	// while the extension is paused: queue them for replay on resume
+	// (true), or reject them immediately (false, the default). There is no
+	// data-frame concept in this package yet, so this only covers cmds.
+	queueWhilePaused bool
+	pendingCmds      []Cmd
+
+	// sender and exSender simulate the rest of the graph for SendCmd and
+	// SendCmdEx respectively: they are invoked with every Cmd an extension
+	// sends "downstream" and drive handler with the resulting
+	// CmdResult(s). Tests substitute them to simulate single-shot or
+	// streaming responders.
+	sender   func(cmd Cmd, handler CmdResultHandler)
+	exSender func(cmd Cmd, handler CmdResultExHandler)
+
+	// shutdownDeadline bounds how long Stop() waits for OnStopDone before
+	// forcing it; see WithShutdownDeadline.
+	shutdownDeadline time.Duration
+	shutdownCtx      context.Context
+	shutdownCancel   context.CancelFunc
+
+	pendingMu     sync.Mutex
+	pendingCalls  map[uint64]func()
+	nextPendingID uint64
+}
+
+// NewEngine creates an Engine for ext, ready to be driven through Init,
+// Start, Pause, Resume and Stop.
+func NewEngine(ext Extension, opts ...EngineOption) *Engine {
+	e := &Engine{
+		ext:          ext,
+		initDone:     make(chan struct{}),
+		startDone:    make(chan struct{}),
+		stopDone:     make(chan struct{}),
+		pauseDone:    make(chan struct{}),
+		resumeDone:   make(chan struct{}),
+		stopPreempt:  make(chan struct{}),
+		pendingCalls: make(map[uint64]func()),
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+	e.sender = e.defaultSender
+	e.exSender = e.defaultExSender
+	e.tenEnv = newTenEnv(e)
+	e.shutdownCtx, e.shutdownCancel = context.WithCancel(context.Background())
+	return e
+}
+
+// SetQueueWhilePaused configures whether cmds delivered while paused are
+// queued for replay on resume, instead of being rejected outright.
+func (e *Engine) SetQueueWhilePaused(queue bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.queueWhilePaused = queue
+}
+
+// SetSender overrides how the Engine simulates the downstream graph for
+// SendCmd. Intended for tests.
+func (e *Engine) SetSender(
+	sender func(cmd Cmd, handler CmdResultHandler),
+) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.sender = sender
+}
+
+// SetExSender overrides how the Engine simulates the downstream graph for
+// SendCmdEx, e.g. to emit a series of non-final results before the final
+// one. Intended for tests.
+func (e *Engine) SetExSender(
+	exSender func(cmd Cmd, handler CmdResultExHandler),
+) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.exSender = exSender
+}
+
+func (e *Engine) defaultSender(cmd Cmd, handler CmdResultHandler) {
+	result, _ := NewCmdResult(StatusCodeOk, cmd)
+	handler(e.tenEnv, result, nil)
+}
+
+func (e *Engine) defaultExSender(cmd Cmd, handler CmdResultExHandler) {
+	result, _ := NewCmdResult(StatusCodeOk, cmd)
+	handler(e.tenEnv, result, true, nil)
+}
+
+// TenEnv returns the TenEnv bound to this Engine's Extension instance.
+func (e *Engine) TenEnv() TenEnv {
+	return e.tenEnv
+}
+
+// registerPending tracks an in-flight SendCmd/SendCmdEx call so that
+// cancelPending can invoke onTimeout if the graceful-shutdown deadline
+// expires before it resolves.
+//
+// resolve removes the pending entry and reports whether it was still
+// present, i.e. whether the caller won the race against a timeout
+// cancellation; it should be called from the final completion callback.
+//
+// peek reports whether the entry is still present without removing it; it
+// should be called from any intermediate (non-final) callback so a
+// SendCmdEx streaming responder stops forwarding once the cmd has been
+// force-cancelled.
+func (e *Engine) registerPending(
+	onTimeout func(),
+) (resolve func() bool, peek func() bool) {
+	e.pendingMu.Lock()
+	id := e.nextPendingID
+	e.nextPendingID++
+	e.pendingCalls[id] = onTimeout
+	e.pendingMu.Unlock()
+
+	resolve = func() bool {
+		e.pendingMu.Lock()
+		_, stillPending := e.pendingCalls[id]
+		delete(e.pendingCalls, id)
+		e.pendingMu.Unlock()
+		return stillPending
+	}
+	peek = func() bool {
+		e.pendingMu.Lock()
+		_, stillPending := e.pendingCalls[id]
+		e.pendingMu.Unlock()
+		return stillPending
+	}
+	return resolve, peek
+}
+
+// signalStopDone marks OnStop as acknowledged, exactly once: either the
+// extension calls OnStopDone itself, or the graceful-shutdown deadline
+// forces it on the extension's behalf -- whichever happens first wins, and
+// the other is a no-op.
+func (e *Engine) signalStopDone() {
+	e.stopDoneOnce.Do(func() {
+		close(e.stopDone)
+	})
+}
+
+// cancelPending force-resolves every still in-flight SendCmd/SendCmdEx call
+// with ErrShutdownTimeout.
+func (e *Engine) cancelPending() {
+	e.pendingMu.Lock()
+	calls := e.pendingCalls
+	e.pendingCalls = make(map[uint64]func())
+	e.pendingMu.Unlock()
+
+	for _, onTimeout := range calls {
+		onTimeout()
+	}
+}
+
+func (e *Engine) transition(from, to runState) error {
+	e.mu.Lock()
+	if e.state != from {
+		cur := e.state
+		e.mu.Unlock()
+		return fmt.Errorf(
+			"%w: expected state %d, got %d", ErrInvalidState, from, cur,
+		)
+	}
+	e.state = to
+	e.mu.Unlock()
+	return nil
+}
+
+// Init runs the extension's OnInit callback and blocks until it calls
+// OnInitDone.
+func (e *Engine) Init() error {
+	if err := e.transition(stateInit, stateStarting); err != nil {
+		return err
+	}
+	e.ext.OnInit(e.tenEnv)
+	<-e.initDone
+	return nil
+}
+
+// Start runs the extension's OnStart callback and blocks until it calls
+// OnStartDone.
+func (e *Engine) Start() error {
+	if err := e.transition(stateStarting, stateRunning); err != nil {
+		return err
+	}
+	e.ext.OnStart(e.tenEnv)
+	<-e.startDone
+	return nil
+}
+
+// Pause suspends the extension: cmd delivery is held back (see DeliverCmd)
+// while existing in-memory state is preserved. It blocks until the
+// extension acknowledges via OnPauseDone.
+//
+// Known limitation: this package has no Data type / data-frame delivery
+// path yet, so only cmds are gated by the pause state; a future
+// data-frame hook would need to be threaded through the same state
+// machine to fully satisfy "stop consuming/producing data frames".
+func (e *Engine) Pause() error {
+	if err := e.transition(stateRunning, statePausing); err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	e.pauseDone = make(chan struct{})
+	done := e.pauseDone
+	preempt := e.stopPreempt
+	e.mu.Unlock()
+
+	e.ext.OnPause(e.tenEnv)
+	select {
+	case <-done:
+	case <-preempt:
+		// Stop already moved the state machine past statePausing on our
+		// behalf; don't race it back to statePaused.
+		return ErrShutdownPreempted
+	}
+
+	e.mu.Lock()
+	if e.state == statePausing {
+		e.state = statePaused
+	}
+	e.mu.Unlock()
+	return nil
+}
+
+// Resume reverses Pause, replaying any cmds queued while paused (if
+// queueWhilePaused is set) once the extension acknowledges via
+// OnResumeDone.
+func (e *Engine) Resume() error {
+	if err := e.transition(statePaused, stateResuming); err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	e.resumeDone = make(chan struct{})
+	done := e.resumeDone
+	preempt := e.stopPreempt
+	e.mu.Unlock()
+
+	e.ext.OnResume(e.tenEnv)
+	select {
+	case <-done:
+	case <-preempt:
+		// Stop already moved the state machine past stateResuming on our
+		// behalf; don't race it back to stateRunning.
+		return ErrShutdownPreempted
+	}
+
+	e.mu.Lock()
+	if e.state != stateResuming {
+		e.mu.Unlock()
+		return nil
+	}
+	e.state = stateRunning
+	pending := e.pendingCmds
+	e.pendingCmds = nil
+	e.mu.Unlock()
+
+	for _, cmd := range pending {
+		e.ext.OnCmd(e.tenEnv, cmd)
+	}
+	return nil
+}
+
+// Stop may be called from the running or paused state, or while a Pause/
+// Resume call is still in flight (statePausing/stateResuming) -- a pending
+// or stuck pause/resume must not prevent shutdown, the same "peer crashed
+// and never answered" scenario OnStop's own deadline guards against,
+// reached through a different entry point. In the latter case the blocked
+// Pause/Resume call is woken with ErrShutdownPreempted instead of hanging.
+// It starts the graceful-shutdown deadline (see WithShutdownDeadline) and
+// blocks until the extension acknowledges via OnStopDone, or until the
+// deadline expires, whichever comes first. On expiry it logs a warning,
+// cancels any in-flight SendCmd/SendCmdEx callbacks with
+// ErrShutdownTimeout, and invokes OnStopDone on the extension's behalf.
+func (e *Engine) Stop() error {
+	e.mu.Lock()
+	switch e.state {
+	case stateRunning, statePaused:
+		e.state = stateStopping
+	case statePausing, stateResuming:
+		e.state = stateStopping
+		close(e.stopPreempt)
+	default:
+		cur := e.state
+		e.mu.Unlock()
+		return fmt.Errorf(
+			"%w: cannot stop from state %d", ErrInvalidState, cur,
+		)
+	}
+	shutdownCtx, shutdownCancel := newShutdownContext(e.shutdownDeadline)
+	e.shutdownCtx, e.shutdownCancel = shutdownCtx, shutdownCancel
+	e.mu.Unlock()
+	defer shutdownCancel()
+
+	e.ext.OnStop(e.tenEnv)
+
+	select {
+	case <-e.stopDone:
+	case <-shutdownCtx.Done():
+		e.tenEnv.Log(
+			LogLevelWarn,
+			fmt.Sprintf(
+				"ten: graceful-shutdown deadline (%s) expired before "+
+					"OnStopDone; forcing shutdown",
+				e.shutdownDeadline,
+			),
+		)
+		e.cancelPending()
+		e.signalStopDone()
+	}
+
+	e.mu.Lock()
+	e.state = stateStopped
+	e.mu.Unlock()
+	return nil
+}
+
+// DeliverCmd simulates the runtime handing an incoming Cmd to the
+// extension's OnCmd. While paused it either queues the cmd for replay on
+// Resume, or rejects it with ErrExtensionPaused, depending on
+// queueWhilePaused.
+func (e *Engine) DeliverCmd(cmd Cmd) error {
+	e.mu.Lock()
+	switch e.state {
+	case statePaused, statePausing, stateResuming:
+		// stateResuming must queue too: Resume() doesn't swap in
+		// stateRunning and drain pendingCmds until OnResumeDone fires, so
+		// a cmd delivered while that's still pending would otherwise jump
+		// ahead of cmds queued earlier while genuinely paused.
+		if e.queueWhilePaused {
+			e.pendingCmds = append(e.pendingCmds, cmd)
+			e.mu.Unlock()
+			return nil
+		}
+		e.mu.Unlock()
+		return ErrExtensionPaused
+	case stateStopping, stateStopped:
+		e.mu.Unlock()
+		return fmt.Errorf("%w: extension is stopped", ErrInvalidState)
+	}
+	e.mu.Unlock()
+
+	e.ext.OnCmd(e.tenEnv, cmd)
+	return nil
+}
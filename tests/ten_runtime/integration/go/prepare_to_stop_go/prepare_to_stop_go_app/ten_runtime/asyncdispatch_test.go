@@ -0,0 +1,106 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWithAsyncDispatch_RunsOnCmdOffTheCallingGoroutine(t *testing.T) {
+	callerID := goroutineID()
+	onCmdID := make(chan int64, 1)
+
+	ext := &blockingSendExtension{onCmd: func(tenEnv TenEnv, cmd Cmd) {
+		onCmdID <- goroutineID()
+		result, _ := NewCmdResult(StatusCodeOk, cmd)
+		tenEnv.ReturnResult(result, cmd)
+	}}
+	engine := NewEngine(ext, WithAsyncDispatch(true))
+	if err := engine.Init(); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	if err := engine.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	cmd, _ := NewCmd("greet")
+	if err := engine.DeliverCmd(cmd); err != nil {
+		t.Fatalf("DeliverCmd: %v", err)
+	}
+
+	select {
+	case got := <-onCmdID:
+		if got == callerID {
+			t.Fatal("OnCmd ran on the calling goroutine despite WithAsyncDispatch(true)")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("OnCmd never ran")
+	}
+}
+
+func TestWithoutAsyncDispatch_RunsOnCmdOnTheCallingGoroutine(t *testing.T) {
+	callerID := goroutineID()
+	var onCmdID int64
+
+	ext := &blockingSendExtension{onCmd: func(tenEnv TenEnv, cmd Cmd) {
+		onCmdID = goroutineID()
+		result, _ := NewCmdResult(StatusCodeOk, cmd)
+		tenEnv.ReturnResult(result, cmd)
+	}}
+	engine := NewEngine(ext)
+	if err := engine.Init(); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	if err := engine.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	cmd, _ := NewCmd("greet")
+	if err := engine.DeliverCmd(cmd); err != nil {
+		t.Fatalf("DeliverCmd: %v", err)
+	}
+
+	if onCmdID != callerID {
+		t.Fatalf("OnCmd ran on goroutine %d, want it to run synchronously on the caller's goroutine %d", onCmdID, callerID)
+	}
+}
+
+func TestWithAsyncDispatch_CanRunConcurrentOnCmdCalls(t *testing.T) {
+	release := make(chan struct{})
+	entered := make(chan struct{}, 2)
+
+	ext := &blockingSendExtension{onCmd: func(tenEnv TenEnv, cmd Cmd) {
+		entered <- struct{}{}
+		<-release
+		result, _ := NewCmdResult(StatusCodeOk, cmd)
+		tenEnv.ReturnResult(result, cmd)
+	}}
+	engine := NewEngine(ext, WithAsyncDispatch(true))
+	if err := engine.Init(); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	if err := engine.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	cmd1, _ := NewCmd("greet")
+	cmd2, _ := NewCmd("greet")
+	if err := engine.DeliverCmd(cmd1); err != nil {
+		t.Fatalf("DeliverCmd: %v", err)
+	}
+	if err := engine.DeliverCmd(cmd2); err != nil {
+		t.Fatalf("DeliverCmd: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		select {
+		case <-entered:
+		case <-time.After(time.Second):
+			t.Fatal("both concurrently dispatched OnCmd calls never both entered")
+		}
+	}
+	close(release)
+}
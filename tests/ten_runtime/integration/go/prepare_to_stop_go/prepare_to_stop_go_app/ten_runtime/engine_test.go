@@ -0,0 +1,335 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// lifecycleExtension records every callback it receives and requires an
+// explicit signal (via its *Chan fields) before acknowledging pause, resume
+// and stop, the same way a real extension defers Done() until its own
+// cleanup/resume work finishes.
+type lifecycleExtension struct {
+	DefaultExtension
+
+	events []string
+
+	pauseChan  chan struct{}
+	resumeChan chan struct{}
+	stopChan   chan struct{}
+
+	cmdsSeen []string
+}
+
+func newLifecycleExtension() *lifecycleExtension {
+	return &lifecycleExtension{
+		pauseChan:  make(chan struct{}),
+		resumeChan: make(chan struct{}),
+		stopChan:   make(chan struct{}),
+	}
+}
+
+func (l *lifecycleExtension) OnPause(tenEnv TenEnv) {
+	l.events = append(l.events, "pause")
+	go func() {
+		<-l.pauseChan
+		tenEnv.OnPauseDone()
+	}()
+}
+
+func (l *lifecycleExtension) OnResume(tenEnv TenEnv) {
+	l.events = append(l.events, "resume")
+	go func() {
+		<-l.resumeChan
+		tenEnv.OnResumeDone()
+	}()
+}
+
+func (l *lifecycleExtension) OnStop(tenEnv TenEnv) {
+	l.events = append(l.events, "stop")
+	go func() {
+		<-l.stopChan
+		tenEnv.OnStopDone()
+	}()
+}
+
+func (l *lifecycleExtension) OnCmd(tenEnv TenEnv, cmd Cmd) {
+	name, _ := cmd.GetName()
+	l.cmdsSeen = append(l.cmdsSeen, name)
+	result, _ := NewCmdResult(StatusCodeOk, cmd)
+	tenEnv.ReturnResult(result, cmd)
+}
+
+func TestEngineLifecycle_StartPauseResumeStop(t *testing.T) {
+	ext := newLifecycleExtension()
+	engine := NewEngine(ext)
+
+	if err := engine.Init(); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	if err := engine.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	cmd, _ := NewCmd("greet")
+	if err := engine.DeliverCmd(cmd); err != nil {
+		t.Fatalf("DeliverCmd while running: %v", err)
+	}
+
+	pauseErr := make(chan error, 1)
+	go func() { pauseErr <- engine.Pause() }()
+	close(ext.pauseChan)
+	if err := <-pauseErr; err != nil {
+		t.Fatalf("Pause: %v", err)
+	}
+
+	blockedCmd, _ := NewCmd("greet-while-paused")
+	if err := engine.DeliverCmd(blockedCmd); !errors.Is(err, ErrExtensionPaused) {
+		t.Fatalf("DeliverCmd while paused: got %v, want ErrExtensionPaused", err)
+	}
+
+	resumeErr := make(chan error, 1)
+	go func() { resumeErr <- engine.Resume() }()
+	close(ext.resumeChan)
+	if err := <-resumeErr; err != nil {
+		t.Fatalf("Resume: %v", err)
+	}
+
+	cmd2, _ := NewCmd("greet-again")
+	if err := engine.DeliverCmd(cmd2); err != nil {
+		t.Fatalf("DeliverCmd after resume: %v", err)
+	}
+
+	stopErr := make(chan error, 1)
+	go func() { stopErr <- engine.Stop() }()
+	close(ext.stopChan)
+	if err := <-stopErr; err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+
+	wantEvents := []string{"pause", "resume", "stop"}
+	if len(ext.events) != len(wantEvents) {
+		t.Fatalf("events = %v, want %v", ext.events, wantEvents)
+	}
+	for i, ev := range wantEvents {
+		if ext.events[i] != ev {
+			t.Fatalf("events = %v, want %v", ext.events, wantEvents)
+		}
+	}
+
+	wantCmds := []string{"greet", "greet-again"}
+	if len(ext.cmdsSeen) != len(wantCmds) ||
+		ext.cmdsSeen[0] != wantCmds[0] || ext.cmdsSeen[1] != wantCmds[1] {
+		t.Fatalf("cmdsSeen = %v, want %v", ext.cmdsSeen, wantCmds)
+	}
+}
+
+func TestEngineLifecycle_StopFromPausedState(t *testing.T) {
+	ext := newLifecycleExtension()
+	engine := NewEngine(ext)
+
+	if err := engine.Init(); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	if err := engine.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	pauseErr := make(chan error, 1)
+	go func() { pauseErr <- engine.Pause() }()
+	close(ext.pauseChan)
+	if err := <-pauseErr; err != nil {
+		t.Fatalf("Pause: %v", err)
+	}
+
+	// OnStop must be callable from the paused state directly, without
+	// first resuming.
+	stopErr := make(chan error, 1)
+	go func() { stopErr <- engine.Stop() }()
+	close(ext.stopChan)
+	select {
+	case err := <-stopErr:
+		if err != nil {
+			t.Fatalf("Stop from paused state: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Stop from paused state did not complete")
+	}
+}
+
+// stuckPauseExtension never acknowledges OnPause, simulating the "pause"
+// cmd's peer having crashed before replying -- Pause() would otherwise
+// block in statePausing forever.
+type stuckPauseExtension struct {
+	DefaultExtension
+
+	pauseStarted chan struct{}
+	stopChan     chan struct{}
+}
+
+func newStuckPauseExtension() *stuckPauseExtension {
+	return &stuckPauseExtension{
+		pauseStarted: make(chan struct{}),
+		stopChan:     make(chan struct{}),
+	}
+}
+
+func (s *stuckPauseExtension) OnPause(tenEnv TenEnv) {
+	close(s.pauseStarted)
+}
+
+func (s *stuckPauseExtension) OnStop(tenEnv TenEnv) {
+	go func() {
+		<-s.stopChan
+		tenEnv.OnStopDone()
+	}()
+}
+
+func TestEngineLifecycle_StopPreemptsStuckPause(t *testing.T) {
+	ext := newStuckPauseExtension()
+	engine := NewEngine(ext)
+
+	if err := engine.Init(); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	if err := engine.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	pauseErr := make(chan error, 1)
+	go func() { pauseErr <- engine.Pause() }()
+	// Wait for Pause() to have transitioned to statePausing and entered
+	// OnPause before preempting it, so the race is deterministic.
+	<-ext.pauseStarted
+
+	stopErr := make(chan error, 1)
+	go func() { stopErr <- engine.Stop() }()
+	close(ext.stopChan)
+
+	select {
+	case err := <-stopErr:
+		if err != nil {
+			t.Fatalf("Stop while Pause is stuck: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Stop did not complete while Pause was stuck in statePausing")
+	}
+
+	select {
+	case err := <-pauseErr:
+		if !errors.Is(err, ErrShutdownPreempted) {
+			t.Fatalf("Pause = %v, want ErrShutdownPreempted", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Pause was never woken by the preempting Stop")
+	}
+}
+
+func TestEngineLifecycle_QueueWhilePaused(t *testing.T) {
+	ext := newLifecycleExtension()
+	engine := NewEngine(ext)
+	engine.SetQueueWhilePaused(true)
+
+	if err := engine.Init(); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	if err := engine.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	pauseErr := make(chan error, 1)
+	go func() { pauseErr <- engine.Pause() }()
+	close(ext.pauseChan)
+	if err := <-pauseErr; err != nil {
+		t.Fatalf("Pause: %v", err)
+	}
+
+	queuedCmd, _ := NewCmd("queued")
+	if err := engine.DeliverCmd(queuedCmd); err != nil {
+		t.Fatalf("DeliverCmd while paused (queueing): %v", err)
+	}
+	if len(ext.cmdsSeen) != 0 {
+		t.Fatalf(
+			"cmd delivered before resume: cmdsSeen = %v", ext.cmdsSeen,
+		)
+	}
+
+	resumeErr := make(chan error, 1)
+	go func() { resumeErr <- engine.Resume() }()
+	close(ext.resumeChan)
+	if err := <-resumeErr; err != nil {
+		t.Fatalf("Resume: %v", err)
+	}
+
+	if len(ext.cmdsSeen) != 1 || ext.cmdsSeen[0] != "queued" {
+		t.Fatalf(
+			"queued cmd not replayed on resume: cmdsSeen = %v", ext.cmdsSeen,
+		)
+	}
+}
+
+func TestEngineLifecycle_DeliverDuringResumingIsOrdered(t *testing.T) {
+	ext := newLifecycleExtension()
+	engine := NewEngine(ext)
+	engine.SetQueueWhilePaused(true)
+
+	if err := engine.Init(); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	if err := engine.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	pauseErr := make(chan error, 1)
+	go func() { pauseErr <- engine.Pause() }()
+	close(ext.pauseChan)
+	if err := <-pauseErr; err != nil {
+		t.Fatalf("Pause: %v", err)
+	}
+
+	queuedCmd, _ := NewCmd("queued-while-paused")
+	if err := engine.DeliverCmd(queuedCmd); err != nil {
+		t.Fatalf("DeliverCmd while paused: %v", err)
+	}
+
+	// Start Resume() but withhold OnResumeDone so the engine sits in
+	// stateResuming while we deliver another cmd.
+	resumeErr := make(chan error, 1)
+	go func() { resumeErr <- engine.Resume() }()
+	for {
+		engine.mu.Lock()
+		state := engine.state
+		engine.mu.Unlock()
+		if state == stateResuming {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	duringResumeCmd, _ := NewCmd("delivered-during-resuming")
+	if err := engine.DeliverCmd(duringResumeCmd); err != nil {
+		t.Fatalf("DeliverCmd while resuming: %v", err)
+	}
+	if len(ext.cmdsSeen) != 0 {
+		t.Fatalf(
+			"cmd delivered before resume completed: cmdsSeen = %v",
+			ext.cmdsSeen,
+		)
+	}
+
+	close(ext.resumeChan)
+	if err := <-resumeErr; err != nil {
+		t.Fatalf("Resume: %v", err)
+	}
+
+	wantCmds := []string{"queued-while-paused", "delivered-during-resuming"}
+	if len(ext.cmdsSeen) != len(wantCmds) ||
+		ext.cmdsSeen[0] != wantCmds[0] || ext.cmdsSeen[1] != wantCmds[1] {
+		t.Fatalf("cmdsSeen = %v, want %v", ext.cmdsSeen, wantCmds)
+	}
+}
@@ -0,0 +1,353 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+)
+
+// AudioFrameLayout identifies how a multi-channel AudioFrame's buffer
+// arranges its samples: interleaved (each sample frame's channels
+// adjacent, the usual PCM wire format) or planar (each channel's samples
+// contiguous, one block per channel). ChannelData and SetChannelData
+// consult it to know how to slice the raw buffer; it has no effect on
+// SetBuf/LockBuf, which always deal in raw bytes.
+type AudioFrameLayout int
+
+const (
+	AudioFrameLayoutInterleaved AudioFrameLayout = iota
+	AudioFrameLayoutPlanar
+)
+
+// ErrAudioFrameChannelIndexOutOfRange is returned by ChannelData,
+// ChannelDataFloat32, SetChannelData and SetChannelDataFloat32 when ch is
+// negative or beyond GetNumberOfChannels.
+var ErrAudioFrameChannelIndexOutOfRange = errors.New("ten: audio frame channel index out of range")
+
+// ErrAudioFrameBytesPerSampleMismatch is returned by ChannelData and
+// SetChannelData when GetBytesPerSample isn't 2 (the size of an int16),
+// and by ChannelDataFloat32 and SetChannelDataFloat32 when it isn't 4 (the
+// size of a float32).
+var ErrAudioFrameBytesPerSampleMismatch = errors.New("ten: audio frame bytes-per-sample does not match the requested sample type")
+
+// ErrAudioFrameSampleCountMismatch is returned by SetChannelData and
+// SetChannelDataFloat32 when samples doesn't have exactly
+// GetSamplesPerChannel elements.
+var ErrAudioFrameSampleCountMismatch = errors.New("ten: audio frame sample count does not match samples-per-channel")
+
+// ErrAudioFrameBufferTooSmall is returned by ChannelData and
+// ChannelDataFloat32 when the buffer set via SetBuf is smaller than
+// GetNumberOfChannels * GetSamplesPerChannel * GetBytesPerSample calls for.
+var ErrAudioFrameBufferTooSmall = errors.New("ten: audio frame buffer is smaller than its declared format")
+
+// AudioFrame represents a single PCM audio frame flowing through a graph,
+// either delivered to an extension or built via NewAudioFrame for
+// TenEnv.SendAudioFrame.
+//
+// Thread-safety: a locked buffer is only ever safe to read on the goroutine
+// that holds it, and only until that goroutine calls UnlockBuf. The runtime
+// (in a real binding, the native side; here, whatever holds the AudioFrame)
+// must not recycle or reuse the frame's storage before unlock.
+type AudioFrame interface {
+	Msg
+
+	// SetBuf, LockBuf, UnlockBuf and GetBufSize implement frameBuf's
+	// buffer contract; see its doc comments for the copy-vs-alias
+	// semantics of each.
+	SetBuf(buf []byte) error
+	LockBuf() ([]byte, error)
+	UnlockBuf(buf []byte) error
+	GetBufSize() (int, error)
+
+	SetSampleRate(rate int32) error
+	GetSampleRate() (int32, error)
+
+	SetChannelLayout(layout uint64) error
+	GetChannelLayout() (uint64, error)
+
+	SetBytesPerSample(n int32) error
+	GetBytesPerSample() (int32, error)
+
+	SetNumberOfChannels(n int32) error
+	GetNumberOfChannels() (int32, error)
+
+	SetSamplesPerChannel(n int32) error
+	GetSamplesPerChannel() (int32, error)
+
+	// SetDataLayout/GetDataLayout select how ChannelData and
+	// SetChannelData interpret the buffer set via SetBuf; the default is
+	// AudioFrameLayoutInterleaved.
+	SetDataLayout(layout AudioFrameLayout) error
+	GetDataLayout() (AudioFrameLayout, error)
+
+	// ChannelData returns channel ch's samples, deinterleaving or slicing
+	// them out of the buffer set via SetBuf according to GetDataLayout.
+	// It requires GetBytesPerSample() == 2; use ChannelDataFloat32 for a
+	// 4-byte-per-sample frame. The returned slice is a fresh copy, safe
+	// to use after further calls on this frame.
+	ChannelData(ch int) ([]int16, error)
+
+	// ChannelDataFloat32 is ChannelData for a frame whose
+	// GetBytesPerSample() == 4.
+	ChannelDataFloat32(ch int) ([]float32, error)
+
+	// SetChannelData writes samples into channel ch's positions in the
+	// buffer according to GetDataLayout, growing the buffer if needed to
+	// fit every channel at the frame's current GetNumberOfChannels and
+	// GetSamplesPerChannel. len(samples) must equal GetSamplesPerChannel,
+	// and GetBytesPerSample() must be 2.
+	SetChannelData(ch int, samples []int16) error
+
+	// SetChannelDataFloat32 is SetChannelData for a frame whose
+	// GetBytesPerSample() == 4.
+	SetChannelDataFloat32(ch int, samples []float32) error
+
+	// SetTimestamp/GetTimestamp carry this frame's presentation timestamp
+	// in microseconds, on whatever clock the frame's source uses -- this
+	// package doesn't interpret it, so aligning frames from more than one
+	// source (e.g. a mixing extension) requires them to share a clock
+	// already, the same way ten_runtime's native audio frame does.
+	SetTimestamp(timestamp int64) error
+	GetTimestamp() (int64, error)
+
+	// GetSource follows the same semantics as Cmd.GetSource: the Loc this
+	// AudioFrame was sent from, stamped by SendAudioFrame just before
+	// handing it to the sender; a frame built via NewAudioFrame that's
+	// never been sent carries a zero Loc.
+	GetSource() (Loc, error)
+
+	// Clone returns a deep copy of this AudioFrame: same name and format
+	// fields, plus a copy of the current buffer independent of this
+	// frame's own lock state, as a new, unlocked, independently owned
+	// AudioFrame. It's for broadcasting the same frame to several
+	// destinations without them fighting over one buffer via LockBuf. The
+	// clone's source is reset to a zero Loc rather than copied, the same
+	// as Cmd.Clone, since it hasn't been sent anywhere yet.
+	Clone() (AudioFrame, error)
+}
+
+type audioFrameImpl struct {
+	frameBuf
+	name              string
+	sampleRate        int32
+	channelLayout     uint64
+	bytesPerSample    int32
+	numberOfChannels  int32
+	samplesPerChannel int32
+	dataLayout        AudioFrameLayout
+	timestamp         int64
+
+	// source is stamped by stampAudioFrameSource; see its doc comment in
+	// loc.go.
+	source Loc
+}
+
+// NewAudioFrame creates a new, empty AudioFrame named name.
+func NewAudioFrame(name string) (AudioFrame, error) {
+	return &audioFrameImpl{name: name}, nil
+}
+
+func (a *audioFrameImpl) GetName() (string, error) {
+	return a.name, nil
+}
+
+func (a *audioFrameImpl) GetType() (MsgType, error) {
+	return MsgTypeAudioFrame, nil
+}
+
+func (a *audioFrameImpl) SetSampleRate(rate int32) error {
+	a.sampleRate = rate
+	return nil
+}
+
+func (a *audioFrameImpl) GetSampleRate() (int32, error) {
+	return a.sampleRate, nil
+}
+
+func (a *audioFrameImpl) SetChannelLayout(layout uint64) error {
+	a.channelLayout = layout
+	return nil
+}
+
+func (a *audioFrameImpl) GetChannelLayout() (uint64, error) {
+	return a.channelLayout, nil
+}
+
+func (a *audioFrameImpl) SetBytesPerSample(n int32) error {
+	a.bytesPerSample = n
+	return nil
+}
+
+func (a *audioFrameImpl) GetBytesPerSample() (int32, error) {
+	return a.bytesPerSample, nil
+}
+
+func (a *audioFrameImpl) SetNumberOfChannels(n int32) error {
+	a.numberOfChannels = n
+	return nil
+}
+
+func (a *audioFrameImpl) GetNumberOfChannels() (int32, error) {
+	return a.numberOfChannels, nil
+}
+
+func (a *audioFrameImpl) SetSamplesPerChannel(n int32) error {
+	a.samplesPerChannel = n
+	return nil
+}
+
+func (a *audioFrameImpl) GetSamplesPerChannel() (int32, error) {
+	return a.samplesPerChannel, nil
+}
+
+func (a *audioFrameImpl) SetDataLayout(layout AudioFrameLayout) error {
+	a.dataLayout = layout
+	return nil
+}
+
+func (a *audioFrameImpl) GetDataLayout() (AudioFrameLayout, error) {
+	return a.dataLayout, nil
+}
+
+// checkChannel reports ErrAudioFrameChannelIndexOutOfRange for a channel
+// index outside [0, GetNumberOfChannels).
+func (a *audioFrameImpl) checkChannel(ch int) error {
+	if ch < 0 || ch >= int(a.numberOfChannels) {
+		return ErrAudioFrameChannelIndexOutOfRange
+	}
+	return nil
+}
+
+// sampleOffset returns the byte offset of channel ch's sample i within the
+// buffer, according to the frame's current data layout and format.
+func (a *audioFrameImpl) sampleOffset(ch, i int) int {
+	bps := int(a.bytesPerSample)
+	if a.dataLayout == AudioFrameLayoutPlanar {
+		return (ch*int(a.samplesPerChannel) + i) * bps
+	}
+	return (i*int(a.numberOfChannels) + ch) * bps
+}
+
+func (a *audioFrameImpl) ChannelData(ch int) ([]int16, error) {
+	if err := a.checkChannel(ch); err != nil {
+		return nil, err
+	}
+	if a.bytesPerSample != 2 {
+		return nil, ErrAudioFrameBytesPerSampleMismatch
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	samples := make([]int16, a.samplesPerChannel)
+	for i := range samples {
+		off := a.sampleOffset(ch, i)
+		if off+2 > len(a.buf) {
+			return nil, ErrAudioFrameBufferTooSmall
+		}
+		samples[i] = int16(binary.LittleEndian.Uint16(a.buf[off:]))
+	}
+	return samples, nil
+}
+
+func (a *audioFrameImpl) ChannelDataFloat32(ch int) ([]float32, error) {
+	if err := a.checkChannel(ch); err != nil {
+		return nil, err
+	}
+	if a.bytesPerSample != 4 {
+		return nil, ErrAudioFrameBytesPerSampleMismatch
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	samples := make([]float32, a.samplesPerChannel)
+	for i := range samples {
+		off := a.sampleOffset(ch, i)
+		if off+4 > len(a.buf) {
+			return nil, ErrAudioFrameBufferTooSmall
+		}
+		samples[i] = math.Float32frombits(binary.LittleEndian.Uint32(a.buf[off:]))
+	}
+	return samples, nil
+}
+
+func (a *audioFrameImpl) SetChannelData(ch int, samples []int16) error {
+	if err := a.checkChannel(ch); err != nil {
+		return err
+	}
+	if a.bytesPerSample != 2 {
+		return ErrAudioFrameBytesPerSampleMismatch
+	}
+	if len(samples) != int(a.samplesPerChannel) {
+		return ErrAudioFrameSampleCountMismatch
+	}
+	if err := a.resizeBuf(int(a.numberOfChannels) * int(a.samplesPerChannel) * int(a.bytesPerSample)); err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for i, s := range samples {
+		off := a.sampleOffset(ch, i)
+		binary.LittleEndian.PutUint16(a.buf[off:], uint16(s))
+	}
+	return nil
+}
+
+func (a *audioFrameImpl) SetChannelDataFloat32(ch int, samples []float32) error {
+	if err := a.checkChannel(ch); err != nil {
+		return err
+	}
+	if a.bytesPerSample != 4 {
+		return ErrAudioFrameBytesPerSampleMismatch
+	}
+	if len(samples) != int(a.samplesPerChannel) {
+		return ErrAudioFrameSampleCountMismatch
+	}
+	if err := a.resizeBuf(int(a.numberOfChannels) * int(a.samplesPerChannel) * int(a.bytesPerSample)); err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for i, s := range samples {
+		off := a.sampleOffset(ch, i)
+		binary.LittleEndian.PutUint32(a.buf[off:], math.Float32bits(s))
+	}
+	return nil
+}
+
+func (a *audioFrameImpl) SetTimestamp(timestamp int64) error {
+	a.timestamp = timestamp
+	return nil
+}
+
+func (a *audioFrameImpl) GetTimestamp() (int64, error) {
+	return a.timestamp, nil
+}
+
+func (a *audioFrameImpl) GetSource() (Loc, error) {
+	return a.source, nil
+}
+
+func (a *audioFrameImpl) Clone() (AudioFrame, error) {
+	clone := &audioFrameImpl{
+		name:              a.name,
+		sampleRate:        a.sampleRate,
+		channelLayout:     a.channelLayout,
+		bytesPerSample:    a.bytesPerSample,
+		numberOfChannels:  a.numberOfChannels,
+		samplesPerChannel: a.samplesPerChannel,
+		dataLayout:        a.dataLayout,
+		timestamp:         a.timestamp,
+	}
+	clone.buf = a.cloneBuf()
+	return clone, nil
+}
@@ -0,0 +1,304 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestAudioFrame_SetBufCopiesSoCallerCanReuseSlice(t *testing.T) {
+	frame, _ := NewAudioFrame("pcm")
+	buf := []byte("pcmdata")
+	if err := frame.SetBuf(buf); err != nil {
+		t.Fatalf("SetBuf: %v", err)
+	}
+	buf[0] = 'X'
+
+	locked, _ := frame.LockBuf()
+	if string(locked) != "pcmdata" {
+		t.Fatalf("buffer after mutating caller's slice = %q, want %q", locked, "pcmdata")
+	}
+}
+
+func TestAudioFrame_LockBufAliasesUnderlyingBuffer(t *testing.T) {
+	frame, _ := NewAudioFrame("pcm")
+	frame.SetBuf([]byte("pcmdata"))
+
+	locked, err := frame.LockBuf()
+	if err != nil {
+		t.Fatalf("LockBuf: %v", err)
+	}
+	locked[0] = 'P'
+
+	if err := frame.UnlockBuf(locked); err != nil {
+		t.Fatalf("UnlockBuf: %v", err)
+	}
+
+	locked2, _ := frame.LockBuf()
+	if string(locked2) != "Pcmdata" {
+		t.Fatalf("buffer after LockBuf mutation = %q, want %q", locked2, "Pcmdata")
+	}
+}
+
+func TestAudioFrame_SecondLockBufBeforeUnlockIsError(t *testing.T) {
+	frame, _ := NewAudioFrame("pcm")
+	if _, err := frame.LockBuf(); err != nil {
+		t.Fatalf("LockBuf: %v", err)
+	}
+	if _, err := frame.LockBuf(); !errors.Is(err, ErrFrameBufLocked) {
+		t.Fatalf("second LockBuf() = %v, want ErrFrameBufLocked", err)
+	}
+}
+
+func TestAudioFrame_UnlockBufWithoutLockIsError(t *testing.T) {
+	frame, _ := NewAudioFrame("pcm")
+	if err := frame.UnlockBuf(nil); !errors.Is(err, ErrFrameBufNotLocked) {
+		t.Fatalf("UnlockBuf() without LockBuf = %v, want ErrFrameBufNotLocked", err)
+	}
+}
+
+func TestAudioFrame_GetBufSizeMatchesBufferLength(t *testing.T) {
+	frame, _ := NewAudioFrame("pcm")
+	frame.SetBuf(make([]byte, 320))
+	size, err := frame.GetBufSize()
+	if err != nil {
+		t.Fatalf("GetBufSize: %v", err)
+	}
+	if size != 320 {
+		t.Fatalf("GetBufSize() = %d, want 320", size)
+	}
+}
+
+func TestAudioFrame_SettersRoundTrip(t *testing.T) {
+	frame, _ := NewAudioFrame("pcm")
+	frame.SetSampleRate(16000)
+	frame.SetChannelLayout(1)
+	frame.SetBytesPerSample(2)
+	frame.SetNumberOfChannels(2)
+	frame.SetSamplesPerChannel(160)
+	frame.SetTimestamp(12345)
+
+	name, _ := frame.GetName()
+	rate, _ := frame.GetSampleRate()
+	layout, _ := frame.GetChannelLayout()
+	bps, _ := frame.GetBytesPerSample()
+	channels, _ := frame.GetNumberOfChannels()
+	samples, _ := frame.GetSamplesPerChannel()
+	ts, _ := frame.GetTimestamp()
+	if name != "pcm" || rate != 16000 || layout != 1 || bps != 2 || channels != 2 || samples != 160 || ts != 12345 {
+		t.Fatalf(
+			"got %q, %d, %d, %d, %d, %d, %d, want %q, 16000, 1, 2, 2, 160, 12345",
+			name, rate, layout, bps, channels, samples, ts, "pcm",
+		)
+	}
+}
+
+func TestTenEnv_SendAudioFrameInvokesDefaultSenderSuccessfully(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+	tenEnv := engine.TenEnv()
+	frame, _ := NewAudioFrame("pcm")
+
+	done := make(chan error, 1)
+	if err := tenEnv.SendAudioFrame(frame, func(_ TenEnv, err error) {
+		done <- err
+	}); err != nil {
+		t.Fatalf("SendAudioFrame: %v", err)
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("SendAudioFrame callback err = %v, want nil", err)
+	}
+}
+
+func TestTenEnv_SendAudioFrameReportsSenderFailure(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+	wantErr := errors.New("downstream unreachable")
+	engine.SetAudioFrameSender(func(frame AudioFrame, cb func(TenEnv, error)) {
+		cb(engine.TenEnv(), wantErr)
+	})
+	tenEnv := engine.TenEnv()
+	frame, _ := NewAudioFrame("pcm")
+
+	done := make(chan error, 1)
+	tenEnv.SendAudioFrame(frame, func(_ TenEnv, err error) {
+		done <- err
+	})
+	if err := <-done; !errors.Is(err, wantErr) {
+		t.Fatalf("SendAudioFrame callback err = %v, want %v", err, wantErr)
+	}
+}
+
+func TestTenEnv_SendAudioFrameRejectsNilFrame(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+	if err := engine.TenEnv().SendAudioFrame(nil, func(TenEnv, error) {}); err == nil {
+		t.Fatal("SendAudioFrame(nil, ...) = nil error, want an error")
+	}
+}
+
+func TestAudioFrame_CloneCopiesFieldsAndBufferIndependently(t *testing.T) {
+	frame, _ := NewAudioFrame("pcm")
+	frame.SetBuf([]byte{1, 2, 3})
+	frame.SetSampleRate(48000)
+	frame.SetChannelLayout(3)
+	frame.SetBytesPerSample(2)
+	frame.SetNumberOfChannels(2)
+	frame.SetSamplesPerChannel(480)
+	frame.SetTimestamp(123)
+
+	clone, err := frame.Clone()
+	if err != nil {
+		t.Fatalf("Clone: %v", err)
+	}
+	clone.SetBuf([]byte{9, 9, 9})
+
+	name, _ := clone.GetName()
+	rate, _ := clone.GetSampleRate()
+	layout, _ := clone.GetChannelLayout()
+	bps, _ := clone.GetBytesPerSample()
+	channels, _ := clone.GetNumberOfChannels()
+	samples, _ := clone.GetSamplesPerChannel()
+	ts, _ := clone.GetTimestamp()
+	if name != "pcm" || rate != 48000 || layout != 3 || bps != 2 || channels != 2 || samples != 480 || ts != 123 {
+		t.Fatalf(
+			"clone fields = %q, %d, %d, %d, %d, %d, %d, want %q, 48000, 3, 2, 2, 480, 123",
+			name, rate, layout, bps, channels, samples, ts, "pcm",
+		)
+	}
+
+	original, _ := frame.LockBuf()
+	if len(original) != 3 || original[0] != 1 {
+		t.Fatalf("original buf = %v after mutating clone, want unchanged [1 2 3]", original)
+	}
+}
+
+func TestAudioFrame_SetChannelDataAndChannelDataRoundTripInterleaved(t *testing.T) {
+	frame, _ := NewAudioFrame("pcm")
+	frame.SetBytesPerSample(2)
+	frame.SetNumberOfChannels(2)
+	frame.SetSamplesPerChannel(3)
+
+	frame.SetChannelData(0, []int16{1, 2, 3})
+	frame.SetChannelData(1, []int16{-1, -2, -3})
+
+	left, err := frame.ChannelData(0)
+	if err != nil {
+		t.Fatalf("ChannelData(0): %v", err)
+	}
+	right, err := frame.ChannelData(1)
+	if err != nil {
+		t.Fatalf("ChannelData(1): %v", err)
+	}
+	if !equalInt16(left, []int16{1, 2, 3}) || !equalInt16(right, []int16{-1, -2, -3}) {
+		t.Fatalf("left = %v, right = %v, want [1 2 3], [-1 -2 -3]", left, right)
+	}
+
+	raw, _ := frame.LockBuf()
+	want := []int16{1, -1, 2, -2, 3, -3}
+	for i, w := range want {
+		if int16(int16(raw[2*i])|int16(raw[2*i+1])<<8) != w {
+			t.Fatalf("interleaved buffer sample %d = %v, want %d", i, raw[2*i:2*i+2], w)
+		}
+	}
+}
+
+func TestAudioFrame_SetChannelDataAndChannelDataRoundTripPlanar(t *testing.T) {
+	frame, _ := NewAudioFrame("pcm")
+	frame.SetDataLayout(AudioFrameLayoutPlanar)
+	frame.SetBytesPerSample(2)
+	frame.SetNumberOfChannels(2)
+	frame.SetSamplesPerChannel(3)
+
+	frame.SetChannelData(0, []int16{1, 2, 3})
+	frame.SetChannelData(1, []int16{-1, -2, -3})
+
+	left, _ := frame.ChannelData(0)
+	right, _ := frame.ChannelData(1)
+	if !equalInt16(left, []int16{1, 2, 3}) || !equalInt16(right, []int16{-1, -2, -3}) {
+		t.Fatalf("left = %v, right = %v, want [1 2 3], [-1 -2 -3]", left, right)
+	}
+}
+
+func TestAudioFrame_SetChannelDataFloat32AndChannelDataFloat32RoundTrip(t *testing.T) {
+	frame, _ := NewAudioFrame("pcm")
+	frame.SetBytesPerSample(4)
+	frame.SetNumberOfChannels(2)
+	frame.SetSamplesPerChannel(2)
+
+	frame.SetChannelDataFloat32(0, []float32{0.5, -0.5})
+	frame.SetChannelDataFloat32(1, []float32{1.5, -1.5})
+
+	left, err := frame.ChannelDataFloat32(0)
+	if err != nil {
+		t.Fatalf("ChannelDataFloat32(0): %v", err)
+	}
+	right, _ := frame.ChannelDataFloat32(1)
+	if !equalFloat32(left, []float32{0.5, -0.5}) || !equalFloat32(right, []float32{1.5, -1.5}) {
+		t.Fatalf("left = %v, right = %v, want [0.5 -0.5], [1.5 -1.5]", left, right)
+	}
+}
+
+func TestAudioFrame_ChannelDataRejectsOutOfRangeChannel(t *testing.T) {
+	frame, _ := NewAudioFrame("pcm")
+	frame.SetBytesPerSample(2)
+	frame.SetNumberOfChannels(2)
+	frame.SetSamplesPerChannel(1)
+
+	if _, err := frame.ChannelData(2); !errors.Is(err, ErrAudioFrameChannelIndexOutOfRange) {
+		t.Fatalf("ChannelData(2) = %v, want ErrAudioFrameChannelIndexOutOfRange", err)
+	}
+	if err := frame.SetChannelData(-1, []int16{0}); !errors.Is(err, ErrAudioFrameChannelIndexOutOfRange) {
+		t.Fatalf("SetChannelData(-1, ...) = %v, want ErrAudioFrameChannelIndexOutOfRange", err)
+	}
+}
+
+func TestAudioFrame_ChannelDataRejectsBytesPerSampleMismatch(t *testing.T) {
+	frame, _ := NewAudioFrame("pcm")
+	frame.SetBytesPerSample(4)
+	frame.SetNumberOfChannels(1)
+	frame.SetSamplesPerChannel(1)
+	frame.SetBuf(make([]byte, 4))
+
+	if _, err := frame.ChannelData(0); !errors.Is(err, ErrAudioFrameBytesPerSampleMismatch) {
+		t.Fatalf("ChannelData(0) with 4-byte samples = %v, want ErrAudioFrameBytesPerSampleMismatch", err)
+	}
+	if _, err := frame.ChannelDataFloat32(0); err != nil {
+		t.Fatalf("ChannelDataFloat32(0): %v", err)
+	}
+}
+
+func TestAudioFrame_SetChannelDataRejectsWrongSampleCount(t *testing.T) {
+	frame, _ := NewAudioFrame("pcm")
+	frame.SetBytesPerSample(2)
+	frame.SetNumberOfChannels(1)
+	frame.SetSamplesPerChannel(3)
+
+	if err := frame.SetChannelData(0, []int16{1, 2}); !errors.Is(err, ErrAudioFrameSampleCountMismatch) {
+		t.Fatalf("SetChannelData with 2 samples, want 3 = %v, want ErrAudioFrameSampleCountMismatch", err)
+	}
+}
+
+func equalInt16(a, b []int16) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func equalFloat32(a, b []float32) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,110 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCmdResultFromError_RoundTripsCode(t *testing.T) {
+	cmd, _ := NewCmd("frobnicate")
+	err := NewError(ErrUnknownCmd, "unknown cmd: frobnicate")
+
+	result, rErr := NewCmdResultFromError(err, cmd)
+	if rErr != nil {
+		t.Fatalf("NewCmdResultFromError: %v", rErr)
+	}
+
+	status, _ := result.StatusCode()
+	if status != StatusCodeError {
+		t.Fatalf("StatusCode = %v, want StatusCodeError", status)
+	}
+
+	gotErr := result.AsError()
+	if gotErr == nil {
+		t.Fatal("AsError returned nil, want a reconstructed error")
+	}
+	if !errors.Is(gotErr, ErrUnknownCmd) {
+		t.Fatalf("errors.Is(AsError(), ErrUnknownCmd) = false, err = %v", gotErr)
+	}
+
+	var te Error
+	if !errors.As(gotErr, &te) {
+		t.Fatalf("errors.As(AsError(), *Error) = false, err = %v", gotErr)
+	}
+	if te.Message() != "unknown cmd: frobnicate" {
+		t.Fatalf("Message() = %q, want %q", te.Message(), "unknown cmd: frobnicate")
+	}
+}
+
+func TestCmdResultFromError_PreservesDetailsAndCause(t *testing.T) {
+	cmd, _ := NewCmd("connect")
+	cause := errors.New("dial tcp: connection refused")
+	err := NewError(
+		ErrUnavailable,
+		"downstream service unreachable",
+		WithDetails(map[string]any{"host": "db.internal", "port": float64(5432)}),
+		WithCause(cause),
+	)
+
+	result, _ := NewCmdResultFromError(err, cmd)
+	gotErr := result.AsError()
+
+	var te Error
+	if !errors.As(gotErr, &te) {
+		t.Fatalf("errors.As failed for %v", gotErr)
+	}
+	if te.Details()["host"] != "db.internal" {
+		t.Fatalf("Details()[\"host\"] = %v, want %q", te.Details()["host"], "db.internal")
+	}
+	if te.Cause() == nil || te.Cause().Error() != cause.Error() {
+		t.Fatalf("Cause() = %v, want %q", te.Cause(), cause.Error())
+	}
+	if !errors.Is(gotErr, ErrUnavailable) {
+		t.Fatalf("errors.Is(AsError(), ErrUnavailable) = false")
+	}
+	if errors.Is(gotErr, ErrUnknownCmd) {
+		t.Fatalf("errors.Is(AsError(), ErrUnknownCmd) = true, want false")
+	}
+}
+
+func TestCmdResult_AsErrorNilForOkStatus(t *testing.T) {
+	cmd, _ := NewCmd("ping")
+	result, _ := NewCmdResult(StatusCodeOk, cmd)
+	if err := result.AsError(); err != nil {
+		t.Fatalf("AsError() on an OK result = %v, want nil", err)
+	}
+}
+
+// resultCapturingTenEnv embeds tenEnvImpl and overrides only ReturnResult,
+// recording the CmdResult it was handed so a test can inspect it.
+type resultCapturingTenEnv struct {
+	*tenEnvImpl
+	returned CmdResult
+}
+
+func (t *resultCapturingTenEnv) ReturnResult(result CmdResult, cmd Cmd) error {
+	t.returned = result
+	return nil
+}
+
+func TestDefaultExtensionOnCmd_ReturnsTypedError(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+	tenEnv := &resultCapturingTenEnv{tenEnvImpl: engine.tenEnv}
+	cmd, _ := NewCmd("unhandled")
+
+	DefaultExtension{}.OnCmd(tenEnv, cmd)
+
+	if tenEnv.returned == nil {
+		t.Fatal("OnCmd did not call ReturnResult")
+	}
+	if !errors.Is(tenEnv.returned.AsError(), ErrUnknownCmd) {
+		t.Fatalf(
+			"errors.Is(result.AsError(), ErrUnknownCmd) = false, err = %v",
+			tenEnv.returned.AsError(),
+		)
+	}
+}
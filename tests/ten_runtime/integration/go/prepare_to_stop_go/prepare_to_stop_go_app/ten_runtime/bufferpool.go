@@ -0,0 +1,184 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"errors"
+	"sync"
+)
+
+// ErrBufferPoolExhausted is returned by a SetBuf/resizeBuf call on an
+// AudioFrame, VideoFrame or Data that would push the process-wide buffer
+// pool's usage past its cap, while the pool is in BufferPoolModeError (the
+// default).
+var ErrBufferPoolExhausted = errors.New("ten: buffer pool exhausted")
+
+// BufferPoolMode chooses what a SetBuf/resizeBuf call does when it would
+// push the buffer pool's usage past its cap.
+type BufferPoolMode int
+
+const (
+	// BufferPoolModeError fails the call immediately with
+	// ErrBufferPoolExhausted, leaving the frame or Data's buffer
+	// unchanged. This is the default.
+	BufferPoolModeError BufferPoolMode = iota
+
+	// BufferPoolModeBlock holds the call until enough usage is released
+	// by some other buffer shrinking or being replaced with a smaller
+	// one, the way a bounded worker pool's Submit blocks for a free
+	// slot; see workerpool.go.
+	BufferPoolModeBlock
+)
+
+// BufferPoolOption configures SetBufferPool beyond the required maxBytes.
+type BufferPoolOption func(*bufferPool)
+
+// WithBufferPoolMode chooses the BufferPoolMode an over-cap allocation
+// hits; the default, if this option isn't given, is BufferPoolModeError.
+func WithBufferPoolMode(mode BufferPoolMode) BufferPoolOption {
+	return func(p *bufferPool) { p.mode = mode }
+}
+
+// bufferPool tracks how many bytes AudioFrame/VideoFrame/Data buffers are
+// currently holding, against a cap, so the process can be given a hard
+// ceiling on media memory instead of relying on GC timing to notice.
+type bufferPool struct {
+	mu   sync.Mutex
+	cond *sync.Cond
+
+	maxBytes  int
+	usedBytes int
+
+	mode BufferPoolMode
+
+	allocations int64
+	rejections  int64
+}
+
+var (
+	processBufferPoolMu sync.Mutex
+	processBufferPool   *bufferPool
+)
+
+// BufferPoolUsage is a snapshot of SetBufferPool's process-wide usage, for
+// a caller monitoring how close media traffic is running to the cap.
+type BufferPoolUsage struct {
+	// MaxBytes is the cap SetBufferPool was last called with. Zero means
+	// SetBufferPool has never been called -- there is no pool, and every
+	// SetBuf/resizeBuf call is unaccounted, exactly as before this
+	// feature existed.
+	MaxBytes int
+
+	// UsedBytes is the sum of every AudioFrame/VideoFrame/Data buffer's
+	// current length that's gone through SetBuf or resizeBuf since the
+	// pool was created.
+	UsedBytes int
+
+	// Allocations counts every SetBuf/resizeBuf call that grew usage and
+	// was admitted, whether immediately or after blocking.
+	Allocations int64
+
+	// Rejections counts every call BufferPoolModeError turned away with
+	// ErrBufferPoolExhausted. It's always zero under BufferPoolModeBlock,
+	// since that mode never rejects -- it only ever waits.
+	Rejections int64
+}
+
+// SetBufferPool turns on a hard ceiling of maxBytes total bytes across
+// every AudioFrame, VideoFrame and Data buffer in the process: a
+// SetBuf or resizeBuf call that would grow usage past the cap is rejected
+// (the default, BufferPoolModeError) or blocked until room frees up
+// (WithBufferPoolMode(BufferPoolModeBlock)), instead of always succeeding
+// and letting RSS grow unbounded under sustained load. maxBytes <= 0
+// means unlimited -- usage is still tracked and visible via
+// BufferPoolStats, but nothing is ever rejected or blocked.
+//
+// This is pure Go accounting, not a real allocator: there is no C-owned
+// native memory in this simulation for GC to lose visibility into (see
+// Data's doc comment), and a shrinking or discarded buffer's bytes are
+// only released by a later SetBuf/resizeBuf call that observes the
+// shrink -- there's no finalizer hooked up to notice a frame becoming
+// unreachable and give its bytes back on its own. Calling SetBufferPool
+// again replaces the previous pool outright, discarding its accumulated
+// usage and stats along with it, the same way SetRateLimit replaces a
+// cmd's previous limiter.
+func SetBufferPool(maxBytes int, opts ...BufferPoolOption) {
+	p := &bufferPool{maxBytes: maxBytes}
+	p.cond = sync.NewCond(&p.mu)
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	processBufferPoolMu.Lock()
+	defer processBufferPoolMu.Unlock()
+	processBufferPool = p
+}
+
+// BufferPoolStats reports the process-wide buffer pool's current usage;
+// see BufferPoolUsage's doc comment for what a zero MaxBytes means.
+func BufferPoolStats() BufferPoolUsage {
+	p := currentBufferPool()
+	if p == nil {
+		return BufferPoolUsage{}
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return BufferPoolUsage{
+		MaxBytes:    p.maxBytes,
+		UsedBytes:   p.usedBytes,
+		Allocations: p.allocations,
+		Rejections:  p.rejections,
+	}
+}
+
+func currentBufferPool() *bufferPool {
+	processBufferPoolMu.Lock()
+	defer processBufferPoolMu.Unlock()
+	return processBufferPool
+}
+
+// reserveBufferPoolDelta adjusts the process-wide buffer pool's usage by
+// delta bytes -- negative when a buffer shrinks or is replaced by a
+// smaller one -- on behalf of AudioFrame/VideoFrame's SetBuf/resizeBuf and
+// Data's SetBuf. It's a no-op returning nil while SetBufferPool was never
+// called, so a caller that never opts into this feature pays no cost for
+// it.
+func reserveBufferPoolDelta(delta int) error {
+	p := currentBufferPool()
+	if p == nil {
+		return nil
+	}
+	return p.reserve(delta)
+}
+
+// reserve blocks or errors, per p.mode, until delta bytes of headroom are
+// available, then applies it. A delta <= 0 always succeeds immediately
+// and wakes any allocation blocked in BufferPoolModeBlock waiting for the
+// freed room.
+func (p *bufferPool) reserve(delta int) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if delta <= 0 {
+		p.usedBytes += delta
+		if p.usedBytes < 0 {
+			p.usedBytes = 0
+		}
+		p.cond.Broadcast()
+		return nil
+	}
+
+	for p.maxBytes > 0 && p.usedBytes+delta > p.maxBytes {
+		if p.mode == BufferPoolModeBlock {
+			p.cond.Wait()
+			continue
+		}
+		p.rejections++
+		return ErrBufferPoolExhausted
+	}
+	p.usedBytes += delta
+	p.allocations++
+	return nil
+}
@@ -0,0 +1,98 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+
+// Package ten is a Go-only simulation of the ten_runtime extension
+// lifecycle, used by the integration tests under this app.
+//
+// Pause/Resume scope: the original pause/resume request's headline use
+// case was suspending a long-running extension "to stop consuming/
+// producing data frames while preserving in-memory state" (e.g. a model
+// handle or connection that keeps streaming media while paused). This
+// package has no Data type or data-frame delivery path at all, only Cmd,
+// so OnPause/OnResume and the pause state machine only ever gate cmds --
+// the data-frame half of the request is not implemented, not merely an
+// edge case of it. Treat cmd-only pause as a partial, not a complete,
+// implementation of that request until confirmed otherwise.
+package ten
+
+import "fmt"
+
+// Extension is the full lifecycle interface a ten_runtime Go extension
+// implements. Extensions normally embed DefaultExtension and only override
+// the callbacks they care about.
+type Extension interface {
+	OnInit(tenEnv TenEnv)
+	OnStart(tenEnv TenEnv)
+	OnStop(tenEnv TenEnv)
+	OnDeinit(tenEnv TenEnv)
+
+	// OnPause/OnResume let a graph suspend an extension without tearing it
+	// down: in-memory state is preserved, and the runtime stops delivering
+	// cmds while the extension is paused. See the package doc for the
+	// cmd-only scope of this pause/resume implementation.
+	OnPause(tenEnv TenEnv)
+	OnResume(tenEnv TenEnv)
+
+	OnCmd(tenEnv TenEnv, cmd Cmd)
+}
+
+// DefaultExtension provides default implementations for every Extension
+// callback: each lifecycle step is acknowledged immediately, and OnCmd
+// answers every cmd with an ErrUnknownCmd error result. Extensions embed
+// it and override only the callbacks whose default behavior doesn't fit.
+type DefaultExtension struct{}
+
+func (DefaultExtension) OnInit(tenEnv TenEnv)   { tenEnv.OnInitDone() }
+func (DefaultExtension) OnStart(tenEnv TenEnv)  { tenEnv.OnStartDone() }
+func (DefaultExtension) OnStop(tenEnv TenEnv)   { tenEnv.OnStopDone() }
+func (DefaultExtension) OnDeinit(tenEnv TenEnv) { tenEnv.OnDeinitDone() }
+func (DefaultExtension) OnPause(tenEnv TenEnv)  { tenEnv.OnPauseDone() }
+func (DefaultExtension) OnResume(tenEnv TenEnv) { tenEnv.OnResumeDone() }
+
+func (DefaultExtension) OnCmd(tenEnv TenEnv, cmd Cmd) {
+	err := NewError(ErrUnknownCmd, "OnCmd is not implemented")
+	cmdResult, _ := NewCmdResultFromError(err, cmd)
+	tenEnv.ReturnResult(cmdResult, nil)
+}
+
+// ExtensionAddon creates Extension instances by name, analogous to the
+// native ten_runtime addon mechanism.
+type ExtensionAddon interface {
+	NewInstance(name string) Extension
+}
+
+type defaultExtensionAddon struct {
+	newInstance func(name string) Extension
+}
+
+func (a *defaultExtensionAddon) NewInstance(name string) Extension {
+	return a.newInstance(name)
+}
+
+// NewDefaultExtensionAddon wraps a constructor function as an ExtensionAddon.
+func NewDefaultExtensionAddon(
+	newInstance func(name string) Extension,
+) ExtensionAddon {
+	return &defaultExtensionAddon{newInstance: newInstance}
+}
+
+var addonRegistry = map[string]ExtensionAddon{}
+
+// RegisterAddonAsExtension registers addon under name so graphs can
+// instantiate it by that name.
+func RegisterAddonAsExtension(name string, addon ExtensionAddon) error {
+	if _, exists := addonRegistry[name]; exists {
+		return fmt.Errorf("addon %q is already registered", name)
+	}
+	addonRegistry[name] = addon
+	return nil
+}
+
+// GetRegisteredExtensionAddon looks up a previously registered addon, for
+// use by the runtime (or tests) driving an extension instance.
+func GetRegisteredExtensionAddon(name string) (ExtensionAddon, bool) {
+	addon, ok := addonRegistry[name]
+	return addon, ok
+}
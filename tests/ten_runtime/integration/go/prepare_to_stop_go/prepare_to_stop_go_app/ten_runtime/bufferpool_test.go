@@ -0,0 +1,122 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSetBufferPool_TracksUsageAcrossAudioAndDataBuffers(t *testing.T) {
+	SetBufferPool(1024)
+	defer SetBufferPool(0)
+
+	af, _ := NewAudioFrame("pcm")
+	if err := af.SetBuf(make([]byte, 100)); err != nil {
+		t.Fatalf("SetBuf: %v", err)
+	}
+	d, _ := NewData()
+	if err := d.SetBuf(make([]byte, 50)); err != nil {
+		t.Fatalf("SetBuf: %v", err)
+	}
+
+	stats := BufferPoolStats()
+	if stats.UsedBytes != 150 {
+		t.Fatalf("UsedBytes = %d, want 150", stats.UsedBytes)
+	}
+	if stats.Allocations != 2 {
+		t.Fatalf("Allocations = %d, want 2", stats.Allocations)
+	}
+
+	// Replacing af's buffer with a smaller one gives back the difference.
+	if err := af.SetBuf(make([]byte, 20)); err != nil {
+		t.Fatalf("SetBuf (shrink): %v", err)
+	}
+	if stats := BufferPoolStats(); stats.UsedBytes != 70 {
+		t.Fatalf("UsedBytes after shrink = %d, want 70", stats.UsedBytes)
+	}
+}
+
+func TestSetBufferPool_ModeErrorRejectsOverCapAllocations(t *testing.T) {
+	SetBufferPool(100)
+	defer SetBufferPool(0)
+
+	af, _ := NewAudioFrame("pcm")
+	if err := af.SetBuf(make([]byte, 100)); err != nil {
+		t.Fatalf("SetBuf: %v", err)
+	}
+
+	other, _ := NewAudioFrame("pcm")
+	if err := other.SetBuf(make([]byte, 1)); err != ErrBufferPoolExhausted {
+		t.Fatalf("err = %v, want ErrBufferPoolExhausted", err)
+	}
+
+	if stats := BufferPoolStats(); stats.Rejections != 1 {
+		t.Fatalf("Rejections = %d, want 1", stats.Rejections)
+	}
+	if size, _ := other.GetBufSize(); size != 0 {
+		t.Fatalf("rejected frame's buffer size = %d, want 0 (unchanged)", size)
+	}
+}
+
+func TestSetBufferPool_ModeBlockWaitsForRoomToFreeUp(t *testing.T) {
+	SetBufferPool(100, WithBufferPoolMode(BufferPoolModeBlock))
+	defer SetBufferPool(0)
+
+	held, _ := NewAudioFrame("pcm")
+	if err := held.SetBuf(make([]byte, 100)); err != nil {
+		t.Fatalf("SetBuf: %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		waiting, _ := NewAudioFrame("pcm")
+		done <- waiting.SetBuf(make([]byte, 50))
+	}()
+
+	select {
+	case err := <-done:
+		t.Fatalf("blocked allocation returned early with err=%v before room freed up", err)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	if err := held.SetBuf(nil); err != nil {
+		t.Fatalf("SetBuf (release): %v", err)
+	}
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("blocked allocation error = %v, want nil", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("blocked allocation never unblocked after room freed up")
+	}
+}
+
+func TestSetBufferPool_TracksUsageAcrossSetProtoBuf(t *testing.T) {
+	SetBufferPool(10)
+	defer SetBufferPool(0)
+
+	d, _ := NewData()
+	if err := d.SetProtoBuf(&fakeProtoMessage{typeName: "myapp.v1.SensorReading", Value: "42C"}); err != nil {
+		t.Fatalf("SetProtoBuf: %v", err)
+	}
+	if stats := BufferPoolStats(); stats.UsedBytes != 3 {
+		t.Fatalf("UsedBytes = %d, want 3 (len of \"42C\")", stats.UsedBytes)
+	}
+
+	other, _ := NewData()
+	if err := other.SetProtoBuf(&fakeProtoMessage{typeName: "myapp.v1.SensorReading", Value: "over the cap"}); err != ErrBufferPoolExhausted {
+		t.Fatalf("err = %v, want ErrBufferPoolExhausted", err)
+	}
+}
+
+func TestBufferPoolStats_ZeroBeforeSetBufferPool(t *testing.T) {
+	SetBufferPool(0)
+	if stats := BufferPoolStats(); stats != (BufferPoolUsage{}) {
+		t.Fatalf("stats = %+v, want zero value", stats)
+	}
+}
@@ -0,0 +1,18 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+// LogLevel mirrors the log levels understood by the native ten_runtime log
+// subsystem.
+type LogLevel int
+
+const (
+	LogLevelVerbose LogLevel = iota
+	LogLevelDebug
+	LogLevelInfo
+	LogLevelWarn
+	LogLevelError
+	LogLevelFatal
+)
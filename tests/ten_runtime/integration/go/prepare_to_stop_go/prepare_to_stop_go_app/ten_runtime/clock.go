@@ -0,0 +1,66 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import "time"
+
+// Clock abstracts the passage of time for internal timeout/backoff logic,
+// so a test can substitute a deterministic implementation -- see
+// tentest.FakeClock -- instead of sleeping through a real duration.
+// SetCommandTimeout is the first caller; rate limiting and retry backoff
+// are natural next ones. NewEngine defaults to the real implementation;
+// see TenEnv.SetClock and WithClock to override it.
+type Clock interface {
+	// Now reports the current time, the way time.Now would.
+	Now() time.Time
+
+	// AfterFunc arranges for f to run, in its own goroutine, once d has
+	// elapsed, the way time.AfterFunc would, and returns a ClockTimer
+	// that can cancel it before it fires.
+	AfterFunc(d time.Duration, f func()) ClockTimer
+}
+
+// ClockTimer is the pending callback returned by Clock.AfterFunc.
+type ClockTimer interface {
+	// Stop cancels the timer, the way (*time.Timer).Stop would: it
+	// reports whether the cancellation beat the timer to firing.
+	Stop() bool
+}
+
+// realClock is the Clock every Engine uses unless overridden, backed
+// directly by the time package. *time.Timer already satisfies ClockTimer,
+// so AfterFunc needs no wrapping.
+type realClock struct{}
+
+func (realClock) Now() time.Time { return time.Now() }
+
+func (realClock) AfterFunc(d time.Duration, f func()) ClockTimer {
+	return time.AfterFunc(d, f)
+}
+
+// WithClock overrides the Clock an Engine uses for its internal
+// timeout/backoff logic; the default is the real time package. Intended
+// for tests that want a SetCommandTimeout path to fire on a
+// tentest.FakeClock's Advance instead of a real duration elapsing.
+func WithClock(clock Clock) EngineOption {
+	return func(e *Engine) {
+		e.clock = clock
+	}
+}
+
+// SetClock implements TenEnv.SetClock; see its doc comment.
+func (t *tenEnvImpl) SetClock(clock Clock) {
+	t.engine.mu.Lock()
+	defer t.engine.mu.Unlock()
+	t.engine.clock = clock
+}
+
+// getClock returns e's Clock, guarding the same mutex SetClock writes
+// through so a concurrent override is never observed half-applied.
+func (e *Engine) getClock() Clock {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.clock
+}
@@ -0,0 +1,73 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrShutdownTimeout is the error handed to any SendCmd/SendCmdEx callback
+// still in flight when an extension's graceful-shutdown deadline expires.
+var ErrShutdownTimeout = errors.New("ten: graceful shutdown deadline exceeded")
+
+// EngineOption configures an Engine at construction time.
+type EngineOption func(*Engine)
+
+// WithShutdownDeadline sets the per-extension graceful-shutdown deadline:
+// once OnStop is invoked, the runtime waits at most deadline for
+// OnStopDone before forcing it. A deadline of 0 (the default) means wait
+// indefinitely.
+func WithShutdownDeadline(deadline time.Duration) EngineOption {
+	return func(e *Engine) {
+		e.shutdownDeadline = deadline
+	}
+}
+
+// PropertyGracefulShutdownTimeoutMs is the property key a real
+// ten_runtime extension declares in its manifest's property schema to
+// configure its graceful-shutdown deadline, in milliseconds.
+const PropertyGracefulShutdownTimeoutMs = "graceful_shutdown_timeout_ms"
+
+// WithProperties configures an Engine from an extension's property schema,
+// the way the native ten_runtime loads an extension's property.json onto
+// it. Currently only PropertyGracefulShutdownTimeoutMs is recognized; it
+// sets the same shutdownDeadline WithShutdownDeadline does, so an
+// extension can declare the timeout in its manifest instead of (or in
+// addition to) a caller passing it in code. Unrecognized keys and values
+// of the wrong type are ignored.
+func WithProperties(props map[string]any) EngineOption {
+	return func(e *Engine) {
+		ms, ok := propertyInt(props, PropertyGracefulShutdownTimeoutMs)
+		if !ok {
+			return
+		}
+		e.shutdownDeadline = time.Duration(ms) * time.Millisecond
+	}
+}
+
+// propertyInt reads key from props as an int64, accepting the numeric
+// types a decoded property.json (JSON numbers decode as float64) or
+// hand-built test properties map might hold it as.
+func propertyInt(props map[string]any, key string) (int64, bool) {
+	switch v := props[key].(type) {
+	case int64:
+		return v, true
+	case int:
+		return int64(v), true
+	case float64:
+		return int64(v), true
+	default:
+		return 0, false
+	}
+}
+
+func newShutdownContext(deadline time.Duration) (context.Context, context.CancelFunc) {
+	if deadline <= 0 {
+		return context.WithCancel(context.Background())
+	}
+	return context.WithTimeout(context.Background(), deadline)
+}
@@ -0,0 +1,766 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sync/atomic"
+	"time"
+)
+
+// StatusCode is the outcome of a Cmd as reported by a CmdResult. The
+// well-known constants below cover the outcomes this protocol distinguishes
+// most often, but StatusCode is a plain int, not a closed enum: NewCmdResult
+// accepts any value, so a sender free to define its own codes for whatever
+// its cmd-specific protocol needs beyond these -- CmdResult.StatusCode()
+// hands the raw value back unchanged for a receiver to switch on.
+type StatusCode int
+
+const (
+	StatusCodeOk StatusCode = iota
+	StatusCodeError
+	StatusCodeNotFound
+	StatusCodeUnauthorized
+	StatusCodeUnavailable
+	StatusCodeInvalidArgument
+)
+
+// Cmd represents an in-flight command flowing through a graph.
+type Cmd interface {
+	Msg
+
+	// SetPropertyString/GetPropertyString and the typed accessors below
+	// store and read a command's properties, the same way a CmdResult
+	// does. GetPropertyString/Int64/Float64/Bool return
+	// ErrPropertyNotFound for a missing path and ErrPropertyTypeMismatch
+	// for a type mismatch; a stored integer widens for
+	// GetPropertyFloat64, but no other coercion is performed. A path may
+	// use dot notation to reach into nested objects and arrays, e.g.
+	// "vad.threshold" or "stream.0.codec"; indexing past the end of an
+	// existing array is ErrPropertyNotFound.
+	SetPropertyString(path string, value string) error
+	GetPropertyString(path string) (string, error)
+
+	SetPropertyInt64(path string, value int64) error
+	GetPropertyInt64(path string) (int64, error)
+
+	SetPropertyFloat64(path string, value float64) error
+	GetPropertyFloat64(path string) (float64, error)
+
+	// GetPropertyNumber is GetPropertyFloat64/GetPropertyInt64's
+	// type-agnostic counterpart, for a field that may arrive as either a
+	// JSON int or a JSON float. Its second return reports whether the
+	// stored value was integral; error cases match GetPropertyFloat64's.
+	GetPropertyNumber(path string) (float64, bool, error)
+
+	SetPropertyBool(path string, value bool) error
+	GetPropertyBool(path string) (bool, error)
+
+	// SetPropertyStringList/GetPropertyStringList store and read a JSON
+	// array of strings, e.g. allowed_models: ["a","b"], as a []string
+	// instead of the clunky GetPropertyToJSONBytes-then-reparse path a
+	// caller would otherwise need for a config array. GetPropertyStringList
+	// returns ErrPropertyTypeMismatch, naming the offending index, if any
+	// element isn't a string; no per-element coercion is performed.
+	SetPropertyStringList(path string, values []string) error
+	GetPropertyStringList(path string) ([]string, error)
+
+	// SetPropertyInt64List/GetPropertyInt64List are
+	// SetPropertyStringList/GetPropertyStringList for a []int64, with the
+	// same int-widens-to-int64 rule GetPropertyInt64 applies per element.
+	SetPropertyInt64List(path string, values []int64) error
+	GetPropertyInt64List(path string) ([]int64, error)
+
+	// SetPropertyFloat64List/GetPropertyFloat64List are
+	// SetPropertyStringList/GetPropertyStringList for a []float64, with the
+	// same int/int64-widens-to-float64 rule GetPropertyFloat64 applies per
+	// element.
+	SetPropertyFloat64List(path string, values []float64) error
+	GetPropertyFloat64List(path string) ([]float64, error)
+
+	// SetPropertyBytes stores an arbitrary blob at path without
+	// SetPropertyString's mangling. Cmd takes ownership of b; the caller
+	// must not mutate it afterwards. GetPropertyBytes returns a copy, and
+	// a missing path is ErrPropertyNotFound, distinguishing it from a
+	// present-but-empty blob.
+	SetPropertyBytes(path string, b []byte) error
+	GetPropertyBytes(path string) ([]byte, error)
+
+	// PropertyWriter returns an io.WriteCloser that stages writes to path
+	// in bounded chunks (DefaultPropertyChunkSize unless overridden via
+	// WithPropertyChunkSize), committing to path in one assignment on
+	// Close -- so a large payload can be io.Copy'd in without path ever
+	// observing a partial write. PropertyReader is the mirror image for
+	// reading. See propertystream.go.
+	PropertyWriter(path string, opts ...PropertyStreamOption) (io.WriteCloser, error)
+	PropertyReader(path string, opts ...PropertyStreamOption) (io.ReadCloser, error)
+
+	// GetPropertyToJSONBytes serializes the value at path to JSON, or the
+	// entire property bag if path is empty. WithLargeIntAsString renders an
+	// int64 beyond JavaScript's Number.MAX_SAFE_INTEGER as a quoted string.
+	// SetPropertyFromJSONBytes parses data and stores it at path (an empty
+	// path replaces the whole bag, requiring a top-level JSON object); it
+	// decodes numbers via json.Number so a large integer round-trips as an
+	// exact int64 rather than a rounded float64.
+	GetPropertyToJSONBytes(path string, opts ...JSONNumberOption) ([]byte, error)
+	SetPropertyFromJSONBytes(path string, data []byte) error
+
+	// SetProperties sets every path/value pair in m in one call. Supported
+	// value types are what the scalar setters support (string, int64,
+	// float64, bool, []byte), plus a nested map[string]any or []any built
+	// from them. If any value's type isn't supported, SetProperties sets
+	// nothing and returns ErrUnsupportedPropertyValueType naming the first
+	// such key in sorted order.
+	SetProperties(m map[string]any) error
+
+	// Unmarshal populates the struct pointed to by v from this Cmd's
+	// properties. Each exported field is matched against a top-level
+	// property by its `ten:"name"` tag, falling back to the field's own
+	// name when untagged; a tag of "-" skips the field, and
+	// `ten:"name,required"` makes a missing property an
+	// ErrMissingRequiredProperty naming the field. Nested structs and
+	// slices decode recursively. v must be a non-nil pointer to a struct.
+	Unmarshal(v any) error
+
+	// Marshal is Unmarshal's inverse: it replaces this Cmd's entire
+	// property bag with one built from v's exported fields, keyed the same
+	// way Unmarshal reads them. v must be a struct, or a pointer to one.
+	Marshal(v any) error
+
+	// PropertyKeys returns the top-level property keys, in sorted order,
+	// for a generic logging/forwarding extension that needs to enumerate
+	// what a Cmd carries without knowing its shape up front. Nested object
+	// keys are not flattened in; use PropertyKeysAt for a nested object.
+	PropertyKeys() ([]string, error)
+
+	// PropertyKeysAt is PropertyKeys for the object at path.
+	PropertyKeysAt(path string) ([]string, error)
+
+	// GetPropertyType reports the PropertyType of the value at path,
+	// without having to guess which typed getter to call first -- the
+	// reflection primitive PropertyKeys/PropertyKeysAt are missing for a
+	// generic transformation extension that needs to walk an arbitrary
+	// Cmd and copy or convert each property by its actual type. Its error
+	// cases are the same as the typed getters': ErrPropertyNotFound if
+	// path doesn't exist, ErrPropertyTypeMismatch if an intermediate
+	// segment can't be navigated into.
+	GetPropertyType(path string) (PropertyType, error)
+
+	// HasProperty reports whether path names an existing property.
+	HasProperty(path string) bool
+
+	// DeleteProperty removes path. See propertyDelete's doc comment for
+	// its exact error cases.
+	DeleteProperty(path string) error
+
+	// PropertiesSnapshot returns a deep, independently-owned copy of every
+	// property this Cmd carries. A []byte property comes back as a
+	// []byte, not a base64 string, so passing the result straight to
+	// SetProperties reproduces it exactly. It's safe to read from a
+	// different goroutine than the one still mutating the Cmd.
+	PropertiesSnapshot() (map[string]any, error)
+
+	// GetSource reports the Loc this Cmd arrived from. A Cmd delivered
+	// straight from outside the graph carries a zero Loc; a Cmd sent via
+	// SendCmd/SendCmdEx has its source stamped to the sending extension's
+	// own identity (see GetExtensionName/GetGraphID/GetAppURI).
+	GetSource() (Loc, error)
+
+	// SetTimestamp/GetTimestamp carry an opaque, caller-defined timestamp
+	// for this Cmd, the same as AudioFrame's and VideoFrame's. It
+	// defaults to 0 until set.
+	SetTimestamp(timestamp int64) error
+	GetTimestamp() (int64, error)
+
+	// GetDests reports the destinations this Cmd is explicitly routed to,
+	// as set by SetDests; an empty slice means none were set. This
+	// simulation has no graph JSON to fall back on, so SetDests has no
+	// effect on which sender actually receives a SendCmd/SendCmdEx call --
+	// GetDests/SetDests exist so a router extension's routing decisions
+	// can be exercised and asserted on without wiring a real graph.
+	GetDests() ([]Loc, error)
+
+	// SetDests overrides this Cmd's destinations, replacing whatever was
+	// there before. Calling it with no arguments clears them, reverting
+	// to runtime default routing (see GetDests).
+	SetDests(dests ...Loc) error
+
+	// SetDeadline/GetDeadline carry an end-to-end deadline for cmd in a
+	// reserved property (see isReservedPropertyKey), propagating across
+	// SendCmd/SendCmdEx and Clone. GetDeadline's second return reports
+	// whether a deadline was ever set. See TenEnv.CmdContext and
+	// WithDeadlineEnforcement.
+	SetDeadline(t time.Time) error
+	GetDeadline() (time.Time, bool)
+
+	// Clone returns a deep copy of this Cmd -- same name, properties,
+	// destinations, deadline and timestamp -- as a new, independently
+	// owned Cmd with its own fresh (unfinalized) result state and a
+	// zero-Loc source. Reusing the same Cmd for more than one outgoing
+	// send is not safe, since finalized's bookkeeping is per-Cmd.
+	Clone() (Cmd, error)
+
+	// finalized reports whether a final CmdResult has already been
+	// returned for this Cmd, and atomically marks it final when marking
+	// is true. It backs the "exactly one final result per cmd" guarantee
+	// used by ReturnResultEx.
+	finalized(marking bool) (alreadyFinal bool)
+
+	// markCompletionTokenTaken and tookCompletionToken back the
+	// UnhandledCmdPolicy check dispatchOnCmdSync runs once OnCmd returns:
+	// AcceptCommand and NewResultStream call markCompletionTokenTaken once
+	// they've handed out a JobHandle/ResultStream, so that check knows an
+	// async handler took over responsibility. See
+	// WithUnhandledCmdPolicy's doc comment.
+	markCompletionTokenTaken()
+	tookCompletionToken() bool
+
+	// DetachResponder hands out a ResponderToken standing in for this
+	// cmd's obligation to eventually get a result, so the extension
+	// currently holding it can pass the token along to whichever
+	// extension will actually produce the answer. See
+	// TenEnv.ReturnResultWithToken and responder.go. It fails if cmd was
+	// never dispatched through an Engine.
+	DetachResponder() (ResponderToken, error)
+}
+
+type cmdImpl struct {
+	name  string
+	final int32
+	props map[string]any
+
+	// source and dests back GetSource/GetDests/SetDests; see their doc
+	// comments on the Cmd interface. timestamp backs SetTimestamp/
+	// GetTimestamp.
+	source    Loc
+	dests     []Loc
+	timestamp int64
+
+	// dispatchEngine is the Engine that handed this cmd to an extension's
+	// OnCmd, stamped by dispatchOnCmdSync; DetachResponder needs it to
+	// know which Engine's ReturnResult to eventually call. It's nil for a
+	// Cmd built via NewCmd that was never delivered through an Engine
+	// (e.g. an outgoing cmd on its way to SendCmd), and deliberately not
+	// copied by Clone, for the same reason Clone resets source: a clone
+	// hasn't been dispatched anywhere yet either.
+	dispatchEngine *Engine
+
+	// completionToken backs markCompletionTokenTaken/tookCompletionToken;
+	// see their doc comment on the Cmd interface.
+	completionToken int32
+}
+
+// NewCmd creates a new outgoing Cmd with the given name.
+func NewCmd(name string) (Cmd, error) {
+	return &cmdImpl{name: name, props: map[string]any{}}, nil
+}
+
+func (c *cmdImpl) GetName() (string, error) {
+	return c.name, nil
+}
+
+func (c *cmdImpl) GetType() (MsgType, error) {
+	return MsgTypeCmd, nil
+}
+
+func (c *cmdImpl) SetPropertyString(path string, value string) error {
+	return navigateSet(c.props, path, value)
+}
+
+func (c *cmdImpl) GetPropertyString(path string) (string, error) {
+	return propertyGetString(c.props, path)
+}
+
+func (c *cmdImpl) SetPropertyInt64(path string, value int64) error {
+	return navigateSet(c.props, path, value)
+}
+
+func (c *cmdImpl) GetPropertyInt64(path string) (int64, error) {
+	return propertyGetInt64(c.props, path)
+}
+
+func (c *cmdImpl) SetPropertyFloat64(path string, value float64) error {
+	return navigateSet(c.props, path, value)
+}
+
+func (c *cmdImpl) GetPropertyFloat64(path string) (float64, error) {
+	return propertyGetFloat64(c.props, path)
+}
+
+func (c *cmdImpl) GetPropertyNumber(path string) (float64, bool, error) {
+	return propertyGetNumber(c.props, path)
+}
+
+func (c *cmdImpl) SetPropertyBool(path string, value bool) error {
+	return navigateSet(c.props, path, value)
+}
+
+func (c *cmdImpl) GetPropertyBool(path string) (bool, error) {
+	return propertyGetBool(c.props, path)
+}
+
+func (c *cmdImpl) SetPropertyStringList(path string, values []string) error {
+	return propertySetStringList(c.props, path, values)
+}
+
+func (c *cmdImpl) GetPropertyStringList(path string) ([]string, error) {
+	return propertyGetStringList(c.props, path)
+}
+
+func (c *cmdImpl) SetPropertyInt64List(path string, values []int64) error {
+	return propertySetInt64List(c.props, path, values)
+}
+
+func (c *cmdImpl) GetPropertyInt64List(path string) ([]int64, error) {
+	return propertyGetInt64List(c.props, path)
+}
+
+func (c *cmdImpl) SetPropertyFloat64List(path string, values []float64) error {
+	return propertySetFloat64List(c.props, path, values)
+}
+
+func (c *cmdImpl) GetPropertyFloat64List(path string) ([]float64, error) {
+	return propertyGetFloat64List(c.props, path)
+}
+
+func (c *cmdImpl) SetPropertyBytes(path string, b []byte) error {
+	return navigateSet(c.props, path, b)
+}
+
+func (c *cmdImpl) GetPropertyBytes(path string) ([]byte, error) {
+	return propertyGetBytes(c.props, path)
+}
+
+func (c *cmdImpl) PropertyWriter(path string, opts ...PropertyStreamOption) (io.WriteCloser, error) {
+	return newPropertyWriter(c.SetPropertyBytes, path, opts), nil
+}
+
+func (c *cmdImpl) PropertyReader(path string, opts ...PropertyStreamOption) (io.ReadCloser, error) {
+	return newPropertyReader(c.GetPropertyBytes, path, opts)
+}
+
+func (c *cmdImpl) GetPropertyToJSONBytes(path string, opts ...JSONNumberOption) ([]byte, error) {
+	return propertyGetJSON(c.props, path, opts)
+}
+
+func (c *cmdImpl) SetPropertyFromJSONBytes(path string, data []byte) error {
+	return propertySetJSON(c.props, path, data)
+}
+
+func (c *cmdImpl) SetProperties(m map[string]any) error {
+	return setPropertiesBatch(c.props, m)
+}
+
+func (c *cmdImpl) Unmarshal(v any) error {
+	return unmarshalStruct(c.props, v)
+}
+
+func (c *cmdImpl) Marshal(v any) error {
+	return marshalStruct(c.props, v)
+}
+
+func (c *cmdImpl) PropertyKeys() ([]string, error) {
+	return propertyKeys(c.props), nil
+}
+
+func (c *cmdImpl) PropertyKeysAt(path string) ([]string, error) {
+	return propertyKeysAt(c.props, path)
+}
+
+func (c *cmdImpl) GetPropertyType(path string) (PropertyType, error) {
+	return propertyType(c.props, path)
+}
+
+func (c *cmdImpl) HasProperty(path string) bool {
+	return propertyHas(c.props, path)
+}
+
+func (c *cmdImpl) DeleteProperty(path string) error {
+	return propertyDelete(c.props, path)
+}
+
+func (c *cmdImpl) PropertiesSnapshot() (map[string]any, error) {
+	return propertiesSnapshot(c.props), nil
+}
+
+func (c *cmdImpl) GetSource() (Loc, error) {
+	return c.source, nil
+}
+
+func (c *cmdImpl) SetTimestamp(timestamp int64) error {
+	c.timestamp = timestamp
+	return nil
+}
+
+func (c *cmdImpl) GetTimestamp() (int64, error) {
+	return c.timestamp, nil
+}
+
+func (c *cmdImpl) GetDests() ([]Loc, error) {
+	return append([]Loc(nil), c.dests...), nil
+}
+
+func (c *cmdImpl) SetDests(dests ...Loc) error {
+	c.dests = append([]Loc(nil), dests...)
+	return nil
+}
+
+func (c *cmdImpl) SetDeadline(t time.Time) error {
+	return c.SetPropertyInt64(cmdPropDeadline, t.UnixNano())
+}
+
+func (c *cmdImpl) GetDeadline() (time.Time, bool) {
+	nanos, err := c.GetPropertyInt64(cmdPropDeadline)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(0, nanos), true
+}
+
+func (c *cmdImpl) Clone() (Cmd, error) {
+	return &cmdImpl{
+		name:      c.name,
+		props:     deepCopyProps(c.props),
+		dests:     append([]Loc(nil), c.dests...),
+		timestamp: c.timestamp,
+	}, nil
+}
+
+func (c *cmdImpl) finalized(marking bool) bool {
+	if marking {
+		return !atomic.CompareAndSwapInt32(&c.final, 0, 1)
+	}
+	return atomic.LoadInt32(&c.final) != 0
+}
+
+func (c *cmdImpl) markCompletionTokenTaken() {
+	atomic.StoreInt32(&c.completionToken, 1)
+}
+
+func (c *cmdImpl) tookCompletionToken() bool {
+	return atomic.LoadInt32(&c.completionToken) != 0
+}
+
+func (c *cmdImpl) DetachResponder() (ResponderToken, error) {
+	if c.dispatchEngine == nil {
+		return ResponderToken{}, ErrCmdNotDispatched
+	}
+	c.markCompletionTokenTaken()
+	return registerResponder(c.dispatchEngine, c), nil
+}
+
+// CmdResult carries the outcome of a Cmd back to its originator, optionally
+// as one of a series of streamed partial results (see SendCmdEx). Its
+// property accessors follow the same not-found/type-mismatch rules as
+// Cmd's.
+type CmdResult interface {
+	StatusCode() (StatusCode, error)
+
+	SetPropertyString(path string, value string) error
+	GetPropertyString(path string) (string, error)
+
+	SetPropertyInt64(path string, value int64) error
+	GetPropertyInt64(path string) (int64, error)
+
+	SetPropertyFloat64(path string, value float64) error
+	GetPropertyFloat64(path string) (float64, error)
+
+	// GetPropertyNumber follows the same semantics as its Cmd
+	// counterpart.
+	GetPropertyNumber(path string) (float64, bool, error)
+
+	SetPropertyBool(path string, value bool) error
+	GetPropertyBool(path string) (bool, error)
+
+	// SetPropertyStringList/GetPropertyStringList,
+	// SetPropertyInt64List/GetPropertyInt64List and
+	// SetPropertyFloat64List/GetPropertyFloat64List follow the same
+	// semantics as their Cmd counterparts.
+	SetPropertyStringList(path string, values []string) error
+	GetPropertyStringList(path string) ([]string, error)
+	SetPropertyInt64List(path string, values []int64) error
+	GetPropertyInt64List(path string) ([]int64, error)
+	SetPropertyFloat64List(path string, values []float64) error
+	GetPropertyFloat64List(path string) ([]float64, error)
+
+	// SetPropertyBytes/GetPropertyBytes follow the same semantics as
+	// their Cmd counterparts.
+	SetPropertyBytes(path string, b []byte) error
+	GetPropertyBytes(path string) ([]byte, error)
+
+	// PropertyWriter/PropertyReader follow the same semantics as their
+	// Cmd counterparts.
+	PropertyWriter(path string, opts ...PropertyStreamOption) (io.WriteCloser, error)
+	PropertyReader(path string, opts ...PropertyStreamOption) (io.ReadCloser, error)
+
+	// GetPropertyToJSONBytes/SetPropertyFromJSONBytes are the whole-object
+	// marshaling counterparts of the scalar accessors above; see Cmd's
+	// doc comment for their exact semantics.
+	GetPropertyToJSONBytes(path string, opts ...JSONNumberOption) ([]byte, error)
+	SetPropertyFromJSONBytes(path string, data []byte) error
+
+	// SetProperties is Cmd.SetProperties's batch setter, for a result
+	// carrying many fields back to its originator in one call.
+	SetProperties(m map[string]any) error
+
+	// Unmarshal is Cmd.Unmarshal for a result: it decodes this CmdResult's
+	// properties into the struct pointed to by v, using the same `ten`
+	// struct tags and type-coercion rules, so a SendCmd/SendCmdEx callback
+	// can read a whole result in one call instead of one typed getter per
+	// field.
+	Unmarshal(v any) error
+
+	// PropertyKeys, PropertyKeysAt, GetPropertyType, HasProperty and
+	// DeleteProperty follow the same semantics as their Cmd counterparts.
+	PropertyKeys() ([]string, error)
+	PropertyKeysAt(path string) ([]string, error)
+	GetPropertyType(path string) (PropertyType, error)
+	HasProperty(path string) bool
+	DeleteProperty(path string) error
+
+	// PropertiesSnapshot follows the same semantics as Cmd.
+	// PropertiesSnapshot: a deep, independently-owned copy of every
+	// property this result carries, safe to read from a goroutine other
+	// than the one still populating the result.
+	PropertiesSnapshot() (map[string]any, error)
+
+	// IsFinal reports whether this is the terminal result for its Cmd.
+	IsFinal() (bool, error)
+
+	// SetIsFinal marks whether this result is the terminal one for its
+	// Cmd; NewCmdResult defaults it to true. A sender streaming a series
+	// of results for a single Cmd through the plain SendCmd/
+	// CmdResultHandler path (rather than SendCmdEx) calls SetIsFinal(false)
+	// on every intermediate result, so SendCmd's callback keeps being
+	// invoked instead of being torn down after the first one. Callers
+	// that never call it keep the single-result behavior unchanged.
+	SetIsFinal(final bool) error
+
+	// AsError reconstructs the typed error carried by this CmdResult, if
+	// any. It returns nil for StatusCodeOk and a non-nil error for every
+	// other status code, StatusCodeError included -- a receiver that only
+	// cares whether the cmd succeeded can check AsError() == nil without
+	// also special-casing StatusCodeNotFound/Unauthorized/Unavailable/
+	// InvalidArgument or a sender's own custom codes; one that needs to
+	// branch on the specific outcome calls StatusCode() instead.
+	AsError() error
+
+	// IsOK reports whether StatusCode() is StatusCodeOk. It's sugar for a
+	// caller that just wants a bool instead of switching on StatusCode()
+	// or checking AsError() == nil.
+	IsOK() (bool, error)
+
+	// IsError is IsOK negated, for the (more common) call site that
+	// branches on failure -- `if cs.IsError() { ... }` reads better than
+	// `if !cs.IsOK() { ... }`.
+	IsError() (bool, error)
+
+	// Detail is GetPropertyString("detail"), the conventional path
+	// NewCmdResultFromError (and, by convention, any sender reporting a
+	// human-readable failure reason) stores a result's outcome under --
+	// including ErrPropertyNotFound if no such property was ever set.
+	// Detail exists only to spare a caller the property-path string
+	// literal, not to change GetPropertyString's semantics.
+	Detail() (string, error)
+}
+
+type cmdResultImpl struct {
+	statusCode StatusCode
+	props      map[string]any
+	isFinal    bool
+}
+
+// NewCmdResult creates a final CmdResult with the given status for cmd.
+// statusCode isn't restricted to the well-known constants -- any int-backed
+// StatusCode value is accepted, so a protocol built on this package can
+// define its own codes for cmd-specific outcomes a receiver switches on via
+// StatusCode(), the same way an HTTP handler isn't limited to a fixed
+// enum of status codes either.
+func NewCmdResult(statusCode StatusCode, cmd Cmd) (CmdResult, error) {
+	return &cmdResultImpl{
+		statusCode: statusCode,
+		props:      map[string]any{},
+		isFinal:    true,
+	}, nil
+}
+
+// reservedErrorProperty is the CmdResult property key under which
+// NewCmdResultFromError serializes a ten.Error's Code/Message/Details/
+// Cause chain, and from which AsError reconstructs it.
+const reservedErrorProperty = "_ten_error"
+
+// NewCmdResultFromError builds a final, StatusCodeError CmdResult for cmd
+// out of err. If err is a ten.Error, its Code/Message/Details/Cause chain
+// is preserved across the property boundary so the receiving side's
+// AsError can reconstruct it for use with errors.Is/errors.As; otherwise
+// only its message survives.
+func NewCmdResultFromError(err error, cmd Cmd) (CmdResult, error) {
+	result, _ := NewCmdResult(StatusCodeError, cmd)
+	impl := result.(*cmdResultImpl)
+	impl.props[reservedErrorProperty] = encodeError(err)
+	impl.SetPropertyString("detail", err.Error())
+	return result, nil
+}
+
+func (r *cmdResultImpl) StatusCode() (StatusCode, error) {
+	return r.statusCode, nil
+}
+
+func (r *cmdResultImpl) SetPropertyString(path string, value string) error {
+	return navigateSet(r.props, path, value)
+}
+
+func (r *cmdResultImpl) GetPropertyString(path string) (string, error) {
+	return propertyGetString(r.props, path)
+}
+
+func (r *cmdResultImpl) SetPropertyInt64(path string, value int64) error {
+	return navigateSet(r.props, path, value)
+}
+
+func (r *cmdResultImpl) GetPropertyInt64(path string) (int64, error) {
+	return propertyGetInt64(r.props, path)
+}
+
+func (r *cmdResultImpl) SetPropertyFloat64(path string, value float64) error {
+	return navigateSet(r.props, path, value)
+}
+
+func (r *cmdResultImpl) GetPropertyFloat64(path string) (float64, error) {
+	return propertyGetFloat64(r.props, path)
+}
+
+func (r *cmdResultImpl) GetPropertyNumber(path string) (float64, bool, error) {
+	return propertyGetNumber(r.props, path)
+}
+
+func (r *cmdResultImpl) SetPropertyBool(path string, value bool) error {
+	return navigateSet(r.props, path, value)
+}
+
+func (r *cmdResultImpl) GetPropertyBool(path string) (bool, error) {
+	return propertyGetBool(r.props, path)
+}
+
+func (r *cmdResultImpl) SetPropertyStringList(path string, values []string) error {
+	return propertySetStringList(r.props, path, values)
+}
+
+func (r *cmdResultImpl) GetPropertyStringList(path string) ([]string, error) {
+	return propertyGetStringList(r.props, path)
+}
+
+func (r *cmdResultImpl) SetPropertyInt64List(path string, values []int64) error {
+	return propertySetInt64List(r.props, path, values)
+}
+
+func (r *cmdResultImpl) GetPropertyInt64List(path string) ([]int64, error) {
+	return propertyGetInt64List(r.props, path)
+}
+
+func (r *cmdResultImpl) SetPropertyFloat64List(path string, values []float64) error {
+	return propertySetFloat64List(r.props, path, values)
+}
+
+func (r *cmdResultImpl) GetPropertyFloat64List(path string) ([]float64, error) {
+	return propertyGetFloat64List(r.props, path)
+}
+
+func (r *cmdResultImpl) SetPropertyBytes(path string, b []byte) error {
+	return navigateSet(r.props, path, b)
+}
+
+func (r *cmdResultImpl) GetPropertyBytes(path string) ([]byte, error) {
+	return propertyGetBytes(r.props, path)
+}
+
+func (r *cmdResultImpl) PropertyWriter(path string, opts ...PropertyStreamOption) (io.WriteCloser, error) {
+	return newPropertyWriter(r.SetPropertyBytes, path, opts), nil
+}
+
+func (r *cmdResultImpl) PropertyReader(path string, opts ...PropertyStreamOption) (io.ReadCloser, error) {
+	return newPropertyReader(r.GetPropertyBytes, path, opts)
+}
+
+func (r *cmdResultImpl) GetPropertyToJSONBytes(path string, opts ...JSONNumberOption) ([]byte, error) {
+	return propertyGetJSON(r.props, path, opts)
+}
+
+func (r *cmdResultImpl) SetPropertyFromJSONBytes(path string, data []byte) error {
+	return propertySetJSON(r.props, path, data)
+}
+
+func (r *cmdResultImpl) SetProperties(m map[string]any) error {
+	return setPropertiesBatch(r.props, m)
+}
+
+func (r *cmdResultImpl) Unmarshal(v any) error {
+	return unmarshalStruct(r.props, v)
+}
+
+func (r *cmdResultImpl) PropertyKeys() ([]string, error) {
+	return propertyKeys(r.props), nil
+}
+
+func (r *cmdResultImpl) PropertyKeysAt(path string) ([]string, error) {
+	return propertyKeysAt(r.props, path)
+}
+
+func (r *cmdResultImpl) GetPropertyType(path string) (PropertyType, error) {
+	return propertyType(r.props, path)
+}
+
+func (r *cmdResultImpl) HasProperty(path string) bool {
+	return propertyHas(r.props, path)
+}
+
+func (r *cmdResultImpl) DeleteProperty(path string) error {
+	return propertyDelete(r.props, path)
+}
+
+func (r *cmdResultImpl) PropertiesSnapshot() (map[string]any, error) {
+	return propertiesSnapshot(r.props), nil
+}
+
+func (r *cmdResultImpl) IsFinal() (bool, error) {
+	return r.isFinal, nil
+}
+
+func (r *cmdResultImpl) SetIsFinal(final bool) error {
+	r.isFinal = final
+	return nil
+}
+
+func (r *cmdResultImpl) AsError() error {
+	if r.statusCode == StatusCodeOk {
+		return nil
+	}
+	if m, ok := r.props[reservedErrorProperty].(map[string]any); ok {
+		return decodeError(m)
+	}
+	if detail := stringProp(r.props, "detail"); detail != "" {
+		return errors.New(detail)
+	}
+	return fmt.Errorf("ten: cmd result status code %d", r.statusCode)
+}
+
+func (r *cmdResultImpl) IsOK() (bool, error) {
+	return r.statusCode == StatusCodeOk, nil
+}
+
+func (r *cmdResultImpl) IsError() (bool, error) {
+	return r.statusCode != StatusCodeOk, nil
+}
+
+func (r *cmdResultImpl) Detail() (string, error) {
+	return r.GetPropertyString("detail")
+}
+
+func stringProp(props map[string]any, key string) string {
+	s, _ := props[key].(string)
+	return s
+}
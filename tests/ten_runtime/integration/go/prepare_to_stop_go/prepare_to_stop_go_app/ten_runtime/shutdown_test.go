@@ -0,0 +1,155 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// hangingStopExtension never signals OnStopDone on its own, simulating an
+// OnStop that's waiting on a peer that has crashed.
+type hangingStopExtension struct {
+	DefaultExtension
+}
+
+func (hangingStopExtension) OnStop(tenEnv TenEnv) {}
+
+func TestEngineStop_DeadlineForcesOnStopDone(t *testing.T) {
+	engine := NewEngine(
+		hangingStopExtension{},
+		WithShutdownDeadline(20*time.Millisecond),
+	)
+
+	if err := engine.Init(); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	if err := engine.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- engine.Stop() }()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Stop: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Stop did not return after the shutdown deadline expired")
+	}
+}
+
+func TestEngineStop_CancelsInFlightSendCmdOnTimeout(t *testing.T) {
+	block := make(chan struct{})
+	senderStarted := make(chan struct{})
+	engine := NewEngine(
+		hangingStopExtension{},
+		WithShutdownDeadline(20*time.Millisecond),
+	)
+	engine.SetSender(func(cmd Cmd, handler CmdResultHandler) {
+		// A downstream responder that never answers -- the deadline must
+		// still unblock the caller.
+		close(senderStarted)
+		<-block
+	})
+
+	if err := engine.Init(); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	if err := engine.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	cmd, _ := NewCmd("long-running")
+	gotErr := make(chan error, 1)
+	go func() {
+		// Real extensions call SendCmd from their own goroutine (see
+		// bExtension.OnCmd); the downstream responder here blocks until
+		// the test closes block, simulating a peer that never replies.
+		engine.tenEnv.SendCmd(
+			cmd,
+			func(tenEnv TenEnv, result CmdResult, err error) {
+				gotErr <- err
+			},
+		)
+	}()
+	<-senderStarted
+
+	if err := engine.Stop(); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+	close(block)
+
+	select {
+	case err := <-gotErr:
+		if !errors.Is(err, ErrShutdownTimeout) {
+			t.Fatalf("SendCmd callback err = %v, want ErrShutdownTimeout", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("SendCmd callback was never invoked")
+	}
+}
+
+func TestEngineStop_WithPropertiesSetsDeadline(t *testing.T) {
+	engine := NewEngine(
+		hangingStopExtension{},
+		WithProperties(map[string]any{
+			PropertyGracefulShutdownTimeoutMs: 20,
+		}),
+	)
+
+	if err := engine.Init(); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	if err := engine.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- engine.Stop() }()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Stop: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Stop did not return after the property-configured deadline expired")
+	}
+}
+
+func TestEngineStop_NoDeadlineWaitsIndefinitely(t *testing.T) {
+	ext := newLifecycleExtension()
+	engine := NewEngine(ext)
+
+	if err := engine.Init(); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	if err := engine.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- engine.Stop() }()
+
+	select {
+	case <-done:
+		t.Fatal("Stop returned before OnStopDone, with no deadline set")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(ext.stopChan)
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Stop: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Stop did not return after OnStopDone")
+	}
+}
@@ -0,0 +1,172 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestSendCmd_StreamsPartialResultsViaSetIsFinal(t *testing.T) {
+	ext := newLifecycleExtension()
+	engine := NewEngine(ext)
+	engine.SetSender(func(cmd Cmd, handler CmdResultHandler) {
+		for i := 0; i < 2; i++ {
+			partial, _ := NewCmdResult(StatusCodeOk, cmd)
+			partial.SetIsFinal(false)
+			partial.SetPropertyInt64("progress", int64(i))
+			handler(engine.tenEnv, partial, nil)
+		}
+		final, _ := NewCmdResult(StatusCodeOk, cmd)
+		final.SetPropertyString("output", "done")
+		handler(engine.tenEnv, final, nil)
+	})
+
+	if err := engine.Init(); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	if err := engine.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	cmd, _ := NewCmd("generate")
+	var finals []bool
+	err := engine.tenEnv.SendCmd(cmd, func(_ TenEnv, result CmdResult, err error) {
+		isFinal, _ := result.IsFinal()
+		finals = append(finals, isFinal)
+	})
+	if err != nil {
+		t.Fatalf("SendCmd: %v", err)
+	}
+
+	if len(finals) != 3 || finals[0] || finals[1] || !finals[2] {
+		t.Fatalf("isFinal sequence = %v, want [false false true]", finals)
+	}
+}
+
+func TestCmdResult_IsFinalDefaultsToTrueWithoutSetIsFinal(t *testing.T) {
+	cmd, _ := NewCmd("generate")
+	result, _ := NewCmdResult(StatusCodeOk, cmd)
+	isFinal, err := result.IsFinal()
+	if err != nil || !isFinal {
+		t.Fatalf("IsFinal() = %v, %v, want true, nil", isFinal, err)
+	}
+}
+
+func TestSendCmdEx_StreamsPartialResultsThenFinal(t *testing.T) {
+	ext := newLifecycleExtension()
+	engine := NewEngine(ext)
+	engine.SetExSender(func(cmd Cmd, handler CmdResultExHandler) {
+		for i := 0; i < 2; i++ {
+			partial, _ := NewCmdResult(StatusCodeOk, cmd)
+			partial.SetPropertyInt64("progress", int64(i))
+			handler(engine.tenEnv, partial, false, nil)
+		}
+		final, _ := NewCmdResult(StatusCodeOk, cmd)
+		final.SetPropertyString("output", "done")
+		handler(engine.tenEnv, final, true, nil)
+	})
+
+	if err := engine.Init(); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	if err := engine.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	cmd, _ := NewCmd("generate")
+	var results []CmdResult
+	var finals []bool
+	err := engine.tenEnv.SendCmdEx(
+		cmd,
+		func(tenEnv TenEnv, result CmdResult, isFinal bool, err error) {
+			results = append(results, result)
+			finals = append(finals, isFinal)
+		},
+	)
+	if err != nil {
+		t.Fatalf("SendCmdEx: %v", err)
+	}
+
+	if len(results) != 3 {
+		t.Fatalf("got %d results, want 3", len(results))
+	}
+	if finals[0] || finals[1] || !finals[2] {
+		t.Fatalf("isFinal sequence = %v, want [false false true]", finals)
+	}
+
+	output, _ := results[2].GetPropertyString("output")
+	if output != "done" {
+		t.Fatalf("final result output = %q, want %q", output, "done")
+	}
+}
+
+func TestReturnResultEx_ExactlyOneFinalPerCmd(t *testing.T) {
+	cmd, _ := NewCmd("work")
+	engine := NewEngine(newLifecycleExtension())
+	tenEnv := engine.tenEnv
+
+	partial, _ := NewCmdResult(StatusCodeOk, cmd)
+	if err := tenEnv.ReturnResultEx(partial, cmd, false); err != nil {
+		t.Fatalf("first non-final ReturnResultEx: %v", err)
+	}
+
+	final, _ := NewCmdResult(StatusCodeOk, cmd)
+	if err := tenEnv.ReturnResultEx(final, cmd, true); err != nil {
+		t.Fatalf("final ReturnResultEx: %v", err)
+	}
+
+	tooLate, _ := NewCmdResult(StatusCodeOk, cmd)
+	if err := tenEnv.ReturnResultEx(tooLate, cmd, false); !errors.Is(
+		err, ErrCmdAlreadyFinal,
+	) {
+		t.Fatalf(
+			"ReturnResultEx after final: got %v, want ErrCmdAlreadyFinal",
+			err,
+		)
+	}
+}
+
+// TestReturnResultEx_ConcurrentFinalsRaceToExactlyOne forces two goroutines
+// to call ReturnResultEx(..., true) for the same cmd concurrently: exactly
+// one must succeed and the other must see ErrCmdAlreadyFinal, never both
+// succeeding because the check and the mark were separate atomic ops.
+func TestReturnResultEx_ConcurrentFinalsRaceToExactlyOne(t *testing.T) {
+	cmd, _ := NewCmd("work")
+	engine := NewEngine(newLifecycleExtension())
+	tenEnv := engine.tenEnv
+
+	const attempts = 200
+	for i := 0; i < attempts; i++ {
+		cmd, _ = NewCmd("work")
+		var wg sync.WaitGroup
+		errs := make([]error, 2)
+		for g := 0; g < 2; g++ {
+			wg.Add(1)
+			go func(g int) {
+				defer wg.Done()
+				result, _ := NewCmdResult(StatusCodeOk, cmd)
+				errs[g] = tenEnv.ReturnResultEx(result, cmd, true)
+			}(g)
+		}
+		wg.Wait()
+
+		nilCount := 0
+		for _, err := range errs {
+			if err == nil {
+				nilCount++
+			} else if !errors.Is(err, ErrCmdAlreadyFinal) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+		if nilCount != 1 {
+			t.Fatalf(
+				"attempt %d: %d of 2 concurrent final ReturnResultEx calls "+
+					"succeeded, want exactly 1", i, nilCount,
+			)
+		}
+	}
+}
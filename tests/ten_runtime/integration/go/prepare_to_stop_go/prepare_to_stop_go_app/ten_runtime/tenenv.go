@@ -0,0 +1,149 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"context"
+	"fmt"
+)
+
+// CmdResultHandler is invoked once for every CmdResult a sent Cmd produces.
+type CmdResultHandler func(tenEnv TenEnv, result CmdResult, err error)
+
+// CmdResultExHandler is invoked once per CmdResult a SendCmdEx'd Cmd
+// produces, isFinal marking the terminal one. Exactly one call with
+// isFinal == true is guaranteed for a given Cmd.
+type CmdResultExHandler func(
+	tenEnv TenEnv, result CmdResult, isFinal bool, err error,
+)
+
+// TenEnv is the handle an Extension uses to talk back to the runtime: to
+// log, to send commands on to the rest of the graph, to return results for
+// commands it was itself handed, and to acknowledge lifecycle callbacks.
+type TenEnv interface {
+	Log(level LogLevel, msg string)
+
+	SendCmd(cmd Cmd, handler CmdResultHandler) error
+	ReturnResult(result CmdResult, cmd Cmd) error
+
+	// SendCmdEx is like SendCmd but the callback may be invoked more than
+	// once: zero or more non-final progress results followed by exactly
+	// one final result.
+	SendCmdEx(cmd Cmd, handler CmdResultExHandler) error
+
+	// ReturnResultEx is like ReturnResult but tags result with an is_final
+	// bit instead of always closing the cmd. It returns ErrCmdAlreadyFinal
+	// if a final result was already returned for cmd.
+	ReturnResultEx(result CmdResult, cmd Cmd, isFinal bool) error
+
+	OnInitDone()
+	OnStartDone()
+	OnStopDone()
+	OnDeinitDone()
+
+	// OnPauseDone/OnResumeDone acknowledge OnPause/OnResume, letting the
+	// runtime complete the pause/resume transition it is waiting on.
+	OnPauseDone()
+	OnResumeDone()
+
+	// ShutdownContext is Done() once OnStop's graceful-shutdown deadline
+	// (see WithShutdownDeadline) expires, so idiomatic Go code can write
+	// select { case <-myChan: case <-tenEnv.ShutdownContext().Done(): }.
+	// Before OnStop has been called it never fires.
+	ShutdownContext() context.Context
+}
+
+// tenEnvImpl is the Engine-backed TenEnv handed to an Extension instance.
+type tenEnvImpl struct {
+	engine *Engine
+}
+
+func newTenEnv(engine *Engine) *tenEnvImpl {
+	return &tenEnvImpl{engine: engine}
+}
+
+func (t *tenEnvImpl) Log(level LogLevel, msg string) {
+	fmt.Printf("[%d] %s\n", level, msg)
+}
+
+func (t *tenEnvImpl) SendCmd(cmd Cmd, handler CmdResultHandler) error {
+	resolve, _ := t.engine.registerPending(func() {
+		handler(t, nil, ErrShutdownTimeout)
+	})
+	t.engine.sender(cmd, func(tenEnv TenEnv, result CmdResult, err error) {
+		if !resolve() {
+			// Already force-cancelled by a graceful-shutdown timeout.
+			return
+		}
+		handler(tenEnv, result, err)
+	})
+	return nil
+}
+
+func (t *tenEnvImpl) ReturnResult(result CmdResult, cmd Cmd) error {
+	return nil
+}
+
+func (t *tenEnvImpl) SendCmdEx(cmd Cmd, handler CmdResultExHandler) error {
+	resolve, peek := t.engine.registerPending(func() {
+		handler(t, nil, true, ErrShutdownTimeout)
+	})
+	t.engine.exSender(
+		cmd,
+		func(tenEnv TenEnv, result CmdResult, isFinal bool, err error) {
+			if isFinal {
+				if !resolve() {
+					return
+				}
+			} else if !peek() {
+				return
+			}
+			handler(tenEnv, result, isFinal, err)
+		},
+	)
+	return nil
+}
+
+func (t *tenEnvImpl) ReturnResultEx(
+	result CmdResult, cmd Cmd, isFinal bool,
+) error {
+	// cmd.finalized(isFinal) is the single atomic check-and-mark: calling
+	// it once, rather than peeking with finalized(false) and separately
+	// marking with finalized(true), closes the window where two
+	// concurrent final ReturnResultEx calls could both observe
+	// "not yet final" before either one marks it.
+	if cmd.finalized(isFinal) {
+		return ErrCmdAlreadyFinal
+	}
+	if impl, ok := result.(*cmdResultImpl); ok {
+		impl.isFinal = isFinal
+	}
+	return nil
+}
+
+func (t *tenEnvImpl) OnInitDone()   { close(t.engine.initDone) }
+func (t *tenEnvImpl) OnStartDone()  { close(t.engine.startDone) }
+func (t *tenEnvImpl) OnStopDone()   { t.engine.signalStopDone() }
+func (t *tenEnvImpl) OnDeinitDone() {}
+
+func (t *tenEnvImpl) ShutdownContext() context.Context {
+	t.engine.mu.Lock()
+	defer t.engine.mu.Unlock()
+	return t.engine.shutdownCtx
+}
+
+func (t *tenEnvImpl) OnPauseDone() {
+	t.engine.mu.Lock()
+	done := t.engine.pauseDone
+	t.engine.mu.Unlock()
+	close(done)
+}
+
+func (t *tenEnvImpl) OnResumeDone() {
+	t.engine.mu.Lock()
+	done := t.engine.resumeDone
+	t.engine.mu.Unlock()
+	close(done)
+}
@@ -0,0 +1,542 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestCmd_CloneCopiesNameAndProperties(t *testing.T) {
+	cmd, _ := NewCmd("route")
+	cmd.SetPropertyString("dest", "primary")
+	cmd.SetPropertyInt64("attempt", 1)
+
+	clone, err := cmd.Clone()
+	if err != nil {
+		t.Fatalf("Clone: %v", err)
+	}
+
+	name, _ := clone.GetName()
+	dest, _ := clone.GetPropertyString("dest")
+	attempt, _ := clone.GetPropertyInt64("attempt")
+	if name != "route" || dest != "primary" || attempt != 1 {
+		t.Fatalf("clone = %q, %q, %d, want %q, %q, 1", name, dest, attempt, "route", "primary")
+	}
+}
+
+func TestCmd_CloneIsIndependentOfOriginal(t *testing.T) {
+	cmd, _ := NewCmd("route")
+	cmd.SetPropertyString("dest", "primary")
+
+	clone, _ := cmd.Clone()
+	clone.SetPropertyString("dest", "secondary")
+
+	original, _ := cmd.GetPropertyString("dest")
+	if original != "primary" {
+		t.Fatalf("original dest = %q after mutating clone, want unchanged %q", original, "primary")
+	}
+}
+
+func TestCmd_CloneStartsWithFreshFinalState(t *testing.T) {
+	cmd, _ := NewCmd("route")
+	if cmd.finalized(true) {
+		t.Fatal("finalized(true) on a fresh Cmd reported already-final")
+	}
+
+	clone, _ := cmd.Clone()
+	if clone.finalized(true) {
+		t.Fatal("a clone of an already-finalized Cmd reported already-final")
+	}
+}
+
+func TestCmd_SetPropertiesSetsEveryKey(t *testing.T) {
+	cmd, _ := NewCmd("route")
+	err := cmd.SetProperties(map[string]any{
+		"dest":     "primary",
+		"attempt":  int64(2),
+		"gain":     1.5,
+		"muted":    false,
+		"payload":  []byte("hi"),
+		"stream":   map[string]any{"codec": "opus"},
+		"backends": []any{"a", "b"},
+	})
+	if err != nil {
+		t.Fatalf("SetProperties: %v", err)
+	}
+
+	dest, _ := cmd.GetPropertyString("dest")
+	attempt, _ := cmd.GetPropertyInt64("attempt")
+	gain, _ := cmd.GetPropertyFloat64("gain")
+	muted, _ := cmd.GetPropertyBool("muted")
+	codec, _ := cmd.GetPropertyString("stream.codec")
+	if dest != "primary" || attempt != 2 || gain != 1.5 || muted != false || codec != "opus" {
+		t.Fatalf("properties after SetProperties: dest=%q attempt=%d gain=%v muted=%v codec=%q",
+			dest, attempt, gain, muted, codec)
+	}
+}
+
+func TestCmd_SetPropertiesRejectsUnsupportedTypeAndSetsNothing(t *testing.T) {
+	cmd, _ := NewCmd("route")
+	err := cmd.SetProperties(map[string]any{
+		"dest":  "primary",
+		"limit": 3, // plain int, not int64 -- unsupported
+	})
+	if !errors.Is(err, ErrUnsupportedPropertyValueType) {
+		t.Fatalf("SetProperties error = %v, want ErrUnsupportedPropertyValueType", err)
+	}
+	if cmd.HasProperty("dest") {
+		t.Fatal("SetProperties partially applied a batch that failed validation")
+	}
+}
+
+func TestCmd_SetPropertiesNamesFirstBadKeyInSortOrder(t *testing.T) {
+	cmd, _ := NewCmd("route")
+	err := cmd.SetProperties(map[string]any{
+		"zeta":  3,
+		"alpha": 3,
+	})
+	if err == nil || !strings.Contains(err.Error(), `"alpha"`) {
+		t.Fatalf("SetProperties error = %v, want it to name %q", err, "alpha")
+	}
+}
+
+func TestCmd_SetPropertiesAcceptsValueWrappersForAmbiguousTypes(t *testing.T) {
+	cmd, _ := NewCmd("route")
+	err := cmd.SetProperties(map[string]any{
+		"attempt": Int64(3),
+		"gain":    Float64(1.5),
+		"payload": Bytes([]byte("hi")),
+		"muted":   Bool(true),
+	})
+	if err != nil {
+		t.Fatalf("SetProperties: %v", err)
+	}
+
+	attempt, _ := cmd.GetPropertyInt64("attempt")
+	gain, _ := cmd.GetPropertyFloat64("gain")
+	payload, _ := cmd.GetPropertyBytes("payload")
+	muted, _ := cmd.GetPropertyBool("muted")
+	if attempt != 3 || gain != 1.5 || string(payload) != "hi" || !muted {
+		t.Fatalf("properties after SetProperties with Value wrappers: attempt=%d gain=%v payload=%q muted=%v",
+			attempt, gain, payload, muted)
+	}
+}
+
+func TestCmd_SetPropertiesResolvesValueWrappersInsideNestedObjects(t *testing.T) {
+	cmd, _ := NewCmd("route")
+	err := cmd.SetProperties(map[string]any{
+		"stream": map[string]any{"bitrate": Int64(128)},
+	})
+	if err != nil {
+		t.Fatalf("SetProperties: %v", err)
+	}
+
+	bitrate, err := cmd.GetPropertyInt64("stream.bitrate")
+	if err != nil || bitrate != 128 {
+		t.Fatalf("GetPropertyInt64(stream.bitrate) = %d, %v, want 128, nil", bitrate, err)
+	}
+}
+
+func TestCmdResult_SetPropertiesSetsEveryKey(t *testing.T) {
+	cmd, _ := NewCmd("route")
+	result, _ := NewCmdResult(StatusCodeOk, cmd)
+
+	if err := result.SetProperties(map[string]any{
+		"detail": "done",
+		"count":  int64(4),
+	}); err != nil {
+		t.Fatalf("SetProperties: %v", err)
+	}
+
+	detail, _ := result.GetPropertyString("detail")
+	count, _ := result.GetPropertyInt64("count")
+	if detail != "done" || count != 4 {
+		t.Fatalf("properties after SetProperties: detail=%q count=%d", detail, count)
+	}
+}
+
+func TestCmdResult_StatusCodeRoundTripsCustomAndWellKnownCodes(t *testing.T) {
+	cmd, _ := NewCmd("lookup")
+
+	for _, want := range []StatusCode{
+		StatusCodeOk, StatusCodeError, StatusCodeNotFound,
+		StatusCodeUnauthorized, StatusCodeUnavailable, StatusCodeInvalidArgument,
+		StatusCode(1000),
+	} {
+		result, err := NewCmdResult(want, cmd)
+		if err != nil {
+			t.Fatalf("NewCmdResult(%d): %v", want, err)
+		}
+		got, err := result.StatusCode()
+		if err != nil || got != want {
+			t.Fatalf("StatusCode() = %d, %v, want %d, nil", got, err, want)
+		}
+	}
+}
+
+func TestCmdResult_AsErrorNilOnlyForStatusCodeOk(t *testing.T) {
+	cmd, _ := NewCmd("lookup")
+
+	ok, _ := NewCmdResult(StatusCodeOk, cmd)
+	if err := ok.AsError(); err != nil {
+		t.Fatalf("AsError() on StatusCodeOk = %v, want nil", err)
+	}
+
+	for _, code := range []StatusCode{
+		StatusCodeError, StatusCodeNotFound, StatusCodeUnauthorized,
+		StatusCodeUnavailable, StatusCodeInvalidArgument, StatusCode(1000),
+	} {
+		result, _ := NewCmdResult(code, cmd)
+		if err := result.AsError(); err == nil {
+			t.Fatalf("AsError() on status code %d = nil, want an error", code)
+		}
+	}
+}
+
+func TestCmdResult_AsErrorUsesDetailWhenPresent(t *testing.T) {
+	cmd, _ := NewCmd("lookup")
+	result, _ := NewCmdResult(StatusCodeNotFound, cmd)
+	result.SetPropertyString("detail", "no route matched")
+
+	err := result.AsError()
+	if err == nil || err.Error() != "no route matched" {
+		t.Fatalf("AsError() = %v, want it to surface the detail property", err)
+	}
+}
+
+func TestCmdResult_IsOKAndIsErrorFollowStatusCode(t *testing.T) {
+	cmd, _ := NewCmd("lookup")
+
+	ok, _ := NewCmdResult(StatusCodeOk, cmd)
+	if isOK, _ := ok.IsOK(); !isOK {
+		t.Fatal("IsOK() on StatusCodeOk = false, want true")
+	}
+	if isErr, _ := ok.IsError(); isErr {
+		t.Fatal("IsError() on StatusCodeOk = true, want false")
+	}
+
+	failed, _ := NewCmdResult(StatusCodeNotFound, cmd)
+	if isOK, _ := failed.IsOK(); isOK {
+		t.Fatal("IsOK() on StatusCodeNotFound = true, want false")
+	}
+	if isErr, _ := failed.IsError(); !isErr {
+		t.Fatal("IsError() on StatusCodeNotFound = false, want true")
+	}
+}
+
+func TestCmdResult_DetailReadsTheConventionalProperty(t *testing.T) {
+	cmd, _ := NewCmd("lookup")
+	result, _ := NewCmdResult(StatusCodeNotFound, cmd)
+	result.SetPropertyString("detail", "no route matched")
+
+	detail, err := result.Detail()
+	if err != nil || detail != "no route matched" {
+		t.Fatalf("Detail() = (%q, %v), want (%q, nil)", detail, err, "no route matched")
+	}
+}
+
+func TestCmdResult_DetailNotFoundWhenNeverSet(t *testing.T) {
+	cmd, _ := NewCmd("lookup")
+	result, _ := NewCmdResult(StatusCodeOk, cmd)
+
+	if _, err := result.Detail(); !errors.Is(err, ErrPropertyNotFound) {
+		t.Fatalf("Detail() err = %v, want ErrPropertyNotFound", err)
+	}
+}
+
+func TestCmd_PropertiesSnapshotIsIndependentOfOriginal(t *testing.T) {
+	cmd, _ := NewCmd("route")
+	cmd.SetPropertyString("dest", "primary")
+
+	snapshot, err := cmd.PropertiesSnapshot()
+	if err != nil {
+		t.Fatalf("PropertiesSnapshot: %v", err)
+	}
+	snapshot["dest"] = "tampered"
+
+	dest, _ := cmd.GetPropertyString("dest")
+	if dest != "primary" {
+		t.Fatalf("original dest = %q after mutating snapshot, want unchanged %q", dest, "primary")
+	}
+}
+
+func TestCmd_PropertiesSnapshotDeepClonesByteSlices(t *testing.T) {
+	cmd, _ := NewCmd("route")
+	payload := []byte("hi")
+	if err := cmd.SetProperties(map[string]any{"payload": payload}); err != nil {
+		t.Fatalf("SetProperties: %v", err)
+	}
+
+	snapshot, err := cmd.PropertiesSnapshot()
+	if err != nil {
+		t.Fatalf("PropertiesSnapshot: %v", err)
+	}
+	snapshotPayload, ok := snapshot["payload"].([]byte)
+	if !ok {
+		t.Fatalf("snapshot[\"payload\"] = %T, want []byte", snapshot["payload"])
+	}
+	snapshotPayload[0] = 'X'
+
+	if payload[0] == 'X' {
+		t.Fatal("mutating the snapshot's byte slice mutated the caller's original slice")
+	}
+}
+
+func TestCmd_PropertiesSnapshotOmitsReservedTraceKeys(t *testing.T) {
+	cmd, _ := NewCmd("route")
+	cmd.SetPropertyString("dest", "primary")
+	cmd.SetPropertyString(tracePropTraceID, "trace-123")
+	cmd.SetPropertyString(tracePropSpanID, "span-456")
+
+	snapshot, err := cmd.PropertiesSnapshot()
+	if err != nil {
+		t.Fatalf("PropertiesSnapshot: %v", err)
+	}
+	if _, ok := snapshot[tracePropTraceID]; ok {
+		t.Fatal("PropertiesSnapshot included the reserved trace-id key")
+	}
+	if _, ok := snapshot[tracePropSpanID]; ok {
+		t.Fatal("PropertiesSnapshot included the reserved span-id key")
+	}
+	if snapshot["dest"] != "primary" {
+		t.Fatalf("snapshot[\"dest\"] = %v, want %q", snapshot["dest"], "primary")
+	}
+}
+
+func TestCmd_PropertiesSnapshotRoundTripsThroughSetProperties(t *testing.T) {
+	cmd, _ := NewCmd("route")
+	if err := cmd.SetProperties(map[string]any{
+		"dest":    "primary",
+		"attempt": int64(2),
+		"stream":  map[string]any{"codec": "opus"},
+	}); err != nil {
+		t.Fatalf("SetProperties: %v", err)
+	}
+
+	snapshot, err := cmd.PropertiesSnapshot()
+	if err != nil {
+		t.Fatalf("PropertiesSnapshot: %v", err)
+	}
+
+	other, _ := NewCmd("route")
+	if err := other.SetProperties(snapshot); err != nil {
+		t.Fatalf("SetProperties(snapshot): %v", err)
+	}
+	dest, _ := other.GetPropertyString("dest")
+	attempt, _ := other.GetPropertyInt64("attempt")
+	codec, _ := other.GetPropertyString("stream.codec")
+	if dest != "primary" || attempt != 2 || codec != "opus" {
+		t.Fatalf("round-tripped properties: dest=%q attempt=%d codec=%q", dest, attempt, codec)
+	}
+}
+
+func TestCmd_PropertyBytesRoundTripsArbitraryBytes(t *testing.T) {
+	cmd, _ := NewCmd("embed")
+	blob := []byte{0xff, 0x00, 0x80, 'h', 'i'}
+	if err := cmd.SetPropertyBytes("vector", blob); err != nil {
+		t.Fatalf("SetPropertyBytes: %v", err)
+	}
+
+	got, err := cmd.GetPropertyBytes("vector")
+	if err != nil {
+		t.Fatalf("GetPropertyBytes: %v", err)
+	}
+	if string(got) != string(blob) {
+		t.Fatalf("GetPropertyBytes = %v, want %v", got, blob)
+	}
+}
+
+func TestCmd_PropertyBytesGetterReturnsACopy(t *testing.T) {
+	cmd, _ := NewCmd("embed")
+	if err := cmd.SetPropertyBytes("vector", []byte("hi")); err != nil {
+		t.Fatalf("SetPropertyBytes: %v", err)
+	}
+
+	got, _ := cmd.GetPropertyBytes("vector")
+	got[0] = 'X'
+
+	again, _ := cmd.GetPropertyBytes("vector")
+	if string(again) != "hi" {
+		t.Fatalf("stored bytes = %q after mutating a prior GetPropertyBytes result, want unchanged %q", again, "hi")
+	}
+}
+
+func TestCmd_PropertyBytesDistinguishesEmptyFromMissing(t *testing.T) {
+	cmd, _ := NewCmd("embed")
+	if err := cmd.SetPropertyBytes("vector", []byte{}); err != nil {
+		t.Fatalf("SetPropertyBytes: %v", err)
+	}
+
+	got, err := cmd.GetPropertyBytes("vector")
+	if err != nil || len(got) != 0 {
+		t.Fatalf("GetPropertyBytes on an empty blob = %v, %v, want empty slice, nil", got, err)
+	}
+
+	if _, err := cmd.GetPropertyBytes("missing"); !errors.Is(err, ErrPropertyNotFound) {
+		t.Fatalf("GetPropertyBytes on a missing key = %v, want ErrPropertyNotFound", err)
+	}
+}
+
+func TestCmd_PropertyBytesBase64EncodesInJSON(t *testing.T) {
+	cmd, _ := NewCmd("embed")
+	if err := cmd.SetPropertyBytes("vector", []byte("hi")); err != nil {
+		t.Fatalf("SetPropertyBytes: %v", err)
+	}
+
+	data, err := cmd.GetPropertyToJSONBytes("")
+	if err != nil {
+		t.Fatalf("GetPropertyToJSONBytes: %v", err)
+	}
+	if !strings.Contains(string(data), `"vector":"aGk="`) {
+		t.Fatalf("JSON = %s, want the vector property base64-encoded", data)
+	}
+}
+
+func TestCmdResult_PropertyBytesRoundTripsArbitraryBytes(t *testing.T) {
+	cmd, _ := NewCmd("embed")
+	result, _ := NewCmdResult(StatusCodeOk, cmd)
+	blob := []byte{0xff, 0x00, 0x80}
+	if err := result.SetPropertyBytes("token", blob); err != nil {
+		t.Fatalf("SetPropertyBytes: %v", err)
+	}
+
+	got, err := result.GetPropertyBytes("token")
+	if err != nil || string(got) != string(blob) {
+		t.Fatalf("GetPropertyBytes = %v, %v, want %v, nil", got, err, blob)
+	}
+}
+
+func TestCmd_PropertyStringListRoundTrips(t *testing.T) {
+	cmd, _ := NewCmd("configure")
+	values := []string{"a", "b", "c"}
+	if err := cmd.SetPropertyStringList("allowed_models", values); err != nil {
+		t.Fatalf("SetPropertyStringList: %v", err)
+	}
+
+	got, err := cmd.GetPropertyStringList("allowed_models")
+	if err != nil {
+		t.Fatalf("GetPropertyStringList: %v", err)
+	}
+	if strings.Join(got, ",") != strings.Join(values, ",") {
+		t.Fatalf("GetPropertyStringList = %v, want %v", got, values)
+	}
+}
+
+func TestCmd_PropertyStringListRejectsANonArray(t *testing.T) {
+	cmd, _ := NewCmd("configure")
+	cmd.SetPropertyString("allowed_models", "not-a-list")
+
+	if _, err := cmd.GetPropertyStringList("allowed_models"); !errors.Is(err, ErrPropertyTypeMismatch) {
+		t.Fatalf("GetPropertyStringList on a non-array = %v, want ErrPropertyTypeMismatch", err)
+	}
+}
+
+func TestCmd_PropertyStringListNamesTheOffendingIndex(t *testing.T) {
+	cmd, _ := NewCmd("configure")
+	if err := cmd.SetPropertyFloat64List("scores", []float64{1}); err != nil {
+		t.Fatalf("SetPropertyFloat64List: %v", err)
+	}
+
+	_, err := cmd.GetPropertyStringList("scores")
+	if !errors.Is(err, ErrPropertyTypeMismatch) || !strings.Contains(err.Error(), `"scores"[0]`) {
+		t.Fatalf("GetPropertyStringList on mismatched elements = %v, want ErrPropertyTypeMismatch naming scores[0]", err)
+	}
+}
+
+func TestCmd_PropertyStringListMissingKeyIsNotFound(t *testing.T) {
+	cmd, _ := NewCmd("configure")
+
+	if _, err := cmd.GetPropertyStringList("missing"); !errors.Is(err, ErrPropertyNotFound) {
+		t.Fatalf("GetPropertyStringList on a missing key = %v, want ErrPropertyNotFound", err)
+	}
+}
+
+func TestCmd_PropertyInt64ListWidensPlainInts(t *testing.T) {
+	cmd, _ := NewCmd("configure")
+	if err := cmd.SetPropertyFromJSONBytes("retries", []byte(`[1, 2, 3]`)); err != nil {
+		t.Fatalf("SetPropertyFromJSONBytes: %v", err)
+	}
+
+	got, err := cmd.GetPropertyInt64List("retries")
+	if err != nil {
+		t.Fatalf("GetPropertyInt64List: %v", err)
+	}
+	want := []int64{1, 2, 3}
+	for i, v := range want {
+		if got[i] != v {
+			t.Fatalf("GetPropertyInt64List = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestCmd_PropertyFloat64ListWidensIntsAndInt64s(t *testing.T) {
+	cmd, _ := NewCmd("configure")
+	if err := cmd.SetPropertyFromJSONBytes("scores", []byte(`[1, 2, 3.5]`)); err != nil {
+		t.Fatalf("SetPropertyFromJSONBytes: %v", err)
+	}
+
+	got, err := cmd.GetPropertyFloat64List("scores")
+	if err != nil {
+		t.Fatalf("GetPropertyFloat64List: %v", err)
+	}
+	want := []float64{1, 2, 3.5}
+	for i, v := range want {
+		if got[i] != v {
+			t.Fatalf("GetPropertyFloat64List = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestCmdResult_PropertyStringListRoundTrips(t *testing.T) {
+	cmd, _ := NewCmd("configure")
+	result, _ := NewCmdResult(StatusCodeOk, cmd)
+	values := []string{"x", "y"}
+	if err := result.SetPropertyStringList("tags", values); err != nil {
+		t.Fatalf("SetPropertyStringList: %v", err)
+	}
+
+	got, err := result.GetPropertyStringList("tags")
+	if err != nil || strings.Join(got, ",") != strings.Join(values, ",") {
+		t.Fatalf("GetPropertyStringList = %v, %v, want %v, nil", got, err, values)
+	}
+}
+
+func TestCmdResult_PropertyInt64ListRoundTrips(t *testing.T) {
+	cmd, _ := NewCmd("configure")
+	result, _ := NewCmdResult(StatusCodeOk, cmd)
+	values := []int64{7, 8, 9}
+	if err := result.SetPropertyInt64List("codes", values); err != nil {
+		t.Fatalf("SetPropertyInt64List: %v", err)
+	}
+
+	got, err := result.GetPropertyInt64List("codes")
+	if err != nil {
+		t.Fatalf("GetPropertyInt64List: %v", err)
+	}
+	for i, v := range values {
+		if got[i] != v {
+			t.Fatalf("GetPropertyInt64List = %v, want %v", got, values)
+		}
+	}
+}
+
+func TestCmdResult_PropertiesSnapshotIsIndependentOfOriginal(t *testing.T) {
+	cmd, _ := NewCmd("lookup")
+	result, _ := NewCmdResult(StatusCodeOk, cmd)
+	result.SetPropertyString("detail", "done")
+
+	snapshot, err := result.PropertiesSnapshot()
+	if err != nil {
+		t.Fatalf("PropertiesSnapshot: %v", err)
+	}
+	snapshot["detail"] = "tampered"
+
+	detail, _ := result.GetPropertyString("detail")
+	if detail != "done" {
+		t.Fatalf("original detail = %q after mutating snapshot, want unchanged %q", detail, "done")
+	}
+}
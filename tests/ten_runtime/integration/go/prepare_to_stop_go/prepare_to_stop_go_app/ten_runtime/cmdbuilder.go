@@ -0,0 +1,86 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import "errors"
+
+// CmdBuilder is a fluent helper over NewCmd/SetProperty*/SendCmd for the
+// common "build a cmd, set a handful of properties, send it" case. The
+// With* methods accumulate any SetProperty* error instead of returning it,
+// so calls can be chained; Send and SendAndWait surface every accumulated
+// error, joined via errors.Join, before ever touching the network. This is
+// purely additive over the existing primitives -- CmdBuilder never does
+// anything a caller couldn't already do by hand with NewCmd and the
+// SetProperty* methods.
+type CmdBuilder struct {
+	cmd  Cmd
+	errs []error
+}
+
+// NewCmdBuilder starts building a Cmd named name.
+func NewCmdBuilder(name string) *CmdBuilder {
+	cmd, err := NewCmd(name)
+	b := &CmdBuilder{cmd: cmd}
+	if err != nil {
+		b.errs = append(b.errs, err)
+	}
+	return b
+}
+
+// WithString sets a string property, as Cmd.SetPropertyString.
+func (b *CmdBuilder) WithString(path string, value string) *CmdBuilder {
+	b.record(b.cmd.SetPropertyString(path, value))
+	return b
+}
+
+// WithInt sets an int64 property, as Cmd.SetPropertyInt64.
+func (b *CmdBuilder) WithInt(path string, value int64) *CmdBuilder {
+	b.record(b.cmd.SetPropertyInt64(path, value))
+	return b
+}
+
+// WithFloat sets a float64 property, as Cmd.SetPropertyFloat64.
+func (b *CmdBuilder) WithFloat(path string, value float64) *CmdBuilder {
+	b.record(b.cmd.SetPropertyFloat64(path, value))
+	return b
+}
+
+// WithBool sets a bool property, as Cmd.SetPropertyBool.
+func (b *CmdBuilder) WithBool(path string, value bool) *CmdBuilder {
+	b.record(b.cmd.SetPropertyBool(path, value))
+	return b
+}
+
+// WithBytes sets a []byte property, as Cmd.SetPropertyBytes.
+func (b *CmdBuilder) WithBytes(path string, value []byte) *CmdBuilder {
+	b.record(b.cmd.SetPropertyBytes(path, value))
+	return b
+}
+
+func (b *CmdBuilder) record(err error) {
+	if err != nil {
+		b.errs = append(b.errs, err)
+	}
+}
+
+// Send builds the accumulated Cmd and sends it via TenEnv.SendCmd. If any
+// With* call failed, Send returns the joined errors without sending
+// anything -- handler is never invoked in that case.
+func (b *CmdBuilder) Send(tenEnv TenEnv, handler CmdResultHandler) error {
+	if err := errors.Join(b.errs...); err != nil {
+		return err
+	}
+	return tenEnv.SendCmd(b.cmd, handler)
+}
+
+// SendAndWait builds the accumulated Cmd and sends it via
+// TenEnv.SendCmdAndWait. If any With* call failed, SendAndWait returns the
+// joined errors without sending anything.
+func (b *CmdBuilder) SendAndWait(tenEnv TenEnv) (CmdResult, error) {
+	if err := errors.Join(b.errs...); err != nil {
+		return nil, err
+	}
+	return tenEnv.SendCmdAndWait(b.cmd)
+}
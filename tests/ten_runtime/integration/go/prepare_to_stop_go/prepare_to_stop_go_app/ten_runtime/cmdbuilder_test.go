@@ -0,0 +1,74 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import "testing"
+
+func TestCmdBuilder_SendPassesThroughAccumulatedProperties(t *testing.T) {
+	engine := NewEngine(newLifecycleExtension())
+	engine.SetSender(func(cmd Cmd, handler CmdResultHandler) {
+		result, _ := NewCmdResult(StatusCodeOk, cmd)
+		handler(engine.tenEnv, result, nil)
+	})
+
+	var gotName string
+	var gotStr string
+	var gotInt int64
+	engine.SetSender(func(cmd Cmd, handler CmdResultHandler) {
+		gotName, _ = cmd.GetName()
+		gotStr, _ = cmd.GetPropertyString("k")
+		gotInt, _ = cmd.GetPropertyInt64("n")
+		result, _ := NewCmdResult(StatusCodeOk, cmd)
+		handler(engine.tenEnv, result, nil)
+	})
+
+	err := NewCmdBuilder("start").
+		WithString("k", "v").
+		WithInt("n", 3).
+		Send(engine.tenEnv, func(_ TenEnv, result CmdResult, err error) {})
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if gotName != "start" || gotStr != "v" || gotInt != 3 {
+		t.Fatalf("got name=%q k=%q n=%d, want start/v/3", gotName, gotStr, gotInt)
+	}
+}
+
+func TestCmdBuilder_SendSurfacesAccumulatedPropertyErrorsWithoutSending(t *testing.T) {
+	sent := false
+	engine := NewEngine(newLifecycleExtension())
+	engine.SetSender(func(cmd Cmd, handler CmdResultHandler) {
+		sent = true
+	})
+
+	err := NewCmdBuilder("start").
+		WithString("k", "v").
+		WithString("k.sub", "v"). // "k" is already a string, not an object
+		Send(engine.tenEnv, func(_ TenEnv, result CmdResult, err error) {})
+	if err == nil {
+		t.Fatal("Send did not surface the accumulated property error")
+	}
+	if sent {
+		t.Fatal("Send dispatched the cmd despite an accumulated property error")
+	}
+}
+
+func TestCmdBuilder_SendAndWaitReturnsTheFinalResult(t *testing.T) {
+	engine := NewEngine(newLifecycleExtension())
+	engine.SetSender(func(cmd Cmd, handler CmdResultHandler) {
+		result, _ := NewCmdResult(StatusCodeOk, cmd)
+		result.SetPropertyString("echo", "ok")
+		handler(engine.tenEnv, result, nil)
+	})
+
+	result, err := NewCmdBuilder("ping").WithBool("urgent", true).SendAndWait(engine.tenEnv)
+	if err != nil {
+		t.Fatalf("SendAndWait: %v", err)
+	}
+	echo, _ := result.GetPropertyString("echo")
+	if echo != "ok" {
+		t.Fatalf("echo = %q, want %q", echo, "ok")
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"errors"
+	"sync"
+)
+
+// ErrWouldBlock is returned by SendCmd/SendCmdEx (and, via them,
+// SendCmdWithContext/SendCmdAndWait/SendCmdWithRetry/SendCmdChan) when the
+// concurrency limit set by TenEnv.SetCmdConcurrencyLimit is already
+// reached and CmdConcurrencyModeNonBlocking was chosen instead of the
+// default blocking behavior.
+var ErrWouldBlock = errors.New("ten: cmd concurrency limit reached")
+
+// CmdConcurrencyMode chooses what SendCmd/SendCmdEx do once
+// SetCmdConcurrencyLimit's limit is already reached.
+type CmdConcurrencyMode int
+
+const (
+	// CmdConcurrencyModeBlock, the default, holds the calling goroutine
+	// until an in-flight cmd's final result (or cancellation) frees a
+	// slot -- genuine backpressure, at the cost of stalling the caller.
+	CmdConcurrencyModeBlock CmdConcurrencyMode = iota
+
+	// CmdConcurrencyModeNonBlocking returns ErrWouldBlock immediately
+	// instead of waiting, for a caller that would rather fall back to
+	// something else than stall.
+	CmdConcurrencyModeNonBlocking
+)
+
+// CmdConcurrencyOption configures SetCmdConcurrencyLimit beyond the
+// required limit.
+type CmdConcurrencyOption func(*Engine)
+
+// WithCmdConcurrencyMode chooses the CmdConcurrencyMode SetCmdConcurrencyLimit
+// enforces; the default, if this option isn't given, is
+// CmdConcurrencyModeBlock.
+func WithCmdConcurrencyMode(mode CmdConcurrencyMode) CmdConcurrencyOption {
+	return func(e *Engine) { e.cmdConcurrencyMode = mode }
+}
+
+// SetCmdConcurrencyLimit caps how many SendCmd/SendCmdEx calls (including
+// those made through SendCmdWithContext, SendCmdAndWait, SendCmdWithRetry
+// and SendCmdChan, which all funnel through one of the two) may be
+// outstanding at once, waiting on a downstream final result. A limit
+// <= 0, the default, means unbounded -- SendCmd/SendCmdEx never wait or
+// fail because of this limit. Calling it again replaces the previous
+// limit and mode; a call already waiting for a slot under the old limit
+// is re-evaluated against the new one immediately.
+//
+// A cmd's slot is released the moment its final result arrives, exactly
+// like registerPending's graceful-shutdown bookkeeping -- except
+// SendCmdWithContext also releases its slot the instant ctx is cancelled
+// or expires, without waiting for whatever real result (if any) shows up
+// later, so a caller that gives up on a cmd doesn't keep occupying the
+// window it was sent under.
+func (t *tenEnvImpl) SetCmdConcurrencyLimit(n int, opts ...CmdConcurrencyOption) {
+	e := t.engine
+	e.cmdConcurrencyMu.Lock()
+	defer e.cmdConcurrencyMu.Unlock()
+	e.cmdConcurrencyLimit = n
+	e.cmdConcurrencyMode = CmdConcurrencyModeBlock
+	for _, opt := range opts {
+		opt(e)
+	}
+	e.cmdConcurrencyCond.Broadcast()
+}
+
+// acquireCmdSlot admits one SendCmd/SendCmdEx call under the limit set by
+// SetCmdConcurrencyLimit, blocking or failing per its mode once the limit
+// is reached. release is idempotent -- safe to call more than once, and
+// from a different goroutine than the one that acquired the slot -- since
+// a SendCmdWithContext cancellation and the underlying send's eventual
+// completion race to release the same slot.
+func (e *Engine) acquireCmdSlot() (release func(), err error) {
+	e.cmdConcurrencyMu.Lock()
+	defer e.cmdConcurrencyMu.Unlock()
+	if e.cmdConcurrencyLimit <= 0 {
+		return func() {}, nil
+	}
+	for e.cmdConcurrencyInFlight >= e.cmdConcurrencyLimit {
+		if e.cmdConcurrencyMode == CmdConcurrencyModeNonBlocking {
+			return nil, ErrWouldBlock
+		}
+		e.cmdConcurrencyCond.Wait()
+	}
+	e.cmdConcurrencyInFlight++
+	return sync.OnceFunc(func() {
+		e.cmdConcurrencyMu.Lock()
+		e.cmdConcurrencyInFlight--
+		e.cmdConcurrencyCond.Broadcast()
+		e.cmdConcurrencyMu.Unlock()
+	}), nil
+}
@@ -0,0 +1,181 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func newStartedEngineForCmdConcurrency(t *testing.T) *Engine {
+	t.Helper()
+	engine := NewEngine(DefaultExtension{})
+	engine.Init()
+	engine.Start()
+	return engine
+}
+
+func TestTenEnv_SendCmdUnboundedByDefault(t *testing.T) {
+	engine := newStartedEngineForCmdConcurrency(t)
+	cmd, _ := NewCmd("ping")
+
+	done := make(chan error, 1)
+	if err := engine.TenEnv().SendCmd(cmd, func(_ TenEnv, _ CmdResult, err error) { done <- err }); err != nil {
+		t.Fatalf("SendCmd: %v", err)
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("SendCmd handler err = %v, want nil", err)
+	}
+}
+
+func TestTenEnv_SetCmdConcurrencyLimitBlocksUntilASlotFrees(t *testing.T) {
+	engine := newStartedEngineForCmdConcurrency(t)
+	engine.TenEnv().SetCmdConcurrencyLimit(1)
+
+	release := make(chan struct{})
+	engine.SetSender(func(cmd Cmd, handler CmdResultHandler) {
+		<-release
+		result, _ := NewCmdResult(StatusCodeOk, cmd)
+		handler(engine.TenEnv(), result, nil)
+	})
+
+	first, _ := NewCmd("first")
+	firstDone := make(chan error, 1)
+	go func() {
+		engine.TenEnv().SendCmd(first, func(_ TenEnv, _ CmdResult, err error) { firstDone <- err })
+	}()
+
+	// Give the first send a moment to actually occupy the only slot before
+	// starting the second, so the second reliably blocks on it rather than
+	// racing in ahead of it.
+	time.Sleep(20 * time.Millisecond)
+
+	second, _ := NewCmd("second")
+	var wg sync.WaitGroup
+	wg.Add(1)
+	secondAdmittedAt := make(chan time.Time, 1)
+	go func() {
+		defer wg.Done()
+		engine.TenEnv().SendCmd(second, func(_ TenEnv, _ CmdResult, _ error) {
+			secondAdmittedAt <- time.Now()
+		})
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	releasedAt := time.Now()
+	close(release)
+
+	select {
+	case admittedAt := <-secondAdmittedAt:
+		if admittedAt.Before(releasedAt) {
+			t.Fatalf("second SendCmd was admitted before the first slot was released")
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("blocked SendCmd never unblocked after the first slot freed up")
+	}
+	wg.Wait()
+	if err := <-firstDone; err != nil {
+		t.Fatalf("first SendCmd handler err = %v, want nil", err)
+	}
+}
+
+func TestTenEnv_SetCmdConcurrencyLimitNonBlockingReturnsErrWouldBlock(t *testing.T) {
+	engine := newStartedEngineForCmdConcurrency(t)
+	engine.TenEnv().SetCmdConcurrencyLimit(1, WithCmdConcurrencyMode(CmdConcurrencyModeNonBlocking))
+
+	release := make(chan struct{})
+	engine.SetSender(func(cmd Cmd, handler CmdResultHandler) {
+		<-release
+		result, _ := NewCmdResult(StatusCodeOk, cmd)
+		handler(engine.TenEnv(), result, nil)
+	})
+
+	first, _ := NewCmd("first")
+	firstDone := make(chan error, 1)
+	go func() {
+		engine.TenEnv().SendCmd(first, func(_ TenEnv, _ CmdResult, err error) { firstDone <- err })
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	second, _ := NewCmd("second")
+	err := engine.TenEnv().SendCmd(second, func(TenEnv, CmdResult, error) {
+		t.Fatalf("handler should not be invoked when SendCmd itself returns ErrWouldBlock")
+	})
+	if !errors.Is(err, ErrWouldBlock) {
+		t.Fatalf("SendCmd (second) = %v, want ErrWouldBlock", err)
+	}
+
+	close(release)
+	if err := <-firstDone; err != nil {
+		t.Fatalf("first SendCmd handler err = %v, want nil", err)
+	}
+}
+
+func TestTenEnv_SendCmdWithContextCancellationReleasesSlotImmediately(t *testing.T) {
+	engine := newStartedEngineForCmdConcurrency(t)
+	engine.TenEnv().SetCmdConcurrencyLimit(1)
+
+	// invoked reports each cmd name as it reaches the sender, i.e. once it
+	// has cleared acquireCmdSlot -- distinct from the handler firing. The
+	// sender answers on its own goroutine, behind release, the same way a
+	// real downstream responder would answer asynchronously -- so, unlike
+	// the blocking-limit tests above, SendCmdWithContext itself returns
+	// right away and its ctx-cancellation watcher gets a chance to run.
+	invoked := make(chan string, 2)
+	release := make(chan struct{})
+	engine.SetSender(func(cmd Cmd, handler CmdResultHandler) {
+		name, _ := cmd.GetName()
+		invoked <- name
+		go func() {
+			<-release
+			result, _ := NewCmdResult(StatusCodeOk, cmd)
+			handler(engine.TenEnv(), result, nil)
+		}()
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancelled, _ := NewCmd("cancelled")
+	cancelledDone := make(chan error, 1)
+	if err := engine.TenEnv().SendCmdWithContext(ctx, cancelled, func(_ TenEnv, _ CmdResult, err error) {
+		cancelledDone <- err
+	}); err != nil {
+		t.Fatalf("SendCmdWithContext: %v", err)
+	}
+	if name := <-invoked; name != "cancelled" {
+		t.Fatalf("first sender invocation = %q, want %q", name, "cancelled")
+	}
+
+	// cancelled's real result is still stuck behind release, but cancelling
+	// ctx must free its slot right away rather than waiting for it.
+	cancel()
+	if err := <-cancelledDone; !errors.Is(err, ErrContextCanceled) {
+		t.Fatalf("cancelled SendCmdWithContext handler err = %v, want ErrContextCanceled", err)
+	}
+
+	// If cancelled's slot weren't released promptly, this would block
+	// forever in acquireCmdSlot: the limit is 1 and release hasn't been
+	// closed yet.
+	next, _ := NewCmd("next")
+	nextDone := make(chan error, 1)
+	go func() {
+		nextDone <- engine.TenEnv().SendCmd(next, func(_ TenEnv, _ CmdResult, _ error) {})
+	}()
+	select {
+	case name := <-invoked:
+		if name != "next" {
+			t.Fatalf("second sender invocation = %q, want %q", name, "next")
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("SendCmd after cancellation never reached the sender -- cancelled's slot wasn't released")
+	}
+	if err := <-nextDone; err != nil {
+		t.Fatalf("SendCmd (next): %v", err)
+	}
+
+	close(release)
+}
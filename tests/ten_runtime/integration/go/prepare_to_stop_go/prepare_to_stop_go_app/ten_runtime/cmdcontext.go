@@ -0,0 +1,104 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"context"
+	"sync"
+)
+
+// cmdCtxEntry is what cmdCtxTracker holds per Cmd: the context to hand
+// back from TenEnv.CmdContext, already narrowed to the cmd's own deadline
+// (see cmdContextWithDeadline), plus the cancel function that context
+// derivation returned, released once the entry is forgotten.
+type cmdCtxEntry struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// cmdCtxTracker maps an in-flight Cmd to the context its originating
+// caller delivered it with, so TenEnv.CmdContext can hand it back to the
+// extension handling that Cmd. It's a separate mutex from Engine.mu, the
+// same way cmdStart/cmdStartMu is kept separate for metrics, since
+// DeliverCmdWithContext and CmdContext have nothing to do with lifecycle
+// state.
+type cmdCtxTracker struct {
+	mu    sync.Mutex
+	byCmd map[Cmd]cmdCtxEntry
+}
+
+func (c *cmdCtxTracker) set(cmd Cmd, ctx context.Context, cancel context.CancelFunc) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.byCmd == nil {
+		c.byCmd = map[Cmd]cmdCtxEntry{}
+	}
+	c.byCmd[cmd] = cmdCtxEntry{ctx: ctx, cancel: cancel}
+}
+
+func (c *cmdCtxTracker) get(cmd Cmd) (context.Context, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.byCmd[cmd]
+	return entry.ctx, ok
+}
+
+// delete forgets cmd's tracked entry, releasing its cancel function first
+// so a deadline-bound context's timer is freed immediately rather than
+// waiting for the deadline to arrive on its own.
+func (c *cmdCtxTracker) delete(cmd Cmd) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if entry, ok := c.byCmd[cmd]; ok {
+		entry.cancel()
+		delete(c.byCmd, cmd)
+	}
+}
+
+// DeliverCmdWithContext is DeliverCmd, except ctx is remembered as cmd's
+// originating caller's context: if ctx is cancelled or its deadline
+// expires -- the caller timed out or itself was cancelled -- the
+// extension handling cmd can observe that via TenEnv.CmdContext(cmd).Done()
+// and stop doing work nobody wants the result of anymore (e.g. an LLM
+// extension mid-generation). DeliverCmd is DeliverCmdWithContext called
+// with context.Background(), so a cmd delivered that way simply never
+// reports cancellation of its own accord -- though see cmdContextWithDeadline:
+// either way, a deadline set via Cmd.SetDeadline still narrows whatever
+// context is ultimately tracked.
+func (e *Engine) DeliverCmdWithContext(ctx context.Context, cmd Cmd) error {
+	ctx, cancel := cmdContextWithDeadline(ctx, cmd)
+	e.cmdCtx.set(cmd, ctx, cancel)
+	if err := e.DeliverCmd(cmd); err != nil {
+		e.cmdCtx.delete(cmd)
+		return err
+	}
+	return nil
+}
+
+// releaseCmdContext forgets cmd's tracked context once it's done being
+// useful: either its final result has been returned (see ReturnResult/
+// ReturnResultEx), or DeliverCmd never got as far as dispatching it.
+func (e *Engine) releaseCmdContext(cmd Cmd) {
+	e.cmdCtx.delete(cmd)
+}
+
+// CmdContext returns the context cmd was delivered with via
+// DeliverCmdWithContext, so OnCmd (or a goroutine it spawns to keep
+// working after OnCmd returns) can select on Done() to notice its caller
+// gave up, or check Deadline() to see how much of its end-to-end budget
+// remains. A cmd delivered via plain DeliverCmd gets context.Background()
+// narrowed to whatever deadline Cmd.SetDeadline set on it, if any -- a
+// context that never cancels on its own if no deadline was ever set. One
+// CmdContext is asked about after its final result was already returned
+// gets the same treatment rather than an error, since "no cancellation
+// was ever wired up for this cmd" isn't a failure the caller needs to
+// handle specially.
+func (t *tenEnvImpl) CmdContext(cmd Cmd) context.Context {
+	if ctx, ok := t.engine.cmdCtx.get(cmd); ok {
+		return ctx
+	}
+	ctx, _ := cmdContextWithDeadline(context.Background(), cmd)
+	return ctx
+}
@@ -0,0 +1,124 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// cmdContextObservingExtension spawns a goroutine from OnCmd that watches
+// tenEnv.CmdContext(cmd).Done() and reports onto done once it fires, the
+// way a long-running extension (e.g. one streaming an LLM response) would
+// give up early on a cancelled caller instead of finishing unobserved work.
+type cmdContextObservingExtension struct {
+	DefaultExtension
+	done chan error
+}
+
+func (c *cmdContextObservingExtension) OnCmd(tenEnv TenEnv, cmd Cmd) {
+	go func() {
+		<-tenEnv.CmdContext(cmd).Done()
+		c.done <- tenEnv.CmdContext(cmd).Err()
+	}()
+}
+
+func TestDeliverCmdWithContext_CancellationObservableFromOnCmd(t *testing.T) {
+	ext := &cmdContextObservingExtension{done: make(chan error, 1)}
+	engine := NewEngine(ext)
+	if err := engine.Init(); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	if err := engine.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cmd, _ := NewCmd("greet")
+	if err := engine.DeliverCmdWithContext(ctx, cmd); err != nil {
+		t.Fatalf("DeliverCmdWithContext: %v", err)
+	}
+
+	select {
+	case err := <-ext.done:
+		t.Fatalf("CmdContext(cmd).Done() fired before cancel, err = %v", err)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	cancel()
+
+	select {
+	case err := <-ext.done:
+		if err != context.Canceled {
+			t.Fatalf("CmdContext(cmd).Err() = %v, want context.Canceled", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("CmdContext(cmd).Done() never fired after cancel")
+	}
+}
+
+func TestDeliverCmd_WithoutContextNeverCancels(t *testing.T) {
+	seen := make(chan context.Context, 1)
+	ext := &blockingSendExtension{onCmd: func(tenEnv TenEnv, cmd Cmd) {
+		seen <- tenEnv.CmdContext(cmd)
+		result, _ := NewCmdResult(StatusCodeOk, cmd)
+		tenEnv.ReturnResult(result, cmd)
+	}}
+	engine := NewEngine(ext)
+	if err := engine.Init(); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	if err := engine.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	cmd, _ := NewCmd("greet")
+	if err := engine.DeliverCmd(cmd); err != nil {
+		t.Fatalf("DeliverCmd: %v", err)
+	}
+
+	ctx := <-seen
+	select {
+	case <-ctx.Done():
+		t.Fatal("CmdContext(cmd) for a plainly-delivered cmd was already done")
+	default:
+	}
+}
+
+func TestTenEnv_CmdContextForUntrackedCmdReturnsBackground(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+	cmd, _ := NewCmd("greet")
+	ctx := engine.tenEnv.CmdContext(cmd)
+	select {
+	case <-ctx.Done():
+		t.Fatal("CmdContext for a cmd never delivered via DeliverCmdWithContext was already done")
+	default:
+	}
+}
+
+func TestEngine_ReturnResultReleasesTrackedCmdContext(t *testing.T) {
+	ext := newLifecycleExtension()
+	engine := NewEngine(ext)
+	if err := engine.Init(); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	if err := engine.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	ctx := context.Background()
+	cmd, _ := NewCmd("greet")
+	if err := engine.DeliverCmdWithContext(ctx, cmd); err != nil {
+		t.Fatalf("DeliverCmdWithContext: %v", err)
+	}
+
+	// lifecycleExtension.OnCmd calls ReturnResult synchronously, so by the
+	// time DeliverCmd above returns, the tracked context should already
+	// have been released.
+	if _, ok := engine.cmdCtx.get(cmd); ok {
+		t.Fatal("cmdCtx still tracks cmd after its result was returned")
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"errors"
+	"path"
+)
+
+// ErrCmdFilterNoMatch is returned by DeliverCmd/DeliverCmdWithContext when
+// TenEnv.SetCmdFilter has narrowed this instance to a set of cmd name
+// patterns and the incoming cmd's name matches none of them. Unlike
+// ErrExtensionPaused, cmd is untouched -- no result is returned for it --
+// so the caller, who owns whatever default routing exists outside this
+// single instance, can hand it elsewhere itself instead of it silently
+// vanishing here.
+var ErrCmdFilterNoMatch = errors.New("ten: cmd does not match this extension's cmd filter")
+
+// matchesCmdFilter reports whether name is admitted by e's registered cmd
+// filter patterns. With no patterns registered, filtering was never
+// opted into, so everything matches. Patterns compose via OR: name is
+// admitted if it matches any one of them, so a catch-all pattern ("*")
+// alongside more specific ones simply makes the specific ones redundant
+// rather than conflicting with them. Each pattern is matched with
+// path.Match, which treats a trailing "*" as an ordinary prefix match
+// (path.Match doesn't cross "/", but cmd names never contain one) and
+// falls back to false, not an error, for a malformed pattern -- a typo'd
+// pattern should silently exclude, not panic mid-dispatch.
+func (e *Engine) matchesCmdFilter(name string) bool {
+	e.cmdFiltersMu.Lock()
+	patterns := e.cmdFilters
+	e.cmdFiltersMu.Unlock()
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, pattern := range patterns {
+		if ok, err := path.Match(pattern, name); err == nil && ok {
+			return true
+		}
+	}
+	return false
+}
+
+// admitThroughCmdFilter is DeliverCmd's cmd-filter gate, mirroring
+// admitThroughDeadline/admitThroughRateLimit in shape, except a
+// non-matching cmd is reported back to the caller via err rather than
+// answered with a result: this instance isn't cmd's destination, so it
+// has no result to give -- routing it elsewhere is the caller's job. A
+// true return means the caller should proceed with dispatch, in which
+// case err is always nil.
+func (e *Engine) admitThroughCmdFilter(cmd Cmd) (bool, error) {
+	name, _ := cmd.GetName()
+	if e.matchesCmdFilter(name) {
+		return true, nil
+	}
+	return false, ErrCmdFilterNoMatch
+}
+
+// SetCmdFilter implements TenEnv.SetCmdFilter; see its doc comment for the
+// contract.
+func (t *tenEnvImpl) SetCmdFilter(pattern string) {
+	t.engine.cmdFiltersMu.Lock()
+	defer t.engine.cmdFiltersMu.Unlock()
+	t.engine.cmdFilters = append(t.engine.cmdFilters, pattern)
+}
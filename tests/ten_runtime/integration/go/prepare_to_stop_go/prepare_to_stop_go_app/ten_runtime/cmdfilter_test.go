@@ -0,0 +1,111 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestTenEnv_SetCmdFilterAdmitsAMatchingCmd(t *testing.T) {
+	engine := NewEngine(countingExtension{calls: make(chan Cmd, 1)})
+	engine.TenEnv().SetCmdFilter("greet*")
+	engine.Init()
+	engine.Start()
+
+	cmd, _ := NewCmd("greet_hello")
+	if err := engine.DeliverCmd(cmd); err != nil {
+		t.Fatalf("DeliverCmd: %v", err)
+	}
+
+	ext := engine.tenEnv.engine.ext.(countingExtension)
+	select {
+	case <-ext.calls:
+	default:
+		t.Fatal("OnCmd never ran for a cmd matching the filter")
+	}
+}
+
+func TestTenEnv_SetCmdFilterRejectsANonMatchingCmd(t *testing.T) {
+	engine := NewEngine(countingExtension{calls: make(chan Cmd, 1)})
+	engine.TenEnv().SetCmdFilter("greet*")
+	engine.Init()
+	engine.Start()
+
+	cmd, _ := NewCmd("farewell")
+	err := engine.DeliverCmd(cmd)
+	if !errors.Is(err, ErrCmdFilterNoMatch) {
+		t.Fatalf("DeliverCmd = %v, want ErrCmdFilterNoMatch", err)
+	}
+
+	ext := engine.tenEnv.engine.ext.(countingExtension)
+	select {
+	case <-ext.calls:
+		t.Fatal("OnCmd ran for a cmd that doesn't match the filter")
+	default:
+	}
+}
+
+func TestTenEnv_SetCmdFilterWithoutAnyPatternAdmitsEverything(t *testing.T) {
+	engine := NewEngine(countingExtension{calls: make(chan Cmd, 1)})
+	engine.Init()
+	engine.Start()
+
+	cmd, _ := NewCmd("anything")
+	if err := engine.DeliverCmd(cmd); err != nil {
+		t.Fatalf("DeliverCmd: %v", err)
+	}
+
+	ext := engine.tenEnv.engine.ext.(countingExtension)
+	select {
+	case <-ext.calls:
+	default:
+		t.Fatal("OnCmd never ran even though no filter was registered")
+	}
+}
+
+func TestTenEnv_SetCmdFilterMultiplePatternsAreOrEd(t *testing.T) {
+	engine := NewEngine(countingExtension{calls: make(chan Cmd, 2)})
+	engine.TenEnv().SetCmdFilter("greet")
+	engine.TenEnv().SetCmdFilter("farewell")
+	engine.Init()
+	engine.Start()
+
+	for _, name := range []string{"greet", "farewell"} {
+		cmd, _ := NewCmd(name)
+		if err := engine.DeliverCmd(cmd); err != nil {
+			t.Fatalf("DeliverCmd(%q): %v", name, err)
+		}
+	}
+
+	ext := engine.tenEnv.engine.ext.(countingExtension)
+	for i := 0; i < 2; i++ {
+		select {
+		case <-ext.calls:
+		default:
+			t.Fatalf("OnCmd ran only %d/2 times, want both patterns admitted", i)
+		}
+	}
+}
+
+func TestTenEnv_SetCmdFilterCatchAllAdmitsEverything(t *testing.T) {
+	engine := NewEngine(countingExtension{calls: make(chan Cmd, 1)})
+	engine.TenEnv().SetCmdFilter("greet")
+	engine.TenEnv().SetCmdFilter("*")
+	engine.Init()
+	engine.Start()
+
+	cmd, _ := NewCmd("anything")
+	if err := engine.DeliverCmd(cmd); err != nil {
+		t.Fatalf("DeliverCmd: %v", err)
+	}
+
+	ext := engine.tenEnv.engine.ext.(countingExtension)
+	select {
+	case <-ext.calls:
+	default:
+		t.Fatal("OnCmd never ran despite a catch-all pattern alongside a specific one")
+	}
+}
@@ -0,0 +1,45 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import "errors"
+
+// ErrCanceled is the error a SendCmdCancelable handler sees when
+// CmdHandle.Cancel wins the race against a real result.
+var ErrCanceled = errors.New("ten: cmd canceled")
+
+// CmdHandle is returned by TenEnv.SendCmdCancelable alongside the usual
+// error, standing in for the one cmd that call sent. See
+// SendCmdCancelable's doc comment for the overall contract.
+type CmdHandle interface {
+	// Cancel aborts this handle's cmd: its handler fires exactly once,
+	// with ErrCanceled, and any result that arrives afterward is
+	// dropped. Calling Cancel on a cmd that has already completed, or
+	// calling it more than once, is a harmless no-op -- the same
+	// resolve-wins-the-race rule registerPending's doc comment in
+	// engine.go describes for a graceful-shutdown timeout, just with
+	// Cancel as one more contender for the same race instead of only
+	// the real result and the shutdown deadline.
+	Cancel()
+}
+
+// cmdHandle is CmdHandle's only implementation. resolve, release and
+// handler are the exact three values sendCmdWithRelease already built for
+// the send itself -- Cancel just calls them from the outside instead of
+// from inside t.engine.sender's own callback.
+type cmdHandle struct {
+	tenEnv  *tenEnvImpl
+	resolve func() bool
+	release func()
+	handler CmdResultHandler
+}
+
+func (h cmdHandle) Cancel() {
+	if !h.resolve() {
+		return
+	}
+	h.release()
+	h.handler(h.tenEnv, nil, ErrCanceled)
+}
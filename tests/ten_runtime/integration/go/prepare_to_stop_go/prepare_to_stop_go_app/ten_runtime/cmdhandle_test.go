@@ -0,0 +1,88 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestTenEnv_SendCmdCancelableFiresErrCanceledExactlyOnce(t *testing.T) {
+	engine := NewEngine(newLifecycleExtension())
+	var deliver func(result CmdResult, err error)
+	engine.SetSender(func(cmd Cmd, handler CmdResultHandler) {
+		deliver = func(result CmdResult, err error) {
+			handler(engine.tenEnv, result, err)
+		}
+	})
+
+	cmd, _ := NewCmd("slow")
+	var results []error
+	handle, err := engine.tenEnv.SendCmdCancelable(cmd, func(_ TenEnv, result CmdResult, err error) {
+		results = append(results, err)
+	})
+	if err != nil {
+		t.Fatalf("SendCmdCancelable: %v", err)
+	}
+
+	handle.Cancel()
+	if len(results) != 1 || !errors.Is(results[0], ErrCanceled) {
+		t.Fatalf("results after Cancel = %v, want exactly one ErrCanceled", results)
+	}
+
+	// The real result showing up later must be dropped, not delivered.
+	result, _ := NewCmdResult(StatusCodeOk, cmd)
+	deliver(result, nil)
+	if len(results) != 1 {
+		t.Fatalf("results after late delivery = %v, want the Cancel result untouched", results)
+	}
+}
+
+func TestCmdHandle_CancelAfterCompletionIsANoOp(t *testing.T) {
+	engine := NewEngine(newLifecycleExtension())
+	engine.SetSender(func(cmd Cmd, handler CmdResultHandler) {
+		result, _ := NewCmdResult(StatusCodeOk, cmd)
+		handler(engine.tenEnv, result, nil)
+	})
+
+	cmd, _ := NewCmd("fast")
+	var results []error
+	handle, err := engine.tenEnv.SendCmdCancelable(cmd, func(_ TenEnv, _ CmdResult, err error) {
+		results = append(results, err)
+	})
+	if err != nil {
+		t.Fatalf("SendCmdCancelable: %v", err)
+	}
+	if len(results) != 1 || results[0] != nil {
+		t.Fatalf("results before Cancel = %v, want one nil-error result", results)
+	}
+
+	handle.Cancel()
+	if len(results) != 1 {
+		t.Fatalf("results after Cancel on a completed cmd = %v, want no additional callback", results)
+	}
+}
+
+func TestCmdHandle_CancelTwiceOnlyFiresOnce(t *testing.T) {
+	engine := NewEngine(newLifecycleExtension())
+	engine.SetSender(func(cmd Cmd, handler CmdResultHandler) {
+		// Never delivers -- Cancel is the only thing that resolves this cmd.
+	})
+
+	cmd, _ := NewCmd("never-answered")
+	var results []error
+	handle, err := engine.tenEnv.SendCmdCancelable(cmd, func(_ TenEnv, _ CmdResult, err error) {
+		results = append(results, err)
+	})
+	if err != nil {
+		t.Fatalf("SendCmdCancelable: %v", err)
+	}
+
+	handle.Cancel()
+	handle.Cancel()
+	if len(results) != 1 || !errors.Is(results[0], ErrCanceled) {
+		t.Fatalf("results after two Cancel calls = %v, want exactly one ErrCanceled", results)
+	}
+}
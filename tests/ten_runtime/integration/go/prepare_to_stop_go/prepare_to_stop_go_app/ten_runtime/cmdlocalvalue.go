@@ -0,0 +1,72 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import "sync"
+
+// cmdLocalValueStore maps an in-flight Cmd to a small key/value bag of
+// arbitrary Go values, the same way cmdCtxTracker maps one to its
+// originating context. It's a separate mutex from Engine.mu and from
+// cmdCtx's, for the same reason both of those already are: this has
+// nothing to do with lifecycle state.
+type cmdLocalValueStore struct {
+	mu    sync.Mutex
+	byCmd map[Cmd]map[any]any
+}
+
+func (s *cmdLocalValueStore) set(cmd Cmd, key, val any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.byCmd == nil {
+		s.byCmd = map[Cmd]map[any]any{}
+	}
+	bag, ok := s.byCmd[cmd]
+	if !ok {
+		bag = map[any]any{}
+		s.byCmd[cmd] = bag
+	}
+	bag[key] = val
+}
+
+func (s *cmdLocalValueStore) get(cmd Cmd, key any) (any, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	bag, ok := s.byCmd[cmd]
+	if !ok {
+		return nil, false
+	}
+	val, ok := bag[key]
+	return val, ok
+}
+
+// delete forgets every value attached to cmd, called once cmd is done
+// being useful -- the same moment releaseCmdContext forgets its tracked
+// context.
+func (s *cmdLocalValueStore) delete(cmd Cmd) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.byCmd, cmd)
+}
+
+// SetLocalValue attaches val to cmd under key, for handing a Go object
+// that can't be serialized into a property -- a *sql.Tx, an open
+// connection, anything request-scoped -- to a downstream in-process
+// extension that also has a handle on the same Cmd. It exists purely as an
+// in-process side channel: unlike a property, a value stored this way
+// never crosses a process or app boundary, is dropped without warning if
+// cmd is ever serialized, and is only ever visible to code running against
+// this same Engine. The bag is released once cmd's final result is
+// returned (see ReturnResult/ReturnResultEx), so it never outlives the Cmd
+// it was attached to.
+func (t *tenEnvImpl) SetLocalValue(cmd Cmd, key any, val any) {
+	t.engine.localValues.set(cmd, key, val)
+}
+
+// GetLocalValue reads back a value SetLocalValue attached to cmd under
+// key, reporting false if nothing was ever set for that key -- including
+// after cmd has already completed and its bag was released.
+func (t *tenEnvImpl) GetLocalValue(cmd Cmd, key any) (any, bool) {
+	return t.engine.localValues.get(cmd, key)
+}
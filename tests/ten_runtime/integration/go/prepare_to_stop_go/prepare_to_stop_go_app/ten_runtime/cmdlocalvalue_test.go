@@ -0,0 +1,71 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import "testing"
+
+func TestTenEnv_GetLocalValueReturnsFalseForUnsetKey(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+	cmd, _ := NewCmd("greet")
+
+	if _, ok := engine.tenEnv.GetLocalValue(cmd, "conn"); ok {
+		t.Fatal("GetLocalValue for a key that was never set = true, want false")
+	}
+}
+
+func TestTenEnv_SetLocalValueRoundTrips(t *testing.T) {
+	ext := &blockingSendExtension{onCmd: func(tenEnv TenEnv, cmd Cmd) {
+		tenEnv.SetLocalValue(cmd, "conn", "the-connection")
+		got, ok := tenEnv.GetLocalValue(cmd, "conn")
+		if !ok || got != "the-connection" {
+			t.Errorf("GetLocalValue(conn) = (%v, %v), want (the-connection, true)", got, ok)
+		}
+		result, _ := NewCmdResult(StatusCodeOk, cmd)
+		tenEnv.ReturnResult(result, cmd)
+	}}
+
+	engine := NewEngine(ext)
+	if err := engine.Init(); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	if err := engine.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	cmd, _ := NewCmd("greet")
+	if err := engine.DeliverCmd(cmd); err != nil {
+		t.Fatalf("DeliverCmd: %v", err)
+	}
+}
+
+func TestEngine_ReturnResultReleasesLocalValues(t *testing.T) {
+	var cmdRef Cmd
+	ext := &blockingSendExtension{onCmd: func(tenEnv TenEnv, cmd Cmd) {
+		cmdRef = cmd
+		tenEnv.SetLocalValue(cmd, "conn", "the-connection")
+		result, _ := NewCmdResult(StatusCodeOk, cmd)
+		tenEnv.ReturnResult(result, cmd)
+	}}
+
+	engine := NewEngine(ext)
+	if err := engine.Init(); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	if err := engine.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	cmd, _ := NewCmd("greet")
+	if err := engine.DeliverCmd(cmd); err != nil {
+		t.Fatalf("DeliverCmd: %v", err)
+	}
+
+	// blockingSendExtension's OnCmd calls ReturnResult synchronously, so by
+	// the time DeliverCmd above returns, the local value bag should already
+	// have been released.
+	if _, ok := engine.tenEnv.GetLocalValue(cmdRef, "conn"); ok {
+		t.Fatal("GetLocalValue still returns a value for cmd after its result was returned")
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// cmdTimeoutEntry is one cmd's armed watchdog: timer fires fireCommandTimeout
+// after the duration SetCommandTimeout was given, and claimed is the
+// single atomic decision point between that firing and a racing manual
+// ReturnResult/ReturnResultEx(..., true) -- whichever CAS wins gets to
+// finalize cmd, the other is discarded.
+type cmdTimeoutEntry struct {
+	timer   ClockTimer
+	claimed int32
+}
+
+// SetCommandTimeout implements TenEnv.SetCommandTimeout; see its doc
+// comment.
+func (t *tenEnvImpl) SetCommandTimeout(cmd Cmd, d time.Duration) {
+	t.engine.armCommandTimeout(cmd, d)
+}
+
+// armCommandTimeout starts cmd's watchdog timer, replacing (and
+// cancelling) whatever one a previous SetCommandTimeout call for the same
+// cmd left armed. It fires off e's Clock (the real one by default), so a
+// test can arm a short timeout and then advance a tentest.FakeClock
+// instead of sleeping through it.
+func (e *Engine) armCommandTimeout(cmd Cmd, d time.Duration) {
+	entry := &cmdTimeoutEntry{}
+	entry.timer = e.getClock().AfterFunc(d, func() { e.fireCommandTimeout(cmd, entry) })
+
+	e.cmdTimeoutMu.Lock()
+	if e.cmdTimeouts == nil {
+		e.cmdTimeouts = map[Cmd]*cmdTimeoutEntry{}
+	}
+	if old, ok := e.cmdTimeouts[cmd]; ok {
+		old.timer.Stop()
+	}
+	e.cmdTimeouts[cmd] = entry
+	e.cmdTimeoutMu.Unlock()
+}
+
+// claimCommandTimeout is ReturnResult/ReturnResultEx's half of the race
+// against a cmd's watchdog, called just before either would otherwise
+// finalize cmd. It reports whether this call should proceed: true if cmd
+// has no armed watchdog at all (the common case -- most cmds never call
+// SetCommandTimeout, and this is a single map lookup for them) or if this
+// call is the one that wins the CAS on entry.claimed; false if
+// fireCommandTimeout already won it first, meaning this manual return has
+// lost the race and must be discarded.
+//
+// Either way, once an entry is found this call is the one that removes it
+// from e.cmdTimeouts: fireCommandTimeout reaches its own entry directly
+// through the closure time.AfterFunc captured, never through the map, so
+// the map's only remaining job is letting a manual call find out whether
+// it won or lost -- once that's answered there's nothing left to look up
+// the entry for again.
+func (e *Engine) claimCommandTimeout(cmd Cmd) bool {
+	e.cmdTimeoutMu.Lock()
+	entry, ok := e.cmdTimeouts[cmd]
+	if ok {
+		delete(e.cmdTimeouts, cmd)
+	}
+	e.cmdTimeoutMu.Unlock()
+	if !ok {
+		return true
+	}
+
+	won := atomic.CompareAndSwapInt32(&entry.claimed, 0, 1)
+	if won {
+		entry.timer.Stop()
+	}
+	return won
+}
+
+// fireCommandTimeout is entry's timer callback. If a manual return hasn't
+// already claimed cmd (the same CAS claimCommandTimeout uses, so exactly
+// one of the two ever wins), it auto-fails cmd with a StatusCodeError
+// "command timeout" result and logs a warning naming it.
+//
+// It deliberately does not touch e.cmdTimeouts: a manual return arriving
+// later still needs to find cmd's entry there (via claimCommandTimeout)
+// to correctly see it as already claimed and lose. It finalizes cmd
+// itself, via finalizeReturnedCmd, rather than calling tenEnv.ReturnError,
+// since fireCommandTimeout has already won the claim above and routing
+// back through ReturnResult would just call claimCommandTimeout a second
+// time for no reason. It still calls notifyCrossExtResultHandler itself,
+// the same as jobhandle.go/resultstream.go's own force-finalize paths, so
+// a SendCmdToExtension/DirectRef.Call caller waiting on cmd is woken
+// instead of hanging forever.
+func (e *Engine) fireCommandTimeout(cmd Cmd, entry *cmdTimeoutEntry) {
+	if !atomic.CompareAndSwapInt32(&entry.claimed, 0, 1) {
+		return
+	}
+
+	name, _ := cmd.GetName()
+	e.tenEnv.LogFields(LogLevelWarn, "command timeout: auto-returning",
+		String("cmd", name),
+		String("extension", e.extensionName()),
+	)
+
+	result, err := NewCmdResult(StatusCodeError, cmd)
+	if err != nil {
+		e.finalizeReturnedCmd(cmd)
+		return
+	}
+	_ = result.SetPropertyString("detail", "command timeout")
+	if impl, ok := result.(*cmdResultImpl); ok {
+		impl.isFinal = true
+	}
+	e.finalizeReturnedCmd(cmd)
+	e.notifyCrossExtResultHandler(cmd, result)
+}
+
+// logIgnoredLateCommandTimeoutResult warns that a manual
+// ReturnResult/ReturnResultEx(..., true) call lost its race against
+// fireCommandTimeout and was discarded, so the extension author has a
+// trail to notice their handler ran past its own SetCommandTimeout
+// deadline.
+func (e *Engine) logIgnoredLateCommandTimeoutResult(cmd Cmd) {
+	name, _ := cmd.GetName()
+	e.tenEnv.LogFields(LogLevelWarn, "command timeout: ignoring late manual result",
+		String("cmd", name),
+		String("extension", e.extensionName()),
+	)
+}
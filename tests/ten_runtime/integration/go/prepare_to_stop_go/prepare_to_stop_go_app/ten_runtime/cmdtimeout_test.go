@@ -0,0 +1,163 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// neverRespondsExtension arms a short SetCommandTimeout and then never
+// calls ReturnResult itself, simulating the buggy extension this feature
+// protects a graph from.
+type neverRespondsExtension struct {
+	DefaultExtension
+	timeout time.Duration
+}
+
+func (e *neverRespondsExtension) OnCmd(tenEnv TenEnv, cmd Cmd) {
+	tenEnv.SetCommandTimeout(cmd, e.timeout)
+}
+
+func TestTenEnv_SetCommandTimeoutAutoReturnsWhenHandlerNeverResponds(t *testing.T) {
+	engine := NewEngine(&neverRespondsExtension{timeout: 5 * time.Millisecond})
+	engine.Init()
+	engine.Start()
+
+	buf := &lockedBuffer{}
+	engine.TenEnv().SetLogSink(buf, LogFormatText)
+
+	cmd, _ := NewCmd("ask")
+	if err := engine.DeliverCmd(cmd); err != nil {
+		t.Fatalf("DeliverCmd: %v", err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for !strings.Contains(buf.String(), "command timeout") && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if got := buf.String(); !strings.Contains(got, "command timeout: auto-returning") {
+		t.Fatalf("sink content = %q, want a command-timeout auto-return warning", got)
+	}
+	waitForInflightCount(t, engine, 0)
+}
+
+// fireCommandTimeout reaches ReturnError with the literal detail
+// "command timeout"; this locks that string in directly, the same way
+// TestTenEnv_ReturnErrorDrainingDetailMatchesWhatAdmitThroughDrainSends
+// pins down BeginDrain's "draining" detail -- by calling ReturnError on a
+// resultCapturingTenEnv rather than through Engine.DeliverCmd, since
+// Engine.tenEnv is a concrete *tenEnvImpl a wrapper can't intercept from
+// inside the engine's own dispatch path.
+func TestTenEnv_ReturnErrorCommandTimeoutDetailMatchesWhatFireCommandTimeoutSends(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+	engine.Init()
+	engine.Start()
+
+	cmd, _ := NewCmd("ask")
+	rte := &resultCapturingTenEnv{tenEnvImpl: engine.tenEnv}
+	rte.ReturnError(cmd, "command timeout")
+
+	status, _ := rte.returned.StatusCode()
+	if status != StatusCodeError {
+		t.Fatalf("StatusCode = %v, want StatusCodeError", status)
+	}
+	detail, _ := rte.returned.GetPropertyString("detail")
+	if detail != "command timeout" {
+		t.Fatalf("detail = %q, want %q", detail, "command timeout")
+	}
+}
+
+// respondsInTimeExtension answers well within its own SetCommandTimeout
+// deadline, so the watchdog should never fire.
+type respondsInTimeExtension struct {
+	DefaultExtension
+	timeout time.Duration
+}
+
+func (e *respondsInTimeExtension) OnCmd(tenEnv TenEnv, cmd Cmd) {
+	tenEnv.SetCommandTimeout(cmd, e.timeout)
+	tenEnv.ReturnOK(cmd, "done")
+}
+
+func TestTenEnv_SetCommandTimeoutDoesNothingWhenHandlerRespondsInTime(t *testing.T) {
+	engine := NewEngine(&respondsInTimeExtension{timeout: time.Hour})
+	engine.Init()
+	engine.Start()
+
+	buf := &lockedBuffer{}
+	engine.TenEnv().SetLogSink(buf, LogFormatText)
+
+	cmd, _ := NewCmd("ask")
+	if err := engine.DeliverCmd(cmd); err != nil {
+		t.Fatalf("DeliverCmd: %v", err)
+	}
+
+	if got := buf.String(); strings.Contains(got, "command timeout") {
+		t.Fatalf("sink content = %q, did not expect a command-timeout warning", got)
+	}
+}
+
+// racingManualReturnExtension arms a very short timeout, then blocks past
+// it before finally calling ReturnResult itself, so a test can observe
+// the manual return losing the race and being discarded.
+type racingManualReturnExtension struct {
+	DefaultExtension
+	release chan struct{}
+	done    chan struct{}
+}
+
+func (e *racingManualReturnExtension) OnCmd(tenEnv TenEnv, cmd Cmd) {
+	tenEnv.SetCommandTimeout(cmd, 5*time.Millisecond)
+	<-e.release
+	tenEnv.ReturnOK(cmd, "too late")
+	close(e.done)
+}
+
+func TestTenEnv_SetCommandTimeoutDiscardsALateRacingManualReturn(t *testing.T) {
+	ext := &racingManualReturnExtension{release: make(chan struct{}), done: make(chan struct{})}
+	engine := NewEngine(ext)
+	engine.Init()
+	engine.Start()
+
+	buf := &lockedBuffer{}
+	engine.TenEnv().SetLogSink(buf, LogFormatText)
+
+	cmd, _ := NewCmd("ask")
+	go engine.DeliverCmd(cmd)
+
+	// Give the watchdog time to fire before the handler's own return.
+	time.Sleep(20 * time.Millisecond)
+	close(ext.release)
+	<-ext.done
+
+	got := buf.String()
+	if !strings.Contains(got, "command timeout: auto-returning") {
+		t.Fatalf("sink content = %q, want the watchdog to have auto-returned first", got)
+	}
+	if !strings.Contains(got, "command timeout: ignoring late manual result") {
+		t.Fatalf("sink content = %q, want the late manual return to be logged as discarded", got)
+	}
+}
+
+func TestTenEnv_SetCommandTimeoutReplacesAPreviousDeadlineForTheSameCmd(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+	engine.Init()
+	engine.Start()
+
+	buf := &lockedBuffer{}
+	engine.TenEnv().SetLogSink(buf, LogFormatText)
+
+	cmd, _ := NewCmd("ask")
+	engine.TenEnv().SetCommandTimeout(cmd, 5*time.Millisecond)
+	engine.TenEnv().SetCommandTimeout(cmd, time.Hour)
+	engine.TenEnv().ReturnOK(cmd, "done")
+
+	time.Sleep(20 * time.Millisecond)
+	if got := buf.String(); strings.Contains(got, "command timeout") {
+		t.Fatalf("sink content = %q, want the replaced (short) deadline to never fire", got)
+	}
+}
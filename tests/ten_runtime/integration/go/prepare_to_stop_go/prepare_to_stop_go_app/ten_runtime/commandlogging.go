@@ -0,0 +1,47 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+// correlationIDFor returns the correlation ID for cmd, reusing the same
+// reserved trace-ID property StartSpan reads and writes (see trace.go)
+// so a single ID identifies a command's flow through the graph for both
+// tracing and logging: if cmd already carries one, from an upstream
+// StartSpan or WithCommandLogging call, it's reused; otherwise a fresh
+// one is generated and stamped onto cmd so it propagates to whatever's
+// downstream.
+func correlationIDFor(cmd Cmd) string {
+	if cmd == nil {
+		return newTraceID().String()
+	}
+	if id, err := cmd.GetPropertyString(tracePropTraceID); err == nil {
+		return id
+	}
+	id := newTraceID()
+	cmd.SetPropertyString(tracePropTraceID, id.String())
+	return id.String()
+}
+
+// commandLoggingTenEnv wraps a TenEnv, adding a "correlation_id" field to
+// every log call so it can be told apart from log lines produced outside
+// the handling of any particular command.
+type commandLoggingTenEnv struct {
+	TenEnv
+	correlationID string
+}
+
+func (c *commandLoggingTenEnv) Log(level LogLevel, msg string) {
+	c.TenEnv.LogFields(level, msg, String("correlation_id", c.correlationID))
+}
+
+func (c *commandLoggingTenEnv) LogFields(level LogLevel, msg string, fields ...Field) {
+	c.TenEnv.LogFields(level, msg, append(fields, String("correlation_id", c.correlationID))...)
+}
+
+func (c *commandLoggingTenEnv) LogLazy(level LogLevel, fn func() string) {
+	if !c.IsLogLevelEnabled(level) {
+		return
+	}
+	c.Log(level, fn())
+}
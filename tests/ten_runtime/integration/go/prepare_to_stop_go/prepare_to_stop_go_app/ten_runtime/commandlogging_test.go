@@ -0,0 +1,139 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import "testing"
+
+// recordingTenEnv embeds a real *tenEnvImpl and overrides LogFields to
+// record its arguments instead of printing them, so a test can inspect
+// exactly what commandLoggingTenEnv passes through.
+type recordingTenEnv struct {
+	*tenEnvImpl
+	msg    string
+	fields []Field
+}
+
+func (r *recordingTenEnv) LogFields(level LogLevel, msg string, fields ...Field) {
+	r.msg = msg
+	r.fields = fields
+}
+
+func TestTenEnv_WithCommandLoggingReusesCorrelationIDAcrossCalls(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+	cmd, _ := NewCmd("greet")
+
+	first := engine.TenEnv().WithCommandLogging(cmd).(*commandLoggingTenEnv)
+	second := engine.TenEnv().WithCommandLogging(cmd).(*commandLoggingTenEnv)
+
+	if first.correlationID != second.correlationID {
+		t.Fatalf("expected the same correlation ID across calls, got %q and %q",
+			first.correlationID, second.correlationID)
+	}
+}
+
+func TestTenEnv_WithCommandLoggingSharesIDWithStartSpan(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+	cmd, _ := NewCmd("greet")
+
+	_, span := engine.TenEnv().StartSpan(cmd)
+	scoped := engine.TenEnv().WithCommandLogging(cmd).(*commandLoggingTenEnv)
+
+	if scoped.correlationID != span.TraceID().String() {
+		t.Fatalf("expected correlation ID %q to match trace ID %q", scoped.correlationID, span.TraceID())
+	}
+}
+
+func TestTenEnv_WithCommandLoggingPropagatesAcrossSendCmd(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+	engine.Init()
+	engine.Start()
+
+	cmd, _ := NewCmd("greet")
+	upstream := engine.TenEnv().WithCommandLogging(cmd).(*commandLoggingTenEnv)
+
+	var downstreamID string
+	engine.SetSender(func(cmd Cmd, cb CmdResultHandler) {
+		downstream := engine.TenEnv().WithCommandLogging(cmd).(*commandLoggingTenEnv)
+		downstreamID = downstream.correlationID
+		result, _ := NewCmdResult(StatusCodeOk, cmd)
+		cb(engine.TenEnv(), result, nil)
+	})
+
+	if err := engine.TenEnv().SendCmd(cmd, func(TenEnv, CmdResult, error) {}); err != nil {
+		t.Fatalf("SendCmd: %v", err)
+	}
+	if downstreamID != upstream.correlationID {
+		t.Fatalf("downstream correlation ID %q doesn't match upstream %q", downstreamID, upstream.correlationID)
+	}
+}
+
+func TestCommandLoggingTenEnv_LogAttachesCorrelationIDField(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+	recorder := &recordingTenEnv{tenEnvImpl: engine.TenEnv().(*tenEnvImpl)}
+	scoped := &commandLoggingTenEnv{TenEnv: recorder, correlationID: "trace-abc"}
+
+	scoped.Log(LogLevelInfo, "handling greet")
+
+	if len(recorder.fields) != 1 || recorder.fields[0] != String("correlation_id", "trace-abc") {
+		t.Fatalf("expected a single correlation_id field, got %+v", recorder.fields)
+	}
+}
+
+func TestCommandLoggingTenEnv_LogFieldsAppendsCorrelationIDField(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+	recorder := &recordingTenEnv{tenEnvImpl: engine.TenEnv().(*tenEnvImpl)}
+	scoped := &commandLoggingTenEnv{TenEnv: recorder, correlationID: "trace-abc"}
+
+	scoped.LogFields(LogLevelInfo, "handling greet", String("user_id", "u1"))
+
+	want := []Field{String("user_id", "u1"), String("correlation_id", "trace-abc")}
+	if len(recorder.fields) != len(want) || recorder.fields[0] != want[0] || recorder.fields[1] != want[1] {
+		t.Fatalf("LogFields fields = %+v, want %+v", recorder.fields, want)
+	}
+}
+
+func TestTenEnv_ForCommandMatchesWithCommandLoggingCorrelationID(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+	cmd, _ := NewCmd("greet")
+
+	fromForCommand := engine.TenEnv().ForCommand(cmd).(*commandLoggingTenEnv)
+	fromWithCommandLogging := engine.TenEnv().WithCommandLogging(cmd).(*commandLoggingTenEnv)
+
+	if fromForCommand.correlationID != fromWithCommandLogging.correlationID {
+		t.Fatalf("ForCommand correlation ID %q, want it to match WithCommandLogging's %q",
+			fromForCommand.correlationID, fromWithCommandLogging.correlationID)
+	}
+}
+
+func TestTenEnv_ForCommandWorksOnAnAlreadyWrappedEnv(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+	cmd, _ := NewCmd("greet")
+
+	wrapped := engine.TenEnv().WithCommandLogging(cmd)
+	again := wrapped.ForCommand(cmd).(*commandLoggingTenEnv)
+
+	if again.correlationID == "" {
+		t.Fatal("ForCommand called on an already-wrapped TenEnv produced an empty correlation ID")
+	}
+}
+
+func TestReturnResult_IsInterchangeableAcrossTenEnvValuesForTheSameEngine(t *testing.T) {
+	// Simulates the scenario CmdResultHandler's doc comment describes: an
+	// extension wraps its own tenEnv with WithCommandLogging for
+	// correlated logging, but the callback it registers is handed a
+	// different (bare) TenEnv value by the sender -- ReturnResult must
+	// still work correctly no matter which of the two is used.
+	engine := NewEngine(DefaultExtension{})
+	engine.Init()
+	engine.Start()
+
+	cmd, _ := NewCmd("greet")
+	logEnv := engine.TenEnv().WithCommandLogging(cmd)
+
+	result, _ := NewCmdResult(StatusCodeOk, cmd)
+	if err := logEnv.ReturnResult(result, cmd); err != nil {
+		t.Fatalf("ReturnResult via the wrapped env: %v", err)
+	}
+}
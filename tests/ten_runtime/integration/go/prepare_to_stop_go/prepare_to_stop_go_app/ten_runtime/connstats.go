@@ -0,0 +1,82 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+// ConnStat reports point-in-time depth and drop bookkeeping for one of
+// this instance's internal message queues. This package models a single
+// extension instance, not the full inter-extension message bus the C
+// runtime maintains, so the only queue it actually holds is
+// DeliverCmd's pendingCmds -- cmds queued for replay while the
+// extension is still starting, or paused with queueWhilePaused. Source
+// and Destination describe that queue's two ends, matching the
+// vocabulary a real per-connection stat would use once this binding
+// grows enough to report on more than one.
+type ConnStat struct {
+	// Source is always "caller" today: this package doesn't track which
+	// upstream extension a queued cmd came from.
+	Source string
+
+	// Destination is this instance's extension name; see
+	// TenEnv.GetExtensionName.
+	Destination string
+
+	// Depth is how many cmds are in the queue right now.
+	Depth int
+
+	// HighWaterMark is the largest Depth has ever been.
+	HighWaterMark int
+
+	// Dropped counts cmds rejected because the queue was at capacity;
+	// see WithPendingCmdQueueCapacity. It's always 0 if no capacity was
+	// ever configured, since an unbounded queue never drops.
+	Dropped int64
+}
+
+// WithPendingCmdQueueCapacity bounds how many cmds DeliverCmd will hold
+// in pendingCmds at once -- queued for replay once the extension
+// finishes starting, or resumes from being paused with
+// queueWhilePaused. A cmd that arrives once the queue is already at
+// capacity is answered immediately with a StatusCodeError result
+// (detail "queue capacity exceeded") instead of growing the queue
+// further, and counted in ConnStat.Dropped, the same
+// reject-before-dispatch shape SetRateLimit and WithDeadlineEnforcement
+// use. capacity <= 0 (the default) leaves the queue unbounded, matching
+// this package's behavior before this option existed.
+func WithPendingCmdQueueCapacity(capacity int) EngineOption {
+	return func(e *Engine) { e.pendingCmdCapacity = capacity }
+}
+
+// enqueuePendingCmdLocked appends cmd to e.pendingCmds if there's room,
+// updating the high-water mark, and reports whether it did. Callers
+// must hold e.mu. A false return means the caller must answer cmd
+// itself -- e.pendingCmdDropped has already been incremented, but cmd
+// hasn't been touched.
+func (e *Engine) enqueuePendingCmdLocked(cmd Cmd) bool {
+	if e.pendingCmdCapacity > 0 && len(e.pendingCmds) >= e.pendingCmdCapacity {
+		e.pendingCmdDropped++
+		return false
+	}
+	e.pendingCmds = append(e.pendingCmds, cmd)
+	if len(e.pendingCmds) > e.pendingCmdHighWater {
+		e.pendingCmdHighWater = len(e.pendingCmds)
+	}
+	return true
+}
+
+// ConnectionStats implements TenEnv.ConnectionStats; see its doc comment
+// for the contract.
+func (t *tenEnvImpl) ConnectionStats() ([]ConnStat, error) {
+	e := t.engine
+	e.mu.Lock()
+	stat := ConnStat{
+		Source:        "caller",
+		Destination:   e.extensionName(),
+		Depth:         len(e.pendingCmds),
+		HighWaterMark: e.pendingCmdHighWater,
+		Dropped:       e.pendingCmdDropped,
+	}
+	e.mu.Unlock()
+	return []ConnStat{stat}, nil
+}
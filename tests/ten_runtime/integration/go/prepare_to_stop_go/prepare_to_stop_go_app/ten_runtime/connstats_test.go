@@ -0,0 +1,102 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import "testing"
+
+func TestTenEnv_ConnectionStatsReportsDepthBeforeStart(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+
+	cmd1, _ := NewCmd("greet")
+	cmd2, _ := NewCmd("greet")
+	engine.DeliverCmd(cmd1)
+	engine.DeliverCmd(cmd2)
+
+	stats, err := engine.TenEnv().ConnectionStats()
+	if err != nil {
+		t.Fatalf("ConnectionStats: %v", err)
+	}
+	if len(stats) != 1 {
+		t.Fatalf("ConnectionStats returned %d entries, want 1", len(stats))
+	}
+	if stats[0].Depth != 2 || stats[0].HighWaterMark != 2 {
+		t.Fatalf("ConnectionStats = %+v, want Depth=2 HighWaterMark=2", stats[0])
+	}
+}
+
+func TestTenEnv_ConnectionStatsHighWaterMarkSurvivesDrain(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+
+	cmd1, _ := NewCmd("greet")
+	cmd2, _ := NewCmd("greet")
+	cmd3, _ := NewCmd("greet")
+	engine.DeliverCmd(cmd1)
+	engine.DeliverCmd(cmd2)
+	engine.DeliverCmd(cmd3)
+
+	engine.Init()
+	engine.Start()
+
+	stats, _ := engine.TenEnv().ConnectionStats()
+	if stats[0].Depth != 0 {
+		t.Fatalf("Depth after Start = %d, want 0 (queue drained)", stats[0].Depth)
+	}
+	if stats[0].HighWaterMark != 3 {
+		t.Fatalf("HighWaterMark after Start = %d, want 3 (peak before drain)", stats[0].HighWaterMark)
+	}
+}
+
+func TestEngine_WithPendingCmdQueueCapacityDropsOverflow(t *testing.T) {
+	engine := NewEngine(countingExtension{calls: make(chan Cmd, 2)}, WithPendingCmdQueueCapacity(1))
+
+	cmd1, _ := NewCmd("greet")
+	cmd2, _ := NewCmd("greet")
+	if err := engine.DeliverCmd(cmd1); err != nil {
+		t.Fatalf("DeliverCmd(cmd1): %v", err)
+	}
+	if err := engine.DeliverCmd(cmd2); err != nil {
+		t.Fatalf("DeliverCmd(cmd2): %v", err)
+	}
+
+	engine.Init()
+	engine.Start()
+
+	ext := engine.tenEnv.engine.ext.(countingExtension)
+	select {
+	case <-ext.calls:
+	default:
+		t.Fatal("cmd1 never reached OnCmd, want it admitted under the capacity-1 queue")
+	}
+	select {
+	case <-ext.calls:
+		t.Fatal("cmd2 reached OnCmd, want it dropped: queue capacity was 1")
+	default:
+	}
+
+	stats, err := engine.TenEnv().ConnectionStats()
+	if err != nil {
+		t.Fatalf("ConnectionStats: %v", err)
+	}
+	if stats[0].Dropped != 1 {
+		t.Fatalf("Dropped = %d, want 1", stats[0].Dropped)
+	}
+}
+
+func TestEngine_WithoutPendingCmdQueueCapacityNeverDrops(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+
+	for i := 0; i < 5; i++ {
+		cmd, _ := NewCmd("greet")
+		engine.DeliverCmd(cmd)
+	}
+
+	stats, _ := engine.TenEnv().ConnectionStats()
+	if stats[0].Dropped != 0 {
+		t.Fatalf("Dropped = %d, want 0 for an unbounded queue", stats[0].Dropped)
+	}
+	if stats[0].Depth != 5 {
+		t.Fatalf("Depth = %d, want 5", stats[0].Depth)
+	}
+}
@@ -0,0 +1,277 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"errors"
+	"sync"
+)
+
+// ErrDataBufLocked is returned by GetBuf/SetBuf while the buffer is held by
+// a LockBuf/UnlockBuf pair.
+var ErrDataBufLocked = errors.New("ten: data buffer is locked")
+
+// ErrDataBufNotLocked is returned by UnlockBuf when called without a
+// matching LockBuf.
+var ErrDataBufNotLocked = errors.New("ten: data buffer is not locked")
+
+// Data represents a data-frame message's payload. Unlike Cmd, it carries no
+// property bag yet -- see the package doc for the scope of this pause/
+// resume-motivated Data introduction -- so it has no PropertyKeys/
+// HasProperty/DeleteProperty either; there's nothing yet for them to
+// enumerate, check or remove. For the same reason it has no
+// PropertiesSnapshot, and no SetPropertyBytes/GetPropertyBytes: there is
+// no property bag to snapshot or to store a blob in. A binary payload on
+// Data belongs in the buffer itself -- see SetBuf/GetBuf/LockBuf.
+type Data interface {
+	Msg
+
+	// SetName/GetName carry an optional name for this Data, the way a
+	// name identifies a Cmd or a real ten_runtime AudioFrame/VideoFrame.
+	// NewData leaves it empty; unlike AudioFrame/VideoFrame's
+	// construction-only name, Data's is a Set/Get pair like SetSeq/GetSeq,
+	// since NewData takes no arguments to set it up front.
+	SetName(name string) error
+	GetName() (string, error)
+
+	// SetTimestamp/GetTimestamp follow the same semantics as AudioFrame's
+	// and VideoFrame's: an opaque, caller-defined timestamp this package
+	// never interprets, defaulting to 0 until set.
+	SetTimestamp(timestamp int64) error
+	GetTimestamp() (int64, error)
+
+	// GetSource follows the same semantics as Cmd.GetSource: the Loc this
+	// Data was sent from, stamped by SendData/SendDataWithAck just before
+	// handing it to the sender; a Data built via NewData that's never been
+	// sent carries a zero Loc.
+	GetSource() (Loc, error)
+
+	// GetBuf returns a copy of the payload. In this pure-Go simulation
+	// there is no C-owned buffer to alias, so the returned slice never
+	// aliases Data's internal storage; mutating it has no effect on what
+	// Data holds. Use LockBuf for zero-copy access to the live buffer.
+	GetBuf() ([]byte, error)
+
+	// SetBuf replaces the payload with buf. Data takes ownership of buf;
+	// the caller must not mutate it afterwards. It returns
+	// ErrBufferPoolExhausted if SetBufferPool is enforcing a cap that
+	// this call's growth would exceed.
+	SetBuf(buf []byte) error
+
+	// LockBuf grants zero-copy access to the live payload: the returned
+	// slice aliases Data's internal storage directly, and stays valid
+	// until the matching UnlockBuf call. GetBuf and SetBuf return
+	// ErrDataBufLocked while the buffer is locked. A second LockBuf before
+	// the first is unlocked also returns ErrDataBufLocked.
+	LockBuf() ([]byte, error)
+
+	// UnlockBuf releases a buffer acquired via LockBuf. It returns
+	// ErrDataBufNotLocked if the buffer isn't currently locked.
+	UnlockBuf() error
+
+	// Clone returns a deep copy of Data: same name and timestamp, a copy
+	// of the current buffer independent of this Data's own lock state, as
+	// a new, unlocked Data. It's for broadcasting the same payload to
+	// several destinations without them fighting over one buffer via
+	// LockBuf. The clone's source is reset to a zero Loc rather than
+	// copied, the same as Cmd.Clone, since it hasn't been sent anywhere
+	// yet.
+	Clone() (Data, error)
+
+	// SetProtoBuf and GetProtoBuf are declared in protobuf.go; see
+	// ProtoMessage's doc comment for why they take a local interface
+	// instead of google.golang.org/protobuf/proto.Message.
+	SetProtoBuf(m ProtoMessage) error
+	GetProtoBuf(m ProtoMessage) error
+
+	// SetSeq and GetSeq carry this chunk's position within a stream split
+	// across several Data messages; they default to 0, indistinguishable
+	// from an explicitly-set first chunk -- callers that split a payload
+	// are expected to set it on every chunk, not just later ones. See
+	// StreamReassembler in streamreassembler.go for the receiver side.
+	SetSeq(n uint64)
+	GetSeq() uint64
+
+	// SetEndOfStream and IsEndOfStream mark whichever chunk is the last
+	// one in a stream, telling a StreamReassembler it has seen every
+	// sequence number it's going to get once the gap up to this chunk's
+	// GetSeq closes.
+	SetEndOfStream(eos bool)
+	IsEndOfStream() bool
+
+	// GetDests and SetDests work exactly like Cmd's GetDests/SetDests:
+	// they let a router extension record where this Data is headed
+	// without a real graph to route it, and SendData/SendDataWithAck read
+	// the first entry's ExtensionName as the destination for queue-depth
+	// tracking. An empty slice means no destination was set.
+	GetDests() ([]Loc, error)
+	SetDests(dests ...Loc) error
+}
+
+type dataImpl struct {
+	mu     sync.Mutex
+	buf    []byte
+	locked bool
+
+	// name and timestamp are set by SetName/SetTimestamp.
+	name      string
+	timestamp int64
+
+	// protoTypeURL is set by SetProtoBuf and validated by GetProtoBuf; see
+	// protobuf.go. Empty means no protobuf payload has been set.
+	protoTypeURL string
+
+	// seq and endOfStream are set by SetSeq/SetEndOfStream; see
+	// streamreassembler.go.
+	seq         uint64
+	endOfStream bool
+
+	// dests is set by SetDests; see senddata.go. source is stamped by
+	// stampDataSource; see its doc comment in loc.go.
+	dests  []Loc
+	source Loc
+}
+
+// NewData creates a new, empty Data.
+func NewData() (Data, error) {
+	return &dataImpl{}, nil
+}
+
+func (d *dataImpl) GetType() (MsgType, error) {
+	return MsgTypeData, nil
+}
+
+func (d *dataImpl) SetName(name string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.name = name
+	return nil
+}
+
+func (d *dataImpl) GetName() (string, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.name, nil
+}
+
+func (d *dataImpl) SetTimestamp(timestamp int64) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.timestamp = timestamp
+	return nil
+}
+
+func (d *dataImpl) GetTimestamp() (int64, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.timestamp, nil
+}
+
+func (d *dataImpl) GetSource() (Loc, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.source, nil
+}
+
+func (d *dataImpl) GetBuf() ([]byte, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.locked {
+		return nil, ErrDataBufLocked
+	}
+	out := make([]byte, len(d.buf))
+	copy(out, d.buf)
+	return out, nil
+}
+
+func (d *dataImpl) SetBuf(buf []byte) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.locked {
+		return ErrDataBufLocked
+	}
+	if err := reserveBufferPoolDelta(len(buf) - len(d.buf)); err != nil {
+		return err
+	}
+	d.buf = buf
+	// A raw SetBuf replaces whatever protobuf payload (if any) SetProtoBuf
+	// had stored, so GetProtoBuf must no longer claim a match against it.
+	d.protoTypeURL = ""
+	return nil
+}
+
+func (d *dataImpl) LockBuf() ([]byte, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.locked {
+		return nil, ErrDataBufLocked
+	}
+	d.locked = true
+	return d.buf, nil
+}
+
+func (d *dataImpl) UnlockBuf() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if !d.locked {
+		return ErrDataBufNotLocked
+	}
+	d.locked = false
+	return nil
+}
+
+func (d *dataImpl) Clone() (Data, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return &dataImpl{
+		buf:          append([]byte(nil), d.buf...),
+		name:         d.name,
+		timestamp:    d.timestamp,
+		protoTypeURL: d.protoTypeURL,
+		seq:          d.seq,
+		endOfStream:  d.endOfStream,
+		dests:        append([]Loc(nil), d.dests...),
+		// source is reset to a zero Loc, the same as Cmd.Clone: the clone
+		// hasn't been sent anywhere yet, so SendData/SendDataWithAck will
+		// stamp it fresh.
+	}, nil
+}
+
+func (d *dataImpl) GetDests() ([]Loc, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]Loc(nil), d.dests...), nil
+}
+
+func (d *dataImpl) SetDests(dests ...Loc) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.dests = append([]Loc(nil), dests...)
+	return nil
+}
+
+func (d *dataImpl) SetSeq(n uint64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.seq = n
+}
+
+func (d *dataImpl) GetSeq() uint64 {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.seq
+}
+
+func (d *dataImpl) SetEndOfStream(eos bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.endOfStream = eos
+}
+
+func (d *dataImpl) IsEndOfStream() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.endOfStream
+}
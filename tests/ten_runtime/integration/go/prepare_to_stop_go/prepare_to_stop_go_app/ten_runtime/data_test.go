@@ -0,0 +1,249 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestData_GetBufReturnsCopyNotAlias(t *testing.T) {
+	data, _ := NewData()
+	data.SetBuf([]byte("hello"))
+
+	got, err := data.GetBuf()
+	if err != nil {
+		t.Fatalf("GetBuf: %v", err)
+	}
+	got[0] = 'H'
+
+	got2, _ := data.GetBuf()
+	if string(got2) != "hello" {
+		t.Fatalf("GetBuf() after mutating a prior copy = %q, want %q", got2, "hello")
+	}
+}
+
+func TestData_LockBufAliasesLiveBuffer(t *testing.T) {
+	data, _ := NewData()
+	data.SetBuf([]byte("hello"))
+
+	locked, err := data.LockBuf()
+	if err != nil {
+		t.Fatalf("LockBuf: %v", err)
+	}
+	locked[0] = 'H'
+
+	if err := data.UnlockBuf(); err != nil {
+		t.Fatalf("UnlockBuf: %v", err)
+	}
+	got, _ := data.GetBuf()
+	if string(got) != "Hello" {
+		t.Fatalf("GetBuf() after LockBuf mutation = %q, want %q", got, "Hello")
+	}
+}
+
+func TestData_GetBufAndSetBufRejectedWhileLocked(t *testing.T) {
+	data, _ := NewData()
+	if _, err := data.LockBuf(); err != nil {
+		t.Fatalf("LockBuf: %v", err)
+	}
+
+	if _, err := data.GetBuf(); !errors.Is(err, ErrDataBufLocked) {
+		t.Fatalf("GetBuf() while locked = %v, want ErrDataBufLocked", err)
+	}
+	if err := data.SetBuf([]byte("x")); !errors.Is(err, ErrDataBufLocked) {
+		t.Fatalf("SetBuf() while locked = %v, want ErrDataBufLocked", err)
+	}
+	if _, err := data.LockBuf(); !errors.Is(err, ErrDataBufLocked) {
+		t.Fatalf("second LockBuf() = %v, want ErrDataBufLocked", err)
+	}
+}
+
+func TestData_UnlockBufWithoutLockIsError(t *testing.T) {
+	data, _ := NewData()
+	if err := data.UnlockBuf(); !errors.Is(err, ErrDataBufNotLocked) {
+		t.Fatalf("UnlockBuf() without LockBuf = %v, want ErrDataBufNotLocked", err)
+	}
+}
+
+func TestData_CloneCopiesBufferIndependently(t *testing.T) {
+	data, _ := NewData()
+	data.SetBuf([]byte("hello"))
+
+	clone, err := data.Clone()
+	if err != nil {
+		t.Fatalf("Clone: %v", err)
+	}
+	clone.SetBuf([]byte("goodbye"))
+
+	original, _ := data.GetBuf()
+	if string(original) != "hello" {
+		t.Fatalf("original buf = %q after mutating clone, want unchanged %q", original, "hello")
+	}
+}
+
+func TestData_CloneOfLockedDataStartsUnlocked(t *testing.T) {
+	data, _ := NewData()
+	data.SetBuf([]byte("hello"))
+	if _, err := data.LockBuf(); err != nil {
+		t.Fatalf("LockBuf: %v", err)
+	}
+
+	clone, err := data.Clone()
+	if err != nil {
+		t.Fatalf("Clone: %v", err)
+	}
+	got, err := clone.GetBuf()
+	if err != nil {
+		t.Fatalf("GetBuf on clone of a locked Data: %v", err)
+	}
+	if string(got) != "hello" {
+		t.Fatalf("clone buf = %q, want %q", got, "hello")
+	}
+}
+
+func TestDefaultExtensionOnData_IsNoOp(t *testing.T) {
+	data, _ := NewData()
+	// OnData's default must not touch tenEnv at all; passing nil exercises
+	// that guarantee directly rather than merely observing no panic
+	// through a real one.
+	DefaultExtension{}.OnData(nil, data)
+}
+
+// fakeProtoMessage is a minimal ProtoMessage stand-in for tests -- a real
+// generated message would marshal to actual protobuf wire bytes, but
+// SetProtoBuf/GetProtoBuf don't care about the wire format itself, only
+// that Marshal/Unmarshal/ProtoMessageName round-trip consistently.
+type fakeProtoMessage struct {
+	typeName string
+	Value    string
+}
+
+func (m *fakeProtoMessage) ProtoMessageName() string { return m.typeName }
+
+func (m *fakeProtoMessage) Marshal() ([]byte, error) { return []byte(m.Value), nil }
+
+func (m *fakeProtoMessage) Unmarshal(buf []byte) error {
+	m.Value = string(buf)
+	return nil
+}
+
+func TestData_SetProtoBufThenGetProtoBufRoundTrips(t *testing.T) {
+	data, _ := NewData()
+	in := &fakeProtoMessage{typeName: "myapp.v1.SensorReading", Value: "42C"}
+	if err := data.SetProtoBuf(in); err != nil {
+		t.Fatalf("SetProtoBuf: %v", err)
+	}
+
+	out := &fakeProtoMessage{typeName: "myapp.v1.SensorReading"}
+	if err := data.GetProtoBuf(out); err != nil {
+		t.Fatalf("GetProtoBuf: %v", err)
+	}
+	if out.Value != "42C" {
+		t.Fatalf("GetProtoBuf() decoded value = %q, want %q", out.Value, "42C")
+	}
+}
+
+func TestData_GetProtoBufRejectsMismatchedType(t *testing.T) {
+	data, _ := NewData()
+	if err := data.SetProtoBuf(&fakeProtoMessage{typeName: "myapp.v1.SensorReading", Value: "42C"}); err != nil {
+		t.Fatalf("SetProtoBuf: %v", err)
+	}
+
+	err := data.GetProtoBuf(&fakeProtoMessage{typeName: "myapp.v1.Alert"})
+	if err == nil {
+		t.Fatalf("GetProtoBuf() with mismatched type = nil error, want a descriptive error")
+	}
+}
+
+func TestData_GetProtoBufWithoutSetProtoBufIsError(t *testing.T) {
+	data, _ := NewData()
+	if err := data.GetProtoBuf(&fakeProtoMessage{typeName: "myapp.v1.SensorReading"}); err == nil {
+		t.Fatalf("GetProtoBuf() before any SetProtoBuf = nil error, want a descriptive error")
+	}
+}
+
+func TestData_SetProtoBufRejectedWhileLocked(t *testing.T) {
+	data, _ := NewData()
+	if _, err := data.LockBuf(); err != nil {
+		t.Fatalf("LockBuf: %v", err)
+	}
+
+	if err := data.SetProtoBuf(&fakeProtoMessage{typeName: "myapp.v1.SensorReading"}); !errors.Is(err, ErrDataBufLocked) {
+		t.Fatalf("SetProtoBuf() while locked = %v, want ErrDataBufLocked", err)
+	}
+	if err := data.GetProtoBuf(&fakeProtoMessage{typeName: "myapp.v1.SensorReading"}); !errors.Is(err, ErrDataBufLocked) {
+		t.Fatalf("GetProtoBuf() while locked = %v, want ErrDataBufLocked", err)
+	}
+}
+
+func TestData_SetBufClearsPriorProtoBufType(t *testing.T) {
+	data, _ := NewData()
+	if err := data.SetProtoBuf(&fakeProtoMessage{typeName: "myapp.v1.SensorReading", Value: "42C"}); err != nil {
+		t.Fatalf("SetProtoBuf: %v", err)
+	}
+	if err := data.SetBuf([]byte("raw bytes")); err != nil {
+		t.Fatalf("SetBuf: %v", err)
+	}
+
+	if err := data.GetProtoBuf(&fakeProtoMessage{typeName: "myapp.v1.SensorReading"}); err == nil {
+		t.Fatalf("GetProtoBuf() after a plain SetBuf = nil error, want it to no longer claim the old proto type")
+	}
+}
+
+func TestData_SetSeqAndSetEndOfStreamRoundTrip(t *testing.T) {
+	data, _ := NewData()
+	if got := data.GetSeq(); got != 0 {
+		t.Fatalf("GetSeq() on a fresh Data = %d, want 0", got)
+	}
+	if data.IsEndOfStream() {
+		t.Fatalf("IsEndOfStream() on a fresh Data = true, want false")
+	}
+
+	data.SetSeq(7)
+	data.SetEndOfStream(true)
+	if got := data.GetSeq(); got != 7 {
+		t.Fatalf("GetSeq() = %d, want 7", got)
+	}
+	if !data.IsEndOfStream() {
+		t.Fatalf("IsEndOfStream() = false, want true")
+	}
+}
+
+func TestData_CloneCarriesSeqAndEndOfStream(t *testing.T) {
+	data, _ := NewData()
+	data.SetSeq(3)
+	data.SetEndOfStream(true)
+
+	clone, err := data.Clone()
+	if err != nil {
+		t.Fatalf("Clone: %v", err)
+	}
+	if got := clone.GetSeq(); got != 3 {
+		t.Fatalf("clone GetSeq() = %d, want 3", got)
+	}
+	if !clone.IsEndOfStream() {
+		t.Fatalf("clone IsEndOfStream() = false, want true")
+	}
+}
+
+func TestData_CloneCarriesProtoBufType(t *testing.T) {
+	data, _ := NewData()
+	if err := data.SetProtoBuf(&fakeProtoMessage{typeName: "myapp.v1.SensorReading", Value: "42C"}); err != nil {
+		t.Fatalf("SetProtoBuf: %v", err)
+	}
+
+	clone, err := data.Clone()
+	if err != nil {
+		t.Fatalf("Clone: %v", err)
+	}
+	out := &fakeProtoMessage{typeName: "myapp.v1.SensorReading"}
+	if err := clone.GetProtoBuf(out); err != nil {
+		t.Fatalf("GetProtoBuf on clone: %v", err)
+	}
+	if out.Value != "42C" {
+		t.Fatalf("clone decoded value = %q, want %q", out.Value, "42C")
+	}
+}
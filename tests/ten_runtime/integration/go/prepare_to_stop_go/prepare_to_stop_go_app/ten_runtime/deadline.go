@@ -0,0 +1,56 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"context"
+	"time"
+)
+
+// WithDeadlineEnforcement opts an Engine into checking Cmd.GetDeadline on
+// every DeliverCmd/DeliverCmdWithContext call: a cmd whose deadline has
+// already passed by the time it arrives is short-circuited with a
+// StatusCodeError "deadline exceeded" result instead of ever reaching
+// OnCmd, the same reject-before-dispatch pattern SetRateLimit's
+// RateLimitModeReject uses. It's off by default -- not every extension
+// sets deadlines, and unconditionally rejecting a cmd that carries no
+// deadline (or one from a caller that never adopted SetDeadline) would be
+// wrong, so this is opt-in the same way rate limiting is.
+func WithDeadlineEnforcement(enabled bool) EngineOption {
+	return func(e *Engine) { e.deadlineEnforced = enabled }
+}
+
+// admitThroughDeadline is DeliverCmd's deadline-enforcement gate,
+// mirroring admitThroughRateLimit: if deadline enforcement is enabled and
+// cmd carries a deadline that has already passed, it returns an error
+// result for cmd itself and reports false so DeliverCmd skips dispatch.
+func (e *Engine) admitThroughDeadline(cmd Cmd) bool {
+	if !e.deadlineEnforced {
+		return true
+	}
+	deadline, ok := cmd.GetDeadline()
+	if !ok || deadline.After(time.Now()) {
+		return true
+	}
+	e.tenEnv.ReturnError(cmd, "deadline exceeded")
+	return false
+}
+
+// cmdContextWithDeadline narrows ctx to cmd's own deadline, if cmd carries
+// one and it's earlier than whatever deadline ctx already has (or ctx has
+// none) -- so a context handed back for cmd cancels, and reports
+// Deadline() correctly, the moment either the caller's own context or
+// cmd's end-to-end budget runs out, whichever comes first. A ctx with no
+// narrower cmd deadline is returned unchanged, with a no-op cancel.
+func cmdContextWithDeadline(ctx context.Context, cmd Cmd) (context.Context, context.CancelFunc) {
+	deadline, ok := cmd.GetDeadline()
+	if !ok {
+		return ctx, func() {}
+	}
+	if existing, has := ctx.Deadline(); has && existing.Before(deadline) {
+		return ctx, func() {}
+	}
+	return context.WithDeadline(ctx, deadline)
+}
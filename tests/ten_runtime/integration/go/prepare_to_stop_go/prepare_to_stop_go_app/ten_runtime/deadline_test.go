@@ -0,0 +1,182 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestCmd_GetDeadlineReportsFalseWhenNeverSet(t *testing.T) {
+	cmd, _ := NewCmd("greet")
+	if _, ok := cmd.GetDeadline(); ok {
+		t.Fatal("GetDeadline() ok = true for a cmd that never had SetDeadline called")
+	}
+}
+
+func TestCmd_SetDeadlineRoundTrips(t *testing.T) {
+	cmd, _ := NewCmd("greet")
+	want := time.Now().Add(5 * time.Second)
+
+	if err := cmd.SetDeadline(want); err != nil {
+		t.Fatalf("SetDeadline: %v", err)
+	}
+	got, ok := cmd.GetDeadline()
+	if !ok {
+		t.Fatal("GetDeadline() ok = false after SetDeadline")
+	}
+	if !got.Equal(want) {
+		t.Fatalf("GetDeadline() = %v, want %v", got, want)
+	}
+}
+
+func TestCmd_SetDeadlineIsAReservedProperty(t *testing.T) {
+	cmd, _ := NewCmd("greet")
+	cmd.SetDeadline(time.Now().Add(time.Second))
+	cmd.SetPropertyString("visible", "yes")
+
+	keys, err := cmd.PropertyKeys()
+	if err != nil {
+		t.Fatalf("PropertyKeys: %v", err)
+	}
+	for _, k := range keys {
+		if k != "visible" {
+			t.Fatalf("PropertyKeys = %v, want the deadline hidden the same way trace IDs are", keys)
+		}
+	}
+}
+
+func TestCmd_CloneCopiesDeadline(t *testing.T) {
+	cmd, _ := NewCmd("greet")
+	want := time.Now().Add(10 * time.Second)
+	cmd.SetDeadline(want)
+
+	clone, err := cmd.Clone()
+	if err != nil {
+		t.Fatalf("Clone: %v", err)
+	}
+	got, ok := clone.GetDeadline()
+	if !ok {
+		t.Fatal("cloned cmd lost its deadline")
+	}
+	if !got.Equal(want) {
+		t.Fatalf("cloned GetDeadline() = %v, want %v", got, want)
+	}
+}
+
+func TestTenEnv_CmdContextReflectsCmdDeadline(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+	engine.Init()
+	engine.Start()
+
+	cmd, _ := NewCmd("greet")
+	want := time.Now().Add(time.Hour)
+	cmd.SetDeadline(want)
+
+	ctx := engine.TenEnv().CmdContext(cmd)
+	got, ok := ctx.Deadline()
+	if !ok {
+		t.Fatal("CmdContext(cmd).Deadline() ok = false, want the cmd's own deadline")
+	}
+	if !got.Equal(want) {
+		t.Fatalf("CmdContext(cmd).Deadline() = %v, want %v", got, want)
+	}
+}
+
+func TestTenEnv_CmdContextUsesEarlierOfCallerAndCmdDeadline(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+	engine.Init()
+	engine.Start()
+
+	cmd, _ := NewCmd("greet")
+	cmd.SetDeadline(time.Now().Add(time.Hour))
+
+	callerCtx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+
+	if err := engine.DeliverCmdWithContext(callerCtx, cmd); err != nil {
+		t.Fatalf("DeliverCmdWithContext: %v", err)
+	}
+	ctx := engine.TenEnv().CmdContext(cmd)
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("CmdContext(cmd) never cancelled, want it to inherit the caller's earlier timeout")
+	}
+}
+
+func TestTenEnv_CmdContextWithoutDeadlineNeverCancels(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+	engine.Init()
+	engine.Start()
+
+	cmd, _ := NewCmd("greet")
+	ctx := engine.TenEnv().CmdContext(cmd)
+	if _, ok := ctx.Deadline(); ok {
+		t.Fatal("Deadline() ok = true for a cmd with no deadline and no caller context")
+	}
+}
+
+func TestEngine_WithDeadlineEnforcementRejectsAlreadyExpiredCmd(t *testing.T) {
+	engine := NewEngine(countingExtension{calls: make(chan Cmd, 1)}, WithDeadlineEnforcement(true))
+	engine.Init()
+	engine.Start()
+
+	cmd, _ := NewCmd("greet")
+	cmd.SetDeadline(time.Now().Add(-time.Second))
+
+	if err := engine.DeliverCmd(cmd); err != nil {
+		t.Fatalf("DeliverCmd: %v", err)
+	}
+
+	ext := engine.tenEnv.engine.ext.(countingExtension)
+	select {
+	case <-ext.calls:
+		t.Fatal("OnCmd ran for a cmd delivered past its deadline")
+	default:
+	}
+}
+
+func TestEngine_WithDeadlineEnforcementAdmitsCmdWithTimeRemaining(t *testing.T) {
+	engine := NewEngine(countingExtension{calls: make(chan Cmd, 1)}, WithDeadlineEnforcement(true))
+	engine.Init()
+	engine.Start()
+
+	cmd, _ := NewCmd("greet")
+	cmd.SetDeadline(time.Now().Add(time.Hour))
+
+	if err := engine.DeliverCmd(cmd); err != nil {
+		t.Fatalf("DeliverCmd: %v", err)
+	}
+
+	ext := engine.tenEnv.engine.ext.(countingExtension)
+	select {
+	case <-ext.calls:
+	default:
+		t.Fatal("OnCmd never ran for a cmd with time remaining")
+	}
+}
+
+func TestEngine_WithoutDeadlineEnforcementIgnoresExpiredDeadline(t *testing.T) {
+	engine := NewEngine(countingExtension{calls: make(chan Cmd, 1)})
+	engine.Init()
+	engine.Start()
+
+	cmd, _ := NewCmd("greet")
+	cmd.SetDeadline(time.Now().Add(-time.Second))
+
+	if err := engine.DeliverCmd(cmd); err != nil {
+		t.Fatalf("DeliverCmd: %v", err)
+	}
+
+	ext := engine.tenEnv.engine.ext.(countingExtension)
+	select {
+	case <-ext.calls:
+	default:
+		t.Fatal("OnCmd never ran for an expired-deadline cmd with enforcement disabled")
+	}
+}
@@ -0,0 +1,118 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrExtensionNotDirectCallable is returned by LookupExtension when the
+// resolved instance hasn't opted in via WithDirectCallable(true).
+var ErrExtensionNotDirectCallable = errors.New("ten: extension is not direct-callable")
+
+// WithDirectCallable opts an Engine into being a valid LookupExtension
+// target: without it, LookupExtension refuses to hand out a DirectRef even
+// if the instance name resolves, since an extension that never asked for
+// this has no reason to expect OnCmd to be invoked synchronously from an
+// arbitrary caller goroutine outside its normal dispatch path -- see
+// DirectRef.Call's doc comment for exactly what that exposes it to. This is
+// how a top-level Engine (one constructed with its own NewEngine call) opts
+// in; a CreateExtension-created dynamic instance has no NewEngine call of
+// its own for this to be passed to, so it opts in via DirectCallable
+// instead.
+func WithDirectCallable(enabled bool) EngineOption {
+	return func(e *Engine) {
+		e.directCallable = enabled
+	}
+}
+
+// DirectCallable is an optional interface an Extension can implement to opt
+// itself into being a valid LookupExtension target when it's created via
+// CreateExtension, analogous to AddonLifecycle's "absence is the no-op
+// default" contract (see extension.go) -- an Extension that doesn't
+// implement it simply can't be looked up this way. CreateExtension checks
+// it once, right after constructing the instance's child Engine, the same
+// point a direct NewEngine caller would instead pass WithDirectCallable to
+// that call.
+type DirectCallable interface {
+	WantsDirectCall() bool
+}
+
+// DirectRef is an address-stable handle to another extension instance in
+// the same process, obtained via TenEnv.LookupExtension, for two tightly
+// coupled extensions that can't afford the latency of the ordinary
+// message-passing path. It's an advanced performance escape hatch, not a
+// general substitute for SendCmd/SendCmdToExtension -- see Call's doc
+// comment for its sharp edges before reaching for it.
+type DirectRef struct {
+	engine *Engine
+}
+
+// Call synchronously invokes the target's OnCmd with cmd and blocks until
+// its final CmdResult is returned, going around this package's entire
+// cmd-delivery path: no DeliverCmd queueing, no pause-state gating, no
+// rate limiting or deadline admission control, and -- the whole point --
+// no serialization of cmd into any wire format, since it's handed to the
+// target's OnCmd exactly as-is, in-process. This only works within the
+// same process: there is nothing here analogous to crossing an app or
+// graph boundary the way SendCmdToExtension's own doc comment describes.
+//
+// Threading and reentrancy, read carefully: Call runs the target's OnCmd
+// on the calling goroutine, not on whatever goroutine the target Engine
+// would otherwise treat as its dispatch goroutine, and WithSerialDispatch's
+// guarantee -- that OnCmd is never invoked concurrently with another OnCmd
+// or with OnStop -- does not apply to it, since Call bypasses the very
+// dispatch path that guarantee is enforced around. Two callers invoking
+// Call against the same target concurrently, or one invoking Call while an
+// ordinarily-delivered cmd is also in flight on the target, can run both
+// OnCmd calls at once even under WithSerialDispatch. A cycle of two
+// extensions each holding a DirectRef to the other, calling into each
+// other from inside OnCmd, deadlocks if either expects the other's OnCmd
+// to have returned first -- Call has no cycle detection of its own. An
+// extension that opts in via WithDirectCallable(true) is accepting all of
+// this: its OnCmd must be safe to invoke concurrently with itself and with
+// its own normal cmd delivery, from any goroutine.
+//
+// Call also blocks forever if the target's OnCmd never returns a result
+// for cmd (a fire-and-forget handler that never calls ReturnResult/
+// ReturnResultEx) -- unlike SendCmd, there is no shutdown-deadline
+// enforcement backing Call the way registerPending gives AcceptCommand's
+// JobHandle one, since Call isn't tied to any Engine's own shutdown
+// sequence in particular.
+func (r DirectRef) Call(cmd Cmd) (CmdResult, error) {
+	if cmd == nil {
+		return nil, fmt.Errorf("ten: DirectRef.Call: cmd must not be nil")
+	}
+
+	resultCh := make(chan CmdResultOrError, 1)
+	r.engine.registerCrossExtResultHandler(cmd, func(_ TenEnv, result CmdResult, err error) {
+		resultCh <- CmdResultOrError{Result: result, IsFinal: true, Err: err}
+	})
+
+	r.engine.dispatchOnCmdSync(cmd)
+
+	v := <-resultCh
+	return v.Result, v.Err
+}
+
+// LookupExtension resolves name against the extension instances live in
+// this extension's own graph -- the same registry, and the same scope
+// limitations, SendCmdToExtension resolves extName against -- and, if the
+// resolved instance opted in via WithDirectCallable(true), returns a
+// DirectRef to it. It fails with ErrExtensionInstanceNotFound if name
+// doesn't resolve, or ErrExtensionNotDirectCallable if it resolves to an
+// instance that didn't opt in.
+func (t *tenEnvImpl) LookupExtension(name string) (DirectRef, error) {
+	_, _, graphID, _ := t.engine.identityState()
+	member, ok := dynamicInstanceSnapshot(graphID)[name]
+	if !ok {
+		return DirectRef{}, fmt.Errorf("%w: %q", ErrExtensionInstanceNotFound, name)
+	}
+	if !member.engine.directCallable {
+		return DirectRef{}, fmt.Errorf("%w: %q", ErrExtensionNotDirectCallable, name)
+	}
+	return DirectRef{engine: member.engine}, nil
+}
@@ -0,0 +1,113 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"errors"
+	"testing"
+)
+
+// directEchoExtension is echoExtension plus an opt-in into DirectCallable,
+// for exercising CreateExtension's WantsDirectCall check.
+type directEchoExtension struct {
+	echoExtension
+}
+
+func (directEchoExtension) WantsDirectCall() bool { return true }
+
+func TestTenEnv_LookupExtensionCallRoundTrips(t *testing.T) {
+	reg, err := RegisterAddonAsExtension("synth105_direct", NewDefaultExtensionAddon(
+		func(name string) Extension { return directEchoExtension{} },
+	))
+	if err != nil {
+		t.Fatalf("RegisterAddonAsExtension: %v", err)
+	}
+	defer reg.ReleaseInstance("worker-1")
+	defer reg.Unregister()
+
+	engine := NewEngine(DefaultExtension{}, WithGraphID("g-direct"))
+	engine.Init()
+	engine.Start()
+
+	if _, err := engine.TenEnv().CreateExtension("synth105_direct", "worker-1"); err != nil {
+		t.Fatalf("CreateExtension: %v", err)
+	}
+
+	ref, err := engine.TenEnv().LookupExtension("worker-1")
+	if err != nil {
+		t.Fatalf("LookupExtension: %v", err)
+	}
+
+	cmd, _ := NewCmd("ping")
+	result, err := ref.Call(cmd)
+	if err != nil {
+		t.Fatalf("Call: %v", err)
+	}
+	status, _ := result.StatusCode()
+	if status != StatusCodeOk {
+		t.Fatalf("StatusCode() = %v, want StatusCodeOk", status)
+	}
+}
+
+func TestTenEnv_LookupExtensionFailsForUnknownInstance(t *testing.T) {
+	engine := NewEngine(DefaultExtension{}, WithGraphID("g-direct-missing"))
+	engine.Init()
+	engine.Start()
+
+	if _, err := engine.TenEnv().LookupExtension("no-such-worker"); !errors.Is(err, ErrExtensionInstanceNotFound) {
+		t.Fatalf("LookupExtension(unknown) = %v, want ErrExtensionInstanceNotFound", err)
+	}
+}
+
+func TestTenEnv_LookupExtensionFailsForNonOptedInInstance(t *testing.T) {
+	reg, err := RegisterAddonAsExtension("synth105_not_direct", NewDefaultExtensionAddon(
+		func(name string) Extension { return echoExtension{} },
+	))
+	if err != nil {
+		t.Fatalf("RegisterAddonAsExtension: %v", err)
+	}
+	defer reg.ReleaseInstance("worker-2")
+	defer reg.Unregister()
+
+	engine := NewEngine(DefaultExtension{}, WithGraphID("g-direct-not-opted-in"))
+	engine.Init()
+	engine.Start()
+
+	if _, err := engine.TenEnv().CreateExtension("synth105_not_direct", "worker-2"); err != nil {
+		t.Fatalf("CreateExtension: %v", err)
+	}
+
+	if _, err := engine.TenEnv().LookupExtension("worker-2"); !errors.Is(err, ErrExtensionNotDirectCallable) {
+		t.Fatalf("LookupExtension(non-opted-in) = %v, want ErrExtensionNotDirectCallable", err)
+	}
+}
+
+func TestDirectRef_CallRejectsANilCmd(t *testing.T) {
+	reg, err := RegisterAddonAsExtension("synth105_nil_cmd", NewDefaultExtensionAddon(
+		func(name string) Extension { return directEchoExtension{} },
+	))
+	if err != nil {
+		t.Fatalf("RegisterAddonAsExtension: %v", err)
+	}
+	defer reg.ReleaseInstance("worker-3")
+	defer reg.Unregister()
+
+	engine := NewEngine(DefaultExtension{}, WithGraphID("g-direct-nil"))
+	engine.Init()
+	engine.Start()
+
+	if _, err := engine.TenEnv().CreateExtension("synth105_nil_cmd", "worker-3"); err != nil {
+		t.Fatalf("CreateExtension: %v", err)
+	}
+
+	ref, err := engine.TenEnv().LookupExtension("worker-3")
+	if err != nil {
+		t.Fatalf("LookupExtension: %v", err)
+	}
+
+	if _, err := ref.Call(nil); err == nil {
+		t.Fatal("Call(nil) = nil error, want one")
+	}
+}
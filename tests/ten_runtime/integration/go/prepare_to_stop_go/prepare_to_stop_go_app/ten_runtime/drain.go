@@ -0,0 +1,64 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import "sync/atomic"
+
+// BeginDrain implements TenEnv.BeginDrain; see its doc comment.
+func (t *tenEnvImpl) BeginDrain() {
+	atomic.StoreInt32(&t.engine.draining, 1)
+}
+
+// InflightCount implements TenEnv.InflightCount; see its doc comment.
+func (t *tenEnvImpl) InflightCount() int {
+	return t.engine.inflightCmdCount()
+}
+
+// admitThroughDrain is DeliverCmd's drain-mode gate, mirroring
+// admitThroughRateLimit and admitThroughDeadline: once BeginDrain has run,
+// every newly arriving cmd is answered with a "draining" StatusCodeError
+// result instead of being dispatched, so an operator can watch
+// InflightCount fall to zero and know it's safe to call Stop.
+//
+// Data, AudioFrame and VideoFrame messages are untouched by drain mode:
+// this package has no delivery path for any of them (see the package
+// doc), so there's nothing here for draining to gate -- draining only
+// ever meant something for the one message type DeliverCmd actually
+// delivers.
+func (e *Engine) admitThroughDrain(cmd Cmd) bool {
+	if atomic.LoadInt32(&e.draining) == 0 {
+		return true
+	}
+	e.tenEnv.ReturnError(cmd, "draining")
+	return false
+}
+
+// trackCmdInflight records cmd as dispatched-but-not-yet-finalized, for
+// InflightCount. Called once per dispatchOnCmdSync call, alongside
+// recordCmdDispatched.
+func (e *Engine) trackCmdInflight(cmd Cmd) {
+	e.inflightMu.Lock()
+	defer e.inflightMu.Unlock()
+	if e.inflightCmds == nil {
+		e.inflightCmds = map[Cmd]struct{}{}
+	}
+	e.inflightCmds[cmd] = struct{}{}
+}
+
+// untrackCmdInflight removes cmd from the inflight set, for InflightCount.
+// It's a no-op if cmd was never tracked (e.g. a test that calls OnCmd
+// directly instead of going through DeliverCmd), so InflightCount can
+// never be driven negative.
+func (e *Engine) untrackCmdInflight(cmd Cmd) {
+	e.inflightMu.Lock()
+	defer e.inflightMu.Unlock()
+	delete(e.inflightCmds, cmd)
+}
+
+func (e *Engine) inflightCmdCount() int {
+	e.inflightMu.Lock()
+	defer e.inflightMu.Unlock()
+	return len(e.inflightCmds)
+}
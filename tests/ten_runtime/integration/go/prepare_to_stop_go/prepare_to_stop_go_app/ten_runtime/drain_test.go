@@ -0,0 +1,102 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"testing"
+	"time"
+)
+
+// blockingCmdExtension holds OnCmd open until release is closed, so a test
+// can observe a cmd while it's still in flight instead of only ever seeing
+// it before dispatch or after completion.
+type blockingCmdExtension struct {
+	DefaultExtension
+	release chan struct{}
+}
+
+func (b *blockingCmdExtension) OnCmd(tenEnv TenEnv, cmd Cmd) {
+	<-b.release
+	tenEnv.ReturnOK(cmd, "")
+}
+
+func TestTenEnv_BeginDrainRejectsNewCmds(t *testing.T) {
+	engine := NewEngine(countingExtension{calls: make(chan Cmd, 1)})
+	engine.Init()
+	engine.Start()
+	engine.TenEnv().BeginDrain()
+
+	cmd, _ := NewCmd("greet")
+	if err := engine.DeliverCmd(cmd); err != nil {
+		t.Fatalf("DeliverCmd: %v", err)
+	}
+
+	// Like ratelimit_test.go and deadline_test.go, this checks admission
+	// via whether OnCmd ran rather than inspecting the CmdResult
+	// ReturnError produces -- Engine.tenEnv is a concrete *tenEnvImpl, not
+	// the TenEnv interface, so a result-capturing wrapper like
+	// resultCapturingTenEnv can't be substituted for it here.
+	ext := engine.tenEnv.engine.ext.(countingExtension)
+	select {
+	case <-ext.calls:
+		t.Fatal("OnCmd ran for a cmd delivered after BeginDrain")
+	default:
+	}
+}
+
+func TestTenEnv_ReturnErrorDrainingDetailMatchesWhatAdmitThroughDrainSends(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+	engine.Init()
+	engine.Start()
+
+	cmd, _ := NewCmd("greet")
+	rte := &resultCapturingTenEnv{tenEnvImpl: engine.tenEnv}
+	rte.ReturnError(cmd, "draining")
+
+	status, _ := rte.returned.StatusCode()
+	if status != StatusCodeError {
+		t.Fatalf("StatusCode = %v, want StatusCodeError", status)
+	}
+	detail, _ := rte.returned.GetPropertyString("detail")
+	if detail != "draining" {
+		t.Fatalf("detail = %q, want %q", detail, "draining")
+	}
+}
+
+func TestTenEnv_BeginDrainLeavesAlreadyDispatchedCmdsRunning(t *testing.T) {
+	release := make(chan struct{})
+	ext := &blockingCmdExtension{release: release}
+	engine := NewEngine(ext)
+	engine.Init()
+	engine.Start()
+
+	cmd, _ := NewCmd("greet")
+	go engine.DeliverCmd(cmd)
+	waitForInflightCount(t, engine, 1)
+
+	engine.TenEnv().BeginDrain()
+	if got := engine.TenEnv().InflightCount(); got != 1 {
+		t.Fatalf("InflightCount after BeginDrain = %d, want 1 (already-dispatched cmd unaffected)", got)
+	}
+
+	close(release)
+	waitForInflightCount(t, engine, 0)
+}
+
+// waitForInflightCount polls InflightCount until it matches want, failing
+// the test if it never does -- OnCmd dispatch happens on whatever
+// goroutine DeliverCmd was called from, so a caller driving it via `go`
+// has no other signal for "the dispatch has actually started".
+func waitForInflightCount(t *testing.T, engine *Engine, want int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if engine.TenEnv().InflightCount() == want {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("InflightCount never reached %d", want)
+}
@@ -0,0 +1,235 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+var (
+	// ErrAddonNotRegistered is returned by CreateExtension when addonName
+	// hasn't been registered via RegisterAddonAsExtension.
+	ErrAddonNotRegistered = errors.New("ten: addon not registered")
+
+	// ErrExtensionInstanceNameCollision is returned by CreateExtension
+	// when instanceName is already live within the current graph -- the
+	// same collision a static graph's JSON would refuse to load, just
+	// caught at CreateExtension time instead of at graph-load time.
+	ErrExtensionInstanceNameCollision = errors.New("ten: extension instance name already exists in this graph")
+)
+
+// ConnectionSpec describes one static connection the newly created
+// extension should be wired into its graph with, the way a graph's JSON
+// "connections" section wires two extensions together via a named cmd.
+// Like Cmd's GetDests/SetDests (see loc.go), this simulation has no graph
+// JSON to load such connections from and no multi-extension routing
+// engine to enforce them automatically, so a ConnectionSpec is only
+// recorded on the returned ExtensionHandle, for a generic router/
+// supervisor extension to act on itself -- typically by calling
+// ExtensionHandle.DeliverCmd for a cmd it decides matches CmdName.
+type ConnectionSpec struct {
+	// CmdName is the name of the cmd this connection routes.
+	CmdName string
+}
+
+// ExtensionHandle refers to an extension instance created via
+// TenEnv.CreateExtension, letting its creator interact with it directly
+// (see DeliverCmd) and eventually tear it down via TenEnv.DestroyExtension.
+// The zero ExtensionHandle refers to no instance.
+type ExtensionHandle struct {
+	instanceName string
+	addonName    string
+	graphID      string
+	conns        []ConnectionSpec
+	engine       *Engine
+}
+
+// InstanceName is the name this instance was created with.
+func (h ExtensionHandle) InstanceName() string { return h.instanceName }
+
+// AddonName is the addon this instance was created from.
+func (h ExtensionHandle) AddonName() string { return h.addonName }
+
+// Connections returns the ConnectionSpecs this instance was created with;
+// see ConnectionSpec's doc comment for how they're meant to be acted on.
+func (h ExtensionHandle) Connections() []ConnectionSpec {
+	return append([]ConnectionSpec(nil), h.conns...)
+}
+
+// TenEnv returns the created instance's own TenEnv, e.g. for a supervisor
+// extension that wants to call SendCmd or one of the identity accessors
+// (GetExtensionName and friends, see identity.go) against it directly.
+func (h ExtensionHandle) TenEnv() TenEnv { return h.engine.TenEnv() }
+
+// DeliverCmd hands cmd to the created instance's OnCmd, the same way
+// Engine.DeliverCmd does for a top-level extension. This is how a
+// ConnectionSpec's routing intent is actually carried out today, since
+// CreateExtension doesn't enforce it automatically.
+func (h ExtensionHandle) DeliverCmd(cmd Cmd) error { return h.engine.DeliverCmd(cmd) }
+
+// dynamicMember is what dynamicInstances records for one instance created
+// via CreateExtension -- enough for GetGraphInfo (see graphinfo.go) to
+// report its addon, current lifecycle state, and the connection it was
+// wired into its creator with, without CreateExtension's own signature
+// having to grow a way to look any of that back up.
+type dynamicMember struct {
+	engine      *Engine
+	addonName   string
+	creatorName string
+	conns       []ConnectionSpec
+}
+
+// dynamicInstances tracks which instance names are live within each
+// graph, across every Engine, so CreateExtension can detect a collision
+// the same way a static graph's JSON loader would refuse two nodes with
+// the same name. It's keyed first by graph ID (the empty string when
+// WithGraphID was never configured, which still correctly collides
+// dynamic instances created by Engines that share that same default). A
+// reserved-but-not-yet-registered name (see reserveDynamicInstance) maps
+// to a nil *dynamicMember.
+var dynamicInstances = struct {
+	mu   sync.Mutex
+	live map[string]map[string]*dynamicMember
+}{live: map[string]map[string]*dynamicMember{}}
+
+func reserveDynamicInstance(graphID, instanceName string) bool {
+	dynamicInstances.mu.Lock()
+	defer dynamicInstances.mu.Unlock()
+	members := dynamicInstances.live[graphID]
+	if members == nil {
+		members = map[string]*dynamicMember{}
+		dynamicInstances.live[graphID] = members
+	}
+	if _, exists := members[instanceName]; exists {
+		return false
+	}
+	members[instanceName] = nil
+	return true
+}
+
+// registerDynamicInstance fills in instanceName's entry, reserved earlier
+// by reserveDynamicInstance, once CreateExtension has successfully
+// started it -- so a lookup never observes an instance that failed Init
+// or Start.
+func registerDynamicInstance(graphID, instanceName string, member *dynamicMember) {
+	dynamicInstances.mu.Lock()
+	defer dynamicInstances.mu.Unlock()
+	dynamicInstances.live[graphID][instanceName] = member
+}
+
+func releaseDynamicInstance(graphID, instanceName string) {
+	dynamicInstances.mu.Lock()
+	defer dynamicInstances.mu.Unlock()
+	delete(dynamicInstances.live[graphID], instanceName)
+}
+
+// dynamicInstanceSnapshot returns the fully-registered members of graphID,
+// keyed by instance name, as a copy safe to range over without holding
+// dynamicInstances.mu. Reserved-but-not-yet-registered names are omitted.
+func dynamicInstanceSnapshot(graphID string) map[string]*dynamicMember {
+	dynamicInstances.mu.Lock()
+	defer dynamicInstances.mu.Unlock()
+	out := make(map[string]*dynamicMember, len(dynamicInstances.live[graphID]))
+	for name, member := range dynamicInstances.live[graphID] {
+		if member != nil {
+			out[name] = member
+		}
+	}
+	return out
+}
+
+// CreateExtension instantiates addonName (previously registered via
+// RegisterAddonAsExtension) under instanceName, wires it into the current
+// graph -- the same graph ID and app URI this extension's own
+// GetGraphID/GetAppURI report, see identity.go -- with the given
+// ConnectionSpecs, and drives it through Init and Start before returning,
+// so its lifecycle callbacks (OnConfigure, OnInit, OnStart) have already
+// fired normally by the time CreateExtension returns. It fails with
+// ErrAddonNotRegistered if addonName isn't registered, or
+// ErrExtensionInstanceNameCollision if instanceName is already live
+// within this graph.
+func (t *tenEnvImpl) CreateExtension(
+	addonName, instanceName string, conns ...ConnectionSpec,
+) (ExtensionHandle, error) {
+	addon, ok := GetRegisteredExtensionAddon(addonName)
+	if !ok {
+		return ExtensionHandle{}, fmt.Errorf("%w: %q", ErrAddonNotRegistered, addonName)
+	}
+
+	_, _, graphID, appURI := t.engine.identityState()
+	if !reserveDynamicInstance(graphID, instanceName) {
+		return ExtensionHandle{}, fmt.Errorf(
+			"%w: %q", ErrExtensionInstanceNameCollision, instanceName,
+		)
+	}
+
+	ext := addon.NewInstance(instanceName)
+	child := NewEngine(ext,
+		WithExtensionName(instanceName),
+		WithGraphID(graphID),
+		WithAppURI(appURI),
+	)
+	if dc, ok := ext.(DirectCallable); ok && dc.WantsDirectCall() {
+		child.directCallable = true
+	}
+	if err := child.Init(); err != nil {
+		releaseDynamicInstance(graphID, instanceName)
+		return ExtensionHandle{}, err
+	}
+	if err := child.Start(); err != nil {
+		releaseDynamicInstance(graphID, instanceName)
+		return ExtensionHandle{}, err
+	}
+
+	creatorName, _ := t.GetExtensionName()
+	registerDynamicInstance(graphID, instanceName, &dynamicMember{
+		engine:      child,
+		addonName:   addonName,
+		creatorName: creatorName,
+		conns:       append([]ConnectionSpec(nil), conns...),
+	})
+
+	return ExtensionHandle{
+		instanceName: instanceName,
+		addonName:    addonName,
+		graphID:      graphID,
+		conns:        append([]ConnectionSpec(nil), conns...),
+		engine:       child,
+	}, nil
+}
+
+// DestroyExtension tears handle's instance down: it runs Stop (so OnStop
+// fires and is waited on exactly like a top-level Engine's Stop), then
+// OnDeinit -- closing the gap RegisterAddonAsExtension's own doc comment
+// notes, that nothing in this package otherwise ever calls OnDeinit -- and
+// finally frees instanceName so a later CreateExtension can reuse it. If
+// the addon handle came from is still registered, it also marks this
+// instance no longer live against it, the same bookkeeping
+// Registration.ReleaseInstance does, so Registration.Unregister isn't
+// blocked forever by an instance this call already tore down.
+func (t *tenEnvImpl) DestroyExtension(handle ExtensionHandle) error {
+	if handle.engine == nil {
+		return fmt.Errorf("ten: DestroyExtension: handle refers to no instance")
+	}
+	if err := handle.engine.Stop(); err != nil {
+		return err
+	}
+	handle.engine.callOnDeinit()
+
+	releaseDynamicInstance(handle.graphID, handle.instanceName)
+	if addon, ok := GetRegisteredExtensionAddon(handle.addonName); ok {
+		if ra, ok := addon.(*registeredAddon); ok {
+			ra.release(handle.instanceName)
+		}
+	}
+	return nil
+}
+
+func (e *Engine) callOnDeinit() {
+	defer e.recoverInto("OnDeinit", nil)
+	e.ext.OnDeinit(e.tenEnv)
+}
@@ -0,0 +1,195 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"errors"
+	"testing"
+)
+
+// lifecycleTrackingExtension records which lifecycle callbacks fired, so
+// tests can assert a dynamically created instance goes through the same
+// sequence a top-level Engine drives its extension through.
+type lifecycleTrackingExtension struct {
+	DefaultExtension
+	calls *[]string
+}
+
+func (e lifecycleTrackingExtension) OnConfigure(tenEnv TenEnv) {
+	*e.calls = append(*e.calls, "OnConfigure")
+	tenEnv.OnConfigureDone()
+}
+func (e lifecycleTrackingExtension) OnInit(tenEnv TenEnv) {
+	*e.calls = append(*e.calls, "OnInit")
+	tenEnv.OnInitDone()
+}
+func (e lifecycleTrackingExtension) OnStart(tenEnv TenEnv) {
+	*e.calls = append(*e.calls, "OnStart")
+	tenEnv.OnStartDone()
+}
+func (e lifecycleTrackingExtension) OnStop(tenEnv TenEnv) {
+	*e.calls = append(*e.calls, "OnStop")
+	tenEnv.OnStopDone()
+}
+func (e lifecycleTrackingExtension) OnDeinit(tenEnv TenEnv) {
+	*e.calls = append(*e.calls, "OnDeinit")
+	tenEnv.OnDeinitDone()
+}
+
+func TestTenEnv_CreateExtensionDrivesLifecycleNormally(t *testing.T) {
+	var calls []string
+	reg, err := RegisterAddonAsExtension("synth39_tracking", NewDefaultExtensionAddon(
+		func(name string) Extension { return lifecycleTrackingExtension{calls: &calls} },
+	))
+	if err != nil {
+		t.Fatalf("RegisterAddonAsExtension: %v", err)
+	}
+	defer reg.ReleaseInstance("session-1")
+	defer reg.Unregister()
+
+	engine := NewEngine(DefaultExtension{}, WithGraphID("g-create"))
+	engine.Init()
+	engine.Start()
+
+	handle, err := engine.TenEnv().CreateExtension("synth39_tracking", "session-1")
+	if err != nil {
+		t.Fatalf("CreateExtension: %v", err)
+	}
+	if handle.InstanceName() != "session-1" || handle.AddonName() != "synth39_tracking" {
+		t.Fatalf("handle = %+v, want instance/addon names to match", handle)
+	}
+
+	want := []string{"OnConfigure", "OnInit", "OnStart"}
+	if len(calls) != len(want) {
+		t.Fatalf("lifecycle calls = %v, want %v", calls, want)
+	}
+	for i, name := range want {
+		if calls[i] != name {
+			t.Fatalf("lifecycle calls = %v, want %v", calls, want)
+		}
+	}
+}
+
+func TestTenEnv_CreateExtensionUnregisteredAddonIsError(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+	engine.Init()
+	engine.Start()
+
+	_, err := engine.TenEnv().CreateExtension("no-such-addon", "instance-1")
+	if !errors.Is(err, ErrAddonNotRegistered) {
+		t.Fatalf("CreateExtension error = %v, want ErrAddonNotRegistered", err)
+	}
+}
+
+func TestTenEnv_CreateExtensionNameCollisionIsError(t *testing.T) {
+	reg, err := RegisterAddonAsExtension("synth39_collide", NewDefaultExtensionAddon(
+		func(name string) Extension { return DefaultExtension{} },
+	))
+	if err != nil {
+		t.Fatalf("RegisterAddonAsExtension: %v", err)
+	}
+	defer reg.ReleaseInstance("dup")
+	defer reg.Unregister()
+
+	engine := NewEngine(DefaultExtension{}, WithGraphID("g-collide"))
+	engine.Init()
+	engine.Start()
+
+	if _, err := engine.TenEnv().CreateExtension("synth39_collide", "dup"); err != nil {
+		t.Fatalf("first CreateExtension: %v", err)
+	}
+	_, err = engine.TenEnv().CreateExtension("synth39_collide", "dup")
+	if !errors.Is(err, ErrExtensionInstanceNameCollision) {
+		t.Fatalf("second CreateExtension error = %v, want ErrExtensionInstanceNameCollision", err)
+	}
+}
+
+func TestTenEnv_DestroyExtensionRunsStopAndDeinitThenFreesTheName(t *testing.T) {
+	var calls []string
+	reg, err := RegisterAddonAsExtension("synth39_destroy", NewDefaultExtensionAddon(
+		func(name string) Extension { return lifecycleTrackingExtension{calls: &calls} },
+	))
+	if err != nil {
+		t.Fatalf("RegisterAddonAsExtension: %v", err)
+	}
+	defer reg.Unregister()
+
+	engine := NewEngine(DefaultExtension{}, WithGraphID("g-destroy"))
+	engine.Init()
+	engine.Start()
+
+	handle, err := engine.TenEnv().CreateExtension("synth39_destroy", "session-2")
+	if err != nil {
+		t.Fatalf("CreateExtension: %v", err)
+	}
+	if err := engine.TenEnv().DestroyExtension(handle); err != nil {
+		t.Fatalf("DestroyExtension: %v", err)
+	}
+
+	want := []string{"OnConfigure", "OnInit", "OnStart", "OnStop", "OnDeinit"}
+	if len(calls) != len(want) {
+		t.Fatalf("lifecycle calls = %v, want %v", calls, want)
+	}
+	for i, name := range want {
+		if calls[i] != name {
+			t.Fatalf("lifecycle calls = %v, want %v", calls, want)
+		}
+	}
+
+	// The name is free again, and Unregister no longer sees it as live.
+	if _, err := engine.TenEnv().CreateExtension("synth39_destroy", "session-2"); err != nil {
+		t.Fatalf("CreateExtension after Destroy: %v", err)
+	}
+}
+
+func TestExtensionHandle_ConnectionsAreRecordedNotEnforced(t *testing.T) {
+	reg, err := RegisterAddonAsExtension("synth39_conns", NewDefaultExtensionAddon(
+		func(name string) Extension { return DefaultExtension{} },
+	))
+	if err != nil {
+		t.Fatalf("RegisterAddonAsExtension: %v", err)
+	}
+	defer reg.ReleaseInstance("session-3")
+	defer reg.Unregister()
+
+	engine := NewEngine(DefaultExtension{}, WithGraphID("g-conns"))
+	engine.Init()
+	engine.Start()
+
+	spec := ConnectionSpec{CmdName: "greet"}
+	handle, err := engine.TenEnv().CreateExtension("synth39_conns", "session-3", spec)
+	if err != nil {
+		t.Fatalf("CreateExtension: %v", err)
+	}
+	conns := handle.Connections()
+	if len(conns) != 1 || conns[0] != spec {
+		t.Fatalf("Connections = %+v, want %+v", conns, []ConnectionSpec{spec})
+	}
+}
+
+func TestExtensionHandle_DeliverCmdReachesTheCreatedInstance(t *testing.T) {
+	reg, err := RegisterAddonAsExtension("synth39_deliver", NewDefaultExtensionAddon(
+		func(name string) Extension { return echoExtension{} },
+	))
+	if err != nil {
+		t.Fatalf("RegisterAddonAsExtension: %v", err)
+	}
+	defer reg.ReleaseInstance("session-4")
+	defer reg.Unregister()
+
+	engine := NewEngine(DefaultExtension{}, WithGraphID("g-deliver"))
+	engine.Init()
+	engine.Start()
+
+	handle, err := engine.TenEnv().CreateExtension("synth39_deliver", "session-4")
+	if err != nil {
+		t.Fatalf("CreateExtension: %v", err)
+	}
+
+	cmd, _ := NewCmd("ping")
+	if err := handle.DeliverCmd(cmd); err != nil {
+		t.Fatalf("DeliverCmd: %v", err)
+	}
+}
@@ -0,0 +1,1162 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// runState is the lifecycle state of a single Extension instance as driven
+// by an Engine.
+type runState int
+
+const (
+	stateInit runState = iota
+	stateStarting
+	stateRunning
+	statePausing
+	statePaused
+	stateResuming
+	stateStopping
+	stateStopped
+)
+
+var (
+	// ErrInvalidState is returned when a lifecycle transition is requested
+	// from a state that doesn't support it (e.g. Pause() while stopped).
+	ErrInvalidState = errors.New("ten: invalid state transition")
+
+	// ErrExtensionPaused is returned by DeliverCmd when the extension is
+	// paused and the Engine is configured to reject rather than queue.
+	ErrExtensionPaused = errors.New("ten: extension is paused")
+
+	// ErrCmdAlreadyFinal is returned by ReturnResultEx when a final result
+	// was already returned for the given Cmd.
+	ErrCmdAlreadyFinal = errors.New("ten: cmd already has a final result")
+
+	// ErrShutdownPreempted is returned by Pause/Resume when Stop takes over
+	// the extension mid-transition, e.g. the "pause"/"resume" cmd never
+	// arrives because its peer crashed -- the same failure this package
+	// guards OnStop against, just reachable through Pause/Resume instead.
+	ErrShutdownPreempted = errors.New("ten: pause/resume preempted by shutdown")
+
+	// ErrSendCmdAndWaitOnDispatchGoroutine is returned by SendCmdAndWait
+	// when it's called synchronously from the goroutine the runtime used to
+	// invoke OnCmd, instead of from a goroutine OnCmd spawned. Blocking the
+	// dispatch goroutine there would prevent it from ever processing the
+	// result that would unblock it.
+	ErrSendCmdAndWaitOnDispatchGoroutine = errors.New(
+		"ten: SendCmdAndWait called from the OnCmd dispatch goroutine",
+	)
+
+	// ErrPingOnDispatchGoroutine is TenEnv.Ping's counterpart to
+	// ErrSendCmdAndWaitOnDispatchGoroutine: Ping blocks the same way
+	// SendCmdAndWait does, so calling it synchronously from OnCmd's own
+	// dispatch goroutine would deadlock the same way.
+	ErrPingOnDispatchGoroutine = errors.New(
+		"ten: Ping called from the OnCmd dispatch goroutine",
+	)
+)
+
+// Engine drives a single Extension instance through its lifecycle
+// (init/start/pause/resume/stop) and delivers commands to it, playing the
+// role that the native ten_runtime engine plays for a real graph node.
+//
+// Panic recovery: OnInit, OnStart, OnStop, OnPause, OnResume and OnCmd are
+// each invoked with a recover in place (see WithPanicRecovery to disable
+// it); a panic is logged with its stack trace, and for OnCmd a final
+// StatusCodeError CmdResult with a "panic: ..." detail is returned in the
+// extension's place so the sender still gets an answer. OnData isn't
+// covered -- nothing in this package invokes it yet, see extension.go's
+// doc comment -- and neither is a panic on a goroutine the extension
+// itself spawned from one of these callbacks: recover only catches a
+// panic on the same goroutine that calls it, so a panic on a
+// caller-spawned goroutine still crashes the process, same as it always
+// has.
+type Engine struct {
+	mu     sync.Mutex
+	state  runState
+	ext    Extension
+	tenEnv *tenEnvImpl
+
+	configureDone                 chan struct{}
+	initDone, startDone, stopDone chan struct{}
+	pauseDone, resumeDone         chan struct{}
+
+	// configureDoneOnce/initDoneOnce/startDoneOnce/stopDoneOnce guard the
+	// matching *Done channel against being closed more than once --
+	// OnConfigureDone/OnInitDone/OnStartDone/OnStopDone are idempotent, so
+	// an extension that (racily, or just defensively) signals completion
+	// twice gets a harmless no-op the second time instead of a panic.
+	// pauseDoneOnce/resumeDoneOnce guard pauseDone/resumeDone the same
+	// way, and are replaced alongside those channels at the start of each
+	// Pause/Resume call, since each cycle needs its own.
+	configureDoneOnce, initDoneOnce, startDoneOnce, stopDoneOnce sync.Once
+	pauseDoneOnce, resumeDoneOnce                                sync.Once
+
+	// properties holds whatever was passed to WithProperties, the way a
+	// real ten_runtime extension's property.json is loaded onto it.
+	// propertySchema is set by TenEnv.SetPropertySchema during OnConfigure,
+	// and is what Init validates properties against before calling OnInit.
+	properties     map[string]any
+	propertySchema *propertySchema
+
+	// envInterpolationEnabled is set by TenEnv.EnableEnvInterpolation
+	// during OnConfigure; see interpolateEnvProperties.
+	envInterpolationEnabled bool
+
+	// propertySubs holds the callbacks registered via TenEnv.
+	// OnPropertyChanged, keyed by the property path they subscribed to.
+	// See UpdateProperty for how an update against one path decides which
+	// of these fire.
+	propertySubs map[string][]func(any)
+
+	// appSignalSubs holds the callbacks registered via TenEnv.
+	// OnAppSignal. Unlike propertySubs there's nothing to key them by --
+	// every callback fires on every SignalApp call. See appsignal.go.
+	appSignalSubs []func(AppSignal)
+
+	// stopPreempt is closed by Stop when it takes over from a Pause/Resume
+	// call stuck in statePausing/stateResuming, waking the blocked call so
+	// it can return ErrShutdownPreempted instead of hanging forever.
+	stopPreempt chan struct{}
+
+	// queueWhilePaused controls what DeliverCmd does with cmds that arrive
+	// while the extension is paused: queue them for replay on resume
+	// (true), or reject them immediately (false, the default). There is no
+	// data-frame concept in this package yet, so this only covers cmds.
+	queueWhilePaused bool
+	pendingCmds      []Cmd
+
+	// pendingCmdCapacity, pendingCmdHighWater and pendingCmdDropped back
+	// TenEnv.ConnectionStats and WithPendingCmdQueueCapacity; see
+	// connstats.go. pendingCmdCapacity <= 0 means unbounded.
+	pendingCmdCapacity  int
+	pendingCmdHighWater int
+	pendingCmdDropped   int64
+
+	// draining is set to 1 via TenEnv.BeginDrain; see admitThroughDrain.
+	draining int32
+
+	// inflightMu/inflightCmds back TenEnv.InflightCount: inflightCmds
+	// holds every Cmd currently dispatched to OnCmd but not yet given a
+	// final result. See trackCmdInflight/untrackCmdInflight.
+	inflightMu   sync.Mutex
+	inflightCmds map[Cmd]struct{}
+
+	// slowHandlerThreshold is set via TenEnv.SetSlowHandlerThreshold, in
+	// nanoseconds so it can be read/written with atomic.Load/StoreInt64;
+	// zero (the default) disables slow-handler warnings entirely.
+	slowHandlerThreshold int64
+
+	// handlerStartMu/handlerStart back the slow-handler timer:
+	// handlerStart holds the dispatch time of every cmd that's been timed
+	// but not yet finalized, either because OnCmd hasn't returned yet or
+	// because the extension hasn't called MarkHandlerComplete for async
+	// work it kicked off from OnCmd. See dispatchOnCmdSync and
+	// finishHandlerTiming.
+	handlerStartMu sync.Mutex
+	handlerStart   map[Cmd]time.Time
+
+	// cmdTimeoutMu/cmdTimeouts back TenEnv.SetCommandTimeout: cmdTimeouts
+	// holds the armed watchdog for every cmd that has one outstanding, so
+	// a manual ReturnResult/ReturnResultEx(..., true) can find and race
+	// against it. See cmdtimeout.go.
+	cmdTimeoutMu sync.Mutex
+	cmdTimeouts  map[Cmd]*cmdTimeoutEntry
+
+	// crossExtMu/crossExtPending back SendCmdToExtension: a handler
+	// registered for a cmd this Engine's own extension is about to
+	// receive from a different graph member, invoked once
+	// ReturnResult/ReturnResultEx(..., true) delivers its final result.
+	// See sendcmdtoextension.go.
+	crossExtMu      sync.Mutex
+	crossExtPending map[Cmd]CmdResultHandler
+
+	// clock is consulted by every internal timeout/backoff mechanism that
+	// has been migrated to it -- SetCommandTimeout so far -- instead of
+	// calling the time package directly, so a test can substitute a
+	// tentest.FakeClock. Defaults to realClock; see WithClock and
+	// TenEnv.SetClock.
+	clock Clock
+
+	// sender and exSender simulate the rest of the graph for SendCmd and
+	// SendCmdEx respectively: they are invoked with every Cmd an extension
+	// sends "downstream" and drive handler with the resulting
+	// CmdResult(s). Tests substitute them to simulate single-shot or
+	// streaming responders.
+	sender   func(cmd Cmd, handler CmdResultHandler)
+	exSender func(cmd Cmd, handler CmdResultExHandler)
+
+	// audioFrameSender and videoFrameSender simulate the rest of the graph
+	// for SendAudioFrame and SendVideoFrame: they are invoked with every
+	// frame an extension sends "downstream" and report the outcome via
+	// cb. Tests substitute them to simulate a send failure.
+	audioFrameSender func(frame AudioFrame, cb func(TenEnv, error))
+	videoFrameSender func(frame VideoFrame, cb func(TenEnv, error))
+
+	// dataSender simulates the rest of the graph for SendData and
+	// SendDataWithAck: it is invoked with every Data message an extension
+	// sends "downstream" and reports the outcome via cb. Tests substitute
+	// it to simulate a slow or failing consumer; see senddata.go.
+	dataSender func(data Data, cb func(TenEnv, error))
+
+	// shutdownDeadline bounds how long Stop() waits for OnStopDone before
+	// forcing it; see WithShutdownDeadline.
+	shutdownDeadline time.Duration
+	shutdownCtx      context.Context
+	shutdownCancel   context.CancelFunc
+
+	// startTimeout bounds how long Start() waits for OnStartDone before
+	// failing graph startup instead of hanging; see TenEnv.SetStartTimeout.
+	startTimeout time.Duration
+	startCtx     context.Context
+	startCancel  context.CancelFunc
+
+	// lifeCtx is live for the extension's whole lifetime and is cancelled
+	// as the very first thing Stop does, before OnStop is even called; see
+	// TenEnv.Context for how it differs from shutdownCtx.
+	lifeCtx    context.Context
+	lifeCancel context.CancelFunc
+
+	pendingMu     sync.Mutex
+	pendingCalls  map[uint64]func()
+	nextPendingID uint64
+
+	// dispatchGoroutine holds the ID of the goroutine currently inside
+	// ext.OnCmd (0 when none), so SendCmdAndWait can detect being called
+	// from it; see dispatchOnCmd.
+	dispatchGoroutine int64
+
+	// logFormat controls how TenEnv.LogFields renders its fields; see
+	// WithLogFormat.
+	logFormat LogFormat
+
+	// logLevel is the effective log level threshold IsLogLevelEnabled
+	// checks against; see WithLogLevel. Its zero value is LogLevelVerbose,
+	// so by default every level is enabled.
+	logLevel LogLevel
+
+	// panicRecoveryDisabled inverts WithPanicRecovery's enabled flag so
+	// its zero value keeps recovery on, matching the default described in
+	// that option's doc comment.
+	panicRecoveryDisabled bool
+
+	// metrics is nil unless the Engine was constructed with
+	// WithMetricsRegistry, in which case dispatchOnCmd and ReturnResult/
+	// ReturnResultEx automatically record per-extension command counts and
+	// result latency into it; see recordCmdDispatched/recordCmdCompleted.
+	metrics *MetricsRegistry
+
+	cmdStartMu sync.Mutex
+	cmdStart   map[Cmd]time.Time
+
+	// cmdCtx tracks the originating caller's context for a Cmd delivered
+	// via DeliverCmdWithContext; see TenEnv.CmdContext.
+	cmdCtx cmdCtxTracker
+
+	// localValues tracks the in-process Go value bag attached to a Cmd via
+	// TenEnv.SetLocalValue; see TenEnv.GetLocalValue.
+	localValues cmdLocalValueStore
+
+	// deadlineEnforced is set by WithDeadlineEnforcement; see
+	// admitThroughDeadline in deadline.go.
+	deadlineEnforced bool
+
+	// asyncDispatch is set by WithAsyncDispatch; see dispatchOnCmd.
+	asyncDispatch bool
+
+	// serialDispatch is set by WithSerialDispatch; serialMu is held for
+	// the duration of every OnCmd and OnStop call while it's set, so the
+	// two are never invoked concurrently with each other; see
+	// dispatchOnCmdSync and callOnStop.
+	serialDispatch bool
+	serialMu       sync.Mutex
+
+	// directCallable is set by WithDirectCallable; TenEnv.LookupExtension
+	// refuses to hand out a DirectRef to an Engine that hasn't opted in.
+	// See direct.go.
+	directCallable bool
+
+	// unhandledCmdPolicy is set by WithUnhandledCmdPolicy; dispatchOnCmdSync
+	// enforces it once OnCmd returns without finalizing cmd. See
+	// unhandledcmd.go.
+	unhandledCmdPolicy UnhandledCmdPolicy
+
+	// extensionInstanceName, graphID and appURI are the extension's
+	// identity within its graph, set via WithExtensionName/WithGraphID/
+	// WithAppURI; see GetExtensionName/GetGraphID/GetAppURI.
+	extensionInstanceName string
+	graphID               string
+	appURI                string
+
+	// rateLimiters holds the per-cmd-name token buckets registered via
+	// TenEnv.SetRateLimit, keyed by cmd name; see rateLimiterFor and
+	// admitThroughRateLimit in ratelimit.go.
+	rateLimitersMu sync.Mutex
+	rateLimiters   map[string]*rateLimiter
+
+	// cmdFilters holds the glob/prefix patterns registered via
+	// TenEnv.SetCmdFilter; see matchesCmdFilter and admitThroughCmdFilter
+	// in cmdfilter.go. Empty means filtering was never opted into, so
+	// everything is admitted.
+	cmdFiltersMu sync.Mutex
+	cmdFilters   []string
+
+	// startedAt is when NewEngine created this Engine; see TenEnv.Uptime.
+	startedAt time.Time
+
+	// logWG tracks Log calls currently writing, so TenEnv.FlushLogs has
+	// something concrete to wait on.
+	logWG sync.WaitGroup
+
+	// logSink and logSinkFormat are set by TenEnv.SetLogSink; see
+	// writeToLogSink in logsink.go.
+	logSinkMu     sync.Mutex
+	logSink       io.Writer
+	logSinkFormat LogFormat
+
+	// logBuf* hold TenEnv.SetLogBuffering's configuration and pending
+	// records; see logbuffering.go.
+	logBufMu       sync.Mutex
+	logBufEnabled  bool
+	logBufMaxBatch int
+	logBufMaxDelay time.Duration
+	logBufRecords  []logRecord
+	logBufTimer    *time.Timer
+
+	// dataQueues holds each destination's SendDataWithAck admission state,
+	// keyed by destination extension name ("" for a Data with no dests
+	// set); see dataQueueFor in senddata.go.
+	dataQueuesMu sync.Mutex
+	dataQueues   map[string]*dataQueue
+
+	// cmdConcurrencyLimit and cmdConcurrencyMode are set by
+	// TenEnv.SetCmdConcurrencyLimit; cmdConcurrencyInFlight is the number
+	// of SendCmd/SendCmdEx calls currently holding a slot. See
+	// acquireCmdSlot in cmdconcurrency.go.
+	cmdConcurrencyMu       sync.Mutex
+	cmdConcurrencyCond     *sync.Cond
+	cmdConcurrencyLimit    int
+	cmdConcurrencyMode     CmdConcurrencyMode
+	cmdConcurrencyInFlight int
+}
+
+// NewEngine creates an Engine for ext, ready to be driven through Init,
+// Start, Pause, Resume and Stop.
+func NewEngine(ext Extension, opts ...EngineOption) *Engine {
+	e := &Engine{
+		ext:           ext,
+		configureDone: make(chan struct{}),
+		initDone:      make(chan struct{}),
+		startDone:     make(chan struct{}),
+		stopDone:      make(chan struct{}),
+		pauseDone:     make(chan struct{}),
+		resumeDone:    make(chan struct{}),
+		stopPreempt:   make(chan struct{}),
+		pendingCalls:  make(map[uint64]func()),
+		cmdStart:      make(map[Cmd]time.Time),
+		startedAt:     time.Now(),
+		clock:         realClock{},
+	}
+	e.cmdConcurrencyCond = sync.NewCond(&e.cmdConcurrencyMu)
+	for _, opt := range opts {
+		opt(e)
+	}
+	e.sender = e.defaultSender
+	e.exSender = e.defaultExSender
+	e.audioFrameSender = e.defaultAudioFrameSender
+	e.videoFrameSender = e.defaultVideoFrameSender
+	e.dataSender = e.defaultDataSender
+	e.tenEnv = newTenEnv(e)
+	e.shutdownCtx, e.shutdownCancel = context.WithCancel(context.Background())
+	e.startCtx, e.startCancel = context.WithCancel(context.Background())
+	e.lifeCtx, e.lifeCancel = context.WithCancel(context.Background())
+	return e
+}
+
+// SetQueueWhilePaused configures whether cmds delivered while paused are
+// queued for replay on resume, instead of being rejected outright.
+func (e *Engine) SetQueueWhilePaused(queue bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.queueWhilePaused = queue
+}
+
+// SetLogLevel changes the log level threshold IsLogLevelEnabled, LogLazy
+// and GetLogLevel observe, taking effect immediately for any call made
+// after it returns.
+func (e *Engine) SetLogLevel(level LogLevel) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.logLevel = level
+}
+
+func (e *Engine) getLogLevel() LogLevel {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.logLevel
+}
+
+// SetSender overrides how the Engine simulates the downstream graph for
+// SendCmd. Intended for tests.
+func (e *Engine) SetSender(
+	sender func(cmd Cmd, handler CmdResultHandler),
+) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.sender = sender
+}
+
+// SetExSender overrides how the Engine simulates the downstream graph for
+// SendCmdEx, e.g. to emit a series of non-final results before the final
+// one. Intended for tests.
+func (e *Engine) SetExSender(
+	exSender func(cmd Cmd, handler CmdResultExHandler),
+) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.exSender = exSender
+}
+
+func (e *Engine) defaultSender(cmd Cmd, handler CmdResultHandler) {
+	result, _ := NewCmdResult(StatusCodeOk, cmd)
+	handler(e.tenEnv, result, nil)
+}
+
+func (e *Engine) defaultExSender(cmd Cmd, handler CmdResultExHandler) {
+	result, _ := NewCmdResult(StatusCodeOk, cmd)
+	handler(e.tenEnv, result, true, nil)
+}
+
+// SetAudioFrameSender overrides how the Engine simulates the downstream
+// graph for SendAudioFrame. Intended for tests.
+func (e *Engine) SetAudioFrameSender(
+	sender func(frame AudioFrame, cb func(TenEnv, error)),
+) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.audioFrameSender = sender
+}
+
+// SetVideoFrameSender overrides how the Engine simulates the downstream
+// graph for SendVideoFrame. Intended for tests.
+func (e *Engine) SetVideoFrameSender(
+	sender func(frame VideoFrame, cb func(TenEnv, error)),
+) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.videoFrameSender = sender
+}
+
+func (e *Engine) defaultAudioFrameSender(frame AudioFrame, cb func(TenEnv, error)) {
+	cb(e.tenEnv, nil)
+}
+
+func (e *Engine) defaultVideoFrameSender(frame VideoFrame, cb func(TenEnv, error)) {
+	cb(e.tenEnv, nil)
+}
+
+// SetDataSender overrides how the Engine simulates the downstream graph for
+// SendData and SendDataWithAck. Intended for tests that need to hold cb
+// back to simulate a slow consumer and exercise DataQueueDepth/
+// SetDataQueueCapacity's queue-full behavior.
+func (e *Engine) SetDataSender(
+	sender func(data Data, cb func(TenEnv, error)),
+) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.dataSender = sender
+}
+
+func (e *Engine) defaultDataSender(data Data, cb func(TenEnv, error)) {
+	cb(e.tenEnv, nil)
+}
+
+// TenEnv returns the TenEnv bound to this Engine's Extension instance.
+func (e *Engine) TenEnv() TenEnv {
+	return e.tenEnv
+}
+
+// registerPending tracks an in-flight SendCmd/SendCmdEx call so that
+// cancelPending can invoke onTimeout if the graceful-shutdown deadline
+// expires before it resolves.
+//
+// resolve removes the pending entry and reports whether it was still
+// present, i.e. whether the caller won the race against a timeout
+// cancellation; it should be called from the final completion callback.
+//
+// peek reports whether the entry is still present without removing it; it
+// should be called from any intermediate (non-final) callback so a
+// SendCmdEx streaming responder stops forwarding once the cmd has been
+// force-cancelled.
+func (e *Engine) registerPending(
+	onTimeout func(),
+) (resolve func() bool, peek func() bool) {
+	e.pendingMu.Lock()
+	id := e.nextPendingID
+	e.nextPendingID++
+	e.pendingCalls[id] = onTimeout
+	e.pendingMu.Unlock()
+
+	resolve = func() bool {
+		e.pendingMu.Lock()
+		_, stillPending := e.pendingCalls[id]
+		delete(e.pendingCalls, id)
+		e.pendingMu.Unlock()
+		return stillPending
+	}
+	peek = func() bool {
+		e.pendingMu.Lock()
+		_, stillPending := e.pendingCalls[id]
+		e.pendingMu.Unlock()
+		return stillPending
+	}
+	return resolve, peek
+}
+
+// signalStopDone marks OnStop as acknowledged, exactly once: either the
+// extension calls OnStopDone itself, or the graceful-shutdown deadline
+// forces it on the extension's behalf -- whichever happens first wins, and
+// the other is a no-op. It reports whether this call was the one that
+// closed e.stopDone, so OnStopDone can tell a genuine first signal apart
+// from a redundant later one.
+func (e *Engine) signalStopDone() (closed bool) {
+	e.stopDoneOnce.Do(func() {
+		close(e.stopDone)
+		closed = true
+	})
+	return closed
+}
+
+// signalDoneOnce runs close(ch) at most once, guarded by once, and reports
+// whether this call was the one that closed it. It backs
+// OnConfigureDone/OnInitDone/OnStartDone/OnPauseDone/OnResumeDone, all of
+// which -- like OnStopDone above -- must tolerate an extension calling
+// them more than once (e.g. two goroutines racing to signal the same
+// completion) without panicking on a double close.
+func signalDoneOnce(once *sync.Once, ch chan struct{}) (closed bool) {
+	once.Do(func() {
+		close(ch)
+		closed = true
+	})
+	return closed
+}
+
+// logRedundantDone logs a debug line for a callback-completion signal
+// (OnConfigureDone, OnInitDone, OnStartDone, OnStopDone, OnPauseDone or
+// OnResumeDone) that fired more than once for the same lifecycle step,
+// naming both which one and which extension, so a redundant signal shows
+// up in the log instead of vanishing silently as a no-op.
+func (e *Engine) logRedundantDone(callback string) {
+	e.tenEnv.LogFields(LogLevelDebug, callback+" called more than once, ignoring",
+		String("extension", e.extensionName()),
+	)
+}
+
+// cancelPending force-resolves every still in-flight SendCmd/SendCmdEx call
+// with ErrShutdownTimeout.
+func (e *Engine) cancelPending() {
+	e.pendingMu.Lock()
+	calls := e.pendingCalls
+	e.pendingCalls = make(map[uint64]func())
+	e.pendingMu.Unlock()
+
+	for _, onTimeout := range calls {
+		onTimeout()
+	}
+}
+
+// recoverInto, deferred directly around an extension callback invocation,
+// recovers a panic, logs it with its stack trace via the runtime logger,
+// and calls onRecovered (if non-nil) so the caller can put the Engine back
+// into a consistent state -- e.g. force a Done() the panicking callback
+// never got to call. If WithPanicRecovery(false) disabled recovery, it
+// re-panics instead, so the panic crashes the process exactly as it would
+// have without this package's involvement.
+func (e *Engine) recoverInto(callback string, onRecovered func(recovered any)) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	if e.panicRecoveryDisabled {
+		panic(r)
+	}
+	e.tenEnv.Log(LogLevelError, fmt.Sprintf(
+		"ten: recovered panic in %s: %v\n%s", callback, r, debug.Stack(),
+	))
+	if onRecovered != nil {
+		onRecovered(r)
+	}
+}
+
+func (e *Engine) transition(from, to runState) error {
+	e.mu.Lock()
+	if e.state != from {
+		cur := e.state
+		e.mu.Unlock()
+		return fmt.Errorf(
+			"%w: expected state %d, got %d", ErrInvalidState, from, cur,
+		)
+	}
+	e.state = to
+	e.mu.Unlock()
+	return nil
+}
+
+// Init first runs the extension's OnConfigure callback, blocking until it
+// calls OnConfigureDone, then validates whatever properties were passed via
+// WithProperties against the schema OnConfigure registered (if any) via
+// TenEnv.SetPropertySchema -- failing here, before OnInit ever runs, is how
+// this package catches a config typo at load time instead of at first
+// message. Only once that passes does it run OnInit and block until it
+// calls OnInitDone.
+//
+// A cmd delivered via DeliverCmd any time between here and Start's own
+// OnStartDone is queued, not dropped or delivered early -- see Start's doc
+// comment for the full init -> start -> first command ordering guarantee.
+func (e *Engine) Init() error {
+	if err := e.transition(stateInit, stateStarting); err != nil {
+		return err
+	}
+	e.callOnConfigure()
+	<-e.configureDone
+	if err := e.interpolateEnvProperties(); err != nil {
+		return err
+	}
+	e.applySchemaDefaults()
+	if err := e.validateProperties(); err != nil {
+		return err
+	}
+	e.callOnInit()
+	<-e.initDone
+	emitLifecycleEvent(LifecycleExtensionCreated, e.extensionName())
+	return nil
+}
+
+func (e *Engine) callOnConfigure() {
+	defer e.recoverInto("OnConfigure", func(any) {
+		signalDoneOnce(&e.configureDoneOnce, e.configureDone)
+	})
+	e.ext.OnConfigure(e.tenEnv)
+}
+
+// interpolateEnvProperties expands ${VAR}/${VAR:-default} references in
+// e.properties against the process environment, if OnConfigure called
+// TenEnv.EnableEnvInterpolation(true); see envinterp.go. It runs after
+// OnConfigure and before schema validation, so a missing referenced
+// variable fails Init the same way a schema violation does, rather than
+// letting OnInit see a half-expanded value.
+func (e *Engine) interpolateEnvProperties() error {
+	e.mu.Lock()
+	enabled := e.envInterpolationEnabled
+	props := e.properties
+	e.mu.Unlock()
+	if !enabled || props == nil {
+		return nil
+	}
+	_, err := interpolateEnvValue(props)
+	return err
+}
+
+// applySchemaDefaults fills in schema-declared defaults for any property
+// missing from e.properties, before validateProperties runs -- so a
+// Required property backed by a default doesn't need the graph to supply
+// it explicitly. See applyPropertySchemaDefaults.
+func (e *Engine) applySchemaDefaults() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.properties = applyPropertySchemaDefaults(e.propertySchema, e.properties)
+}
+
+// validateProperties checks e.properties against whatever schema OnConfigure
+// registered via TenEnv.SetPropertySchema, returning
+// ErrPropertySchemaViolation if it doesn't match. A schema that was never
+// registered always passes.
+func (e *Engine) validateProperties() error {
+	e.mu.Lock()
+	schema := e.propertySchema
+	props := e.properties
+	e.mu.Unlock()
+	return validatePropertySchema(schema, props)
+}
+
+func (e *Engine) callOnInit() {
+	defer e.recoverInto("OnInit", func(any) {
+		signalDoneOnce(&e.initDoneOnce, e.initDone)
+	})
+	e.ext.OnInit(e.tenEnv)
+}
+
+// Start runs the extension's OnStart callback and blocks until it calls
+// OnStartDone. The engine only reaches stateRunning once OnStartDone fires
+// -- until then, any cmd DeliverCmd receives (whether during Init, or
+// during Start while OnStartDone is still pending) is queued rather than
+// delivered, and is replayed, in order, immediately after OnStartDone.
+// This is the same queue-then-replay shape Resume uses for cmds that
+// arrive mid-resume, applied here to the init -> start -> first command
+// boundary: an extension's OnStart is always given the chance to finish
+// whatever async setup it needs (e.g. opening a DB connection) before
+// OnCmd ever sees a cmd.
+//
+// If SetStartTimeout was called, Start instead fails fast: once the
+// deadline expires without OnStartDone, it cancels TenEnv.StartContext,
+// logs an error naming the extension, and returns ErrStartTimeout instead
+// of hanging the whole graph's startup on one wedged dependency. Unlike
+// Stop's graceful-shutdown deadline, expiry here doesn't force OnStartDone
+// on the extension's behalf -- there's no safe "pretend it started"
+// outcome, so the caller gets a clear failure and is expected to tear the
+// graph down instead of trying to run it.
+func (e *Engine) Start() error {
+	e.mu.Lock()
+	if e.state != stateStarting {
+		cur := e.state
+		e.mu.Unlock()
+		return fmt.Errorf(
+			"%w: expected state %d, got %d", ErrInvalidState, stateStarting, cur,
+		)
+	}
+	startTimeout := e.startTimeout
+	e.mu.Unlock()
+
+	startCtx, startCancel := newStartContext(startTimeout)
+	e.mu.Lock()
+	e.startCtx, e.startCancel = startCtx, startCancel
+	e.mu.Unlock()
+	defer startCancel()
+
+	e.callOnStart()
+	select {
+	case <-e.startDone:
+	case <-startCtx.Done():
+		e.tenEnv.Log(
+			LogLevelError,
+			fmt.Sprintf(
+				"ten: extension %q did not call OnStartDone within the "+
+					"start timeout (%s); failing graph startup",
+				e.extensionName(), startTimeout,
+			),
+		)
+		return ErrStartTimeout
+	}
+
+	e.mu.Lock()
+	e.state = stateRunning
+	pending := e.pendingCmds
+	e.pendingCmds = nil
+	e.mu.Unlock()
+
+	emitLifecycleEvent(LifecycleExtensionStarted, e.extensionName())
+
+	for _, cmd := range pending {
+		e.dispatchOnCmd(cmd)
+	}
+	return nil
+}
+
+func (e *Engine) callOnStart() {
+	defer e.recoverInto("OnStart", func(any) {
+		signalDoneOnce(&e.startDoneOnce, e.startDone)
+	})
+	e.ext.OnStart(e.tenEnv)
+}
+
+func (e *Engine) callOnStop() {
+	defer e.recoverInto("OnStop", func(any) { e.signalStopDone() })
+	if e.serialDispatch {
+		// Wait out any OnCmd already in flight (and block any that
+		// arrives after) before OnStop runs, so an extension relying on
+		// WithSerialDispatch never sees OnStop overlap an OnCmd call.
+		e.serialMu.Lock()
+		defer e.serialMu.Unlock()
+	}
+	e.ext.OnStop(e.tenEnv)
+}
+
+func (e *Engine) callOnPause(once *sync.Once, done chan struct{}) {
+	defer e.recoverInto("OnPause", func(any) { signalDoneOnce(once, done) })
+	e.ext.OnPause(e.tenEnv)
+}
+
+func (e *Engine) callOnResume(once *sync.Once, done chan struct{}) {
+	defer e.recoverInto("OnResume", func(any) { signalDoneOnce(once, done) })
+	e.ext.OnResume(e.tenEnv)
+}
+
+// Pause suspends the extension: cmd delivery is held back (see DeliverCmd)
+// while existing in-memory state is preserved. It blocks until the
+// extension acknowledges via OnPauseDone.
+//
+// Known limitation: this package has no Data type / data-frame delivery
+// path yet, so only cmds are gated by the pause state; a future
+// data-frame hook would need to be threaded through the same state
+// machine to fully satisfy "stop consuming/producing data frames".
+func (e *Engine) Pause() error {
+	if err := e.transition(stateRunning, statePausing); err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	e.pauseDone = make(chan struct{})
+	e.pauseDoneOnce = sync.Once{}
+	done := e.pauseDone
+	once := &e.pauseDoneOnce
+	preempt := e.stopPreempt
+	e.mu.Unlock()
+
+	e.callOnPause(once, done)
+	select {
+	case <-done:
+	case <-preempt:
+		// Stop already moved the state machine past statePausing on our
+		// behalf; don't race it back to statePaused.
+		return ErrShutdownPreempted
+	}
+
+	e.mu.Lock()
+	if e.state == statePausing {
+		e.state = statePaused
+	}
+	e.mu.Unlock()
+	return nil
+}
+
+// Resume reverses Pause, replaying any cmds queued while paused (if
+// queueWhilePaused is set) once the extension acknowledges via
+// OnResumeDone.
+func (e *Engine) Resume() error {
+	if err := e.transition(statePaused, stateResuming); err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	e.resumeDone = make(chan struct{})
+	e.resumeDoneOnce = sync.Once{}
+	done := e.resumeDone
+	once := &e.resumeDoneOnce
+	preempt := e.stopPreempt
+	e.mu.Unlock()
+
+	e.callOnResume(once, done)
+	select {
+	case <-done:
+	case <-preempt:
+		// Stop already moved the state machine past stateResuming on our
+		// behalf; don't race it back to stateRunning.
+		return ErrShutdownPreempted
+	}
+
+	e.mu.Lock()
+	if e.state != stateResuming {
+		e.mu.Unlock()
+		return nil
+	}
+	e.state = stateRunning
+	pending := e.pendingCmds
+	e.pendingCmds = nil
+	e.mu.Unlock()
+
+	for _, cmd := range pending {
+		e.dispatchOnCmd(cmd)
+	}
+	return nil
+}
+
+// Stop may be called from the running or paused state, or while a Pause/
+// Resume call is still in flight (statePausing/stateResuming) -- a pending
+// or stuck pause/resume must not prevent shutdown, the same "peer crashed
+// and never answered" scenario OnStop's own deadline guards against,
+// reached through a different entry point. In the latter case the blocked
+// Pause/Resume call is woken with ErrShutdownPreempted instead of hanging.
+// It starts the graceful-shutdown deadline (see WithShutdownDeadline) and
+// blocks until the extension acknowledges via OnStopDone, or until the
+// deadline expires, whichever comes first. On expiry it logs a warning,
+// cancels any in-flight SendCmd/SendCmdEx callbacks with
+// ErrShutdownTimeout, and invokes OnStopDone on the extension's behalf.
+// If SetStopWatchdog is armed, a separate, shorter window can also log a
+// named, stack-dumping diagnostic (and optionally force the same
+// teardown) before the graceful-shutdown deadline itself would.
+func (e *Engine) Stop() error {
+	e.mu.Lock()
+	switch e.state {
+	case stateRunning, statePaused:
+		e.state = stateStopping
+	case statePausing, stateResuming:
+		e.state = stateStopping
+		close(e.stopPreempt)
+	default:
+		cur := e.state
+		e.mu.Unlock()
+		return fmt.Errorf(
+			"%w: cannot stop from state %d", ErrInvalidState, cur,
+		)
+	}
+	shutdownCtx, shutdownCancel := newShutdownContext(e.shutdownDeadline)
+	e.shutdownCtx, e.shutdownCancel = shutdownCtx, shutdownCancel
+	e.mu.Unlock()
+	defer shutdownCancel()
+
+	e.SignalApp(AppSignalShuttingDown)
+	e.lifeCancel()
+	e.callOnStop()
+
+	// watchdogFireC logs the named, stack-dumping diagnostic once, at
+	// watchdogWindow; watchdogForceC, armed only if WithStopWatchdogForceAfter
+	// was given, forces the teardown watchdogForceAfter after that -- both
+	// timers are set up front, from the same instant, rather than one
+	// spawned reactively inside the wait below.
+	watchdogWindow, watchdogForceAfter := getStopWatchdog()
+	var watchdogFireC, watchdogForceC <-chan time.Time
+	if watchdogWindow > 0 {
+		fireTimer := time.NewTimer(watchdogWindow)
+		defer fireTimer.Stop()
+		watchdogFireC = fireTimer.C
+		if watchdogForceAfter > 0 {
+			forceTimer := time.NewTimer(watchdogWindow + watchdogForceAfter)
+			defer forceTimer.Stop()
+			watchdogForceC = forceTimer.C
+		}
+	}
+
+waitForStopDone:
+	for {
+		select {
+		case <-e.stopDone:
+			break waitForStopDone
+		case <-watchdogFireC:
+			e.logStopWatchdogTripped(watchdogWindow)
+			watchdogFireC = nil
+		case <-watchdogForceC:
+			e.tenEnv.Log(
+				LogLevelError,
+				fmt.Sprintf(
+					"ten: extension %q still hasn't called OnStopDone %s "+
+						"after the stop watchdog tripped; forcing shutdown",
+					e.extensionName(), watchdogForceAfter,
+				),
+			)
+			e.cancelPending()
+			e.signalStopDone()
+			break waitForStopDone
+		case <-shutdownCtx.Done():
+			e.tenEnv.Log(
+				LogLevelWarn,
+				fmt.Sprintf(
+					"ten: graceful-shutdown deadline (%s) expired before "+
+						"OnStopDone; forcing shutdown",
+					e.shutdownDeadline,
+				),
+			)
+			e.cancelPending()
+			e.signalStopDone()
+			break waitForStopDone
+		}
+	}
+
+	e.tenEnv.FlushLogs()
+
+	e.mu.Lock()
+	e.state = stateStopped
+	e.mu.Unlock()
+	emitLifecycleEvent(LifecycleExtensionStopped, e.extensionName())
+	return nil
+}
+
+// DeliverCmd simulates the runtime handing an incoming Cmd to the
+// extension's OnCmd. Before OnStartDone fires it always queues the cmd for
+// replay once Start completes -- unlike the pause case below, there's no
+// queueWhilePaused-style option to reject early cmds instead, since a cmd
+// delivered before an extension has finished starting isn't a policy
+// choice the way pausing is -- unless WithPendingCmdQueueCapacity has
+// bounded that queue and it's already full, in which case the cmd is
+// answered with an error instead of queued; see enqueuePendingCmdLocked.
+// While paused it either queues the cmd for replay on Resume (subject to
+// the same capacity), or rejects it with ErrExtensionPaused, depending on
+// queueWhilePaused. Once TenEnv.BeginDrain has run, every further cmd
+// that would otherwise dispatch is instead answered with a "draining"
+// StatusCodeError result; see admitThroughDrain.
+func (e *Engine) DeliverCmd(cmd Cmd) error {
+	e.mu.Lock()
+	switch e.state {
+	case stateInit, stateStarting:
+		ok := e.enqueuePendingCmdLocked(cmd)
+		e.mu.Unlock()
+		if !ok {
+			e.tenEnv.ReturnError(cmd, "queue capacity exceeded")
+		}
+		return nil
+	case statePaused, statePausing, stateResuming:
+		// stateResuming must queue too: Resume() doesn't swap in
+		// stateRunning and drain pendingCmds until OnResumeDone fires, so
+		// a cmd delivered while that's still pending would otherwise jump
+		// ahead of cmds queued earlier while genuinely paused.
+		if e.queueWhilePaused {
+			ok := e.enqueuePendingCmdLocked(cmd)
+			e.mu.Unlock()
+			if !ok {
+				e.tenEnv.ReturnError(cmd, "queue capacity exceeded")
+			}
+			return nil
+		}
+		e.mu.Unlock()
+		return ErrExtensionPaused
+	case stateStopping, stateStopped:
+		e.mu.Unlock()
+		return fmt.Errorf("%w: %w: extension is stopped", ErrInvalidState, ErrStopped)
+	}
+	e.mu.Unlock()
+
+	if ok, err := e.admitThroughCmdFilter(cmd); !ok {
+		return err
+	}
+	if !e.admitThroughDrain(cmd) {
+		return nil
+	}
+	if l, ok := e.rateLimiterFor(cmd); ok {
+		if !e.admitThroughRateLimit(l, cmd) {
+			return nil
+		}
+	}
+	if !e.admitThroughDeadline(cmd) {
+		return nil
+	}
+
+	e.dispatchOnCmd(cmd)
+	return nil
+}
+
+// dispatchOnCmd invokes ext.OnCmd -- wrapped in whatever middleware chain
+// Use has registered, see buildCmdChain -- either on the calling goroutine,
+// on a freshly spawned one if WithAsyncDispatch is enabled, or serialized
+// against every other OnCmd/OnStop call if WithSerialDispatch is enabled.
+// If both are set, serial dispatch wins: WithSerialDispatch's whole point
+// is a guarantee against concurrent OnCmd calls, which WithAsyncDispatch
+// would otherwise undermine, so it takes priority rather than the two
+// silently fighting over dispatchGoroutine.
+func (e *Engine) dispatchOnCmd(cmd Cmd) {
+	if e.serialDispatch {
+		e.serialMu.Lock()
+		defer e.serialMu.Unlock()
+		e.dispatchOnCmdSync(cmd)
+		return
+	}
+	if e.asyncDispatch {
+		go e.dispatchOnCmdSync(cmd)
+		return
+	}
+	e.dispatchOnCmdSync(cmd)
+}
+
+// dispatchOnCmdSync runs ext.OnCmd on the calling goroutine, recording it
+// as the dispatch goroutine for the duration of the call so
+// SendCmdAndWait can detect being called synchronously from it (see
+// ErrSendCmdAndWaitOnDispatchGoroutine). Extensions that spawn their own
+// goroutine from OnCmd, per convention, are unaffected: dispatchGoroutine
+// only covers this call's own goroutine, not ones OnCmd starts -- and
+// under WithAsyncDispatch, "this call's own goroutine" is already the
+// fresh one dispatchOnCmd spawned, not the runtime's dispatch thread.
+func (e *Engine) dispatchOnCmdSync(cmd Cmd) {
+	atomic.StoreInt64(&e.dispatchGoroutine, goroutineID())
+	defer atomic.StoreInt64(&e.dispatchGoroutine, 0)
+	defer e.recoverInto("OnCmd", func(r any) {
+		e.tenEnv.ReturnError(cmd, fmt.Sprintf("panic: %v", r))
+	})
+	e.recordCmdDispatched(cmd)
+	e.trackCmdInflight(cmd)
+	e.startHandlerTiming(cmd)
+	stampCmdDispatchEngine(e, cmd)
+	chain := buildCmdChain(e.ext.OnCmd)
+	chain(e.tenEnv, cmd)
+	e.enforceUnhandledCmdPolicy(cmd)
+}
+
+// extensionName identifies the Engine's extension for a metric's
+// "extension" tag. It prefers whatever WithExtensionName configured --
+// the name a deployer's graph config actually gave this node -- and
+// falls back to the extension's own Go type name when that wasn't set,
+// since nothing else in this package tracks a friendlier one (see
+// RegisterAddonAsExtension).
+func (e *Engine) extensionName() string {
+	if e.extensionInstanceName != "" {
+		return e.extensionInstanceName
+	}
+	return fmt.Sprintf("%T", e.ext)
+}
+
+// recordCmdDispatched is a no-op unless WithMetricsRegistry was used: it
+// increments the per-extension command counter and remembers cmd's
+// dispatch time so recordCmdCompleted can later observe its latency.
+func (e *Engine) recordCmdDispatched(cmd Cmd) {
+	name, _ := cmd.GetName()
+	emitLifecycleMessageEvent(LifecycleCmdReceived, e.extensionName(), name, "cmd")
+
+	// cmdStart is recorded unconditionally, not just when WithMetricsRegistry
+	// is in use, since recordCmdCompleted also needs it to know whether cmd
+	// came through here at all -- that's what tells it whether to emit
+	// LifecycleResultReturned, regardless of whether metrics are enabled.
+	e.cmdStartMu.Lock()
+	e.cmdStart[cmd] = time.Now()
+	e.cmdStartMu.Unlock()
+
+	if e.metrics == nil {
+		return
+	}
+	e.metrics.IncCounter(
+		"ten_extension_cmd_total",
+		Tag{Key: "extension", Value: e.extensionName()},
+		Tag{Key: "cmd", Value: name},
+	)
+}
+
+// finalizeReturnedCmd runs the bookkeeping every plain ReturnResult call
+// triggers once it's cleared to proceed (i.e. it either has no armed
+// SetCommandTimeout watchdog, or it just won the claim against one).
+// fireCommandTimeout also calls this directly, since by the time it does
+// it has already won that claim itself and calling back into ReturnResult
+// would just re-check (and needlessly contend) the same claim again.
+func (e *Engine) finalizeReturnedCmd(cmd Cmd) {
+	e.recordCmdCompleted(cmd)
+	e.releaseCmdContext(cmd)
+	e.localValues.delete(cmd)
+	e.untrackCmdInflight(cmd)
+	e.finishHandlerTiming(cmd)
+}
+
+// recordCmdCompleted is a no-op unless WithMetricsRegistry was used, or
+// cmd wasn't dispatched through dispatchOnCmd (e.g. it's a downstream
+// response, not one this extension was handed). It observes the latency
+// between dispatch and this call, which ReturnResult and a final
+// ReturnResultEx both count as "the command completed".
+func (e *Engine) recordCmdCompleted(cmd Cmd) {
+	e.cmdStartMu.Lock()
+	start, ok := e.cmdStart[cmd]
+	if ok {
+		delete(e.cmdStart, cmd)
+	}
+	e.cmdStartMu.Unlock()
+	if !ok {
+		return
+	}
+
+	name, _ := cmd.GetName()
+	emitLifecycleMessageEvent(LifecycleResultReturned, e.extensionName(), name, "cmd")
+
+	if e.metrics == nil {
+		return
+	}
+	e.metrics.ObserveLatency(
+		"ten_extension_cmd_latency_seconds",
+		time.Since(start),
+		Tag{Key: "extension", Value: e.extensionName()},
+		Tag{Key: "cmd", Value: name},
+	)
+}
@@ -0,0 +1,88 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ErrEnvVarNotSet is returned by Init, once TenEnv.EnableEnvInterpolation
+// has been called, when a property value references ${VAR} and the
+// process environment has no VAR and no ":-default" fallback was given.
+var ErrEnvVarNotSet = errors.New("ten: referenced environment variable is not set")
+
+// interpolateEnvValue walks v -- a property value, or the whole property
+// bag, since both are built from string/map[string]any/[]any/scalar --
+// expanding every string leaf via expandEnvString. Maps and slices are
+// mutated in place and returned as-is; a fresh value is only allocated for
+// a string leaf, since strings are immutable.
+func interpolateEnvValue(v any) (any, error) {
+	switch val := v.(type) {
+	case string:
+		return expandEnvString(val)
+	case map[string]any:
+		for k, sub := range val {
+			expanded, err := interpolateEnvValue(sub)
+			if err != nil {
+				return nil, fmt.Errorf("property %q: %w", k, err)
+			}
+			val[k] = expanded
+		}
+		return val, nil
+	case []any:
+		for i, sub := range val {
+			expanded, err := interpolateEnvValue(sub)
+			if err != nil {
+				return nil, err
+			}
+			val[i] = expanded
+		}
+		return val, nil
+	default:
+		return v, nil
+	}
+}
+
+// expandEnvString expands every ${VAR} or ${VAR:-default} reference in s
+// against the process environment, and unescapes $$ to a literal $. A bare
+// $ not followed by { or $ is left untouched.
+func expandEnvString(s string) (string, error) {
+	var buf strings.Builder
+	for i := 0; i < len(s); {
+		if s[i] != '$' {
+			buf.WriteByte(s[i])
+			i++
+			continue
+		}
+		switch {
+		case i+1 < len(s) && s[i+1] == '$':
+			buf.WriteByte('$')
+			i += 2
+		case i+1 < len(s) && s[i+1] == '{':
+			end := strings.IndexByte(s[i+2:], '}')
+			if end == -1 {
+				return "", fmt.Errorf("ten: unterminated \"${\" in property value %q", s)
+			}
+			expr := s[i+2 : i+2+end]
+			name, def, hasDefault := strings.Cut(expr, ":-")
+			value, ok := os.LookupEnv(name)
+			if !ok {
+				if !hasDefault {
+					return "", fmt.Errorf("%w: %q", ErrEnvVarNotSet, name)
+				}
+				value = def
+			}
+			buf.WriteString(value)
+			i += 2 + end + 1
+		default:
+			buf.WriteByte('$')
+			i++
+		}
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,100 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"errors"
+	"testing"
+)
+
+// envInterpolationExtension enables env interpolation from OnConfigure,
+// the way a real extension is expected to.
+type envInterpolationExtension struct {
+	DefaultExtension
+}
+
+func (envInterpolationExtension) OnConfigure(tenEnv TenEnv) {
+	tenEnv.EnableEnvInterpolation(true)
+	tenEnv.OnConfigureDone()
+}
+
+func TestEngineInit_EnvInterpolationExpandsReferencedVariable(t *testing.T) {
+	t.Setenv("TEST_API_KEY", "sk-live-123")
+
+	engine := NewEngine(envInterpolationExtension{}, WithProperties(map[string]any{
+		"api_key": "${TEST_API_KEY}",
+	}))
+	if err := engine.Init(); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+
+	if got := engine.properties["api_key"]; got != "sk-live-123" {
+		t.Fatalf("api_key = %v, want %q", got, "sk-live-123")
+	}
+}
+
+func TestEngineInit_EnvInterpolationUsesDefaultWhenUnset(t *testing.T) {
+	engine := NewEngine(envInterpolationExtension{}, WithProperties(map[string]any{
+		"region": "${TEST_UNSET_REGION:-us-east-1}",
+	}))
+	if err := engine.Init(); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+
+	if got := engine.properties["region"]; got != "us-east-1" {
+		t.Fatalf("region = %v, want %q", got, "us-east-1")
+	}
+}
+
+func TestEngineInit_EnvInterpolationFailsFastOnUnsetVariable(t *testing.T) {
+	engine := NewEngine(envInterpolationExtension{}, WithProperties(map[string]any{
+		"api_key": "${TEST_DEFINITELY_UNSET_VAR}",
+	}))
+	if err := engine.Init(); !errors.Is(err, ErrEnvVarNotSet) {
+		t.Fatalf("Init err = %v, want ErrEnvVarNotSet", err)
+	}
+}
+
+func TestEngineInit_EnvInterpolationEscapesLiteralDollar(t *testing.T) {
+	engine := NewEngine(envInterpolationExtension{}, WithProperties(map[string]any{
+		"price": "$$5.00",
+	}))
+	if err := engine.Init(); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+
+	if got := engine.properties["price"]; got != "$5.00" {
+		t.Fatalf("price = %v, want %q", got, "$5.00")
+	}
+}
+
+func TestEngineInit_EnvInterpolationExpandsNestedProperties(t *testing.T) {
+	t.Setenv("TEST_NESTED_HOST", "db.internal")
+
+	engine := NewEngine(envInterpolationExtension{}, WithProperties(map[string]any{
+		"db": map[string]any{"host": "${TEST_NESTED_HOST}"},
+	}))
+	if err := engine.Init(); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+
+	host, err := navigateGet(engine.properties, "db.host")
+	if err != nil || host != "db.internal" {
+		t.Fatalf("db.host = %v, %v, want %q, nil", host, err, "db.internal")
+	}
+}
+
+func TestEngineInit_EnvInterpolationDisabledLeavesValueLiteral(t *testing.T) {
+	engine := NewEngine(DefaultExtension{}, WithProperties(map[string]any{
+		"api_key": "${TEST_DEFINITELY_UNSET_VAR}",
+	}))
+	if err := engine.Init(); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+
+	if got := engine.properties["api_key"]; got != "${TEST_DEFINITELY_UNSET_VAR}" {
+		t.Fatalf("api_key = %v, want the literal string unchanged", got)
+	}
+}
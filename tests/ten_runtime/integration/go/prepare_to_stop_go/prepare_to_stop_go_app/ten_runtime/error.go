@@ -0,0 +1,205 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Code is a stable, machine-comparable error category. Code itself
+// satisfies the error interface, so a bare Code can be used both as the
+// first argument to NewError and as the target of errors.Is.
+type Code int
+
+const (
+	ErrUnknownCmd Code = iota + 1
+	ErrInvalidArgument
+	ErrUnavailable
+	ErrInternal
+	ErrTimeout
+
+	// ErrNotFound is the Code for a lookup that found nothing -- e.g. a
+	// requested addon or, going forward, other named resources this
+	// package grows. It's distinct from ErrPropertyNotFound, which stays a
+	// plain sentinel: that one's for a Cmd/CmdResult's own property bag
+	// navigation, a purely internal concern with no Code-worthy
+	// cross-boundary meaning.
+	ErrNotFound
+
+	// ErrStopped is the Code for an operation rejected because the
+	// extension has already been stopped, e.g. a Cmd delivered after Stop.
+	ErrStopped
+)
+
+func (c Code) Error() string {
+	switch c {
+	case ErrUnknownCmd:
+		return "unknown cmd"
+	case ErrInvalidArgument:
+		return "invalid argument"
+	case ErrUnavailable:
+		return "unavailable"
+	case ErrInternal:
+		return "internal error"
+	case ErrTimeout:
+		return "timeout"
+	case ErrNotFound:
+		return "not found"
+	case ErrStopped:
+		return "stopped"
+	default:
+		return fmt.Sprintf("ten error code %d", int(c))
+	}
+}
+
+// Error is a typed ten_runtime error: a stable Code plus a human-readable
+// Message, optional structured Details, and an optional wrapped Cause so
+// the chain survives round-tripping through a CmdResult (see
+// NewCmdResultFromError and CmdResult.AsError) and cooperates with
+// errors.Is/errors.As.
+type Error interface {
+	error
+
+	Code() Code
+	Message() string
+	Details() map[string]any
+	Cause() error
+	Unwrap() error
+}
+
+type errImpl struct {
+	code    Code
+	message string
+	details map[string]any
+	cause   error
+}
+
+// ErrorOption configures optional fields on a new Error.
+type ErrorOption func(*errImpl)
+
+// WithDetails attaches structured, machine-readable context to an Error.
+func WithDetails(details map[string]any) ErrorOption {
+	return func(e *errImpl) { e.details = details }
+}
+
+// WithCause wraps an underlying error, preserving it for errors.Is/As and
+// for %w-style chains.
+func WithCause(cause error) ErrorOption {
+	return func(e *errImpl) { e.cause = cause }
+}
+
+// NewError creates a typed Error with the given Code and Message.
+func NewError(code Code, message string, opts ...ErrorOption) Error {
+	e := &errImpl{code: code, message: message}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
+}
+
+// AsTenError unwraps err's chain looking for a ten.Error, the same way
+// errors.As(err, &target) would, so a caller can branch on Code without
+// declaring its own Error variable first. It returns false if no Error is
+// found anywhere in err's chain.
+func AsTenError(err error) (Error, bool) {
+	var te Error
+	if errors.As(err, &te) {
+		return te, true
+	}
+	return nil, false
+}
+
+func (e *errImpl) Code() Code              { return e.code }
+func (e *errImpl) Message() string         { return e.message }
+func (e *errImpl) Details() map[string]any { return e.details }
+func (e *errImpl) Cause() error            { return e.cause }
+func (e *errImpl) Unwrap() error           { return e.cause }
+
+func (e *errImpl) Error() string {
+	if e.cause != nil {
+		return fmt.Sprintf("%s: %s: %v", e.code, e.message, e.cause)
+	}
+	return fmt.Sprintf("%s: %s", e.code, e.message)
+}
+
+// Is lets errors.Is(err, ten.ErrUnknownCmd) (and similar comparisons
+// against another *ten.Error) succeed by Code, regardless of Message,
+// Details or Cause.
+func (e *errImpl) Is(target error) bool {
+	if code, ok := target.(Code); ok {
+		return e.code == code
+	}
+	if other, ok := target.(*errImpl); ok {
+		return e.code == other.code
+	}
+	return false
+}
+
+// encodeError turns err into a plain map[string]any tree so it can be
+// carried across the CmdResult property boundary. A typed Error keeps its
+// Code/Message/Details/Cause; any other error is flattened to its message.
+func encodeError(err error) map[string]any {
+	if err == nil {
+		return nil
+	}
+
+	var te Error
+	if errors.As(err, &te) {
+		m := map[string]any{
+			"code":    int(te.Code()),
+			"message": te.Message(),
+		}
+		if details := te.Details(); len(details) > 0 {
+			m["details"] = details
+		}
+		if cause := te.Cause(); cause != nil {
+			m["cause"] = encodeError(cause)
+		}
+		return m
+	}
+
+	return map[string]any{"message": err.Error()}
+}
+
+// decodeError reverses encodeError, reconstructing a ten.Error (or, for a
+// non-ten.Error leaf, a plain error) so the caller can use errors.Is/As on
+// the result.
+func decodeError(m map[string]any) error {
+	if m == nil {
+		return nil
+	}
+
+	message, _ := m["message"].(string)
+
+	codeVal, hasCode := m["code"]
+	if !hasCode {
+		return errors.New(message)
+	}
+	code := Code(toInt(codeVal))
+
+	var opts []ErrorOption
+	if details, ok := m["details"].(map[string]any); ok {
+		opts = append(opts, WithDetails(details))
+	}
+	if causeMap, ok := m["cause"].(map[string]any); ok {
+		opts = append(opts, WithCause(decodeError(causeMap)))
+	}
+
+	return NewError(code, message, opts...)
+}
+
+func toInt(v any) int {
+	switch n := v.(type) {
+	case int:
+		return n
+	case int64:
+		return int(n)
+	case float64:
+		return int(n)
+	default:
+		return 0
+	}
+}
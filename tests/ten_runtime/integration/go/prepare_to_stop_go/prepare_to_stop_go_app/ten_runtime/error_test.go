@@ -0,0 +1,231 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestCmdResultFromError_RoundTripsCode(t *testing.T) {
+	cmd, _ := NewCmd("frobnicate")
+	err := NewError(ErrUnknownCmd, "unknown cmd: frobnicate")
+
+	result, rErr := NewCmdResultFromError(err, cmd)
+	if rErr != nil {
+		t.Fatalf("NewCmdResultFromError: %v", rErr)
+	}
+
+	status, _ := result.StatusCode()
+	if status != StatusCodeError {
+		t.Fatalf("StatusCode = %v, want StatusCodeError", status)
+	}
+
+	gotErr := result.AsError()
+	if gotErr == nil {
+		t.Fatal("AsError returned nil, want a reconstructed error")
+	}
+	if !errors.Is(gotErr, ErrUnknownCmd) {
+		t.Fatalf("errors.Is(AsError(), ErrUnknownCmd) = false, err = %v", gotErr)
+	}
+
+	var te Error
+	if !errors.As(gotErr, &te) {
+		t.Fatalf("errors.As(AsError(), *Error) = false, err = %v", gotErr)
+	}
+	if te.Message() != "unknown cmd: frobnicate" {
+		t.Fatalf("Message() = %q, want %q", te.Message(), "unknown cmd: frobnicate")
+	}
+}
+
+func TestCmdResultFromError_PreservesDetailsAndCause(t *testing.T) {
+	cmd, _ := NewCmd("connect")
+	cause := errors.New("dial tcp: connection refused")
+	err := NewError(
+		ErrUnavailable,
+		"downstream service unreachable",
+		WithDetails(map[string]any{"host": "db.internal", "port": float64(5432)}),
+		WithCause(cause),
+	)
+
+	result, _ := NewCmdResultFromError(err, cmd)
+	gotErr := result.AsError()
+
+	var te Error
+	if !errors.As(gotErr, &te) {
+		t.Fatalf("errors.As failed for %v", gotErr)
+	}
+	if te.Details()["host"] != "db.internal" {
+		t.Fatalf("Details()[\"host\"] = %v, want %q", te.Details()["host"], "db.internal")
+	}
+	if te.Cause() == nil || te.Cause().Error() != cause.Error() {
+		t.Fatalf("Cause() = %v, want %q", te.Cause(), cause.Error())
+	}
+	if !errors.Is(gotErr, ErrUnavailable) {
+		t.Fatalf("errors.Is(AsError(), ErrUnavailable) = false")
+	}
+	if errors.Is(gotErr, ErrUnknownCmd) {
+		t.Fatalf("errors.Is(AsError(), ErrUnknownCmd) = true, want false")
+	}
+}
+
+func TestAsTenError_FindsErrorInWrappedChain(t *testing.T) {
+	inner := NewError(ErrNotFound, "addon \"foo\" not registered")
+	wrapped := fmt.Errorf("registering extension: %w", inner)
+
+	te, ok := AsTenError(wrapped)
+	if !ok {
+		t.Fatal("AsTenError(wrapped ten.Error) = false, want true")
+	}
+	if te.Code() != ErrNotFound {
+		t.Fatalf("te.Code() = %v, want ErrNotFound", te.Code())
+	}
+}
+
+func TestAsTenError_FalseForPlainError(t *testing.T) {
+	if _, ok := AsTenError(errors.New("boom")); ok {
+		t.Fatal("AsTenError(plain error) = true, want false")
+	}
+}
+
+func TestEngine_DeliverCmdAfterStopIsErrStopped(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+	engine.Init()
+	engine.Start()
+	engine.Stop()
+
+	cmd, _ := NewCmd("ping")
+	err := engine.DeliverCmd(cmd)
+	if !errors.Is(err, ErrStopped) {
+		t.Fatalf("DeliverCmd after Stop = %v, want ErrStopped", err)
+	}
+	if !errors.Is(err, ErrInvalidState) {
+		t.Fatalf("DeliverCmd after Stop = %v, want it to still satisfy ErrInvalidState", err)
+	}
+}
+
+func TestCmdResult_AsErrorNilForOkStatus(t *testing.T) {
+	cmd, _ := NewCmd("ping")
+	result, _ := NewCmdResult(StatusCodeOk, cmd)
+	if err := result.AsError(); err != nil {
+		t.Fatalf("AsError() on an OK result = %v, want nil", err)
+	}
+}
+
+// resultCapturingTenEnv embeds tenEnvImpl and overrides only ReturnResult,
+// recording the CmdResult it was handed so a test can inspect it.
+type resultCapturingTenEnv struct {
+	*tenEnvImpl
+	returned CmdResult
+}
+
+func (t *resultCapturingTenEnv) ReturnResult(result CmdResult, cmd Cmd) error {
+	t.returned = result
+	return nil
+}
+
+// ReturnError/ReturnErrorf/ReturnOK are re-implemented here, rather than
+// left to the embedded *tenEnvImpl, because a promoted method's own body
+// calls the embedded type's ReturnResult directly -- it can't see this
+// type's override. Duplicating the three-line body is the standard
+// workaround.
+func (t *resultCapturingTenEnv) ReturnError(cmd Cmd, detail string) error {
+	result, err := NewCmdResult(StatusCodeError, cmd)
+	if err != nil {
+		return err
+	}
+	if err := result.SetPropertyString("detail", detail); err != nil {
+		return err
+	}
+	return t.ReturnResult(result, cmd)
+}
+
+func (t *resultCapturingTenEnv) ReturnErrorf(cmd Cmd, format string, args ...any) error {
+	return t.ReturnError(cmd, fmt.Sprintf(format, args...))
+}
+
+func (t *resultCapturingTenEnv) ReturnOK(cmd Cmd, detail string) error {
+	result, err := NewCmdResult(StatusCodeOk, cmd)
+	if err != nil {
+		return err
+	}
+	if err := result.SetPropertyString("detail", detail); err != nil {
+		return err
+	}
+	return t.ReturnResult(result, cmd)
+}
+
+func TestDefaultExtensionOnCmd_ReturnsTypedError(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+	tenEnv := &resultCapturingTenEnv{tenEnvImpl: engine.tenEnv}
+	cmd, _ := NewCmd("unhandled")
+
+	DefaultExtension{}.OnCmd(tenEnv, cmd)
+
+	if tenEnv.returned == nil {
+		t.Fatal("OnCmd did not call ReturnResult")
+	}
+	if !errors.Is(tenEnv.returned.AsError(), ErrUnknownCmd) {
+		t.Fatalf(
+			"errors.Is(result.AsError(), ErrUnknownCmd) = false, err = %v",
+			tenEnv.returned.AsError(),
+		)
+	}
+}
+
+func TestTenEnv_ReturnErrorSetsStatusAndDetail(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+	tenEnv := &resultCapturingTenEnv{tenEnvImpl: engine.tenEnv}
+	cmd, _ := NewCmd("connect")
+
+	if err := tenEnv.ReturnError(cmd, "dial tcp: connection refused"); err != nil {
+		t.Fatalf("ReturnError: %v", err)
+	}
+
+	if tenEnv.returned == nil {
+		t.Fatal("ReturnError did not call ReturnResult")
+	}
+	status, _ := tenEnv.returned.StatusCode()
+	if status != StatusCodeError {
+		t.Fatalf("StatusCode = %v, want StatusCodeError", status)
+	}
+	detail, err := tenEnv.returned.GetPropertyString("detail")
+	if err != nil || detail != "dial tcp: connection refused" {
+		t.Fatalf("detail = %q, %v, want %q, nil", detail, err, "dial tcp: connection refused")
+	}
+}
+
+func TestTenEnv_ReturnErrorfFormatsMessage(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+	tenEnv := &resultCapturingTenEnv{tenEnvImpl: engine.tenEnv}
+	cmd, _ := NewCmd("connect")
+
+	if err := tenEnv.ReturnErrorf(cmd, "attempt %d failed", 3); err != nil {
+		t.Fatalf("ReturnErrorf: %v", err)
+	}
+	detail, _ := tenEnv.returned.GetPropertyString("detail")
+	if detail != "attempt 3 failed" {
+		t.Fatalf("detail = %q, want %q", detail, "attempt 3 failed")
+	}
+}
+
+func TestTenEnv_ReturnOKSetsStatusAndDetail(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+	tenEnv := &resultCapturingTenEnv{tenEnvImpl: engine.tenEnv}
+	cmd, _ := NewCmd("greet")
+
+	if err := tenEnv.ReturnOK(cmd, "done"); err != nil {
+		t.Fatalf("ReturnOK: %v", err)
+	}
+	status, _ := tenEnv.returned.StatusCode()
+	if status != StatusCodeOk {
+		t.Fatalf("StatusCode = %v, want StatusCodeOk", status)
+	}
+	detail, _ := tenEnv.returned.GetPropertyString("detail")
+	if detail != "done" {
+		t.Fatalf("detail = %q, want %q", detail, "done")
+	}
+}
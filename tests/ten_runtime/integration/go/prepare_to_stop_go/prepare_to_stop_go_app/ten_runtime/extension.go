@@ -0,0 +1,469 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+
+// Package ten is a Go-only simulation of the ten_runtime extension
+// lifecycle, used by the integration tests under this app.
+//
+// Pause/Resume scope: the original pause/resume request's headline use
+// case was suspending a long-running extension "to stop consuming/
+// producing data frames while preserving in-memory state" (e.g. a model
+// handle or connection that keeps streaming media while paused). This
+// package has a Data type (see data.go) for extensions to consume, but no
+// data-frame delivery path -- nothing routes a Data through the engine or
+// gates it on pause state the way DeliverCmd does for Cmd -- so
+// OnPause/OnResume and the pause state machine only ever gate cmds. The
+// data-frame half of the original request is not implemented, not merely
+// an edge case of it. Treat cmd-only pause as a partial, not a complete,
+// implementation of that request until confirmed otherwise.
+//
+// Inbound Data/AudioFrame/VideoFrame scope: Extension.OnData, OnAudioFrame
+// and OnVideoFrame exist so an extension's method set matches the native
+// callback surface, and DefaultExtension provides no-op defaults for all
+// three -- but nothing in this package delivers a Data, AudioFrame or
+// VideoFrame to an extension the way DeliverCmd does for Cmd. SendAudioFrame
+// and SendVideoFrame (see tenenv.go) only simulate handing a frame to the
+// downstream graph; they don't loop back into any extension's OnAudioFrame
+// or OnVideoFrame. Extensions that need one of these callbacks driven must
+// call it directly, e.g. from a test, until an inbound delivery path exists.
+package ten
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Extension is the full lifecycle interface a ten_runtime Go extension
+// implements. Extensions normally embed DefaultExtension and only override
+// the callbacks they care about.
+type Extension interface {
+	// OnConfigure runs before OnInit, so an extension can register a
+	// property schema (see TenEnv.SetPropertySchema) before the runtime
+	// validates whatever properties it was supplied against it. The
+	// default, provided by DefaultExtension, registers no schema and
+	// immediately acknowledges via OnConfigureDone.
+	OnConfigure(tenEnv TenEnv)
+
+	OnInit(tenEnv TenEnv)
+	OnStart(tenEnv TenEnv)
+	OnStop(tenEnv TenEnv)
+	OnDeinit(tenEnv TenEnv)
+
+	// OnPause/OnResume let a graph suspend an extension without tearing it
+	// down: in-memory state is preserved, and the runtime stops delivering
+	// cmds while the extension is paused. See the package doc for the
+	// cmd-only scope of this pause/resume implementation.
+	OnPause(tenEnv TenEnv)
+	OnResume(tenEnv TenEnv)
+
+	OnCmd(tenEnv TenEnv, cmd Cmd)
+
+	// OnData is called for each Data delivered to the extension. The
+	// default, provided by DefaultExtension, does nothing -- most
+	// extensions that don't consume data frames never need to override it.
+	OnData(tenEnv TenEnv, data Data)
+
+	// OnAudioFrame is called for each AudioFrame delivered to the
+	// extension, and OnVideoFrame for each VideoFrame. Both default to a
+	// no-op via DefaultExtension, so an extension that only cares about
+	// one media type overrides just that one method -- Go's usual
+	// embedding rule applies: a method an extension defines on its own
+	// type shadows the promoted DefaultExtension one in its method set,
+	// with no separate registration step. See the package doc for the
+	// scope of what currently calls these.
+	OnAudioFrame(tenEnv TenEnv, frame AudioFrame)
+	OnVideoFrame(tenEnv TenEnv, frame VideoFrame)
+
+	// OnConfigReload is invoked once after Engine.ReloadProperties applies a
+	// batch of externally-changed properties -- an operator editing a
+	// live graph's config, e.g. a model name or a threshold, without
+	// restarting the extension. changed lists every top-level property
+	// path that was part of the batch, sorted, so the extension can
+	// re-read just those instead of its whole property set. It
+	// complements TenEnv.OnPropertyChanged, which still fires once per
+	// changed path for any subscription registered against it:
+	// OnConfigReload is for an extension that would rather see the whole
+	// batch at once and decide atomically what to apply live versus what
+	// still requires a restart. The default, provided by DefaultExtension,
+	// does nothing.
+	OnConfigReload(tenEnv TenEnv, changed []string)
+}
+
+// HealthCheckCmdName is the reserved cmd name ops tooling probes an
+// extension's liveness with. DefaultExtension.OnCmd matches it exactly --
+// a cmd merely prefixed with it (e.g. "__health__check") falls through to
+// the ordinary unknown-cmd handling below -- and answers with a
+// StatusCodeOk result carrying "uptime_seconds" and "extension_name",
+// plus whatever SetHealthDetail's callback contributes. An extension that
+// overrides OnCmd entirely is naturally exempted, the same way overriding
+// OnCmd already exempts it from the unknown-cmd result.
+const HealthCheckCmdName = "__health__"
+
+// PingCmdName is the reserved cmd name TenEnv.Ping sends to measure
+// round-trip time to another extension. DefaultExtension.OnCmd matches it
+// exactly, the same way it matches HealthCheckCmdName, and answers
+// immediately with a bare StatusCodeOk result -- the "pong" -- so every
+// extension responds to a Ping without writing any code of its own,
+// unless it overrides OnCmd entirely.
+const PingCmdName = "__ping__"
+
+// DefaultExtension provides no-op implementations for every Extension
+// callback, immediately acknowledging each lifecycle step. Extensions embed
+// it and override only the callbacks whose default behavior doesn't fit.
+//
+// healthDetail is set via SetHealthDetail, which takes a pointer receiver
+// to persist -- an extension that wants it must be constructed so
+// SetHealthDetail is called on an addressable value, e.g.
+// ext := &MyExtension{}; ext.SetHealthDetail(...); ten.NewEngine(ext).
+type DefaultExtension struct {
+	healthDetail func() map[string]any
+}
+
+func (DefaultExtension) OnConfigure(tenEnv TenEnv) { tenEnv.OnConfigureDone() }
+func (DefaultExtension) OnInit(tenEnv TenEnv)      { tenEnv.OnInitDone() }
+func (DefaultExtension) OnStart(tenEnv TenEnv)     { tenEnv.OnStartDone() }
+func (DefaultExtension) OnStop(tenEnv TenEnv)      { tenEnv.OnStopDone() }
+func (DefaultExtension) OnDeinit(tenEnv TenEnv)    { tenEnv.OnDeinitDone() }
+func (DefaultExtension) OnPause(tenEnv TenEnv)     { tenEnv.OnPauseDone() }
+func (DefaultExtension) OnResume(tenEnv TenEnv)    { tenEnv.OnResumeDone() }
+
+func (DefaultExtension) OnConfigReload(tenEnv TenEnv, changed []string) {}
+
+func (d DefaultExtension) OnCmd(tenEnv TenEnv, cmd Cmd) {
+	name, _ := cmd.GetName()
+	switch name {
+	case HealthCheckCmdName:
+		d.handleHealthCheck(tenEnv, cmd)
+		return
+	case PingCmdName:
+		d.handlePing(tenEnv, cmd)
+		return
+	}
+	err := NewError(ErrUnknownCmd, "OnCmd is not implemented")
+	cmdResult, _ := NewCmdResultFromError(err, cmd)
+	tenEnv.ReturnResult(cmdResult, nil)
+}
+
+// handlePing answers cmd, already confirmed named PingCmdName, with a bare
+// StatusCodeOk pong -- TenEnv.Ping only cares how long the round trip
+// took, not anything the pong itself carries.
+func (d DefaultExtension) handlePing(tenEnv TenEnv, cmd Cmd) {
+	result, err := NewCmdResult(StatusCodeOk, cmd)
+	if err != nil {
+		tenEnv.ReturnResult(result, cmd)
+		return
+	}
+	tenEnv.ReturnResult(result, cmd)
+}
+
+// handleHealthCheck answers cmd, already confirmed named
+// HealthCheckCmdName, with an OK result. GetExtensionName's error is
+// ignored -- by the time a cmd is being delivered the extension has
+// virtually always finished starting, and an empty extension_name is a
+// more useful answer to a health probe than dropping the whole result.
+func (d DefaultExtension) handleHealthCheck(tenEnv TenEnv, cmd Cmd) {
+	result, err := NewCmdResult(StatusCodeOk, cmd)
+	if err != nil {
+		tenEnv.ReturnResult(result, cmd)
+		return
+	}
+	result.SetPropertyFloat64("uptime_seconds", tenEnv.Uptime().Seconds())
+	name, _ := tenEnv.GetExtensionName()
+	result.SetPropertyString("extension_name", name)
+	if d.healthDetail != nil {
+		result.SetProperties(d.healthDetail())
+	}
+	tenEnv.ReturnResult(result, cmd)
+}
+
+// SetHealthDetail registers fn to contribute extra fields to the
+// HealthCheckCmdName result DefaultExtension.OnCmd answers with,
+// alongside "uptime_seconds" and "extension_name". fn runs fresh on every
+// health check, so it can report live state -- queue depth, last error,
+// whatever's relevant -- rather than a snapshot taken once at startup.
+func (d *DefaultExtension) SetHealthDetail(fn func() map[string]any) {
+	d.healthDetail = fn
+}
+
+func (DefaultExtension) OnData(tenEnv TenEnv, data Data) {}
+
+func (DefaultExtension) OnAudioFrame(tenEnv TenEnv, frame AudioFrame) {}
+func (DefaultExtension) OnVideoFrame(tenEnv TenEnv, frame VideoFrame) {}
+
+// ExtensionAddon creates Extension instances by name, analogous to the
+// native ten_runtime addon mechanism.
+type ExtensionAddon interface {
+	NewInstance(name string) Extension
+}
+
+// AddonLifecycle is an optional interface an ExtensionAddon can implement
+// for one-time setup and teardown shared across every instance it creates
+// -- warming a model, opening a connection pool -- as distinct from
+// Extension.OnInit/OnDeinit, which run once per instance. An ExtensionAddon
+// that doesn't implement AddonLifecycle simply has no addon-level hook,
+// the same "absence is the no-op default" contract Go's optional
+// interfaces (io.Closer, sort.Interface, ...) already use; there's no
+// DefaultAddon to embed because there's nothing to override.
+//
+// Ordering relative to instance creation: OnAddonInit runs synchronously
+// inside RegisterAddonAsExtension, exactly once, before that call returns
+// -- so it has always already run by the time any NewInstance call for
+// this addon is possible, since NewInstance is only ever reachable once
+// registration succeeds (see GetRegisteredExtensionAddon/CreateExtension).
+// OnAddonDeinit runs synchronously inside Registration.Unregister, exactly
+// once, and only once Unregister's own check confirms every instance this
+// addon ever created has already been released (see
+// Registration.ReleaseInstance) -- so every instance's own OnDeinit has
+// necessarily already run by the time OnAddonDeinit does. Neither hook
+// runs again if the same addon is registered again under a different name;
+// each registration gets its own OnAddonInit/OnAddonDeinit pair.
+type AddonLifecycle interface {
+	OnAddonInit(tenEnv TenEnv)
+	OnAddonDeinit(tenEnv TenEnv)
+}
+
+type defaultExtensionAddon struct {
+	newInstance func(name string) Extension
+}
+
+func (a *defaultExtensionAddon) NewInstance(name string) Extension {
+	return a.newInstance(name)
+}
+
+// NewDefaultExtensionAddon wraps a constructor function as an ExtensionAddon.
+func NewDefaultExtensionAddon(
+	newInstance func(name string) Extension,
+) ExtensionAddon {
+	return &defaultExtensionAddon{newInstance: newInstance}
+}
+
+// genericExtensionAddon is NewExtensionAddon's backing type. It stores ctor
+// at its concrete type T and only converts to the Extension interface at
+// the NewInstance call boundary, where an ExtensionAddon is required to
+// produce one -- there's no reflection and no allocation beyond ctor's own.
+type genericExtensionAddon[T Extension] struct {
+	newInstance func(name string) T
+}
+
+func (a *genericExtensionAddon[T]) NewInstance(name string) Extension {
+	return a.newInstance(name)
+}
+
+// NewExtensionAddon is the generic counterpart to NewDefaultExtensionAddon,
+// for constructors that return a concrete Extension type T (e.g.
+// *bExtension) rather than the Extension interface. It keeps ctor's
+// concrete return type all the way to the NewInstance call, so callers
+// like NewExtensionAddon(NewBExtension) don't need to box their
+// constructor's result into Extension themselves just to satisfy this
+// function's signature. NewDefaultExtensionAddon remains for constructors
+// that already return Extension.
+func NewExtensionAddon[T Extension](ctor func(name string) T) ExtensionAddon {
+	return &genericExtensionAddon[T]{newInstance: ctor}
+}
+
+// configuredExtensionAddon is NewDefaultExtensionAddonWithConfig's backing
+// type. cfg is captured once, at registration, and handed to ctor on every
+// NewInstance call -- the shared client/logger/whatever a group of
+// instances needs no longer has to live in a package global for ctor to
+// reach it.
+type configuredExtensionAddon struct {
+	ctor func(name string, cfg any) Extension
+	cfg  any
+}
+
+func (a *configuredExtensionAddon) NewInstance(name string) Extension {
+	return a.ctor(name, a.cfg)
+}
+
+// NewDefaultExtensionAddonWithConfig is NewDefaultExtensionAddon's
+// counterpart for a constructor that also wants a shared configuration
+// value -- a client, a logger, connection settings -- passed to every
+// instance it creates, instead of that value being smuggled in through a
+// package-level variable. cfg is stored once and passed to ctor unchanged
+// on every NewInstance call; it's an any because ctor's constructor
+// signature can't be generic here without also fixing T (see
+// NewExtensionAddonWithConfig for a constructor that returns its own
+// concrete type).
+func NewDefaultExtensionAddonWithConfig(
+	ctor func(name string, cfg any) Extension, cfg any,
+) ExtensionAddon {
+	return &configuredExtensionAddon{ctor: ctor, cfg: cfg}
+}
+
+// genericConfiguredExtensionAddon is NewExtensionAddonWithConfig's backing
+// type, combining genericExtensionAddon's concrete-return-type handling
+// with configuredExtensionAddon's shared cfg.
+type genericConfiguredExtensionAddon[T Extension, C any] struct {
+	ctor func(name string, cfg C) T
+	cfg  C
+}
+
+func (a *genericConfiguredExtensionAddon[T, C]) NewInstance(name string) Extension {
+	return a.ctor(name, a.cfg)
+}
+
+// NewExtensionAddonWithConfig is the generic counterpart to
+// NewDefaultExtensionAddonWithConfig, for constructors that return a
+// concrete Extension type T and want cfg typed as C rather than boxed into
+// any -- e.g. NewExtensionAddonWithConfig(NewBExtension, dbConfig{...}).
+func NewExtensionAddonWithConfig[T Extension, C any](
+	ctor func(name string, cfg C) T, cfg C,
+) ExtensionAddon {
+	return &genericConfiguredExtensionAddon[T, C]{ctor: ctor, cfg: cfg}
+}
+
+var (
+	registryMu    sync.Mutex
+	addonRegistry = map[string]*registeredAddon{}
+)
+
+// registeredAddon wraps a registered ExtensionAddon to track the names
+// still live from it, so Registration.Unregister can refuse to remove an
+// addon that's still instantiated somewhere.
+type registeredAddon struct {
+	addon ExtensionAddon
+
+	// tenEnv backs AddonLifecycle's OnAddonInit/OnAddonDeinit calls. It's
+	// a bare Engine's TenEnv built solely to give those hooks something to
+	// log through -- there's no per-instance Engine yet at registration
+	// time, and the addon-level hooks aren't scoped to any one instance
+	// anyway, so this one TenEnv is shared by both calls for this
+	// registration.
+	tenEnv TenEnv
+
+	mu   sync.Mutex
+	live map[string]int
+}
+
+func (r *registeredAddon) NewInstance(name string) Extension {
+	r.mu.Lock()
+	if r.live == nil {
+		r.live = make(map[string]int)
+	}
+	r.live[name]++
+	r.mu.Unlock()
+	return r.addon.NewInstance(name)
+}
+
+// Registration is returned by RegisterAddonAsExtension so a caller --
+// typically a test that loads and unloads the same addon name repeatedly
+// -- can remove it from the registry once done with it.
+type Registration struct {
+	name  string
+	addon *registeredAddon
+}
+
+// ReleaseInstance marks name as no longer live for this registration's
+// addon. Nothing in this package calls it automatically (there's no
+// Engine-to-addon-registry link that would let a real OnDeinit do it), so
+// a caller that instantiated name via this addon's NewInstance must call
+// ReleaseInstance itself -- typically after tearing the instance down --
+// before Unregister will succeed.
+func (r *Registration) ReleaseInstance(name string) {
+	r.addon.release(name)
+}
+
+// release is ReleaseInstance's implementation, also used directly by
+// DestroyExtension (see dynamic.go) for an instance it created without
+// going through a Registration.
+func (r *registeredAddon) release(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.live[name] <= 1 {
+		delete(r.live, name)
+		return
+	}
+	r.live[name]--
+}
+
+// Unregister removes this registration's addon from the registry. It's
+// safe to call from test teardown, but fails, naming the offending
+// instances, if any instance obtained from this addon hasn't been passed
+// to ReleaseInstance yet. If the addon implements AddonLifecycle, its
+// OnAddonDeinit runs once, synchronously, after that live check passes --
+// see AddonLifecycle's doc comment for the ordering guarantee relative to
+// each instance's own OnDeinit.
+func (r *Registration) Unregister() error {
+	r.addon.mu.Lock()
+	if len(r.addon.live) > 0 {
+		names := make([]string, 0, len(r.addon.live))
+		for name := range r.addon.live {
+			names = append(names, name)
+		}
+		r.addon.mu.Unlock()
+		sort.Strings(names)
+		return fmt.Errorf(
+			"cannot unregister addon %q: still instantiated as %v",
+			r.name, names,
+		)
+	}
+	r.addon.mu.Unlock()
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	delete(addonRegistry, r.name)
+	if lifecycle, ok := r.addon.addon.(AddonLifecycle); ok {
+		lifecycle.OnAddonDeinit(r.addon.tenEnv)
+	}
+	return nil
+}
+
+// RegisterAddonAsExtension registers addon under name so graphs can
+// instantiate it by that name. The returned Registration lets a caller
+// unregister it later. If addon implements AddonLifecycle, its
+// OnAddonInit runs once, synchronously, before this call returns -- see
+// AddonLifecycle's doc comment for the ordering guarantee relative to
+// instance creation.
+func RegisterAddonAsExtension(name string, addon ExtensionAddon) (*Registration, error) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if _, exists := addonRegistry[name]; exists {
+		return nil, fmt.Errorf("addon %q is already registered", name)
+	}
+	wrapped := &registeredAddon{
+		addon:  addon,
+		tenEnv: NewEngine(DefaultExtension{}, WithExtensionName(name)).TenEnv(),
+	}
+	addonRegistry[name] = wrapped
+	if lifecycle, ok := addon.(AddonLifecycle); ok {
+		lifecycle.OnAddonInit(wrapped.tenEnv)
+	}
+	return &Registration{name: name, addon: wrapped}, nil
+}
+
+// GetRegisteredExtensionAddon looks up a previously registered addon, for
+// use by the runtime (or tests) driving an extension instance.
+func GetRegisteredExtensionAddon(name string) (ExtensionAddon, bool) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	addon, ok := addonRegistry[name]
+	return addon, ok
+}
+
+// RegisterAddonGroup registers several named extensions from a single
+// plugin atomically: each entry in addons is registered under prefix+name,
+// letting a plugin that logically contains multiple related extensions
+// share one versioned init() instead of calling RegisterAddonAsExtension
+// once per extension. If any registration fails -- e.g. one of the names
+// collides with something already registered -- every registration this
+// call already made is rolled back, so the registry ends up untouched
+// rather than half-populated. The returned error names the specific
+// extension that failed.
+func RegisterAddonGroup(prefix string, addons map[string]ExtensionAddon) error {
+	registered := make([]*Registration, 0, len(addons))
+	for name, addon := range addons {
+		fullName := prefix + name
+		reg, err := RegisterAddonAsExtension(fullName, addon)
+		if err != nil {
+			for _, r := range registered {
+				r.Unregister()
+			}
+			return fmt.Errorf("registering extension %q: %w", fullName, err)
+		}
+		registered = append(registered, reg)
+	}
+	return nil
+}
@@ -0,0 +1,323 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// concreteExtension is a stand-in for a real extension whose constructor
+// returns its own type rather than the Extension interface, the case
+// NewExtensionAddon exists for.
+type concreteExtension struct {
+	DefaultExtension
+}
+
+func newConcreteExtension(name string) *concreteExtension {
+	return &concreteExtension{}
+}
+
+func TestNewExtensionAddon_NewInstanceReturnsWorkingExtension(t *testing.T) {
+	addon := NewExtensionAddon(newConcreteExtension)
+	instance := addon.NewInstance("instance-name")
+	if _, ok := instance.(*concreteExtension); !ok {
+		t.Fatalf("NewInstance returned %T, want *concreteExtension", instance)
+	}
+}
+
+type dbConfig struct {
+	dsn string
+}
+
+type configuredExtension struct {
+	DefaultExtension
+	dsn string
+}
+
+func newConfiguredExtension(name string, cfg dbConfig) *configuredExtension {
+	return &configuredExtension{dsn: cfg.dsn}
+}
+
+func TestNewDefaultExtensionAddonWithConfig_PassesTheSameConfigToEveryInstance(t *testing.T) {
+	var gotNames []string
+	var gotCfgs []any
+	addon := NewDefaultExtensionAddonWithConfig(func(name string, cfg any) Extension {
+		gotNames = append(gotNames, name)
+		gotCfgs = append(gotCfgs, cfg)
+		return DefaultExtension{}
+	}, dbConfig{dsn: "postgres://shared"})
+
+	addon.NewInstance("one")
+	addon.NewInstance("two")
+
+	if want := []string{"one", "two"}; !reflect.DeepEqual(gotNames, want) {
+		t.Fatalf("gotNames = %v, want %v", gotNames, want)
+	}
+	for _, cfg := range gotCfgs {
+		if cfg.(dbConfig).dsn != "postgres://shared" {
+			t.Fatalf("cfg = %v, want dsn %q", cfg, "postgres://shared")
+		}
+	}
+}
+
+func TestNewExtensionAddonWithConfig_ReturnsConcreteTypeWithConfigApplied(t *testing.T) {
+	addon := NewExtensionAddonWithConfig(newConfiguredExtension, dbConfig{dsn: "postgres://typed"})
+
+	instance := addon.NewInstance("instance-name")
+	concrete, ok := instance.(*configuredExtension)
+	if !ok {
+		t.Fatalf("NewInstance returned %T, want *configuredExtension", instance)
+	}
+	if concrete.dsn != "postgres://typed" {
+		t.Fatalf("dsn = %q, want %q", concrete.dsn, "postgres://typed")
+	}
+}
+
+func TestRegisterAddonGroup_RegistersEveryExtensionUnderPrefix(t *testing.T) {
+	addons := map[string]ExtensionAddon{
+		"foo": NewDefaultExtensionAddon(func(string) Extension { return DefaultExtension{} }),
+		"bar": NewDefaultExtensionAddon(func(string) Extension { return DefaultExtension{} }),
+	}
+	if err := RegisterAddonGroup("group17_", addons); err != nil {
+		t.Fatalf("RegisterAddonGroup: %v", err)
+	}
+
+	if _, ok := GetRegisteredExtensionAddon("group17_foo"); !ok {
+		t.Fatal("group17_foo was not registered")
+	}
+	if _, ok := GetRegisteredExtensionAddon("group17_bar"); !ok {
+		t.Fatal("group17_bar was not registered")
+	}
+}
+
+func TestRegisterAddonGroup_RollsBackAllOnFailure(t *testing.T) {
+	fresh := NewDefaultExtensionAddon(func(string) Extension { return DefaultExtension{} })
+	if _, err := RegisterAddonAsExtension("group17rb_taken", fresh); err != nil {
+		t.Fatalf("RegisterAddonAsExtension: %v", err)
+	}
+
+	addons := map[string]ExtensionAddon{
+		"ok":    fresh,
+		"taken": fresh, // collides with the pre-existing "group17rb_taken"
+	}
+	err := RegisterAddonGroup("group17rb_", addons)
+	if err == nil {
+		t.Fatal("RegisterAddonGroup did not fail on a name collision")
+	}
+	if got, want := err.Error(), `registering extension "group17rb_taken"`; !strings.Contains(got, want) {
+		t.Fatalf("error = %q, want it to name the failing extension %q", got, want)
+	}
+
+	if _, ok := GetRegisteredExtensionAddon("group17rb_ok"); ok {
+		t.Fatal("group17rb_ok is still registered after a rolled-back group registration")
+	}
+}
+
+func TestRegistration_UnregisterRemovesAddonWhenIdle(t *testing.T) {
+	reg, err := RegisterAddonAsExtension(
+		"reg19_idle",
+		NewDefaultExtensionAddon(func(string) Extension { return DefaultExtension{} }),
+	)
+	if err != nil {
+		t.Fatalf("RegisterAddonAsExtension: %v", err)
+	}
+
+	if err := reg.Unregister(); err != nil {
+		t.Fatalf("Unregister: %v", err)
+	}
+	if _, ok := GetRegisteredExtensionAddon("reg19_idle"); ok {
+		t.Fatal("reg19_idle is still registered after Unregister")
+	}
+}
+
+func TestRegistration_UnregisterRejectsLiveInstances(t *testing.T) {
+	reg, err := RegisterAddonAsExtension(
+		"reg19_live",
+		NewDefaultExtensionAddon(func(string) Extension { return DefaultExtension{} }),
+	)
+	if err != nil {
+		t.Fatalf("RegisterAddonAsExtension: %v", err)
+	}
+
+	addon, _ := GetRegisteredExtensionAddon("reg19_live")
+	addon.NewInstance("instance-1")
+
+	if err := reg.Unregister(); err == nil {
+		t.Fatal("Unregister succeeded with a live instance outstanding")
+	} else if !strings.Contains(err.Error(), "instance-1") {
+		t.Fatalf("error = %q, want it to name the live instance %q", err, "instance-1")
+	}
+
+	reg.ReleaseInstance("instance-1")
+	if err := reg.Unregister(); err != nil {
+		t.Fatalf("Unregister after ReleaseInstance: %v", err)
+	}
+}
+
+// lifecycleAddon is an ExtensionAddon that also implements AddonLifecycle,
+// recording the order OnAddonInit/NewInstance/OnAddonDeinit fire in.
+type lifecycleAddon struct {
+	events *[]string
+}
+
+func (a lifecycleAddon) NewInstance(name string) Extension {
+	*a.events = append(*a.events, "NewInstance:"+name)
+	return DefaultExtension{}
+}
+
+func (a lifecycleAddon) OnAddonInit(tenEnv TenEnv) {
+	*a.events = append(*a.events, "OnAddonInit")
+}
+
+func (a lifecycleAddon) OnAddonDeinit(tenEnv TenEnv) {
+	*a.events = append(*a.events, "OnAddonDeinit")
+}
+
+func TestAddonLifecycle_OnAddonInitRunsBeforeRegisterAddonAsExtensionReturns(t *testing.T) {
+	var events []string
+	if _, err := RegisterAddonAsExtension("lifecycle22_init", lifecycleAddon{events: &events}); err != nil {
+		t.Fatalf("RegisterAddonAsExtension: %v", err)
+	}
+	if len(events) != 1 || events[0] != "OnAddonInit" {
+		t.Fatalf("events after RegisterAddonAsExtension = %v, want [OnAddonInit]", events)
+	}
+}
+
+func TestAddonLifecycle_OnAddonDeinitRunsAfterEveryInstanceIsReleased(t *testing.T) {
+	var events []string
+	reg, err := RegisterAddonAsExtension("lifecycle22_deinit", lifecycleAddon{events: &events})
+	if err != nil {
+		t.Fatalf("RegisterAddonAsExtension: %v", err)
+	}
+
+	addon, _ := GetRegisteredExtensionAddon("lifecycle22_deinit")
+	addon.NewInstance("instance-1")
+
+	if err := reg.Unregister(); err == nil {
+		t.Fatal("Unregister succeeded with a live instance outstanding")
+	}
+	if want := []string{"OnAddonInit", "NewInstance:instance-1"}; !reflect.DeepEqual(events, want) {
+		t.Fatalf("events before ReleaseInstance = %v, want %v", events, want)
+	}
+
+	reg.ReleaseInstance("instance-1")
+	if err := reg.Unregister(); err != nil {
+		t.Fatalf("Unregister after ReleaseInstance: %v", err)
+	}
+	if want := []string{"OnAddonInit", "NewInstance:instance-1", "OnAddonDeinit"}; !reflect.DeepEqual(events, want) {
+		t.Fatalf("events after Unregister = %v, want %v", events, want)
+	}
+}
+
+func TestAddonLifecycle_OptionalForAddonsThatDontImplementIt(t *testing.T) {
+	reg, err := RegisterAddonAsExtension(
+		"lifecycle22_noop",
+		NewDefaultExtensionAddon(func(string) Extension { return DefaultExtension{} }),
+	)
+	if err != nil {
+		t.Fatalf("RegisterAddonAsExtension: %v", err)
+	}
+	if err := reg.Unregister(); err != nil {
+		t.Fatalf("Unregister: %v", err)
+	}
+}
+
+func TestDefaultExtension_OnCmdAnswersHealthCheckWithUptimeAndName(t *testing.T) {
+	engine := NewEngine(DefaultExtension{}, WithExtensionName("probe-target"))
+	engine.Init()
+	engine.Start()
+
+	cmd, _ := NewCmd(HealthCheckCmdName)
+	rte := &resultCapturingTenEnv{tenEnvImpl: engine.tenEnv}
+	DefaultExtension{}.OnCmd(rte, cmd)
+
+	status, _ := rte.returned.StatusCode()
+	if status != StatusCodeOk {
+		t.Fatalf("StatusCode = %v, want StatusCodeOk", status)
+	}
+	name, err := rte.returned.GetPropertyString("extension_name")
+	if err != nil || name != "probe-target" {
+		t.Fatalf("extension_name = %q, err = %v, want %q", name, err, "probe-target")
+	}
+	if _, err := rte.returned.GetPropertyFloat64("uptime_seconds"); err != nil {
+		t.Fatalf("uptime_seconds missing: %v", err)
+	}
+}
+
+func TestDefaultExtension_OnCmdOnlyMatchesHealthCheckNameExactly(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+	engine.Init()
+	engine.Start()
+
+	cmd, _ := NewCmd(HealthCheckCmdName + "_check")
+	rte := &resultCapturingTenEnv{tenEnvImpl: engine.tenEnv}
+	DefaultExtension{}.OnCmd(rte, cmd)
+
+	status, _ := rte.returned.StatusCode()
+	if status != StatusCodeError {
+		t.Fatalf("StatusCode = %v, want StatusCodeError for a cmd that merely shares the health check's prefix", status)
+	}
+}
+
+func TestDefaultExtension_SetHealthDetailContributesExtraFields(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+	engine.Init()
+	engine.Start()
+
+	ext := &DefaultExtension{}
+	ext.SetHealthDetail(func() map[string]any {
+		return map[string]any{"queue_depth": int64(3)}
+	})
+
+	cmd, _ := NewCmd(HealthCheckCmdName)
+	rte := &resultCapturingTenEnv{tenEnvImpl: engine.tenEnv}
+	ext.OnCmd(rte, cmd)
+
+	depth, err := rte.returned.GetPropertyInt64("queue_depth")
+	if err != nil || depth != 3 {
+		t.Fatalf("queue_depth = %v, err = %v, want 3", depth, err)
+	}
+}
+
+func TestDefaultExtensionOnAudioFrameAndOnVideoFrame_AreNoOps(t *testing.T) {
+	frame, _ := NewAudioFrame("pcm")
+	video, _ := NewVideoFrame("raw")
+	// Passing nil TenEnv exercises the no-op guarantee directly, the same
+	// way TestDefaultExtensionOnData_IsNoOp does for OnData.
+	DefaultExtension{}.OnAudioFrame(nil, frame)
+	DefaultExtension{}.OnVideoFrame(nil, video)
+}
+
+// audioOnlyExtension overrides only OnAudioFrame, leaving OnVideoFrame (and
+// everything else) at DefaultExtension's default -- Go's embedding rule
+// promotes the unoverridden methods into audioOnlyExtension's method set
+// automatically, so this satisfies Extension with no explicit wiring.
+type audioOnlyExtension struct {
+	DefaultExtension
+	gotAudio bool
+}
+
+func (e *audioOnlyExtension) OnAudioFrame(tenEnv TenEnv, frame AudioFrame) {
+	e.gotAudio = true
+}
+
+func TestExtension_OverridingOnAudioFrameLeavesOnVideoFrameAtTheDefault(t *testing.T) {
+	ext := &audioOnlyExtension{}
+	var asExtension Extension = ext
+
+	frame, _ := NewAudioFrame("pcm")
+	video, _ := NewVideoFrame("raw")
+
+	asExtension.OnAudioFrame(nil, frame)
+	if !ext.gotAudio {
+		t.Fatal("OnAudioFrame override was not invoked through the Extension interface")
+	}
+
+	// OnVideoFrame was never overridden, so this must resolve to
+	// DefaultExtension's no-op rather than panicking or touching tenEnv.
+	asExtension.OnVideoFrame(nil, video)
+}
@@ -0,0 +1,34 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import "testing"
+
+func TestTenEnv_FlushLogsReturnsAfterInFlightLogsFinish(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+	engine.Init()
+	engine.Start()
+
+	engine.TenEnv().Log(LogLevelInfo, "before flush")
+	if err := engine.TenEnv().FlushLogs(); err != nil {
+		t.Fatalf("FlushLogs: %v", err)
+	}
+}
+
+func TestEngineStop_CallsFlushLogsDuringTeardown(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+	engine.Init()
+	engine.Start()
+	engine.TenEnv().Log(LogLevelInfo, "last message before shutdown")
+
+	if err := engine.Stop(); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+	// Stop already waited for FlushLogs internally; calling it again here
+	// should return immediately, confirming nothing was left in flight.
+	if err := engine.TenEnv().FlushLogs(); err != nil {
+		t.Fatalf("FlushLogs after Stop: %v", err)
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"errors"
+	"sync"
+)
+
+// ErrFrameBufLocked is returned by LockBuf when a media frame's buffer is
+// already locked, and by SetBuf while it's locked.
+var ErrFrameBufLocked = errors.New("ten: frame buffer is locked")
+
+// ErrFrameBufNotLocked is returned by UnlockBuf when called without a
+// matching LockBuf.
+var ErrFrameBufNotLocked = errors.New("ten: frame buffer is not locked")
+
+// frameBuf is the buffer half of AudioFrame and VideoFrame, embedded by
+// both so the lock/copy contract is implemented once. See AudioFrame's doc
+// comment for the thread-safety contract LockBuf/UnlockBuf follow.
+type frameBuf struct {
+	mu     sync.Mutex
+	buf    []byte
+	locked bool
+}
+
+// SetBuf copies buf into the frame's internal storage, so the caller is
+// free to reuse or mutate buf as soon as SetBuf returns. In a real binding
+// this is where the Go slice gets copied into native memory; this
+// simulation copies into another Go slice for the same effect. It returns
+// ErrBufferPoolExhausted if SetBufferPool is enforcing a cap that this
+// call's growth would exceed.
+func (f *frameBuf) SetBuf(buf []byte) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.locked {
+		return ErrFrameBufLocked
+	}
+	if err := reserveBufferPoolDelta(len(buf) - len(f.buf)); err != nil {
+		return err
+	}
+	f.buf = append([]byte(nil), buf...)
+	return nil
+}
+
+// LockBuf returns a slice aliasing the frame's underlying buffer directly --
+// no copy is made. The slice is valid only until the matching UnlockBuf
+// call.
+func (f *frameBuf) LockBuf() ([]byte, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.locked {
+		return nil, ErrFrameBufLocked
+	}
+	f.locked = true
+	return f.buf, nil
+}
+
+// UnlockBuf releases a buffer acquired via LockBuf. buf must be the slice
+// LockBuf returned; the caller must not use it after this call returns.
+func (f *frameBuf) UnlockBuf(buf []byte) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.locked {
+		return ErrFrameBufNotLocked
+	}
+	f.locked = false
+	return nil
+}
+
+// GetBufSize returns the frame's buffer length, so a caller can size its own
+// reusable buffer without locking.
+func (f *frameBuf) GetBufSize() (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.buf), nil
+}
+
+// resizeBuf grows or truncates the buffer to exactly n bytes in place,
+// preserving existing content and zero-filling any newly added bytes. It's
+// for a caller that fills in one region of the buffer at a time (e.g. one
+// audio channel of an interleaved or planar frame) rather than replacing
+// the whole thing via SetBuf.
+func (f *frameBuf) resizeBuf(n int) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.locked {
+		return ErrFrameBufLocked
+	}
+	if err := reserveBufferPoolDelta(n - len(f.buf)); err != nil {
+		return err
+	}
+	switch {
+	case len(f.buf) == n:
+	case len(f.buf) > n:
+		f.buf = f.buf[:n]
+	default:
+		f.buf = append(f.buf, make([]byte, n-len(f.buf))...)
+	}
+	return nil
+}
+
+// cloneBuf returns a copy of the live buffer, independent of this frameBuf's
+// own lock state -- Clone doesn't go through GetBuf/LockBuf, since a frame
+// being broadcast to several destinations shouldn't have its ability to be
+// cloned depend on whether one of those destinations happens to be holding
+// its buffer locked. The clone always starts unlocked.
+func (f *frameBuf) cloneBuf() []byte {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]byte(nil), f.buf...)
+}
@@ -0,0 +1,27 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"bytes"
+	"runtime"
+	"strconv"
+)
+
+// goroutineID returns the numeric ID of the calling goroutine, parsed from
+// the header line of runtime.Stack ("goroutine 123 [running]:"). Go
+// deliberately doesn't expose goroutine identity; this is the standard
+// workaround, used only to detect the SendCmdAndWait-on-the-dispatch-
+// goroutine misuse in engine.go, never for anything correctness-critical.
+func goroutineID() int64 {
+	var buf [64]byte
+	n := runtime.Stack(buf[:], false)
+	b := bytes.TrimPrefix(buf[:n], []byte("goroutine "))
+	if i := bytes.IndexByte(b, ' '); i >= 0 {
+		b = b[:i]
+	}
+	id, _ := strconv.ParseInt(string(b), 10, 64)
+	return id
+}
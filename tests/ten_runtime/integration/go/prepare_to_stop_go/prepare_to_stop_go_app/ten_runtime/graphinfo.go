@@ -0,0 +1,120 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"fmt"
+	"sort"
+)
+
+// ExtensionInfo describes one extension instance within a graph, as
+// reported by TenEnv.GetGraphInfo. Addon is empty for an instance that
+// wasn't created via CreateExtension -- this simulation has no way to
+// look up which addon (if any) a plain top-level Engine was instantiated
+// from.
+type ExtensionInfo struct {
+	Name  string
+	Addon string
+	State string
+}
+
+// ConnectionInfo describes one static connection between two extension
+// instances in a graph, the way ConnectionSpec records it on the
+// destination side when it's created via CreateExtension.
+type ConnectionInfo struct {
+	From    string
+	To      string
+	CmdName string
+}
+
+// GraphInfo is GetGraphInfo's result: every extension instance known to
+// exist in the calling extension's graph, and the connections declared
+// between them. Extensions is sorted by Name and Connections by
+// (From, To, CmdName), so two calls against an unchanged graph compare
+// equal regardless of internal map iteration order.
+type GraphInfo struct {
+	GraphID     string
+	Extensions  []ExtensionInfo
+	Connections []ConnectionInfo
+}
+
+// runStateName renders s the way GetGraphInfo reports it -- a stable
+// string rather than runState's own int value, since GraphInfo is meant
+// for tooling outside this package that has no reason to know runState's
+// internal numbering.
+func runStateName(s runState) string {
+	switch s {
+	case stateInit:
+		return "init"
+	case stateStarting:
+		return "starting"
+	case stateRunning:
+		return "running"
+	case statePausing:
+		return "pausing"
+	case statePaused:
+		return "paused"
+	case stateResuming:
+		return "resuming"
+	case stateStopping:
+		return "stopping"
+	case stateStopped:
+		return "stopped"
+	default:
+		return fmt.Sprintf("unknown(%d)", int(s))
+	}
+}
+
+func (t *tenEnvImpl) GetGraphInfo() (*GraphInfo, error) {
+	state, name, graphID, _ := t.engine.identityState()
+	if state == stateInit {
+		return nil, ErrExtensionIdentityNotYetAvailable
+	}
+	if name == "" {
+		name = t.engine.extensionName()
+	}
+
+	info := &GraphInfo{
+		GraphID: graphID,
+		Extensions: []ExtensionInfo{
+			{Name: name, State: runStateName(state)},
+		},
+	}
+
+	for childName, member := range dynamicInstanceSnapshot(graphID) {
+		childState, _, _, _ := member.engine.identityState()
+		info.Extensions = append(info.Extensions, ExtensionInfo{
+			Name:  childName,
+			Addon: member.addonName,
+			State: runStateName(childState),
+		})
+		for _, conn := range member.conns {
+			info.Connections = append(info.Connections, ConnectionInfo{
+				From:    member.creatorName,
+				To:      childName,
+				CmdName: conn.CmdName,
+			})
+		}
+	}
+
+	// dynamicInstanceSnapshot is a map, so without sorting, iteration
+	// order (and therefore Extensions/Connections order) would vary
+	// between calls with the exact same graph.
+	sort.Slice(info.Extensions, func(i, j int) bool {
+		return info.Extensions[i].Name < info.Extensions[j].Name
+	})
+	sort.Slice(info.Connections, func(i, j int) bool {
+		a, b := info.Connections[i], info.Connections[j]
+		if a.From != b.From {
+			return a.From < b.From
+		}
+		if a.To != b.To {
+			return a.To < b.To
+		}
+		return a.CmdName < b.CmdName
+	})
+
+	return info, nil
+}
@@ -0,0 +1,111 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestTenEnv_GetGraphInfoBeforeStartIsError(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+	if _, err := engine.TenEnv().GetGraphInfo(); !errors.Is(err, ErrExtensionIdentityNotYetAvailable) {
+		t.Fatalf("GetGraphInfo before Start = %v, want ErrExtensionIdentityNotYetAvailable", err)
+	}
+}
+
+func TestTenEnv_GetGraphInfoReportsSelf(t *testing.T) {
+	engine := NewEngine(DefaultExtension{}, WithExtensionName("solo"), WithGraphID("g-solo"))
+	engine.Init()
+	engine.Start()
+
+	info, err := engine.TenEnv().GetGraphInfo()
+	if err != nil {
+		t.Fatalf("GetGraphInfo: %v", err)
+	}
+	if info.GraphID != "g-solo" {
+		t.Fatalf("GraphID = %q, want %q", info.GraphID, "g-solo")
+	}
+	if len(info.Extensions) != 1 || info.Extensions[0].Name != "solo" || info.Extensions[0].State != "running" {
+		t.Fatalf("Extensions = %+v, want a single running %q entry", info.Extensions, "solo")
+	}
+	if len(info.Connections) != 0 {
+		t.Fatalf("Connections = %+v, want none for a graph with no CreateExtension calls", info.Connections)
+	}
+}
+
+func TestTenEnv_GetGraphInfoIncludesCreatedInstancesAndConnections(t *testing.T) {
+	reg, err := RegisterAddonAsExtension("synth65_child", NewDefaultExtensionAddon(
+		func(name string) Extension { return DefaultExtension{} },
+	))
+	if err != nil {
+		t.Fatalf("RegisterAddonAsExtension: %v", err)
+	}
+	defer reg.ReleaseInstance("worker-1")
+	defer reg.Unregister()
+
+	engine := NewEngine(DefaultExtension{}, WithExtensionName("parent"), WithGraphID("g-nested"))
+	engine.Init()
+	engine.Start()
+
+	handle, err := engine.TenEnv().CreateExtension(
+		"synth65_child", "worker-1", ConnectionSpec{CmdName: "transcribe"},
+	)
+	if err != nil {
+		t.Fatalf("CreateExtension: %v", err)
+	}
+	defer engine.TenEnv().DestroyExtension(handle)
+
+	info, err := engine.TenEnv().GetGraphInfo()
+	if err != nil {
+		t.Fatalf("GetGraphInfo: %v", err)
+	}
+
+	if len(info.Extensions) != 2 {
+		t.Fatalf("Extensions = %+v, want 2 entries", info.Extensions)
+	}
+	// Sorted by name: "parent" before "worker-1".
+	if info.Extensions[0].Name != "parent" || info.Extensions[0].State != "running" {
+		t.Fatalf("Extensions[0] = %+v, want the parent, running", info.Extensions[0])
+	}
+	if info.Extensions[1].Name != "worker-1" || info.Extensions[1].Addon != "synth65_child" || info.Extensions[1].State != "running" {
+		t.Fatalf("Extensions[1] = %+v, want worker-1/synth65_child, running", info.Extensions[1])
+	}
+
+	wantConn := ConnectionInfo{From: "parent", To: "worker-1", CmdName: "transcribe"}
+	if len(info.Connections) != 1 || info.Connections[0] != wantConn {
+		t.Fatalf("Connections = %+v, want [%+v]", info.Connections, wantConn)
+	}
+}
+
+func TestTenEnv_GetGraphInfoDropsDestroyedInstance(t *testing.T) {
+	reg, err := RegisterAddonAsExtension("synth65_gone", NewDefaultExtensionAddon(
+		func(name string) Extension { return DefaultExtension{} },
+	))
+	if err != nil {
+		t.Fatalf("RegisterAddonAsExtension: %v", err)
+	}
+	defer reg.Unregister()
+
+	engine := NewEngine(DefaultExtension{}, WithExtensionName("parent2"), WithGraphID("g-gone"))
+	engine.Init()
+	engine.Start()
+
+	handle, err := engine.TenEnv().CreateExtension("synth65_gone", "temp-1")
+	if err != nil {
+		t.Fatalf("CreateExtension: %v", err)
+	}
+	if err := engine.TenEnv().DestroyExtension(handle); err != nil {
+		t.Fatalf("DestroyExtension: %v", err)
+	}
+
+	info, err := engine.TenEnv().GetGraphInfo()
+	if err != nil {
+		t.Fatalf("GetGraphInfo: %v", err)
+	}
+	if len(info.Extensions) != 1 || info.Extensions[0].Name != "parent2" {
+		t.Fatalf("Extensions = %+v, want only the parent after DestroyExtension", info.Extensions)
+	}
+}
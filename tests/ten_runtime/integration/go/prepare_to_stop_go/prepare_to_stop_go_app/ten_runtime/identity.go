@@ -0,0 +1,85 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import "errors"
+
+// ErrExtensionIdentityNotYetAvailable is returned by GetExtensionName,
+// GetGraphID and GetAppURI when called before OnStart: a real ten_runtime
+// engine doesn't finish placing an extension instance into its graph
+// until then, so this simulation refuses to hand back an identity that
+// wouldn't yet be trustworthy on the native side either.
+var ErrExtensionIdentityNotYetAvailable = errors.New("ten: extension identity not yet available before OnStart")
+
+// WithExtensionName configures the name this extension instance is known
+// by within its graph -- the name a deployer's graph config gives this
+// node, as opposed to e.ext's Go type. It's what GetExtensionName
+// returns, and it's also what metrics recorded via WithMetricsRegistry
+// tag their "extension" dimension with in place of the
+// fmt.Sprintf("%T", ...) fallback extensionName() otherwise falls back
+// to. Leaving it unset keeps that fallback in both places.
+func WithExtensionName(name string) EngineOption {
+	return func(e *Engine) {
+		e.extensionInstanceName = name
+	}
+}
+
+// WithGraphID configures the ID of the graph this extension instance was
+// loaded into, the way a real ten_runtime engine assigns one when it
+// instantiates a graph from its JSON config. It's what GetGraphID
+// returns; there's no default; an Engine constructed without it reports
+// ErrExtensionIdentityNotYetAvailable exactly as if OnStart hadn't run
+// yet, since a real extension is never without a graph ID either.
+func WithGraphID(id string) EngineOption {
+	return func(e *Engine) {
+		e.graphID = id
+	}
+}
+
+// WithAppURI configures the URI of the app this extension instance runs
+// inside, matching the ten_runtime concept of the same name. It's what
+// GetAppURI returns.
+func WithAppURI(uri string) EngineOption {
+	return func(e *Engine) {
+		e.appURI = uri
+	}
+}
+
+// identityState locks the Engine and returns whichever of state's
+// runState / extensionInstanceName / graphID / appURI the caller asked
+// for is needed by the GetExtensionName/GetGraphID/GetAppURI trio below,
+// without each of them repeating the same lock dance.
+func (e *Engine) identityState() (state runState, extensionName, graphID, appURI string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.state, e.extensionInstanceName, e.graphID, e.appURI
+}
+
+func (t *tenEnvImpl) GetExtensionName() (string, error) {
+	state, name, _, _ := t.engine.identityState()
+	if state == stateInit {
+		return "", ErrExtensionIdentityNotYetAvailable
+	}
+	if name == "" {
+		return t.engine.extensionName(), nil
+	}
+	return name, nil
+}
+
+func (t *tenEnvImpl) GetGraphID() (string, error) {
+	state, _, graphID, _ := t.engine.identityState()
+	if state == stateInit {
+		return "", ErrExtensionIdentityNotYetAvailable
+	}
+	return graphID, nil
+}
+
+func (t *tenEnvImpl) GetAppURI() (string, error) {
+	state, _, _, appURI := t.engine.identityState()
+	if state == stateInit {
+		return "", ErrExtensionIdentityNotYetAvailable
+	}
+	return appURI, nil
+}
@@ -0,0 +1,79 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestTenEnv_IdentityUnavailableBeforeOnStart(t *testing.T) {
+	engine := NewEngine(DefaultExtension{},
+		WithExtensionName("router"), WithGraphID("g1"), WithAppURI("app://main"))
+
+	if _, err := engine.TenEnv().GetExtensionName(); !errors.Is(err, ErrExtensionIdentityNotYetAvailable) {
+		t.Fatalf("GetExtensionName before OnStart error = %v, want ErrExtensionIdentityNotYetAvailable", err)
+	}
+	if _, err := engine.TenEnv().GetGraphID(); !errors.Is(err, ErrExtensionIdentityNotYetAvailable) {
+		t.Fatalf("GetGraphID before OnStart error = %v, want ErrExtensionIdentityNotYetAvailable", err)
+	}
+	if _, err := engine.TenEnv().GetAppURI(); !errors.Is(err, ErrExtensionIdentityNotYetAvailable) {
+		t.Fatalf("GetAppURI before OnStart error = %v, want ErrExtensionIdentityNotYetAvailable", err)
+	}
+}
+
+func TestTenEnv_IdentityAvailableFromOnStartOnward(t *testing.T) {
+	engine := NewEngine(DefaultExtension{},
+		WithExtensionName("router"), WithGraphID("g1"), WithAppURI("app://main"))
+	engine.Init()
+	engine.Start()
+
+	name, err := engine.TenEnv().GetExtensionName()
+	if err != nil || name != "router" {
+		t.Fatalf("GetExtensionName = %q, %v, want %q, nil", name, err, "router")
+	}
+	graphID, err := engine.TenEnv().GetGraphID()
+	if err != nil || graphID != "g1" {
+		t.Fatalf("GetGraphID = %q, %v, want %q, nil", graphID, err, "g1")
+	}
+	appURI, err := engine.TenEnv().GetAppURI()
+	if err != nil || appURI != "app://main" {
+		t.Fatalf("GetAppURI = %q, %v, want %q, nil", appURI, err, "app://main")
+	}
+}
+
+func TestTenEnv_GetExtensionNameFallsBackToGoTypeWhenUnconfigured(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+	engine.Init()
+	engine.Start()
+
+	name, err := engine.TenEnv().GetExtensionName()
+	if err != nil {
+		t.Fatalf("GetExtensionName: %v", err)
+	}
+	if name != "ten.DefaultExtension" {
+		t.Fatalf("GetExtensionName = %q, want the fallback %%T name %q", name, "ten.DefaultExtension")
+	}
+}
+
+func TestEngine_ExtensionNameUsedAsMetricsTagWhenConfigured(t *testing.T) {
+	registry := NewMetricsRegistry()
+	engine := NewEngine(echoExtension{}, WithExtensionName("router"), WithMetricsRegistry(registry))
+	engine.Init()
+	engine.Start()
+
+	cmd, _ := NewCmd("ping")
+	if err := engine.DeliverCmd(cmd); err != nil {
+		t.Fatalf("DeliverCmd: %v", err)
+	}
+
+	var buf strings.Builder
+	registry.WriteTo(&buf)
+	out := buf.String()
+	if !strings.Contains(out, `extension="router"`) {
+		t.Fatalf("output missing configured extension name in metrics tag: %s", out)
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import "fmt"
+
+// JobHandle is returned by TenEnv.AcceptCommand for a Cmd that represents a
+// long-running job: it stands in for the terminal CmdResult that will be
+// delivered once the real work finishes, possibly well after OnCmd itself
+// has returned. See AcceptCommand's doc comment for the overall contract.
+type JobHandle interface {
+	// Complete delivers cmd's terminal result. Calling it more than once,
+	// or after the handle has already been force-completed by shutdown,
+	// returns ErrCmdAlreadyFinal -- the same error ReturnResultEx returns
+	// for a cmd that already has a final result, since Complete delegates
+	// to it for the actual delivery.
+	Complete(result CmdResult) error
+}
+
+// jobHandle is JobHandle's only implementation. resolve is the closure
+// AcceptCommand got back from registerPending: calling it both removes
+// cmd's pending-call entry and reports whether Complete won the race
+// against a shutdown-forced cancelPending -- see registerPending's doc
+// comment in engine.go for the resolve/peek contract this mirrors.
+type jobHandle struct {
+	tenEnv  *tenEnvImpl
+	cmd     Cmd
+	resolve func() bool
+}
+
+func (h *jobHandle) Complete(result CmdResult) error {
+	if !h.resolve() {
+		return ErrCmdAlreadyFinal
+	}
+	return h.tenEnv.ReturnResultEx(result, h.cmd, true)
+}
+
+// AcceptCommand implements TenEnv.AcceptCommand; see its doc comment for
+// the overall contract. The immediate ack is sent through ReturnResultEx
+// itself, so a cmd that was already given a final result (or whose engine
+// isn't sendable) fails the same way any other ReturnResultEx(..., false)
+// call would, before a JobHandle is ever handed out.
+func (t *tenEnvImpl) AcceptCommand(cmd Cmd) (JobHandle, error) {
+	if cmd == nil {
+		return nil, fmt.Errorf("ten: AcceptCommand: cmd must not be nil")
+	}
+
+	ack, err := NewCmdResult(StatusCodeOk, cmd)
+	if err != nil {
+		return nil, err
+	}
+	if err := t.ReturnResultEx(ack, cmd, false); err != nil {
+		return nil, err
+	}
+
+	resolve, _ := t.engine.registerPending(func() {
+		// This runs from inside Stop's own shutdown-forcing path, once the
+		// engine is already stateStopping -- going through ReturnResultEx
+		// would just bounce off checkSendable, the same guard SendAudioFrame/
+		// SendData/SendVideoFrame's own onTimeout callbacks above sidestep
+		// by not routing back through their normal send path either. cmd's
+		// finalized bit and finalizeReturnedCmd's bookkeeping are set
+		// directly instead, exactly what ReturnResultEx would have done.
+		if cmd.finalized(true) {
+			return
+		}
+		result, err := NewCmdResult(StatusCodeError, cmd)
+		if err != nil {
+			return
+		}
+		_ = result.SetPropertyString("detail", ErrShutdownTimeout.Error())
+		if impl, ok := result.(*cmdResultImpl); ok {
+			impl.isFinal = true
+		}
+		t.engine.finalizeReturnedCmd(cmd)
+		t.engine.notifyCrossExtResultHandler(cmd, result)
+	})
+
+	cmd.markCompletionTokenTaken()
+	return &jobHandle{tenEnv: t, cmd: cmd, resolve: resolve}, nil
+}
@@ -0,0 +1,97 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTenEnv_AcceptCommandSendsNonFinalAckAndCompleteFinalizes(t *testing.T) {
+	engine := NewEngine(newLifecycleExtension())
+	cmd, _ := NewCmd("job")
+
+	handle, err := engine.tenEnv.AcceptCommand(cmd)
+	if err != nil {
+		t.Fatalf("AcceptCommand: %v", err)
+	}
+	if cmd.finalized(false) {
+		t.Fatal("cmd already final right after AcceptCommand, want only the ack sent")
+	}
+
+	final, _ := NewCmdResult(StatusCodeOk, cmd)
+	if err := handle.Complete(final); err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+	if !cmd.finalized(false) {
+		t.Fatal("cmd not final after Complete")
+	}
+}
+
+func TestTenEnv_AcceptCommandCompleteTwiceIsErrCmdAlreadyFinal(t *testing.T) {
+	engine := NewEngine(newLifecycleExtension())
+	cmd, _ := NewCmd("job")
+
+	handle, err := engine.tenEnv.AcceptCommand(cmd)
+	if err != nil {
+		t.Fatalf("AcceptCommand: %v", err)
+	}
+
+	final, _ := NewCmdResult(StatusCodeOk, cmd)
+	if err := handle.Complete(final); err != nil {
+		t.Fatalf("first Complete: %v", err)
+	}
+
+	again, _ := NewCmdResult(StatusCodeOk, cmd)
+	if err := handle.Complete(again); !errors.Is(err, ErrCmdAlreadyFinal) {
+		t.Fatalf("second Complete = %v, want ErrCmdAlreadyFinal", err)
+	}
+}
+
+func TestTenEnv_AcceptCommandOnNilCmdErrors(t *testing.T) {
+	engine := NewEngine(newLifecycleExtension())
+	if _, err := engine.tenEnv.AcceptCommand(nil); err == nil {
+		t.Fatal("AcceptCommand(nil) = nil error, want an error")
+	}
+}
+
+// TestTenEnv_AcceptCommandForceCompletedOnShutdownDeadline exercises the
+// interaction the request asked to be documented: a JobHandle whose
+// Complete is never called is force-completed with ErrShutdownTimeout the
+// same way an in-flight SendCmd is, once the engine's shutdown deadline
+// expires -- see TestEngineStop_CancelsInFlightSendCmdOnTimeout for the
+// SendCmd analogue this mirrors.
+func TestTenEnv_AcceptCommandForceCompletedOnShutdownDeadline(t *testing.T) {
+	engine := NewEngine(
+		hangingStopExtension{},
+		WithShutdownDeadline(20*time.Millisecond),
+	)
+	if err := engine.Init(); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	if err := engine.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	cmd, _ := NewCmd("job")
+	handle, err := engine.tenEnv.AcceptCommand(cmd)
+	if err != nil {
+		t.Fatalf("AcceptCommand: %v", err)
+	}
+
+	if err := engine.Stop(); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+
+	if !cmd.finalized(false) {
+		t.Fatal("cmd not force-completed by the shutdown deadline")
+	}
+
+	tooLate, _ := NewCmdResult(StatusCodeOk, cmd)
+	if err := handle.Complete(tooLate); !errors.Is(err, ErrCmdAlreadyFinal) {
+		t.Fatalf("Complete after shutdown force = %v, want ErrCmdAlreadyFinal", err)
+	}
+}
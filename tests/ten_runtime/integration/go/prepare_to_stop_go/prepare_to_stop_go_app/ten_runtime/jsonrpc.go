@@ -0,0 +1,142 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// JSONRPCRequest is the subset of a JSON-RPC 2.0 request object
+// JSONRPCCodec understands: Method maps onto the Cmd's name, and Params --
+// when present, it must be a JSON object -- maps onto the Cmd's
+// properties one field per key, the same shape Cmd.SetPropertyFromJSONBytes
+// expects for a whole-bag replace at an empty path. ID may be a string, a
+// number, or absent for a notification; JSONRPCCodec never interprets it,
+// only carries it (see JSONRPCCodec.RequestID).
+type JSONRPCRequest struct {
+	ID     json.RawMessage `json:"id,omitempty"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params,omitempty"`
+}
+
+// JSONRPCError is a JSON-RPC 2.0 error object.
+type JSONRPCError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// JSONRPCResponse is a JSON-RPC 2.0 response object. Result and Error are
+// mutually exclusive, mirroring the spec: JSONRPCCodec.FromResult always
+// sets exactly one.
+type JSONRPCResponse struct {
+	ID     json.RawMessage `json:"id,omitempty"`
+	Result any             `json:"result,omitempty"`
+	Error  *JSONRPCError   `json:"error,omitempty"`
+}
+
+// JSONRPCCodec maps between JSON-RPC 2.0 requests/responses and this
+// package's Cmd/CmdResult, so a JSON-RPC endpoint can front a TEN graph
+// without hand-rolling the translation at every call site. It carries no
+// state of its own -- the zero value is ready to use.
+type JSONRPCCodec struct{}
+
+// ToCmd builds a Cmd for req: req.Method becomes the Cmd's name, and
+// req.Params, if present, replaces the Cmd's entire property bag via
+// SetPropertyFromJSONBytes, so req.Params must be a JSON object (or
+// absent), the same requirement SetPropertyFromJSONBytes has for an empty
+// path. req.ID, if present, is stashed in a reserved property so
+// RequestID can recover it later when assembling the JSONRPCResponse for
+// whatever CmdResult this Cmd eventually finalizes with.
+func (JSONRPCCodec) ToCmd(req JSONRPCRequest) (Cmd, error) {
+	if req.Method == "" {
+		return nil, fmt.Errorf("ten: JSONRPCRequest.Method is empty")
+	}
+
+	cmd, err := NewCmd(req.Method)
+	if err != nil {
+		return nil, err
+	}
+	if len(req.Params) > 0 {
+		if err := cmd.SetPropertyFromJSONBytes("", req.Params); err != nil {
+			return nil, fmt.Errorf("ten: decoding JSON-RPC params: %w", err)
+		}
+	}
+	if len(req.ID) > 0 {
+		if err := cmd.SetPropertyFromJSONBytes(jsonrpcIDProperty, req.ID); err != nil {
+			return nil, fmt.Errorf("ten: decoding JSON-RPC id: %w", err)
+		}
+	}
+	return cmd, nil
+}
+
+// RequestID recovers the JSON-RPC id ToCmd stashed on cmd, for use as the
+// ID field of the JSONRPCResponse FromResult builds for whatever
+// CmdResult eventually finalizes cmd -- a CmdResult carries no reference
+// back to the Cmd it answers (see NewCmdResult, which takes one but
+// doesn't copy anything off it), so nothing FromResult receives could
+// recover the id on its own. It returns nil, false for a Cmd that never
+// went through ToCmd, or one built from a notification (a JSONRPCRequest
+// with no ID).
+func (JSONRPCCodec) RequestID(cmd Cmd) (json.RawMessage, bool) {
+	raw, err := cmd.GetPropertyToJSONBytes(jsonrpcIDProperty)
+	if err != nil {
+		return nil, false
+	}
+	return raw, true
+}
+
+// FromResult maps cs back to a JSON-RPC 2.0 response body: a StatusCodeOk
+// result's properties become Result, in the same whole-bag shape ToCmd
+// decoded Params from; any other status code becomes an Error, built from
+// cs.AsError() with cs.StatusCode() mapped to a JSON-RPC error code via
+// statusCodeToJSONRPCCode. The returned JSONRPCResponse's ID is always
+// unset -- pair it with RequestID(cmd) yourself, typically right where cs
+// was produced and cmd is still at hand.
+func (JSONRPCCodec) FromResult(cs CmdResult) (JSONRPCResponse, error) {
+	statusCode, err := cs.StatusCode()
+	if err != nil {
+		return JSONRPCResponse{}, err
+	}
+
+	if statusCode == StatusCodeOk {
+		props, err := cs.PropertiesSnapshot()
+		if err != nil {
+			return JSONRPCResponse{}, err
+		}
+		return JSONRPCResponse{Result: props}, nil
+	}
+
+	return JSONRPCResponse{
+		Error: &JSONRPCError{
+			Code:    statusCodeToJSONRPCCode(statusCode),
+			Message: cs.AsError().Error(),
+		},
+	}, nil
+}
+
+// statusCodeToJSONRPCCode maps a StatusCode to a JSON-RPC 2.0 error code:
+// StatusCodeInvalidArgument reuses the spec's own "Invalid params"
+// (-32602), an exact semantic match; the rest land in "-32000 to -32099
+// reserved for implementation-defined server errors", the range the spec
+// sets aside for exactly this. A cmd-specific StatusCode beyond the
+// well-known constants still gets a stable, distinct code in that range
+// rather than colliding with one of these.
+func statusCodeToJSONRPCCode(sc StatusCode) int {
+	switch sc {
+	case StatusCodeInvalidArgument:
+		return -32602
+	case StatusCodeNotFound:
+		return -32001
+	case StatusCodeUnauthorized:
+		return -32002
+	case StatusCodeUnavailable:
+		return -32003
+	case StatusCodeError:
+		return -32000
+	default:
+		return -32000 - int(sc)
+	}
+}
@@ -0,0 +1,155 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import "testing"
+
+func TestJSONRPCCodec_ToCmdMapsMethodAndParams(t *testing.T) {
+	var codec JSONRPCCodec
+
+	cmd, err := codec.ToCmd(JSONRPCRequest{
+		ID:     []byte(`1`),
+		Method: "greet",
+		Params: []byte(`{"name":"ada"}`),
+	})
+	if err != nil {
+		t.Fatalf("ToCmd: %v", err)
+	}
+
+	name, err := cmd.GetName()
+	if err != nil || name != "greet" {
+		t.Fatalf("GetName() = %q, %v, want \"greet\", nil", name, err)
+	}
+	got, err := cmd.GetPropertyString("name")
+	if err != nil || got != "ada" {
+		t.Fatalf("GetPropertyString(\"name\") = %q, %v, want \"ada\", nil", got, err)
+	}
+}
+
+func TestJSONRPCCodec_ToCmdRejectsEmptyMethod(t *testing.T) {
+	var codec JSONRPCCodec
+
+	if _, err := codec.ToCmd(JSONRPCRequest{}); err == nil {
+		t.Fatal("ToCmd with an empty Method succeeded, want an error")
+	}
+}
+
+func TestJSONRPCCodec_ToCmdWithoutParamsLeavesPropertiesEmpty(t *testing.T) {
+	var codec JSONRPCCodec
+
+	cmd, err := codec.ToCmd(JSONRPCRequest{Method: "ping"})
+	if err != nil {
+		t.Fatalf("ToCmd: %v", err)
+	}
+	keys, err := cmd.PropertyKeys()
+	if err != nil {
+		t.Fatalf("PropertyKeys: %v", err)
+	}
+	if len(keys) != 0 {
+		t.Fatalf("PropertyKeys() = %v, want none", keys)
+	}
+}
+
+func TestJSONRPCCodec_RequestIDRoundTripsThroughToCmd(t *testing.T) {
+	var codec JSONRPCCodec
+
+	cmd, err := codec.ToCmd(JSONRPCRequest{ID: []byte(`"req-1"`), Method: "ping"})
+	if err != nil {
+		t.Fatalf("ToCmd: %v", err)
+	}
+
+	id, ok := codec.RequestID(cmd)
+	if !ok {
+		t.Fatal("RequestID() ok = false, want true")
+	}
+	if string(id) != `"req-1"` {
+		t.Fatalf("RequestID() = %s, want \"req-1\"", id)
+	}
+}
+
+func TestJSONRPCCodec_RequestIDReportsFalseForANotification(t *testing.T) {
+	var codec JSONRPCCodec
+
+	cmd, err := codec.ToCmd(JSONRPCRequest{Method: "ping"})
+	if err != nil {
+		t.Fatalf("ToCmd: %v", err)
+	}
+	if _, ok := codec.RequestID(cmd); ok {
+		t.Fatal("RequestID() ok = true for a notification with no id")
+	}
+}
+
+func TestJSONRPCCodec_FromResultMapsOkStatusToResult(t *testing.T) {
+	var codec JSONRPCCodec
+
+	cmd, _ := NewCmd("greet")
+	result, _ := NewCmdResult(StatusCodeOk, cmd)
+	result.SetPropertyString("reply", "hello ada")
+
+	resp, err := codec.FromResult(result)
+	if err != nil {
+		t.Fatalf("FromResult: %v", err)
+	}
+	if resp.Error != nil {
+		t.Fatalf("Error = %v, want nil", resp.Error)
+	}
+	props, ok := resp.Result.(map[string]any)
+	if !ok || props["reply"] != "hello ada" {
+		t.Fatalf("Result = %v, want a map with reply=hello ada", resp.Result)
+	}
+}
+
+func TestJSONRPCCodec_FromResultMapsErrorStatusToError(t *testing.T) {
+	var codec JSONRPCCodec
+
+	cmd, _ := NewCmd("greet")
+	result, _ := NewCmdResult(StatusCodeInvalidArgument, cmd)
+	result.SetPropertyString("detail", "missing name")
+
+	resp, err := codec.FromResult(result)
+	if err != nil {
+		t.Fatalf("FromResult: %v", err)
+	}
+	if resp.Result != nil {
+		t.Fatalf("Result = %v, want nil", resp.Result)
+	}
+	if resp.Error == nil {
+		t.Fatal("Error = nil, want a JSON-RPC error object")
+	}
+	if resp.Error.Code != -32602 {
+		t.Fatalf("Error.Code = %d, want -32602 for StatusCodeInvalidArgument", resp.Error.Code)
+	}
+	if resp.Error.Message != "missing name" {
+		t.Fatalf("Error.Message = %q, want %q", resp.Error.Message, "missing name")
+	}
+}
+
+func TestJSONRPCCodec_FromResultMapsEachWellKnownStatusCodeToADistinctErrorCode(t *testing.T) {
+	var codec JSONRPCCodec
+
+	cases := []struct {
+		status StatusCode
+		want   int
+	}{
+		{StatusCodeError, -32000},
+		{StatusCodeNotFound, -32001},
+		{StatusCodeUnauthorized, -32002},
+		{StatusCodeUnavailable, -32003},
+		{StatusCodeInvalidArgument, -32602},
+	}
+	for _, c := range cases {
+		cmd, _ := NewCmd("greet")
+		result, _ := NewCmdResult(c.status, cmd)
+		result.SetPropertyString("detail", "boom")
+
+		resp, err := codec.FromResult(result)
+		if err != nil {
+			t.Fatalf("FromResult: %v", err)
+		}
+		if resp.Error == nil || resp.Error.Code != c.want {
+			t.Fatalf("StatusCode %d -> Error = %v, want code %d", c.status, resp.Error, c.want)
+		}
+	}
+}
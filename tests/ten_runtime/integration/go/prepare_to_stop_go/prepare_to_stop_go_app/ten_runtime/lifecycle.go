@@ -0,0 +1,100 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// LifecycleEventKind identifies which transition a LifecycleEvent reports.
+type LifecycleEventKind int
+
+const (
+	LifecycleExtensionCreated LifecycleEventKind = iota
+	LifecycleExtensionStarted
+	LifecycleExtensionStopped
+	LifecycleCmdReceived
+	LifecycleResultReturned
+)
+
+// String renders k the way a log line or a metric label would want it,
+// rather than the bare integer fmt.Stringer's zero-value default gives.
+func (k LifecycleEventKind) String() string {
+	switch k {
+	case LifecycleExtensionCreated:
+		return "extension_created"
+	case LifecycleExtensionStarted:
+		return "extension_started"
+	case LifecycleExtensionStopped:
+		return "extension_stopped"
+	case LifecycleCmdReceived:
+		return "cmd_received"
+	case LifecycleResultReturned:
+		return "result_returned"
+	default:
+		return "unknown"
+	}
+}
+
+// LifecycleEvent is one transition reported to the observer registered via
+// SetLifecycleObserver. MessageName and MessageType are only populated for
+// LifecycleCmdReceived/LifecycleResultReturned; every other kind leaves
+// them empty.
+type LifecycleEvent struct {
+	Kind        LifecycleEventKind
+	Extension   string
+	Time        time.Time
+	MessageName string
+	MessageType string
+}
+
+// lifecycleObserver holds the single, process-wide observer registered via
+// SetLifecycleObserver, or nil if none is. It's an atomic.Pointer rather
+// than a mutex-guarded var so emitLifecycleEvent's fast path -- no observer
+// registered, the common case outside of active monitoring -- is a single
+// atomic load and nothing else: no lock, no allocation.
+var lifecycleObserver atomic.Pointer[func(LifecycleEvent)]
+
+// SetLifecycleObserver registers fn to be called, from whatever goroutine
+// triggers it, at every extension create/start/stop and command
+// receive/result transition across every Engine in the process -- a single
+// stream for monitoring without instrumenting each extension individually.
+// Passing nil disables it. There is only ever one observer; a second call
+// replaces whatever fn a previous call registered.
+func SetLifecycleObserver(fn func(ev LifecycleEvent)) {
+	if fn == nil {
+		lifecycleObserver.Store(nil)
+		return
+	}
+	lifecycleObserver.Store(&fn)
+}
+
+// emitLifecycleEvent is a no-op, without even constructing a LifecycleEvent,
+// unless SetLifecycleObserver has been called.
+func emitLifecycleEvent(kind LifecycleEventKind, extensionName string) {
+	fn := lifecycleObserver.Load()
+	if fn == nil {
+		return
+	}
+	(*fn)(LifecycleEvent{Kind: kind, Extension: extensionName, Time: time.Now()})
+}
+
+// emitLifecycleMessageEvent is emitLifecycleEvent for the two message-shaped
+// kinds, LifecycleCmdReceived and LifecycleResultReturned, which also carry
+// the message's name and type.
+func emitLifecycleMessageEvent(kind LifecycleEventKind, extensionName, messageName, messageType string) {
+	fn := lifecycleObserver.Load()
+	if fn == nil {
+		return
+	}
+	(*fn)(LifecycleEvent{
+		Kind:        kind,
+		Extension:   extensionName,
+		Time:        time.Now(),
+		MessageName: messageName,
+		MessageType: messageType,
+	})
+}
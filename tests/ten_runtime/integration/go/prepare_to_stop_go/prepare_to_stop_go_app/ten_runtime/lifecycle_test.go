@@ -0,0 +1,130 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"sync"
+	"testing"
+)
+
+// collectLifecycleEvents registers an observer that appends every event it
+// sees to a slice guarded by a mutex, and returns a func to fetch a
+// snapshot plus a cleanup that clears the observer -- every test using it
+// must defer the cleanup so it doesn't leak into a later test.
+func collectLifecycleEvents(t *testing.T) (snapshot func() []LifecycleEvent, cleanup func()) {
+	t.Helper()
+	var mu sync.Mutex
+	var events []LifecycleEvent
+	SetLifecycleObserver(func(ev LifecycleEvent) {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, ev)
+	})
+	return func() []LifecycleEvent {
+			mu.Lock()
+			defer mu.Unlock()
+			return append([]LifecycleEvent(nil), events...)
+		}, func() {
+			SetLifecycleObserver(nil)
+		}
+}
+
+func TestLifecycleObserver_SeesCreatedStartedStopped(t *testing.T) {
+	snapshot, cleanup := collectLifecycleEvents(t)
+	defer cleanup()
+
+	engine := NewEngine(DefaultExtension{}, WithExtensionName("lifecycle-1"))
+	if err := engine.Init(); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	if err := engine.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if err := engine.Stop(); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+
+	events := snapshot()
+	var kinds []LifecycleEventKind
+	for _, ev := range events {
+		if ev.Extension != "lifecycle-1" {
+			t.Fatalf("event %v has Extension = %q, want lifecycle-1", ev.Kind, ev.Extension)
+		}
+		if ev.Time.IsZero() {
+			t.Fatalf("event %v has zero Time", ev.Kind)
+		}
+		kinds = append(kinds, ev.Kind)
+	}
+
+	want := []LifecycleEventKind{
+		LifecycleExtensionCreated, LifecycleExtensionStarted, LifecycleExtensionStopped,
+	}
+	if len(kinds) != len(want) {
+		t.Fatalf("kinds = %v, want %v", kinds, want)
+	}
+	for i, k := range want {
+		if kinds[i] != k {
+			t.Fatalf("kinds[%d] = %v, want %v", i, kinds[i], k)
+		}
+	}
+}
+
+func TestLifecycleObserver_SeesCmdReceivedAndResultReturned(t *testing.T) {
+	snapshot, cleanup := collectLifecycleEvents(t)
+	defer cleanup()
+
+	engine := NewEngine(echoExtension{}, WithExtensionName("lifecycle-2"))
+	if err := engine.Init(); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	if err := engine.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	cmd, _ := NewCmd("greet")
+	if err := engine.DeliverCmd(cmd); err != nil {
+		t.Fatalf("DeliverCmd: %v", err)
+	}
+
+	var received, returned *LifecycleEvent
+	for i, ev := range snapshot() {
+		if ev.Kind == LifecycleCmdReceived {
+			e := snapshot()[i]
+			received = &e
+		}
+		if ev.Kind == LifecycleResultReturned {
+			e := snapshot()[i]
+			returned = &e
+		}
+	}
+	if received == nil {
+		t.Fatal("no LifecycleCmdReceived event observed")
+	}
+	if received.MessageName != "greet" || received.MessageType != "cmd" {
+		t.Fatalf("LifecycleCmdReceived = %+v, want MessageName=greet MessageType=cmd", *received)
+	}
+	if returned == nil {
+		t.Fatal("no LifecycleResultReturned event observed")
+	}
+	if returned.MessageName != "greet" || returned.MessageType != "cmd" {
+		t.Fatalf("LifecycleResultReturned = %+v, want MessageName=greet MessageType=cmd", *returned)
+	}
+}
+
+func TestSetLifecycleObserver_NilDisablesIt(t *testing.T) {
+	var calls int
+	SetLifecycleObserver(func(LifecycleEvent) { calls++ })
+	SetLifecycleObserver(nil)
+	defer SetLifecycleObserver(nil)
+
+	engine := NewEngine(DefaultExtension{})
+	if err := engine.Init(); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+
+	if calls != 0 {
+		t.Fatalf("calls = %d, want 0 after SetLifecycleObserver(nil)", calls)
+	}
+}
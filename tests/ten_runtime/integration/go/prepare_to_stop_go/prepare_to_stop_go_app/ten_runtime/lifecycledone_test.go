@@ -0,0 +1,93 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTenEnv_OnConfigureDoneCalledTwiceDoesNotPanic(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+
+	buf := &lockedBuffer{}
+	engine.TenEnv().SetLogSink(buf, LogFormatText)
+
+	engine.tenEnv.OnConfigureDone()
+	engine.tenEnv.OnConfigureDone()
+
+	if !strings.Contains(buf.String(), "OnConfigureDone called more than once") {
+		t.Fatalf("log = %q, want it to mention the redundant OnConfigureDone call", buf.String())
+	}
+}
+
+func TestTenEnv_OnInitDoneCalledTwiceDoesNotPanic(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+	engine.Init()
+
+	buf := &lockedBuffer{}
+	engine.TenEnv().SetLogSink(buf, LogFormatText)
+
+	engine.tenEnv.OnInitDone()
+	engine.tenEnv.OnInitDone()
+
+	if !strings.Contains(buf.String(), "OnInitDone called more than once") {
+		t.Fatalf("log = %q, want it to mention the redundant OnInitDone call", buf.String())
+	}
+}
+
+func TestTenEnv_OnStartDoneCalledTwiceDoesNotPanic(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+	engine.Init()
+	engine.Start()
+
+	buf := &lockedBuffer{}
+	engine.TenEnv().SetLogSink(buf, LogFormatText)
+
+	engine.tenEnv.OnStartDone()
+	engine.tenEnv.OnStartDone()
+
+	if !strings.Contains(buf.String(), "OnStartDone called more than once") {
+		t.Fatalf("log = %q, want it to mention the redundant OnStartDone call", buf.String())
+	}
+}
+
+func TestTenEnv_OnStopDoneCalledTwiceDoesNotPanic(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+	engine.Init()
+	engine.Start()
+
+	buf := &lockedBuffer{}
+	engine.TenEnv().SetLogSink(buf, LogFormatText)
+
+	go engine.Stop()
+	engine.tenEnv.OnStopDone()
+	engine.tenEnv.OnStopDone()
+
+	if !strings.Contains(buf.String(), "OnStopDone called more than once") {
+		t.Fatalf("log = %q, want it to mention the redundant OnStopDone call", buf.String())
+	}
+}
+
+func TestTenEnv_OnPauseDoneCalledTwiceDoesNotPanic(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+	engine.Init()
+	engine.Start()
+
+	// DefaultExtension.OnPause already calls OnPauseDone once on Pause's
+	// behalf; a second, redundant call simulates two goroutines racing to
+	// signal the same pause acknowledgment.
+	if err := engine.Pause(); err != nil {
+		t.Fatalf("Pause: %v", err)
+	}
+
+	buf := &lockedBuffer{}
+	engine.TenEnv().SetLogSink(buf, LogFormatText)
+	engine.tenEnv.OnPauseDone()
+
+	if !strings.Contains(buf.String(), "OnPauseDone called more than once") {
+		t.Fatalf("log = %q, want it to mention the redundant OnPauseDone call", buf.String())
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+// Loc identifies a location within a graph -- an app, one of its graphs,
+// and (optionally) one extension instance inside that graph -- the same
+// three identifiers GetExtensionName/GetGraphID/GetAppURI report for this
+// extension's own identity (see identity.go). Cmd's GetSource/GetDests/
+// SetDests use it to describe where a command came from and where it's
+// headed, for a generic router extension that needs to make that decision
+// without hard-wiring connections in a graph's JSON.
+type Loc struct {
+	AppURI        string
+	GraphID       string
+	ExtensionName string
+}
+
+// stampCmdSource records this extension's own Loc as cmd's source, the way
+// a real ten_runtime engine attributes an outgoing cmd to whichever
+// extension sent it. It's called by SendCmd/SendCmdEx just before handing
+// cmd to the sender, so a downstream extension's GetSource (if this
+// package ever simulated more than one live extension per Engine) would
+// see this extension's identity. GetExtensionName's own OnStart-only
+// availability doesn't apply here -- SendCmd is only ever meaningfully
+// called from OnStart onward anyway, and a source Loc with an empty
+// ExtensionName (because WithExtensionName was never configured) is still
+// useful, just less specific.
+func stampCmdSource(e *Engine, cmd Cmd) {
+	c, ok := cmd.(*cmdImpl)
+	if !ok {
+		return
+	}
+	c.source = e.currentSourceLoc()
+}
+
+// currentSourceLoc builds the Loc stampCmdSource and its Data/AudioFrame/
+// VideoFrame counterparts stamp onto an outgoing message: this extension's
+// own identity, falling back to e.extensionName() when WithExtensionName
+// wasn't configured, exactly as stampCmdSource always has.
+func (e *Engine) currentSourceLoc() Loc {
+	_, extensionName, graphID, appURI := e.identityState()
+	if extensionName == "" {
+		extensionName = e.extensionName()
+	}
+	return Loc{AppURI: appURI, GraphID: graphID, ExtensionName: extensionName}
+}
+
+// stampDataSource, stampAudioFrameSource and stampVideoFrameSource record
+// this extension's own Loc as the message's source, the same way
+// stampCmdSource does for a Cmd. They're called by SendData/SendAudioFrame/
+// SendVideoFrame just before handing the message to its sender.
+func stampDataSource(e *Engine, data Data) {
+	d, ok := data.(*dataImpl)
+	if !ok {
+		return
+	}
+	d.source = e.currentSourceLoc()
+}
+
+func stampAudioFrameSource(e *Engine, frame AudioFrame) {
+	a, ok := frame.(*audioFrameImpl)
+	if !ok {
+		return
+	}
+	a.source = e.currentSourceLoc()
+}
+
+func stampVideoFrameSource(e *Engine, frame VideoFrame) {
+	v, ok := frame.(*videoFrameImpl)
+	if !ok {
+		return
+	}
+	v.source = e.currentSourceLoc()
+}
+
+// stampCmdDispatchEngine records the Engine that's about to hand cmd to
+// ext.OnCmd, the way stampCmdSource records an outgoing cmd's origin. It's
+// called by dispatchOnCmdSync just before invoking OnCmd, so
+// Cmd.DetachResponder can later find its way back to this same Engine to
+// deliver a result -- possibly from code running against a completely
+// different Engine instance. See responder.go.
+func stampCmdDispatchEngine(e *Engine, cmd Cmd) {
+	c, ok := cmd.(*cmdImpl)
+	if !ok {
+		return
+	}
+	c.dispatchEngine = e
+}
@@ -0,0 +1,99 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import "testing"
+
+func TestCmd_GetSourceIsZeroLocBeforeAnySend(t *testing.T) {
+	cmd, _ := NewCmd("route")
+
+	source, err := cmd.GetSource()
+	if err != nil {
+		t.Fatalf("GetSource: %v", err)
+	}
+	if source != (Loc{}) {
+		t.Fatalf("GetSource on a never-sent cmd = %+v, want a zero Loc", source)
+	}
+}
+
+func TestCmd_GetDestsIsEmptyUntilSetDests(t *testing.T) {
+	cmd, _ := NewCmd("route")
+
+	dests, err := cmd.GetDests()
+	if err != nil {
+		t.Fatalf("GetDests: %v", err)
+	}
+	if len(dests) != 0 {
+		t.Fatalf("GetDests before SetDests = %+v, want empty", dests)
+	}
+
+	want := []Loc{{ExtensionName: "logger"}, {ExtensionName: "storage"}}
+	if err := cmd.SetDests(want...); err != nil {
+		t.Fatalf("SetDests: %v", err)
+	}
+	got, _ := cmd.GetDests()
+	if len(got) != 2 || got[0] != want[0] || got[1] != want[1] {
+		t.Fatalf("GetDests after SetDests = %+v, want %+v", got, want)
+	}
+}
+
+func TestCmd_SetDestsWithNoArgumentsClearsThem(t *testing.T) {
+	cmd, _ := NewCmd("route")
+	cmd.SetDests(Loc{ExtensionName: "logger"})
+
+	if err := cmd.SetDests(); err != nil {
+		t.Fatalf("SetDests: %v", err)
+	}
+	dests, _ := cmd.GetDests()
+	if len(dests) != 0 {
+		t.Fatalf("GetDests after clearing = %+v, want empty", dests)
+	}
+}
+
+func TestCmd_CloneResetsSourceButKeepsDests(t *testing.T) {
+	engine := NewEngine(DefaultExtension{}, WithExtensionName("router"), WithGraphID("g1"), WithAppURI("app://main"))
+	engine.Init()
+	engine.Start()
+	engine.SetSender(func(cmd Cmd, handler CmdResultHandler) {})
+
+	cmd, _ := NewCmd("route")
+	cmd.SetDests(Loc{ExtensionName: "logger"})
+	engine.TenEnv().SendCmd(cmd, func(TenEnv, CmdResult, error) {})
+
+	source, _ := cmd.GetSource()
+	if source.ExtensionName != "router" || source.GraphID != "g1" || source.AppURI != "app://main" {
+		t.Fatalf("GetSource after SendCmd = %+v, want the sending extension's own identity", source)
+	}
+
+	clone, _ := cmd.Clone()
+	cloneSource, _ := clone.GetSource()
+	if cloneSource != (Loc{}) {
+		t.Fatalf("Clone's GetSource = %+v, want a zero Loc since it hasn't been sent yet", cloneSource)
+	}
+	cloneDests, _ := clone.GetDests()
+	if len(cloneDests) != 1 || cloneDests[0].ExtensionName != "logger" {
+		t.Fatalf("Clone's GetDests = %+v, want the original's dests preserved", cloneDests)
+	}
+}
+
+func TestTenEnv_SendCmdStampsSourceFromEngineIdentity(t *testing.T) {
+	engine := NewEngine(DefaultExtension{}, WithExtensionName("router"), WithGraphID("g1"), WithAppURI("app://main"))
+	engine.Init()
+	engine.Start()
+
+	var sourceSeenBySender Loc
+	engine.SetSender(func(cmd Cmd, handler CmdResultHandler) {
+		sourceSeenBySender, _ = cmd.GetSource()
+	})
+
+	cmd, _ := NewCmd("route")
+	if err := engine.TenEnv().SendCmd(cmd, func(TenEnv, CmdResult, error) {}); err != nil {
+		t.Fatalf("SendCmd: %v", err)
+	}
+	want := Loc{AppURI: "app://main", GraphID: "g1", ExtensionName: "router"}
+	if sourceSeenBySender != want {
+		t.Fatalf("source seen by sender = %+v, want %+v", sourceSeenBySender, want)
+	}
+}
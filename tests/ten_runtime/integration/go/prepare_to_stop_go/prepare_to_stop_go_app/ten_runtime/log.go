@@ -0,0 +1,146 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// LogLevel mirrors the log levels understood by the native ten_runtime log
+// subsystem.
+type LogLevel int
+
+const (
+	LogLevelVerbose LogLevel = iota
+	LogLevelDebug
+	LogLevelInfo
+	LogLevelWarn
+	LogLevelError
+	LogLevelFatal
+)
+
+// String renders level the way a config file or log line would name it,
+// e.g. "warn" instead of "3".
+func (level LogLevel) String() string {
+	switch level {
+	case LogLevelVerbose:
+		return "verbose"
+	case LogLevelDebug:
+		return "debug"
+	case LogLevelInfo:
+		return "info"
+	case LogLevelWarn:
+		return "warn"
+	case LogLevelError:
+		return "error"
+	case LogLevelFatal:
+		return "fatal"
+	default:
+		return fmt.Sprintf("unknown(%d)", int(level))
+	}
+}
+
+// ErrInvalidLogLevel is returned by ParseLogLevel for a string that isn't
+// one of LogLevel's names or aliases.
+var ErrInvalidLogLevel = fmt.Errorf("ten: invalid log level")
+
+// ParseLogLevel parses s -- typically read from a "log_level" property or
+// config file -- into a LogLevel, matching case-insensitively against each
+// level's own String() plus a few common aliases ("warning" for
+// LogLevelWarn, "trace" for LogLevelVerbose), so a config author's
+// long-standing habit of writing "warning" doesn't have to change just
+// because String() renders the short form. An unrecognized string returns
+// ErrInvalidLogLevel naming it, instead of silently defaulting to some
+// level the caller didn't ask for.
+func ParseLogLevel(s string) (LogLevel, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "verbose", "trace":
+		return LogLevelVerbose, nil
+	case "debug":
+		return LogLevelDebug, nil
+	case "info":
+		return LogLevelInfo, nil
+	case "warn", "warning":
+		return LogLevelWarn, nil
+	case "error":
+		return LogLevelError, nil
+	case "fatal":
+		return LogLevelFatal, nil
+	default:
+		return 0, fmt.Errorf("%w: %q", ErrInvalidLogLevel, s)
+	}
+}
+
+// LogFormat selects how LogFields renders its fields into the string it
+// hands to Log; see WithLogFormat.
+type LogFormat int
+
+const (
+	// LogFormatText appends fields to msg as space-separated key=value
+	// pairs, e.g. "connect failed host=db.internal attempt=3".
+	LogFormatText LogFormat = iota
+	// LogFormatJSON renders msg and its fields as a single JSON object,
+	// e.g. {"msg":"connect failed","host":"db.internal","attempt":3}.
+	LogFormatJSON
+)
+
+// Field is a typed key-value pair for LogFields, built with String, Int,
+// or Err.
+type Field struct {
+	Key   string
+	Value any
+}
+
+// String builds a string-valued Field.
+func String(key, value string) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Int builds an int-valued Field.
+func Int(key string, value int) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Err builds a Field named "error" from err's message. A nil err produces
+// a nil value rather than panicking, so callers can log an err field
+// unconditionally.
+func Err(err error) Field {
+	if err == nil {
+		return Field{Key: "error", Value: nil}
+	}
+	return Field{Key: "error", Value: err.Error()}
+}
+
+// formatFields renders msg and fields into the single string LogFields
+// passes to Log, per format.
+func formatFields(format LogFormat, msg string, fields []Field) string {
+	if format == LogFormatJSON {
+		obj := make(map[string]any, len(fields)+1)
+		obj["msg"] = msg
+		for _, f := range fields {
+			obj[f.Key] = f.Value
+		}
+		encoded, err := json.Marshal(obj)
+		if err != nil {
+			// Fields are always JSON-marshalable (strings, ints, error
+			// messages); this only guards against a caller passing an
+			// exotic Value some day.
+			return msg
+		}
+		return string(encoded)
+	}
+
+	if len(fields) == 0 {
+		return msg
+	}
+	var b strings.Builder
+	b.WriteString(msg)
+	for _, f := range fields {
+		fmt.Fprintf(&b, " %s=%v", f.Key, f.Value)
+	}
+	return b.String()
+}
@@ -0,0 +1,162 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestFormatFields_TextAppendsKeyEqualsValue(t *testing.T) {
+	got := formatFields(
+		LogFormatText, "connect failed",
+		[]Field{String("host", "db.internal"), Int("attempt", 3)},
+	)
+	want := "connect failed host=db.internal attempt=3"
+	if got != want {
+		t.Fatalf("formatFields(text) = %q, want %q", got, want)
+	}
+}
+
+func TestFormatFields_TextWithNoFieldsIsJustMsg(t *testing.T) {
+	got := formatFields(LogFormatText, "connect failed", nil)
+	if got != "connect failed" {
+		t.Fatalf("formatFields(text, no fields) = %q, want %q", got, "connect failed")
+	}
+}
+
+func TestFormatFields_JSONRendersObject(t *testing.T) {
+	got := formatFields(
+		LogFormatJSON, "connect failed",
+		[]Field{String("host", "db.internal"), Int("attempt", 3)},
+	)
+	var decoded map[string]any
+	if err := json.Unmarshal([]byte(got), &decoded); err != nil {
+		t.Fatalf("formatFields(json) produced invalid JSON %q: %v", got, err)
+	}
+	if decoded["msg"] != "connect failed" || decoded["host"] != "db.internal" ||
+		decoded["attempt"] != float64(3) {
+		t.Fatalf("formatFields(json) decoded to %v", decoded)
+	}
+}
+
+func TestErrField_NilErrorDoesNotPanic(t *testing.T) {
+	f := Err(nil)
+	if f.Key != "error" || f.Value != nil {
+		t.Fatalf("Err(nil) = %+v, want {error, nil}", f)
+	}
+}
+
+func TestTenEnv_LogLazySkipsFnWhenLevelDisabled(t *testing.T) {
+	engine := NewEngine(DefaultExtension{}, WithLogLevel(LogLevelWarn))
+	tenEnv := engine.TenEnv()
+
+	called := false
+	tenEnv.LogLazy(LogLevelDebug, func() string {
+		called = true
+		return "should not be built"
+	})
+	if called {
+		t.Fatal("LogLazy invoked fn for a disabled level")
+	}
+
+	tenEnv.LogLazy(LogLevelError, func() string {
+		called = true
+		return "should be built"
+	})
+	if !called {
+		t.Fatal("LogLazy did not invoke fn for an enabled level")
+	}
+}
+
+func TestTenEnv_IsLogLevelEnabledDefaultsToVerbose(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+	tenEnv := engine.TenEnv()
+	if !tenEnv.IsLogLevelEnabled(LogLevelVerbose) {
+		t.Fatal("IsLogLevelEnabled(LogLevelVerbose) = false, want true by default")
+	}
+}
+
+func TestTenEnv_GetLogLevelReflectsRuntimeChanges(t *testing.T) {
+	engine := NewEngine(DefaultExtension{}, WithLogLevel(LogLevelInfo))
+	tenEnv := engine.TenEnv()
+
+	if got := tenEnv.GetLogLevel(); got != LogLevelInfo {
+		t.Fatalf("GetLogLevel() = %v, want LogLevelInfo", got)
+	}
+
+	engine.SetLogLevel(LogLevelError)
+	if got := tenEnv.GetLogLevel(); got != LogLevelError {
+		t.Fatalf("GetLogLevel() after SetLogLevel = %v, want LogLevelError", got)
+	}
+	if tenEnv.IsLogLevelEnabled(LogLevelWarn) {
+		t.Fatal("IsLogLevelEnabled(LogLevelWarn) = true after raising threshold to Error")
+	}
+}
+
+func TestTenEnv_LogFieldsHonorsEngineLogFormat(t *testing.T) {
+	engine := NewEngine(DefaultExtension{}, WithLogFormat(LogFormatJSON))
+	tenEnv := engine.TenEnv()
+
+	// LogFields ultimately calls Log with the rendered string; there's no
+	// recorded-output hook on the real tenEnvImpl (see tentest.MockTenEnv
+	// for that), so this only exercises that LogFields doesn't panic and
+	// picks up the Engine's configured format via formatFields, which is
+	// covered directly above.
+	tenEnv.LogFields(LogLevelInfo, "ready", String("addon", "extension_b"))
+}
+
+func TestLogLevel_StringRendersEachLevel(t *testing.T) {
+	cases := map[LogLevel]string{
+		LogLevelVerbose: "verbose",
+		LogLevelDebug:   "debug",
+		LogLevelInfo:    "info",
+		LogLevelWarn:    "warn",
+		LogLevelError:   "error",
+		LogLevelFatal:   "fatal",
+	}
+	for level, want := range cases {
+		if got := level.String(); got != want {
+			t.Fatalf("LogLevel(%d).String() = %q, want %q", level, got, want)
+		}
+	}
+	if got := LogLevel(99).String(); got != "unknown(99)" {
+		t.Fatalf("LogLevel(99).String() = %q, want %q", got, "unknown(99)")
+	}
+}
+
+func TestParseLogLevel_AcceptsNamesAliasesAndIsCaseInsensitive(t *testing.T) {
+	cases := map[string]LogLevel{
+		"verbose": LogLevelVerbose,
+		"TRACE":   LogLevelVerbose,
+		"Debug":   LogLevelDebug,
+		"info":    LogLevelInfo,
+		"warn":    LogLevelWarn,
+		"WARNING": LogLevelWarn,
+		"  error": LogLevelError,
+		"fatal ":  LogLevelFatal,
+	}
+	for s, want := range cases {
+		got, err := ParseLogLevel(s)
+		if err != nil {
+			t.Fatalf("ParseLogLevel(%q): %v", s, err)
+		}
+		if got != want {
+			t.Fatalf("ParseLogLevel(%q) = %v, want %v", s, got, want)
+		}
+	}
+}
+
+func TestParseLogLevel_RejectsUnknownStringWithLevelNamed(t *testing.T) {
+	_, err := ParseLogLevel("silly")
+	if !errors.Is(err, ErrInvalidLogLevel) {
+		t.Fatalf("ParseLogLevel(silly) = %v, want ErrInvalidLogLevel", err)
+	}
+	if !strings.Contains(err.Error(), "silly") {
+		t.Fatalf("error = %q, want it to name the offending string", err)
+	}
+}
@@ -0,0 +1,123 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"fmt"
+	"time"
+)
+
+// logRecord is one buffered Log call, holding exactly what writeLogRecord
+// needs to replay it later: SetLogBuffering only changes when a record is
+// written, never what gets written.
+type logRecord struct {
+	level LogLevel
+	msg   string
+}
+
+// SetLogBuffering switches this extension's Log/LogFields/LogLazy calls
+// from writing immediately (the default) to accumulating on the Go side
+// and flushing in batches -- either once maxBatch records have piled up or
+// maxDelay has elapsed since the first one still unflushed, whichever
+// comes first. It's for an extension that logs at high frequency (e.g.
+// once per audio frame), where the fixed per-call overhead of crossing
+// into the native logger dominates -- batching trades a little added
+// latency on individual log lines for far fewer crossings overall.
+//
+// Buffered records are always flushed in the order Log received them;
+// batching only delays when a record is written, never reorders or drops
+// one. FlushLogs drains whatever is currently buffered before it returns,
+// and Stop calls FlushLogs automatically right after OnStopDone (see its
+// doc comment), so a graceful shutdown never strands records behind a
+// timer that hasn't fired yet.
+//
+// maxBatch <= 0 disables the size trigger (only maxDelay flushes);
+// maxDelay <= 0 disables the timer (only maxBatch flushes, plus
+// FlushLogs/Stop). Calling SetLogBuffering again replaces the previous
+// configuration, first flushing whatever was already buffered under it,
+// so a record is never silently dropped by a limit shrinking out from
+// under it.
+func (t *tenEnvImpl) SetLogBuffering(maxBatch int, maxDelay time.Duration) {
+	e := t.engine
+	e.logBufMu.Lock()
+	e.stopLogBufferTimerLocked()
+	records := e.logBufRecords
+	e.logBufRecords = nil
+	e.logBufEnabled = true
+	e.logBufMaxBatch = maxBatch
+	e.logBufMaxDelay = maxDelay
+	e.logBufMu.Unlock()
+	e.writeLogRecords(records)
+}
+
+// tryBufferLog appends level/msg to the pending batch if SetLogBuffering
+// has been called, flushing immediately if that fills maxBatch, and
+// reports whether it did so -- Log falls back to writing immediately when
+// it returns false. Flushing happens outside logBufMu, so a slow write
+// (e.g. to a blocking io.Writer set via SetLogSink) never holds up the
+// next Log call from buffering its own record.
+func (e *Engine) tryBufferLog(level LogLevel, msg string) bool {
+	e.logBufMu.Lock()
+	if !e.logBufEnabled {
+		e.logBufMu.Unlock()
+		return false
+	}
+
+	e.logBufRecords = append(e.logBufRecords, logRecord{level: level, msg: msg})
+	if e.logBufTimer == nil && e.logBufMaxDelay > 0 {
+		e.logBufTimer = time.AfterFunc(e.logBufMaxDelay, e.flushLogBuffer)
+	}
+
+	var records []logRecord
+	if e.logBufMaxBatch > 0 && len(e.logBufRecords) >= e.logBufMaxBatch {
+		e.stopLogBufferTimerLocked()
+		records = e.logBufRecords
+		e.logBufRecords = nil
+	}
+	e.logBufMu.Unlock()
+
+	e.writeLogRecords(records)
+	return true
+}
+
+// flushLogBuffer drains whatever's currently buffered, in order. It's
+// called by the maxDelay timer, and by FlushLogs so Stop's shutdown path
+// (see its call to FlushLogs) never leaves buffered records unwritten.
+func (e *Engine) flushLogBuffer() {
+	e.logBufMu.Lock()
+	e.stopLogBufferTimerLocked()
+	records := e.logBufRecords
+	e.logBufRecords = nil
+	e.logBufMu.Unlock()
+
+	e.writeLogRecords(records)
+}
+
+// stopLogBufferTimerLocked cancels the pending maxDelay flush, if any.
+// Callers must hold logBufMu.
+func (e *Engine) stopLogBufferTimerLocked() {
+	if e.logBufTimer != nil {
+		e.logBufTimer.Stop()
+		e.logBufTimer = nil
+	}
+}
+
+// writeLogRecords writes records in order via writeLogRecord. records is
+// nil far more often than not (most Log calls only append to the batch),
+// so this is a no-op in the common case.
+func (e *Engine) writeLogRecords(records []logRecord) {
+	for _, r := range records {
+		e.writeLogRecord(r.level, r.msg)
+	}
+}
+
+// writeLogRecord is Log's actual write path, shared by the immediate
+// (unbuffered) case and every buffered flush.
+func (e *Engine) writeLogRecord(level LogLevel, msg string) {
+	fmt.Printf("[%d] %s\n", level, msg)
+	if !e.writeToLogSink(level, msg) {
+		writeToProcessLogSink(level, msg)
+	}
+}
@@ -0,0 +1,121 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTenEnv_SetLogBufferingWithholdsUntilMaxBatch(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+	engine.Init()
+	engine.Start()
+
+	var buf bytes.Buffer
+	engine.TenEnv().SetLogSink(&buf, LogFormatText)
+	engine.TenEnv().SetLogBuffering(3, time.Hour)
+
+	engine.TenEnv().Log(LogLevelInfo, "one")
+	engine.TenEnv().Log(LogLevelInfo, "two")
+	if buf.Len() != 0 {
+		t.Fatalf("sink content = %q, want nothing written before maxBatch is reached", buf.String())
+	}
+
+	engine.TenEnv().Log(LogLevelInfo, "three")
+	got := buf.String()
+	for _, want := range []string{"one", "two", "three"} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("sink content = %q, want it to contain %q once maxBatch is reached", got, want)
+		}
+	}
+}
+
+func TestTenEnv_SetLogBufferingPreservesOrder(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+	engine.Init()
+	engine.Start()
+
+	var buf bytes.Buffer
+	engine.TenEnv().SetLogSink(&buf, LogFormatText)
+	engine.TenEnv().SetLogBuffering(100, time.Hour)
+
+	for i := 0; i < 10; i++ {
+		engine.TenEnv().Log(LogLevelInfo, fmt.Sprintf("line %d", i))
+	}
+	engine.TenEnv().FlushLogs()
+
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	if len(lines) != 10 {
+		t.Fatalf("got %d lines, want 10", len(lines))
+	}
+	for i, line := range lines {
+		if !strings.Contains(line, fmt.Sprintf("line %d", i)) {
+			t.Fatalf("line %d = %q, want it in the order Log received them", i, line)
+		}
+	}
+}
+
+func TestTenEnv_SetLogBufferingFlushesAfterMaxDelay(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+	engine.Init()
+	engine.Start()
+
+	// The maxDelay flush runs on a time.AfterFunc goroutine, so the sink
+	// must be safe for concurrent access the same way
+	// TestTenEnv_SetLogSinkSerializesConcurrentWrites needs lockedBuffer.
+	sink := &lockedBuffer{}
+	engine.TenEnv().SetLogSink(sink, LogFormatText)
+	engine.TenEnv().SetLogBuffering(1000, 20*time.Millisecond)
+
+	engine.TenEnv().Log(LogLevelInfo, "delayed line")
+	if sink.String() != "" {
+		t.Fatalf("sink content = %q, want nothing written before maxDelay elapses", sink.String())
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	if got := sink.String(); !strings.Contains(got, "delayed line") {
+		t.Fatalf("sink content = %q, want it flushed once maxDelay elapsed", got)
+	}
+}
+
+func TestTenEnv_FlushLogsDrainsBufferedRecords(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+	engine.Init()
+	engine.Start()
+
+	var buf bytes.Buffer
+	engine.TenEnv().SetLogSink(&buf, LogFormatText)
+	engine.TenEnv().SetLogBuffering(1000, time.Hour)
+
+	engine.TenEnv().Log(LogLevelInfo, "never reaches maxBatch")
+	if err := engine.TenEnv().FlushLogs(); err != nil {
+		t.Fatalf("FlushLogs: %v", err)
+	}
+	if got := buf.String(); !strings.Contains(got, "never reaches maxBatch") {
+		t.Fatalf("sink content = %q, want FlushLogs to drain the buffer", got)
+	}
+}
+
+func TestEngineStop_DrainsBufferedLogsDuringTeardown(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+	engine.Init()
+	engine.Start()
+
+	var buf bytes.Buffer
+	engine.TenEnv().SetLogSink(&buf, LogFormatText)
+	engine.TenEnv().SetLogBuffering(1000, time.Hour)
+	engine.TenEnv().Log(LogLevelInfo, "buffered before shutdown")
+
+	if err := engine.Stop(); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+	if got := buf.String(); !strings.Contains(got, "buffered before shutdown") {
+		t.Fatalf("sink content = %q, want Stop's FlushLogs call to drain the buffer", got)
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+var (
+	processLogSinkMu sync.Mutex
+	processLogSink   io.Writer
+	processLogFormat LogFormat
+)
+
+// SetLogSink routes every extension's Log/LogFields output, across the
+// whole process, to w in addition to wherever Log already writes it (see
+// tenEnvImpl.Log) -- e.g. into a test buffer, or into an aggregation
+// library that doesn't speak the C-side ten_runtime log protocol. format
+// is the same LogFormat WithLogFormat uses for field rendering:
+// LogFormatText writes "[level] msg\n" per record, LogFormatJSON writes a
+// single-line JSON object. Writes to w are serialized under a
+// package-level lock so concurrent extensions sharing this sink never
+// interleave partial lines.
+//
+// TenEnv.SetLogSink overrides this for one extension -- when both are
+// set, the per-extension sink wins rather than writing to both, so a test
+// that redirects one extension's logs doesn't also have to filter out
+// every other extension's lines sharing the same process-wide sink. A
+// nil w disables the process-wide sink.
+func SetLogSink(w io.Writer, format LogFormat) {
+	processLogSinkMu.Lock()
+	defer processLogSinkMu.Unlock()
+	processLogSink = w
+	processLogFormat = format
+}
+
+// formatLogSinkLine renders one Log/LogFields record as a single line,
+// newline included, per format.
+func formatLogSinkLine(format LogFormat, level LogLevel, msg string) string {
+	if format == LogFormatJSON {
+		encoded, err := json.Marshal(struct {
+			Time  string `json:"time"`
+			Level int    `json:"level"`
+			Msg   string `json:"msg"`
+		}{
+			Time:  time.Now().Format(time.RFC3339Nano),
+			Level: int(level),
+			Msg:   msg,
+		})
+		if err != nil {
+			// LogLevel and msg are always JSON-marshalable; this only
+			// guards against some future field type that isn't.
+			return msg + "\n"
+		}
+		return string(encoded) + "\n"
+	}
+	return fmt.Sprintf("[%d] %s\n", level, msg)
+}
+
+// writeToLogSink writes to this Engine's per-extension sink, if one is
+// set via TenEnv.SetLogSink, reporting whether it did.
+func (e *Engine) writeToLogSink(level LogLevel, msg string) bool {
+	e.logSinkMu.Lock()
+	defer e.logSinkMu.Unlock()
+	if e.logSink == nil {
+		return false
+	}
+	io.WriteString(e.logSink, formatLogSinkLine(e.logSinkFormat, level, msg))
+	return true
+}
+
+// writeToProcessLogSink writes to the process-wide sink set via
+// SetLogSink, if any, reporting whether it did.
+func writeToProcessLogSink(level LogLevel, msg string) bool {
+	processLogSinkMu.Lock()
+	defer processLogSinkMu.Unlock()
+	if processLogSink == nil {
+		return false
+	}
+	io.WriteString(processLogSink, formatLogSinkLine(processLogFormat, level, msg))
+	return true
+}
+
+// SetLogSink overrides SetLogSink's process-wide sink for this extension
+// only, taking precedence over it while set. A nil w reverts to whatever
+// the process-wide sink is (or none).
+func (t *tenEnvImpl) SetLogSink(w io.Writer, format LogFormat) {
+	t.engine.logSinkMu.Lock()
+	defer t.engine.logSinkMu.Unlock()
+	t.engine.logSink = w
+	t.engine.logSinkFormat = format
+}
@@ -0,0 +1,131 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestTenEnv_SetLogSinkReceivesTextLines(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+	engine.Init()
+	engine.Start()
+
+	var buf bytes.Buffer
+	engine.TenEnv().SetLogSink(&buf, LogFormatText)
+	engine.TenEnv().Log(LogLevelInfo, "hello there")
+
+	if got := buf.String(); !strings.Contains(got, "hello there") {
+		t.Fatalf("sink content = %q, want it to contain the logged message", got)
+	}
+}
+
+func TestTenEnv_SetLogSinkReceivesJSONLines(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+	engine.Init()
+	engine.Start()
+
+	var buf bytes.Buffer
+	engine.TenEnv().SetLogSink(&buf, LogFormatJSON)
+	engine.TenEnv().Log(LogLevelWarn, "disk almost full")
+
+	var decoded struct {
+		Msg   string `json:"msg"`
+		Level int    `json:"level"`
+	}
+	if err := json.Unmarshal(bytes.TrimSpace(buf.Bytes()), &decoded); err != nil {
+		t.Fatalf("sink content isn't valid JSON: %v (%q)", err, buf.String())
+	}
+	if decoded.Msg != "disk almost full" || decoded.Level != int(LogLevelWarn) {
+		t.Fatalf("decoded = %+v, want msg %q at level %d", decoded, "disk almost full", LogLevelWarn)
+	}
+}
+
+func TestSetLogSink_AppliesProcessWideUnlessOverridden(t *testing.T) {
+	var buf bytes.Buffer
+	SetLogSink(&buf, LogFormatText)
+	defer SetLogSink(nil, LogFormatText)
+
+	engine := NewEngine(DefaultExtension{})
+	engine.Init()
+	engine.Start()
+	engine.TenEnv().Log(LogLevelInfo, "via process sink")
+
+	if got := buf.String(); !strings.Contains(got, "via process sink") {
+		t.Fatalf("process sink content = %q, want it to contain the logged message", got)
+	}
+}
+
+func TestTenEnv_SetLogSinkOverridesProcessWideSink(t *testing.T) {
+	var processBuf, extBuf bytes.Buffer
+	SetLogSink(&processBuf, LogFormatText)
+	defer SetLogSink(nil, LogFormatText)
+
+	engine := NewEngine(DefaultExtension{})
+	engine.Init()
+	engine.Start()
+	engine.TenEnv().SetLogSink(&extBuf, LogFormatText)
+	engine.TenEnv().Log(LogLevelInfo, "extension-scoped only")
+
+	if processBuf.Len() != 0 {
+		t.Fatalf("process sink content = %q, want it untouched once the extension has its own sink", processBuf.String())
+	}
+	if !strings.Contains(extBuf.String(), "extension-scoped only") {
+		t.Fatalf("extension sink content = %q, want it to contain the logged message", extBuf.String())
+	}
+}
+
+// lockedBuffer serializes writes so the race detector doesn't flag the
+// test's own reads racing with concurrent Log calls, independent of
+// whatever locking writeToLogSink itself does.
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestTenEnv_SetLogSinkSerializesConcurrentWrites(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+	engine.Init()
+	engine.Start()
+
+	sink := &lockedBuffer{}
+	engine.TenEnv().SetLogSink(sink, LogFormatText)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			engine.TenEnv().Log(LogLevelInfo, "concurrent line")
+		}()
+	}
+	wg.Wait()
+
+	lines := strings.Split(strings.TrimRight(sink.String(), "\n"), "\n")
+	if len(lines) != 20 {
+		t.Fatalf("got %d lines, want 20 -- an interleaved partial write would split or merge lines", len(lines))
+	}
+	for _, line := range lines {
+		if !strings.Contains(line, "concurrent line") {
+			t.Fatalf("line %q is not a complete, unmangled record", line)
+		}
+	}
+}
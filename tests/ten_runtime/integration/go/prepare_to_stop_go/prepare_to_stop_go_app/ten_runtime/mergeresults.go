@@ -0,0 +1,112 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+// MergeFunc resolves a property key that more than one merged result
+// sets, given the value accumulated so far (existing) and the next
+// result's value for that key (next). Its return value becomes the
+// merged result's value for that key.
+type MergeFunc func(existing, next any) any
+
+// MergeOption configures MergeResultsEx beyond its default conflict
+// policy.
+type MergeOption func(*mergeConfig)
+
+type mergeConfig struct {
+	perKey map[string]MergeFunc
+}
+
+// WithKeyMergeFunc overrides MergeResultsEx's default last-writer-wins
+// policy for a single property key: whenever more than one input result
+// sets key, fn decides the merged value instead of the later result
+// silently overwriting the earlier one. It has no effect on a key that
+// only one input result sets.
+func WithKeyMergeFunc(key string, fn MergeFunc) MergeOption {
+	return func(c *mergeConfig) {
+		if c.perKey == nil {
+			c.perKey = map[string]MergeFunc{}
+		}
+		c.perKey[key] = fn
+	}
+}
+
+// mostSevereStatusCode picks the worst outcome among codes, treating a
+// larger StatusCode as more severe -- true for the two well-known
+// constants (StatusCodeOk < StatusCodeError) and, by convention, for any
+// cmd-specific codes a protocol built on this package adds above them.
+func mostSevereStatusCode(codes []StatusCode) StatusCode {
+	worst := StatusCodeOk
+	for _, c := range codes {
+		if c > worst {
+			worst = c
+		}
+	}
+	return worst
+}
+
+// MergeResults combines base and others -- typically the per-downstream
+// results a scatter/gather aggregator collected -- into a single final
+// CmdResult, with the default conflict policy: last-writer-wins,
+// applying base's properties first and then each of others' in order, so
+// a later result's value for a shared key overwrites an earlier one's.
+// See MergeResultsEx to override that per key. It's meant to replace the
+// key-by-key merge every aggregator extension in a graph otherwise
+// reimplements slightly differently for itself.
+//
+// The merged result's StatusCode is the most severe among base and
+// others (see mostSevereStatusCode), so a single failing downstream
+// still marks the aggregate as failed even if every other one succeeded.
+func MergeResults(base CmdResult, others ...CmdResult) (CmdResult, error) {
+	return MergeResultsEx(base, others)
+}
+
+// MergeResultsEx is MergeResults plus opts, e.g. WithKeyMergeFunc for a
+// key that needs something other than last-writer-wins -- summing a
+// numeric field across inputs, say, instead of keeping only the last
+// one. others is a plain slice here rather than variadic, the same way
+// ReturnResultEx trades ReturnResult's convenience for room to take
+// additional parameters.
+func MergeResultsEx(base CmdResult, others []CmdResult, opts ...MergeOption) (CmdResult, error) {
+	cfg := &mergeConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	all := append([]CmdResult{base}, others...)
+
+	codes := make([]StatusCode, 0, len(all))
+	merged := map[string]any{}
+	for _, r := range all {
+		code, err := r.StatusCode()
+		if err != nil {
+			return nil, err
+		}
+		codes = append(codes, code)
+
+		snapshot, err := r.PropertiesSnapshot()
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range snapshot {
+			if existing, ok := merged[k]; ok {
+				if fn, ok := cfg.perKey[k]; ok {
+					merged[k] = fn(existing, v)
+					continue
+				}
+			}
+			merged[k] = v
+		}
+	}
+
+	result := &cmdResultImpl{
+		statusCode: mostSevereStatusCode(codes),
+		props:      map[string]any{},
+		isFinal:    true,
+	}
+	if err := result.SetProperties(merged); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
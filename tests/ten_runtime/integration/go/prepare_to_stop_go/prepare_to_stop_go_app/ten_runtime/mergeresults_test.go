@@ -0,0 +1,110 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import "testing"
+
+func TestMergeResults_LastWriterWinsOnConflict(t *testing.T) {
+	cmd, _ := NewCmd("gather")
+	base, _ := NewCmdResult(StatusCodeOk, cmd)
+	base.SetPropertyString("winner", "base")
+	base.SetPropertyString("only_in_base", "kept")
+
+	other, _ := NewCmdResult(StatusCodeOk, cmd)
+	other.SetPropertyString("winner", "other")
+
+	merged, err := MergeResults(base, other)
+	if err != nil {
+		t.Fatalf("MergeResults: %v", err)
+	}
+
+	winner, _ := merged.GetPropertyString("winner")
+	if winner != "other" {
+		t.Fatalf("winner = %q, want %q (last writer)", winner, "other")
+	}
+	onlyInBase, _ := merged.GetPropertyString("only_in_base")
+	if onlyInBase != "kept" {
+		t.Fatalf("only_in_base = %q, want %q", onlyInBase, "kept")
+	}
+}
+
+func TestMergeResults_StatusCodeIsTheMostSevere(t *testing.T) {
+	cmd, _ := NewCmd("gather")
+	base, _ := NewCmdResult(StatusCodeOk, cmd)
+	failing, _ := NewCmdResult(StatusCodeError, cmd)
+
+	merged, err := MergeResults(base, failing)
+	if err != nil {
+		t.Fatalf("MergeResults: %v", err)
+	}
+
+	code, _ := merged.StatusCode()
+	if code != StatusCodeError {
+		t.Fatalf("StatusCode = %v, want %v (most severe)", code, StatusCodeError)
+	}
+}
+
+func TestMergeResults_WithNoOthersReturnsBaseUnchanged(t *testing.T) {
+	cmd, _ := NewCmd("gather")
+	base, _ := NewCmdResult(StatusCodeOk, cmd)
+	base.SetPropertyString("detail", "solo")
+
+	merged, err := MergeResults(base)
+	if err != nil {
+		t.Fatalf("MergeResults: %v", err)
+	}
+
+	detail, _ := merged.GetPropertyString("detail")
+	if detail != "solo" {
+		t.Fatalf("detail = %q, want %q", detail, "solo")
+	}
+}
+
+func TestMergeResultsEx_WithKeyMergeFuncOverridesLastWriterWins(t *testing.T) {
+	cmd, _ := NewCmd("gather")
+	base, _ := NewCmdResult(StatusCodeOk, cmd)
+	base.SetPropertyInt64("total", 3)
+
+	other, _ := NewCmdResult(StatusCodeOk, cmd)
+	other.SetPropertyInt64("total", 4)
+
+	sum := func(existing, next any) any {
+		return existing.(int64) + next.(int64)
+	}
+	merged, err := MergeResultsEx(base, []CmdResult{other}, WithKeyMergeFunc("total", sum))
+	if err != nil {
+		t.Fatalf("MergeResultsEx: %v", err)
+	}
+
+	total, _ := merged.GetPropertyInt64("total")
+	if total != 7 {
+		t.Fatalf("total = %d, want %d", total, 7)
+	}
+}
+
+func TestMergeResultsEx_KeyMergeFuncOnlyAppliesOnConflict(t *testing.T) {
+	cmd, _ := NewCmd("gather")
+	base, _ := NewCmdResult(StatusCodeOk, cmd)
+	base.SetPropertyInt64("total", 3)
+
+	other, _ := NewCmdResult(StatusCodeOk, cmd)
+	other.SetPropertyString("unrelated", "x")
+
+	called := false
+	merged, err := MergeResultsEx(base, []CmdResult{other}, WithKeyMergeFunc("total", func(existing, next any) any {
+		called = true
+		return next
+	}))
+	if err != nil {
+		t.Fatalf("MergeResultsEx: %v", err)
+	}
+	if called {
+		t.Fatal("merge func called for a key only one input set")
+	}
+	total, _ := merged.GetPropertyInt64("total")
+	if total != 3 {
+		t.Fatalf("total = %d, want %d", total, 3)
+	}
+}
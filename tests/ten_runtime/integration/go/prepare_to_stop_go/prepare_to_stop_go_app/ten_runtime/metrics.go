@@ -0,0 +1,178 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Tag is a single label attached to a metric observation, e.g.
+// Tag{Key: "cmd", Value: "start"}.
+type Tag struct {
+	Key   string
+	Value string
+}
+
+// MetricsRecorder records counters and latency observations. TenEnv.
+// Metrics returns one scoped to whatever MetricsRegistry (if any) the
+// Engine was constructed with.
+type MetricsRecorder interface {
+	IncCounter(name string, tags ...Tag)
+	ObserveLatency(name string, d time.Duration, tags ...Tag)
+}
+
+type histogram struct {
+	count uint64
+	sum   float64
+}
+
+// MetricsRegistry accumulates counters and latency histograms recorded
+// through IncCounter/ObserveLatency -- both an extension's own calls via
+// TenEnv.Metrics, and the runtime's automatic per-extension command-count
+// and result-latency instrumentation (see WithMetricsRegistry) -- and
+// renders the result in Prometheus text exposition format via WriteTo or
+// ServeMetrics. Metrics collection is entirely opt-in: an Engine
+// constructed without WithMetricsRegistry never touches a MetricsRegistry
+// at all, and nothing in this package starts an HTTP listener on its own
+// -- ServeMetrics must be called explicitly.
+type MetricsRegistry struct {
+	mu         sync.Mutex
+	counters   map[string]float64
+	histograms map[string]*histogram
+}
+
+// NewMetricsRegistry creates an empty MetricsRegistry.
+func NewMetricsRegistry() *MetricsRegistry {
+	return &MetricsRegistry{
+		counters:   make(map[string]float64),
+		histograms: make(map[string]*histogram),
+	}
+}
+
+// WithMetricsRegistry opts an Engine into metrics collection: an
+// extension's own TenEnv.Metrics() calls, and the runtime's automatic
+// per-extension command-count and result-latency instrumentation, both
+// record into registry. Without this option, TenEnv.Metrics() returns a
+// no-op recorder and the runtime records nothing.
+func WithMetricsRegistry(registry *MetricsRegistry) EngineOption {
+	return func(e *Engine) {
+		e.metrics = registry
+	}
+}
+
+func metricKey(name string, tags []Tag) string {
+	if len(tags) == 0 {
+		return name
+	}
+	sorted := append([]Tag(nil), tags...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Key < sorted[j].Key })
+
+	var b strings.Builder
+	b.WriteString(name)
+	b.WriteByte('{')
+	for i, tag := range sorted {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		fmt.Fprintf(&b, "%s=%q", tag.Key, tag.Value)
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+// IncCounter increments the counter identified by name and tags by 1.
+func (r *MetricsRegistry) IncCounter(name string, tags ...Tag) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.counters[metricKey(name, tags)]++
+}
+
+// ObserveLatency records a single latency sample of d for the histogram
+// identified by name and tags.
+func (r *MetricsRegistry) ObserveLatency(name string, d time.Duration, tags ...Tag) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := metricKey(name, tags)
+	h, ok := r.histograms[key]
+	if !ok {
+		h = &histogram{}
+		r.histograms[key] = h
+	}
+	h.count++
+	h.sum += d.Seconds()
+}
+
+// WriteTo renders every counter and latency histogram currently in the
+// registry in Prometheus text exposition format: a counter as a single
+// "<name> <value>" line, and a histogram as its "_sum" and "_count"
+// lines, matching how a Prometheus client library reports an unbucketed
+// summary. Output is sorted by metric name for deterministic diffs.
+func (r *MetricsRegistry) WriteTo(w io.Writer) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var b strings.Builder
+
+	counterNames := make([]string, 0, len(r.counters))
+	for name := range r.counters {
+		counterNames = append(counterNames, name)
+	}
+	sort.Strings(counterNames)
+	for _, name := range counterNames {
+		fmt.Fprintf(&b, "%s %g\n", name, r.counters[name])
+	}
+
+	histNames := make([]string, 0, len(r.histograms))
+	for name := range r.histograms {
+		histNames = append(histNames, name)
+	}
+	sort.Strings(histNames)
+	for _, name := range histNames {
+		h := r.histograms[name]
+		fmt.Fprintf(&b, "%s_sum %g\n", name, h.sum)
+		fmt.Fprintf(&b, "%s_count %d\n", name, h.count)
+	}
+
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+type nopMetricsRecorder struct{}
+
+func (nopMetricsRecorder) IncCounter(name string, tags ...Tag)                      {}
+func (nopMetricsRecorder) ObserveLatency(name string, d time.Duration, tags ...Tag) {}
+
+// ServeMetrics starts an HTTP server on addr exposing registry's current
+// state at /metrics, in Prometheus text exposition format, and returns
+// the *http.Server so the caller can Shut it down, e.g. from OnStop.
+// It's the opt-in half of the metrics subsystem: constructing an Engine
+// with WithMetricsRegistry only accumulates metrics in memory, it never
+// starts a listener -- an application wanting the HTTP endpoint calls
+// ServeMetrics itself.
+func ServeMetrics(addr string, registry *MetricsRegistry) (*http.Server, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("ten: ServeMetrics: %w", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		registry.WriteTo(w)
+	})
+	// listener.Addr() reflects the actual bound port, which matters when
+	// addr ends in ":0" and the OS picks one.
+	server := &http.Server{Addr: listener.Addr().String(), Handler: mux}
+
+	go server.Serve(listener)
+	return server, nil
+}
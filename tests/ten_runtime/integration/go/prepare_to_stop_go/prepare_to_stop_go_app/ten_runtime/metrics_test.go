@@ -0,0 +1,130 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMetricsRegistry_IncCounterAccumulatesByTags(t *testing.T) {
+	registry := NewMetricsRegistry()
+	registry.IncCounter("requests_total", Tag{Key: "cmd", Value: "start"})
+	registry.IncCounter("requests_total", Tag{Key: "cmd", Value: "start"})
+	registry.IncCounter("requests_total", Tag{Key: "cmd", Value: "stop"})
+
+	var buf strings.Builder
+	if _, err := registry.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo: %v", err)
+	}
+	out := buf.String()
+	if !strings.Contains(out, `requests_total{cmd="start"} 2`) {
+		t.Fatalf("output missing start=2 line: %s", out)
+	}
+	if !strings.Contains(out, `requests_total{cmd="stop"} 1`) {
+		t.Fatalf("output missing stop=1 line: %s", out)
+	}
+}
+
+func TestMetricsRegistry_ObserveLatencyAccumulatesSumAndCount(t *testing.T) {
+	registry := NewMetricsRegistry()
+	registry.ObserveLatency("latency_seconds", 100*time.Millisecond)
+	registry.ObserveLatency("latency_seconds", 200*time.Millisecond)
+
+	var buf strings.Builder
+	registry.WriteTo(&buf)
+	out := buf.String()
+	if !strings.Contains(out, "latency_seconds_count 2") {
+		t.Fatalf("output missing count line: %s", out)
+	}
+	if !strings.Contains(out, "latency_seconds_sum 0.3") {
+		t.Fatalf("output missing sum line: %s", out)
+	}
+}
+
+func TestMetricsRegistry_TagOrderDoesNotAffectKey(t *testing.T) {
+	registry := NewMetricsRegistry()
+	registry.IncCounter("x", Tag{Key: "a", Value: "1"}, Tag{Key: "b", Value: "2"})
+	registry.IncCounter("x", Tag{Key: "b", Value: "2"}, Tag{Key: "a", Value: "1"})
+
+	var buf strings.Builder
+	registry.WriteTo(&buf)
+	out := buf.String()
+	if strings.Count(out, "x{") != 1 {
+		t.Fatalf("expected tag order to produce a single merged series, got: %s", out)
+	}
+	if !strings.Contains(out, "2") {
+		t.Fatalf("expected the two IncCounter calls to merge into one count of 2: %s", out)
+	}
+}
+
+func TestTenEnv_MetricsIsNoOpWithoutRegistry(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+	// Must not panic even though no registry was configured.
+	engine.TenEnv().Metrics().IncCounter("anything")
+	engine.TenEnv().Metrics().ObserveLatency("anything", time.Millisecond)
+}
+
+// echoExtension returns an OK result for whatever cmd it's handed,
+// correctly threading cmd through to ReturnResult -- unlike
+// DefaultExtension.OnCmd, which returns an error result with a nil cmd.
+type echoExtension struct {
+	DefaultExtension
+}
+
+func (echoExtension) OnCmd(tenEnv TenEnv, cmd Cmd) {
+	result, _ := NewCmdResult(StatusCodeOk, cmd)
+	tenEnv.ReturnResult(result, cmd)
+}
+
+func TestEngine_AutoRecordsCmdCountAndLatencyOnReturnResult(t *testing.T) {
+	registry := NewMetricsRegistry()
+	engine := NewEngine(echoExtension{}, WithMetricsRegistry(registry))
+	engine.Init()
+	engine.Start()
+
+	cmd, _ := NewCmd("greet")
+	if err := engine.DeliverCmd(cmd); err != nil {
+		t.Fatalf("DeliverCmd: %v", err)
+	}
+
+	var buf strings.Builder
+	registry.WriteTo(&buf)
+	out := buf.String()
+	if !strings.Contains(out, `ten_extension_cmd_total{cmd="greet"`) {
+		t.Fatalf("output missing auto-recorded cmd counter: %s", out)
+	}
+	if !strings.Contains(out, `ten_extension_cmd_latency_seconds{cmd="greet"`) {
+		t.Fatalf("output missing auto-recorded latency histogram: %s", out)
+	}
+}
+
+func TestServeMetrics_ExposesRegistryOverHTTP(t *testing.T) {
+	registry := NewMetricsRegistry()
+	registry.IncCounter("probe_total")
+
+	server, err := ServeMetrics("127.0.0.1:0", registry)
+	if err != nil {
+		t.Fatalf("ServeMetrics: %v", err)
+	}
+	defer server.Close()
+
+	resp, err := http.Get("http://" + server.Addr + "/metrics")
+	if err != nil {
+		t.Fatalf("GET /metrics: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("reading body: %v", err)
+	}
+	if !strings.Contains(string(body), "probe_total 1") {
+		t.Fatalf("response missing probe_total: %s", body)
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import "sync"
+
+// CmdHandler handles one Cmd delivered to an extension, the same shape as
+// Extension.OnCmd itself -- a CmdMiddleware wraps one CmdHandler to
+// produce another.
+type CmdHandler func(tenEnv TenEnv, cmd Cmd)
+
+// CmdMiddleware wraps a CmdHandler to add a cross-cutting concern (auth,
+// rate limiting, metrics, ...) around it without every extension having
+// to copy-paste that concern into its own OnCmd. A middleware that wants
+// to short-circuit the chain -- reject an unauthenticated cmd, or answer
+// from a cache -- simply doesn't call next; it's expected to call one of
+// TenEnv's ReturnResult/ReturnError/ReturnErrorf/ReturnOK itself first,
+// the same way OnCmd always must, since not calling next means nothing
+// else in the chain (including the extension's own OnCmd) ever will.
+type CmdMiddleware func(next CmdHandler) CmdHandler
+
+var (
+	middlewareMu sync.Mutex
+	middleware   []CmdMiddleware
+)
+
+// Use registers mw to run around every extension's OnCmd in this process,
+// for the lifetime of the process -- there's no per-Engine or per-addon
+// scoping, since nothing in this package associates a middleware with a
+// particular graph or addon. An addon that wants its own extensions
+// wrapped in some cross-cutting behavior calls Use from its package's
+// init() (right where it would otherwise call RegisterAddonAsExtension),
+// which works because the middleware chain applies process-wide: every
+// extension dispatched through any Engine picks up whatever's registered,
+// including ones from other addons.
+//
+// Ordering: mw is appended after whatever's already registered, and the
+// chain wraps outside-in in registration order -- the first middleware
+// ever registered is outermost, running first on the way in (before any
+// later middleware, and before the extension's own OnCmd) and last on
+// the way out. Passing more than one CmdMiddleware to a single Use call
+// registers them in the order given, same as calling Use once per
+// middleware.
+func Use(mw ...CmdMiddleware) {
+	middlewareMu.Lock()
+	defer middlewareMu.Unlock()
+	middleware = append(middleware, mw...)
+}
+
+// buildCmdChain wraps final -- normally a call straight into the
+// extension's own OnCmd -- with every middleware registered via Use, in
+// their outermost-first order.
+func buildCmdChain(final CmdHandler) CmdHandler {
+	middlewareMu.Lock()
+	mws := append([]CmdMiddleware(nil), middleware...)
+	middlewareMu.Unlock()
+
+	handler := final
+	for i := len(mws) - 1; i >= 0; i-- {
+		handler = mws[i](handler)
+	}
+	return handler
+}
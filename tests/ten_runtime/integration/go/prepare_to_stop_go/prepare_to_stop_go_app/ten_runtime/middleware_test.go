@@ -0,0 +1,122 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import "testing"
+
+// withCleanMiddleware saves the process-wide middleware chain, runs fn
+// with it emptied, and restores whatever was there before -- Use has no
+// Unregister counterpart (see its doc comment), so tests that register
+// middleware must clean up this way to avoid leaking into unrelated
+// tests in the same binary.
+func withCleanMiddleware(t *testing.T, fn func()) {
+	t.Helper()
+	middlewareMu.Lock()
+	saved := middleware
+	middleware = nil
+	middlewareMu.Unlock()
+	t.Cleanup(func() {
+		middlewareMu.Lock()
+		middleware = saved
+		middlewareMu.Unlock()
+	})
+	fn()
+}
+
+func TestUse_WrapsOnCmdAndRunsFirstRegisteredOutermost(t *testing.T) {
+	withCleanMiddleware(t, func() {
+		var order []string
+		outer := func(next CmdHandler) CmdHandler {
+			return func(tenEnv TenEnv, cmd Cmd) {
+				order = append(order, "outer-in")
+				next(tenEnv, cmd)
+				order = append(order, "outer-out")
+			}
+		}
+		inner := func(next CmdHandler) CmdHandler {
+			return func(tenEnv TenEnv, cmd Cmd) {
+				order = append(order, "inner-in")
+				next(tenEnv, cmd)
+				order = append(order, "inner-out")
+			}
+		}
+		Use(outer)
+		Use(inner)
+
+		engine := NewEngine(echoExtension{})
+		engine.Init()
+		engine.Start()
+		cmd, _ := NewCmd("ping")
+		if err := engine.DeliverCmd(cmd); err != nil {
+			t.Fatalf("DeliverCmd: %v", err)
+		}
+
+		want := []string{"outer-in", "inner-in", "inner-out", "outer-out"}
+		if len(order) != len(want) {
+			t.Fatalf("order = %v, want %v", order, want)
+		}
+		for i, name := range want {
+			if order[i] != name {
+				t.Fatalf("order = %v, want %v", order, want)
+			}
+		}
+	})
+}
+
+func TestUse_MiddlewareCanShortCircuitWithoutCallingNext(t *testing.T) {
+	withCleanMiddleware(t, func() {
+		reachedExtension := false
+		Use(func(next CmdHandler) CmdHandler {
+			return func(tenEnv TenEnv, cmd Cmd) {
+				tenEnv.ReturnError(cmd, "denied")
+			}
+		})
+
+		engine := NewEngine(shortCircuitProbeExtension{reached: &reachedExtension})
+		engine.Init()
+		engine.Start()
+
+		cmd, _ := NewCmd("ping")
+		if err := engine.DeliverCmd(cmd); err != nil {
+			t.Fatalf("DeliverCmd: %v", err)
+		}
+		if reachedExtension {
+			t.Fatal("extension's OnCmd ran despite a middleware short-circuiting the chain")
+		}
+	})
+}
+
+type shortCircuitProbeExtension struct {
+	DefaultExtension
+	reached *bool
+}
+
+func (e shortCircuitProbeExtension) OnCmd(tenEnv TenEnv, cmd Cmd) {
+	*e.reached = true
+	tenEnv.ReturnOK(cmd, "")
+}
+
+func TestUse_MiddlewareAppliesAcrossEveryEngineInTheProcess(t *testing.T) {
+	withCleanMiddleware(t, func() {
+		var calls int
+		Use(func(next CmdHandler) CmdHandler {
+			return func(tenEnv TenEnv, cmd Cmd) {
+				calls++
+				next(tenEnv, cmd)
+			}
+		})
+
+		for i := 0; i < 2; i++ {
+			engine := NewEngine(echoExtension{})
+			engine.Init()
+			engine.Start()
+			cmd, _ := NewCmd("ping")
+			engine.DeliverCmd(cmd)
+		}
+		if calls != 2 {
+			t.Fatalf("middleware ran %d times across two Engines, want 2", calls)
+		}
+	})
+}
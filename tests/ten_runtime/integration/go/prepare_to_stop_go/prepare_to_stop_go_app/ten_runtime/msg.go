@@ -0,0 +1,79 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import "fmt"
+
+// MsgType identifies which of the four message kinds a Msg value actually
+// is. Unlike StatusCode, this is a closed enum: every concrete type in this
+// package that implements Msg is one of the four kinds below, and there's
+// no cmd-specific-protocol equivalent of NewCmdResult accepting an
+// arbitrary caller-defined value.
+type MsgType int
+
+const (
+	MsgTypeCmd MsgType = iota
+	MsgTypeData
+	MsgTypeAudioFrame
+	MsgTypeVideoFrame
+)
+
+// String renders t the way a logging/forwarding extension would want to
+// print it, e.g. "[cmd] start" instead of "[0] start".
+func (t MsgType) String() string {
+	switch t {
+	case MsgTypeCmd:
+		return "cmd"
+	case MsgTypeData:
+		return "data"
+	case MsgTypeAudioFrame:
+		return "audio_frame"
+	case MsgTypeVideoFrame:
+		return "video_frame"
+	default:
+		return fmt.Sprintf("unknown(%d)", int(t))
+	}
+}
+
+// Msg is the common supertype Cmd, Data, AudioFrame and VideoFrame all
+// satisfy: the header metadata every message kind carries regardless of its
+// own payload -- what kind it is, what it's named, when it was stamped, and
+// where it came from. It exists so a generic logging or routing middleware
+// that's handed one of the four as a Msg (e.g. from a queue mixing message
+// kinds) can read all four without a type assertion down to the concrete
+// kind first.
+//
+// Msg deliberately stops at header metadata and doesn't pull in the
+// property accessors (GetPropertyString and the rest): Data carries no
+// property bag at all (see its doc comment), so requiring them here would
+// force Data to grow one it's specifically designed not to have. A caller
+// that needs properties already knows it's holding a Cmd or a CmdResult,
+// the only two kinds that have any, and can use those interfaces directly.
+type Msg interface {
+	// GetType reports which of the four message kinds this value is. It
+	// never returns a non-nil error today -- a Cmd, Data, AudioFrame or
+	// VideoFrame's kind is fixed at construction and never changes -- but
+	// the error return keeps GetType's signature consistent with the rest
+	// of this interface, and leaves room for a future message kind whose
+	// type could plausibly fail to resolve.
+	GetType() (MsgType, error)
+
+	// GetName reports this message's name -- a Cmd's dispatch name, an
+	// AudioFrame's or VideoFrame's construction-time name, or whatever a
+	// Data's SetName last set (empty if it was never called).
+	GetName() (string, error)
+
+	// GetTimestamp reports this message's opaque, caller-defined
+	// timestamp, on whatever clock its source uses; this package never
+	// interprets it. It defaults to 0 until set via the concrete type's
+	// own SetTimestamp.
+	GetTimestamp() (int64, error)
+
+	// GetSource reports the Loc this message was sent from, stamped by
+	// whichever Send* call actually sent it (SendCmd/SendCmdEx for a Cmd,
+	// SendData/SendDataWithAck for a Data, SendAudioFrame/SendVideoFrame
+	// for a frame); a message that's never been sent carries a zero Loc.
+	GetSource() (Loc, error)
+}
@@ -0,0 +1,140 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import "testing"
+
+func TestMsgType_GetTypeIdentifiesEachConcreteMessageKind(t *testing.T) {
+	cmd, _ := NewCmd("greet")
+	data, _ := NewData()
+	audio, _ := NewAudioFrame("frame")
+	video, _ := NewVideoFrame("frame")
+
+	cases := []struct {
+		name string
+		msg  Msg
+		want MsgType
+	}{
+		{"cmd", cmd, MsgTypeCmd},
+		{"data", data, MsgTypeData},
+		{"audio_frame", audio, MsgTypeAudioFrame},
+		{"video_frame", video, MsgTypeVideoFrame},
+	}
+	for _, tc := range cases {
+		got, err := tc.msg.GetType()
+		if err != nil {
+			t.Fatalf("%s: GetType: %v", tc.name, err)
+		}
+		if got != tc.want {
+			t.Fatalf("%s: GetType() = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestMsgType_StringRendersEachKindAndAnUnknownValue(t *testing.T) {
+	cases := []struct {
+		t    MsgType
+		want string
+	}{
+		{MsgTypeCmd, "cmd"},
+		{MsgTypeData, "data"},
+		{MsgTypeAudioFrame, "audio_frame"},
+		{MsgTypeVideoFrame, "video_frame"},
+		{MsgType(99), "unknown(99)"},
+	}
+	for _, tc := range cases {
+		if got := tc.t.String(); got != tc.want {
+			t.Fatalf("MsgType(%d).String() = %q, want %q", int(tc.t), got, tc.want)
+		}
+	}
+}
+
+// describeMsg is the kind of generic logging helper Msg exists for: it
+// prints a uniform "[kind] name" label for any Msg without type-asserting
+// down to Cmd/Data/AudioFrame/VideoFrame first.
+func describeMsg(m Msg) string {
+	kind, err := m.GetType()
+	if err != nil {
+		return "[unknown]"
+	}
+	label := "[" + kind.String() + "]"
+	if name, err := m.GetName(); err == nil && name != "" {
+		return label + " " + name
+	}
+	return label
+}
+
+func TestDescribeMsg_UsesGetTypeUniformlyAcrossMessageKinds(t *testing.T) {
+	cmd, _ := NewCmd("start")
+	data, _ := NewData()
+
+	if got, want := describeMsg(cmd), "[cmd] start"; got != want {
+		t.Fatalf("describeMsg(cmd) = %q, want %q", got, want)
+	}
+	if got, want := describeMsg(data), "[data]"; got != want {
+		t.Fatalf("describeMsg(data) = %q, want %q", got, want)
+	}
+
+	_ = data.SetName("chunk")
+	if got, want := describeMsg(data), "[data] chunk"; got != want {
+		t.Fatalf("describeMsg(data) after SetName = %q, want %q", got, want)
+	}
+}
+
+func TestMsg_GetTimestampAndGetSourceAreUniformAcrossMessageKinds(t *testing.T) {
+	cmd, _ := NewCmd("greet")
+	data, _ := NewData()
+	audio, _ := NewAudioFrame("frame")
+	video, _ := NewVideoFrame("frame")
+
+	msgs := []Msg{cmd, data, audio, video}
+	for _, m := range msgs {
+		if ts, err := m.GetTimestamp(); err != nil || ts != 0 {
+			t.Fatalf("GetTimestamp() on a fresh %T = (%d, %v), want (0, nil)", m, ts, err)
+		}
+		if src, err := m.GetSource(); err != nil || src != (Loc{}) {
+			t.Fatalf("GetSource() on a fresh %T = (%+v, %v), want (Loc{}, nil)", m, src, err)
+		}
+	}
+
+	if err := cmd.SetTimestamp(42); err != nil {
+		t.Fatalf("Cmd.SetTimestamp: %v", err)
+	}
+	if ts, _ := cmd.GetTimestamp(); ts != 42 {
+		t.Fatalf("Cmd.GetTimestamp() = %d, want 42", ts)
+	}
+}
+
+func TestMsg_SendStampsSourceOnDataAndFrames(t *testing.T) {
+	engine := NewEngine(DefaultExtension{}, WithExtensionName("stamper"))
+	engine.Init()
+	engine.Start()
+	defer engine.Stop()
+	tenEnv := engine.TenEnv()
+
+	data, _ := NewData()
+	if err := tenEnv.SendData(data, func(TenEnv, error) {}); err != nil {
+		t.Fatalf("SendData: %v", err)
+	}
+	if src, _ := data.GetSource(); src.ExtensionName != "stamper" {
+		t.Fatalf("Data.GetSource() after SendData = %+v, want ExtensionName=stamper", src)
+	}
+
+	audio, _ := NewAudioFrame("pcm")
+	if err := tenEnv.SendAudioFrame(audio, func(TenEnv, error) {}); err != nil {
+		t.Fatalf("SendAudioFrame: %v", err)
+	}
+	if src, _ := audio.GetSource(); src.ExtensionName != "stamper" {
+		t.Fatalf("AudioFrame.GetSource() after SendAudioFrame = %+v, want ExtensionName=stamper", src)
+	}
+
+	video, _ := NewVideoFrame("rgba")
+	if err := tenEnv.SendVideoFrame(video, func(TenEnv, error) {}); err != nil {
+		t.Fatalf("SendVideoFrame: %v", err)
+	}
+	if src, _ := video.GetSource(); src.ExtensionName != "stamper" {
+		t.Fatalf("VideoFrame.GetSource() after SendVideoFrame = %+v, want ExtensionName=stamper", src)
+	}
+}
@@ -0,0 +1,153 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"testing"
+	"time"
+)
+
+// panickingExtension panics from whichever callback its bool field selects,
+// letting a test exercise the recover-and-continue path for that callback
+// specifically.
+type panickingExtension struct {
+	DefaultExtension
+	onInit, onStart, onStop, onPause, onResume, onCmd bool
+}
+
+func (p *panickingExtension) OnInit(tenEnv TenEnv) {
+	if p.onInit {
+		panic("boom in OnInit")
+	}
+	tenEnv.OnInitDone()
+}
+
+func (p *panickingExtension) OnStart(tenEnv TenEnv) {
+	if p.onStart {
+		panic("boom in OnStart")
+	}
+	tenEnv.OnStartDone()
+}
+
+func (p *panickingExtension) OnStop(tenEnv TenEnv) {
+	if p.onStop {
+		panic("boom in OnStop")
+	}
+	tenEnv.OnStopDone()
+}
+
+func (p *panickingExtension) OnPause(tenEnv TenEnv) {
+	if p.onPause {
+		panic("boom in OnPause")
+	}
+	tenEnv.OnPauseDone()
+}
+
+func (p *panickingExtension) OnResume(tenEnv TenEnv) {
+	if p.onResume {
+		panic("boom in OnResume")
+	}
+	tenEnv.OnResumeDone()
+}
+
+func (p *panickingExtension) OnCmd(tenEnv TenEnv, cmd Cmd) {
+	if p.onCmd {
+		panic("boom in OnCmd")
+	}
+	tenEnv.ReturnOK(cmd, "ok")
+}
+
+func TestEngine_RecoversOnCmdPanicWithoutCrashing(t *testing.T) {
+	engine := NewEngine(&panickingExtension{onCmd: true})
+	cmd, _ := NewCmd("frobnicate")
+
+	if err := engine.DeliverCmd(cmd); err != nil {
+		t.Fatalf("DeliverCmd: %v", err)
+	}
+
+	// The extension, and the Engine driving it, must still be usable after
+	// recovering -- a second cmd shouldn't be affected by the first's panic.
+	cmd2, _ := NewCmd("frobnicate")
+	if err := engine.DeliverCmd(cmd2); err != nil {
+		t.Fatalf("DeliverCmd after a recovered panic: %v", err)
+	}
+}
+
+func TestEngine_RecoversOnInitPanicAndDoesNotHang(t *testing.T) {
+	engine := NewEngine(&panickingExtension{onInit: true})
+	done := make(chan error, 1)
+	go func() { done <- engine.Init() }()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Init: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Init did not return: a panicking OnInit left it hanging")
+	}
+}
+
+func TestEngine_RecoversOnStopPanicAndDoesNotHang(t *testing.T) {
+	engine := NewEngine(&panickingExtension{onStop: true})
+	if err := engine.Init(); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	if err := engine.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- engine.Stop() }()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Stop: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Stop did not return: a panicking OnStop left it hanging")
+	}
+}
+
+func TestEngine_RecoversOnPausePanicAndDoesNotHang(t *testing.T) {
+	engine := NewEngine(&panickingExtension{onPause: true})
+	if err := engine.Init(); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	if err := engine.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- engine.Pause() }()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Pause: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Pause did not return: a panicking OnPause left it hanging")
+	}
+}
+
+func TestEngine_PanicRecoveryDisabledRePanics(t *testing.T) {
+	engine := NewEngine(&panickingExtension{onCmd: true}, WithPanicRecovery(false))
+	if err := engine.Init(); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	if err := engine.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	cmd, _ := NewCmd("frobnicate")
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("DeliverCmd did not panic with WithPanicRecovery(false)")
+		}
+	}()
+	engine.DeliverCmd(cmd)
+}
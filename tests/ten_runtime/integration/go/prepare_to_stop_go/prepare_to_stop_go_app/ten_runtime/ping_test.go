@@ -0,0 +1,89 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestTenEnv_PingMeasuresRTTToAnExtensionUsingOnlyDefaultExtension(t *testing.T) {
+	reg, err := RegisterAddonAsExtension("synth92_pingee", NewDefaultExtensionAddon(
+		func(name string) Extension { return DefaultExtension{} },
+	))
+	if err != nil {
+		t.Fatalf("RegisterAddonAsExtension: %v", err)
+	}
+	defer reg.ReleaseInstance("pingee")
+	defer reg.Unregister()
+
+	engine := NewEngine(DefaultExtension{}, WithGraphID("g-ping"))
+	engine.Init()
+	engine.Start()
+
+	if _, err := engine.TenEnv().CreateExtension("synth92_pingee", "pingee"); err != nil {
+		t.Fatalf("CreateExtension: %v", err)
+	}
+
+	done := make(chan struct{})
+	var rtt = -1
+	go func() {
+		defer close(done)
+		d, err := engine.TenEnv().Ping("pingee")
+		if err != nil {
+			t.Errorf("Ping: %v", err)
+			return
+		}
+		if d < 0 {
+			t.Errorf("Ping RTT = %v, want non-negative", d)
+			return
+		}
+		rtt = 0
+	}()
+	<-done
+	if rtt != 0 {
+		t.Fatal("Ping never completed successfully")
+	}
+}
+
+func TestTenEnv_PingFailsForUnknownInstance(t *testing.T) {
+	engine := NewEngine(DefaultExtension{}, WithGraphID("g-ping-missing"))
+	engine.Init()
+	engine.Start()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if _, err := engine.TenEnv().Ping("no-such-instance"); !errors.Is(err, ErrExtensionInstanceNotFound) {
+			t.Errorf("Ping(unknown) = %v, want ErrExtensionInstanceNotFound", err)
+		}
+	}()
+	<-done
+}
+
+func TestTenEnv_PingFromOnCmdDispatchGoroutineIsRejected(t *testing.T) {
+	ext := &pingFromOnCmdExtension{}
+	engine := NewEngine(ext, WithGraphID("g-ping-dispatch"))
+	engine.Init()
+	engine.Start()
+
+	cmd, _ := NewCmd("trigger")
+	if err := engine.DeliverCmd(cmd); err != nil {
+		t.Fatalf("DeliverCmd: %v", err)
+	}
+	if !errors.Is(ext.pingErr, ErrPingOnDispatchGoroutine) {
+		t.Fatalf("Ping from OnCmd = %v, want ErrPingOnDispatchGoroutine", ext.pingErr)
+	}
+}
+
+type pingFromOnCmdExtension struct {
+	DefaultExtension
+	pingErr error
+}
+
+func (e *pingFromOnCmdExtension) OnCmd(tenEnv TenEnv, cmd Cmd) {
+	_, e.pingErr = tenEnv.Ping("whoever")
+	tenEnv.ReturnOK(cmd, "done")
+}
@@ -0,0 +1,806 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// PropertyType identifies the concrete type of a property value, for a
+// generic transformation extension that needs to walk an arbitrary Cmd/
+// CmdResult and copy or convert each property without already knowing its
+// shape. It mirrors the value types the scalar getters and SetProperties
+// accept, plus Object/Array for a nested map[string]any/[]any and Null for
+// a property explicitly set to a JSON null.
+type PropertyType int
+
+const (
+	PropertyTypeString PropertyType = iota
+	PropertyTypeInt64
+	PropertyTypeFloat64
+	PropertyTypeBool
+	PropertyTypeBytes
+	PropertyTypeObject
+	PropertyTypeArray
+	PropertyTypeNull
+)
+
+// String renders t the way a logging/forwarding extension would want to
+// print it, e.g. "[string]" instead of "[0]".
+func (t PropertyType) String() string {
+	switch t {
+	case PropertyTypeString:
+		return "string"
+	case PropertyTypeInt64:
+		return "int64"
+	case PropertyTypeFloat64:
+		return "float64"
+	case PropertyTypeBool:
+		return "bool"
+	case PropertyTypeBytes:
+		return "bytes"
+	case PropertyTypeObject:
+		return "object"
+	case PropertyTypeArray:
+		return "array"
+	case PropertyTypeNull:
+		return "null"
+	default:
+		return fmt.Sprintf("unknown(%d)", int(t))
+	}
+}
+
+// propertyType reports path's PropertyType, or an error if path doesn't
+// name an existing property (ErrPropertyNotFound) or an intermediate
+// segment can't be navigated into (ErrPropertyTypeMismatch), following the
+// same navigation rules as navigateGet. An int (as opposed to int64) value
+// -- which only ever appears via internal package code, never a public
+// setter -- reports PropertyTypeInt64, matching propertyGetInt64's own
+// int/int64 widening.
+func propertyType(props map[string]any, path string) (PropertyType, error) {
+	v, err := navigateGet(props, path)
+	if err != nil {
+		return 0, err
+	}
+	switch v.(type) {
+	case string:
+		return PropertyTypeString, nil
+	case int64, int:
+		return PropertyTypeInt64, nil
+	case float64:
+		return PropertyTypeFloat64, nil
+	case bool:
+		return PropertyTypeBool, nil
+	case []byte:
+		return PropertyTypeBytes, nil
+	case map[string]any:
+		return PropertyTypeObject, nil
+	case []any:
+		return PropertyTypeArray, nil
+	case nil:
+		return PropertyTypeNull, nil
+	default:
+		return 0, fmt.Errorf(
+			"%w: property %q is %T, not a recognized property type",
+			ErrPropertyTypeMismatch, path, v,
+		)
+	}
+}
+
+// ErrPropertyNotFound is returned by a typed property getter when path
+// doesn't name an existing property.
+var ErrPropertyNotFound = errors.New("ten: property not found")
+
+// ErrPropertyTypeMismatch is returned by a typed property getter when path
+// exists but doesn't hold a value of the requested type. Numeric widening
+// (an int stored value satisfying a float64 getter) is allowed; string
+// values are never coerced to a numeric type, even if they look numeric.
+var ErrPropertyTypeMismatch = errors.New("ten: property type mismatch")
+
+// navigateGet walks root along path's dot-separated segments, descending
+// into nested maps and, for a purely numeric segment, into slice elements
+// by index (so "stream.0.codec" addresses the "codec" key of the first
+// element of the "stream" array). It returns ErrPropertyNotFound if a
+// segment doesn't exist and ErrPropertyTypeMismatch if a non-terminal
+// segment's value isn't something that can be descended into, naming the
+// failing segment in both cases.
+func navigateGet(root map[string]any, path string) (any, error) {
+	var cur any = root
+	for _, seg := range strings.Split(path, ".") {
+		switch c := cur.(type) {
+		case map[string]any:
+			v, ok := c[seg]
+			if !ok {
+				return nil, fmt.Errorf(
+					"%w: %q (no such key %q)", ErrPropertyNotFound, path, seg,
+				)
+			}
+			cur = v
+		case []any:
+			idx, err := strconv.Atoi(seg)
+			if err != nil || idx < 0 || idx >= len(c) {
+				return nil, fmt.Errorf(
+					"%w: %q (index %q out of range)", ErrPropertyNotFound, path, seg,
+				)
+			}
+			cur = c[idx]
+		default:
+			return nil, fmt.Errorf(
+				"%w: %q (segment %q is not an object or array)",
+				ErrPropertyTypeMismatch, path, seg,
+			)
+		}
+	}
+	return cur, nil
+}
+
+// navigateSet walks root the same way navigateGet does, creating an
+// intermediate map[string]any for any missing object segment, then
+// assigns value at the final segment. Array segments are only ever
+// indexed into an existing element, never grown or created -- a missing
+// or out-of-range array index is an error, matching navigateGet.
+func navigateSet(root map[string]any, path string, value any) error {
+	segs := strings.Split(path, ".")
+	var cur any = root
+	for i, seg := range segs {
+		last := i == len(segs)-1
+		switch c := cur.(type) {
+		case map[string]any:
+			if last {
+				c[seg] = value
+				return nil
+			}
+			next, ok := c[seg]
+			if !ok {
+				next = map[string]any{}
+				c[seg] = next
+			}
+			if !isNavigable(next) {
+				return fmt.Errorf(
+					"%w: %q (segment %q is not an object)",
+					ErrPropertyTypeMismatch, path, seg,
+				)
+			}
+			cur = next
+		case []any:
+			idx, err := strconv.Atoi(seg)
+			if err != nil || idx < 0 || idx >= len(c) {
+				return fmt.Errorf(
+					"%w: %q (index %q out of range)", ErrPropertyNotFound, path, seg,
+				)
+			}
+			if last {
+				c[idx] = value
+				return nil
+			}
+			if !isNavigable(c[idx]) {
+				return fmt.Errorf(
+					"%w: %q (segment %q is not an object)",
+					ErrPropertyTypeMismatch, path, seg,
+				)
+			}
+			cur = c[idx]
+		default:
+			return fmt.Errorf(
+				"%w: %q (segment %q is not an object or array)",
+				ErrPropertyTypeMismatch, path, seg,
+			)
+		}
+	}
+	return nil
+}
+
+// propertyKeys returns props's top-level keys in sorted order, for a
+// generic logging/forwarding extension that needs to enumerate what a Cmd
+// or CmdResult carries without knowing its shape up front. Nested object
+// keys are not flattened in; use propertyKeysAt(props, path) for a nested
+// object's keys.
+func propertyKeys(props map[string]any) []string {
+	keys := make([]string, 0, len(props))
+	for k := range props {
+		if isReservedPropertyKey(k) {
+			continue
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// propertyKeysAt is propertyKeys for the object at path.
+func propertyKeysAt(props map[string]any, path string) ([]string, error) {
+	if path == "" {
+		return propertyKeys(props), nil
+	}
+	v, err := navigateGet(props, path)
+	if err != nil {
+		return nil, err
+	}
+	m, ok := v.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf(
+			"%w: property %q is %T, not an object", ErrPropertyTypeMismatch, path, v,
+		)
+	}
+	return propertyKeys(m), nil
+}
+
+// propertyHas reports whether path names an existing property, with no
+// distinction between "not found" and any other navigation failure -- a
+// caller that needs to know why should call the typed getter instead.
+func propertyHas(props map[string]any, path string) bool {
+	_, err := navigateGet(props, path)
+	return err == nil
+}
+
+// propertyDelete removes path from props, walking the same way navigateGet
+// does. It returns ErrPropertyNotFound if path, or an intermediate
+// segment, doesn't exist. Only an object key can be deleted; the last
+// segment naming an array index returns ErrPropertyTypeMismatch, since
+// removing an array element would require shifting every later index and
+// navigateSet doesn't support that kind of array mutation either.
+func propertyDelete(props map[string]any, path string) error {
+	segs := strings.Split(path, ".")
+	var cur any = props
+	for i, seg := range segs {
+		last := i == len(segs)-1
+		switch c := cur.(type) {
+		case map[string]any:
+			v, ok := c[seg]
+			if !ok {
+				return fmt.Errorf(
+					"%w: %q (no such key %q)", ErrPropertyNotFound, path, seg,
+				)
+			}
+			if last {
+				delete(c, seg)
+				return nil
+			}
+			cur = v
+		case []any:
+			idx, err := strconv.Atoi(seg)
+			if err != nil || idx < 0 || idx >= len(c) {
+				return fmt.Errorf(
+					"%w: %q (index %q out of range)", ErrPropertyNotFound, path, seg,
+				)
+			}
+			if last {
+				return fmt.Errorf(
+					"%w: %q (cannot delete an array element)", ErrPropertyTypeMismatch, path,
+				)
+			}
+			cur = c[idx]
+		default:
+			return fmt.Errorf(
+				"%w: %q (segment %q is not an object or array)",
+				ErrPropertyTypeMismatch, path, seg,
+			)
+		}
+	}
+	return nil
+}
+
+func isNavigable(v any) bool {
+	switch v.(type) {
+	case map[string]any, []any:
+		return true
+	default:
+		return false
+	}
+}
+
+// propertyGetString reads path from props as a string, with no coercion
+// from other types.
+func propertyGetString(props map[string]any, path string) (string, error) {
+	v, err := navigateGet(props, path)
+	if err != nil {
+		return "", err
+	}
+	s, ok := v.(string)
+	if !ok {
+		return "", fmt.Errorf(
+			"%w: property %q is %T, not a string", ErrPropertyTypeMismatch, path, v,
+		)
+	}
+	return s, nil
+}
+
+// propertyGetInt64 reads path from props as an int64. Only integer-typed
+// values are accepted; a float64 (even an integral one) or a numeric-
+// looking string is a type mismatch, not a silent coercion.
+func propertyGetInt64(props map[string]any, path string) (int64, error) {
+	v, err := navigateGet(props, path)
+	if err != nil {
+		return 0, err
+	}
+	switch n := v.(type) {
+	case int64:
+		return n, nil
+	case int:
+		return int64(n), nil
+	default:
+		return 0, fmt.Errorf(
+			"%w: property %q is %T, not an integer", ErrPropertyTypeMismatch, path, v,
+		)
+	}
+}
+
+// propertyGetFloat64 reads path from props as a float64. Integer-typed
+// values widen cleanly to float64; a string never coerces, even one that
+// parses as a number.
+func propertyGetFloat64(props map[string]any, path string) (float64, error) {
+	v, err := navigateGet(props, path)
+	if err != nil {
+		return 0, err
+	}
+	switch n := v.(type) {
+	case float64:
+		return n, nil
+	case int64:
+		return float64(n), nil
+	case int:
+		return float64(n), nil
+	default:
+		return 0, fmt.Errorf(
+			"%w: property %q is %T, not a number", ErrPropertyTypeMismatch, path, v,
+		)
+	}
+}
+
+// propertyGetNumber reads path from props as a float64, accepting either
+// storage type propertyGetInt64/propertyGetFloat64 individually require,
+// and reports whether the stored value was integral -- so a caller
+// working against a graph config where the same field might arrive as a
+// JSON int or a JSON float (JSON itself doesn't distinguish them once a
+// value like 3.0 has been round-tripped) can read it without picking the
+// wrong strict getter and failing on whichever type it didn't expect.
+func propertyGetNumber(props map[string]any, path string) (float64, bool, error) {
+	v, err := navigateGet(props, path)
+	if err != nil {
+		return 0, false, err
+	}
+	switch n := v.(type) {
+	case float64:
+		return n, false, nil
+	case int64:
+		return float64(n), true, nil
+	case int:
+		return float64(n), true, nil
+	default:
+		return 0, false, fmt.Errorf(
+			"%w: property %q is %T, not a number", ErrPropertyTypeMismatch, path, v,
+		)
+	}
+}
+
+// propertyGetBool reads path from props as a bool, with no coercion from
+// other types.
+func propertyGetBool(props map[string]any, path string) (bool, error) {
+	v, err := navigateGet(props, path)
+	if err != nil {
+		return false, err
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf(
+			"%w: property %q is %T, not a bool", ErrPropertyTypeMismatch, path, v,
+		)
+	}
+	return b, nil
+}
+
+// propertyGetBytes reads path from props as a []byte, returning a copy so
+// the caller can freely mutate it without touching what's stored. A missing
+// path is ErrPropertyNotFound, distinguishing a genuinely absent property
+// from one holding an empty (but present) blob, which decodes to a
+// zero-length, non-nil []byte.
+func propertyGetBytes(props map[string]any, path string) ([]byte, error) {
+	v, err := navigateGet(props, path)
+	if err != nil {
+		return nil, err
+	}
+	b, ok := v.([]byte)
+	if !ok {
+		return nil, fmt.Errorf(
+			"%w: property %q is %T, not a byte slice", ErrPropertyTypeMismatch, path, v,
+		)
+	}
+	out := make([]byte, len(b))
+	copy(out, b)
+	return out, nil
+}
+
+// propertyGetStringList reads path from props as a []string, the typed
+// counterpart to a JSON array of strings like allowed_models: ["a","b"].
+// The stored value must be a []any (as navigateSet's Set*List counterpart,
+// or SetPropertyFromJSONBytes decoding a JSON array, produces) whose every
+// element is a string; a single non-string element is
+// ErrPropertyTypeMismatch, naming its index, the same way a mismatched
+// scalar property names its path.
+func propertyGetStringList(props map[string]any, path string) ([]string, error) {
+	v, err := navigateGet(props, path)
+	if err != nil {
+		return nil, err
+	}
+	arr, ok := v.([]any)
+	if !ok {
+		return nil, fmt.Errorf(
+			"%w: property %q is %T, not an array", ErrPropertyTypeMismatch, path, v,
+		)
+	}
+	out := make([]string, len(arr))
+	for i, elem := range arr {
+		s, ok := elem.(string)
+		if !ok {
+			return nil, fmt.Errorf(
+				"%w: property %q[%d] is %T, not a string",
+				ErrPropertyTypeMismatch, path, i, elem,
+			)
+		}
+		out[i] = s
+	}
+	return out, nil
+}
+
+// propertyGetInt64List is propertyGetStringList for []int64, with the same
+// int/int64 widening propertyGetInt64 does per element.
+func propertyGetInt64List(props map[string]any, path string) ([]int64, error) {
+	v, err := navigateGet(props, path)
+	if err != nil {
+		return nil, err
+	}
+	arr, ok := v.([]any)
+	if !ok {
+		return nil, fmt.Errorf(
+			"%w: property %q is %T, not an array", ErrPropertyTypeMismatch, path, v,
+		)
+	}
+	out := make([]int64, len(arr))
+	for i, elem := range arr {
+		switch n := elem.(type) {
+		case int64:
+			out[i] = n
+		case int:
+			out[i] = int64(n)
+		default:
+			return nil, fmt.Errorf(
+				"%w: property %q[%d] is %T, not an integer",
+				ErrPropertyTypeMismatch, path, i, elem,
+			)
+		}
+	}
+	return out, nil
+}
+
+// propertyGetFloat64List is propertyGetStringList for []float64, with the
+// same int/int64-widens-to-float64 rule propertyGetFloat64 does per
+// element.
+func propertyGetFloat64List(props map[string]any, path string) ([]float64, error) {
+	v, err := navigateGet(props, path)
+	if err != nil {
+		return nil, err
+	}
+	arr, ok := v.([]any)
+	if !ok {
+		return nil, fmt.Errorf(
+			"%w: property %q is %T, not an array", ErrPropertyTypeMismatch, path, v,
+		)
+	}
+	out := make([]float64, len(arr))
+	for i, elem := range arr {
+		switch n := elem.(type) {
+		case float64:
+			out[i] = n
+		case int64:
+			out[i] = float64(n)
+		case int:
+			out[i] = float64(n)
+		default:
+			return nil, fmt.Errorf(
+				"%w: property %q[%d] is %T, not a number",
+				ErrPropertyTypeMismatch, path, i, elem,
+			)
+		}
+	}
+	return out, nil
+}
+
+// propertySetStringList stores values at path as a []any, the same array
+// shape navigateGet/propertyGetStringList and a decoded JSON array both
+// produce, so a property set this way round-trips through
+// GetPropertyToJSONBytes/SetPropertyFromJSONBytes exactly like one that
+// arrived as JSON in the first place.
+func propertySetStringList(props map[string]any, path string, values []string) error {
+	arr := make([]any, len(values))
+	for i, v := range values {
+		arr[i] = v
+	}
+	return navigateSet(props, path, arr)
+}
+
+// propertySetInt64List is propertySetStringList for []int64.
+func propertySetInt64List(props map[string]any, path string, values []int64) error {
+	arr := make([]any, len(values))
+	for i, v := range values {
+		arr[i] = v
+	}
+	return navigateSet(props, path, arr)
+}
+
+// propertySetFloat64List is propertySetStringList for []float64.
+func propertySetFloat64List(props map[string]any, path string, values []float64) error {
+	arr := make([]any, len(values))
+	for i, v := range values {
+		arr[i] = v
+	}
+	return navigateSet(props, path, arr)
+}
+
+// jsonMaxSafeInteger is JavaScript's Number.MAX_SAFE_INTEGER (2^53 - 1):
+// the largest integer a float64 can represent without losing precision.
+// WithLargeIntAsString uses it as the threshold past which an int64
+// property is rendered as a JSON string instead of a bare number.
+const jsonMaxSafeInteger = 1<<53 - 1
+
+// jsonNumberConfig holds propertyGetJSON's rendering options; see
+// JSONNumberOption.
+type jsonNumberConfig struct {
+	largeIntAsString bool
+}
+
+// JSONNumberOption configures how GetPropertyToJSONBytes renders numeric
+// properties. See WithLargeIntAsString for the one option this package
+// defines.
+type JSONNumberOption func(*jsonNumberConfig)
+
+// WithLargeIntAsString makes GetPropertyToJSONBytes render any int64
+// property whose magnitude exceeds JavaScript's Number.MAX_SAFE_INTEGER
+// (2^53 - 1) as a quoted JSON string rather than a bare number, for a
+// consumer -- e.g. a browser or another language whose only numeric type
+// is a float64 -- that would otherwise silently lose precision decoding a
+// snowflake-style ID. It leaves every int64 within that range, and every
+// float64, exactly as json.Marshal would have rendered it.
+func WithLargeIntAsString() JSONNumberOption {
+	return func(c *jsonNumberConfig) { c.largeIntAsString = true }
+}
+
+// stringifyLargeInts walks v -- a value out of the property tree, so only
+// ever built from map[string]any, []any and the scalar types the setters
+// accept -- replacing any int64 outside jsonMaxSafeInteger's range with its
+// decimal string form. It copies map[string]any/[]any rather than mutating
+// in place, so it never touches the actual stored property tree.
+func stringifyLargeInts(v any) any {
+	switch vv := v.(type) {
+	case int64:
+		if vv > jsonMaxSafeInteger || vv < -jsonMaxSafeInteger {
+			return strconv.FormatInt(vv, 10)
+		}
+		return vv
+	case map[string]any:
+		out := make(map[string]any, len(vv))
+		for k, sub := range vv {
+			out[k] = stringifyLargeInts(sub)
+		}
+		return out
+	case []any:
+		out := make([]any, len(vv))
+		for i, sub := range vv {
+			out[i] = stringifyLargeInts(sub)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+// propertyGetJSON serializes the value at path to JSON, or the whole
+// property bag if path is empty. It marshals straight from the decoded
+// property tree to []byte, with no intermediate string conversion.
+func propertyGetJSON(props map[string]any, path string, opts []JSONNumberOption) ([]byte, error) {
+	var v any = props
+	if path != "" {
+		leaf, err := navigateGet(props, path)
+		if err != nil {
+			return nil, err
+		}
+		v = leaf
+	}
+	var cfg jsonNumberConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.largeIntAsString {
+		v = stringifyLargeInts(v)
+	}
+	return json.Marshal(v)
+}
+
+// normalizeJSONNumbers walks v -- freshly decoded via a json.Decoder with
+// UseNumber, so every number is a json.Number -- converting each one to
+// an int64 when it parses as one and to a float64 otherwise. This is what
+// lets a large integer survive SetPropertyFromJSONBytes without being
+// rounded to the nearest float64. It mutates map[string]any/[]any in
+// place, which is safe since both were only just built by this decode.
+func normalizeJSONNumbers(v any) any {
+	switch vv := v.(type) {
+	case json.Number:
+		if n, err := vv.Int64(); err == nil {
+			return n
+		}
+		f, _ := vv.Float64()
+		return f
+	case map[string]any:
+		for k, sub := range vv {
+			vv[k] = normalizeJSONNumbers(sub)
+		}
+		return vv
+	case []any:
+		for i, sub := range vv {
+			vv[i] = normalizeJSONNumbers(sub)
+		}
+		return vv
+	default:
+		return v
+	}
+}
+
+// propertySetJSON parses data as JSON and stores it at path, or replaces
+// the whole property bag in place if path is empty (in which case the
+// top-level JSON value must be an object). It decodes via a json.Decoder
+// with UseNumber (see normalizeJSONNumbers) instead of json.Unmarshal, so
+// integers round-trip exactly, and shares navigateSet's
+// intermediate-object-creation and array-indexing rules.
+func propertySetJSON(props map[string]any, path string, data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return fmt.Errorf("ten: invalid JSON for property %q: %w", path, err)
+	}
+	v = normalizeJSONNumbers(v)
+	if path == "" {
+		m, ok := v.(map[string]any)
+		if !ok {
+			return fmt.Errorf(
+				"%w: top-level JSON must be an object, got %T", ErrPropertyTypeMismatch, v,
+			)
+		}
+		for k := range props {
+			delete(props, k)
+		}
+		for k, vv := range m {
+			props[k] = vv
+		}
+		return nil
+	}
+	return navigateSet(props, path, v)
+}
+
+// ErrUnsupportedPropertyValueType is returned by setPropertiesBatch (and
+// so by Cmd/CmdResult.SetProperties) for a value whose type isn't one of
+// the scalar setters' types (string, int64, float64, bool, []byte) or a
+// nested map[string]any/[]any built from them.
+var ErrUnsupportedPropertyValueType = errors.New("ten: unsupported property value type")
+
+// validatePropertyValue reports whether v is a type SetProperties knows
+// how to store: the same scalar types SetPropertyString/Int64/Float64/
+// Bool accept plus []byte, or a map[string]any/[]any whose own values
+// all validate recursively -- the same shape SetPropertyFromJSONBytes
+// would produce by decoding a JSON object or array, minus the "numbers
+// decode as float64" quirk JSON unmarshaling has.
+func validatePropertyValue(v any) error {
+	switch vv := v.(type) {
+	case string, int64, float64, bool, []byte:
+		return nil
+	case map[string]any:
+		for _, sub := range vv {
+			if err := validatePropertyValue(sub); err != nil {
+				return err
+			}
+		}
+		return nil
+	case []any:
+		for _, sub := range vv {
+			if err := validatePropertyValue(sub); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("%w: %T", ErrUnsupportedPropertyValueType, v)
+	}
+}
+
+// setPropertiesBatch validates every value in m up front, in sorted key
+// order, before storing any of them -- so a batch with one unsupported
+// value fails as a whole, naming the first offending key (by sort order,
+// for a deterministic error independent of map iteration order) rather
+// than leaving props partially updated. Each key is set the same way
+// SetPropertyString et al. set path, including dot-notation support for
+// reaching into nested objects.
+func setPropertiesBatch(props map[string]any, m map[string]any) error {
+	keys := make([]string, 0, len(m))
+	resolved := make(map[string]any, len(m))
+	for k, v := range m {
+		keys = append(keys, k)
+		resolved[k] = resolvePropertyValue(v)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		if err := validatePropertyValue(resolved[k]); err != nil {
+			return fmt.Errorf("ten: SetProperties: property %q: %w", k, err)
+		}
+	}
+	for _, k := range keys {
+		if err := navigateSet(props, k, resolved[k]); err != nil {
+			return fmt.Errorf("ten: SetProperties: property %q: %w", k, err)
+		}
+	}
+	return nil
+}
+
+// deepCopyProps returns an independently-owned copy of props, for Clone
+// methods that must hand back a value sharing no mutable state with the
+// original: nested maps and slices in props (e.g. from a prior
+// SetPropertyFromJSONBytes) are reference types, so a shallow copy of the
+// top-level map would still alias them. It recurses into maps, slices and
+// []byte values and copies everything else (including int64, which
+// round-tripping through JSON would silently turn into float64) as-is.
+func deepCopyProps(props map[string]any) map[string]any {
+	dst := make(map[string]any, len(props))
+	for k, v := range props {
+		dst[k] = deepCopyValue(v)
+	}
+	return dst
+}
+
+func deepCopyValue(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		dst := make(map[string]any, len(v))
+		for k, vv := range v {
+			dst[k] = deepCopyValue(vv)
+		}
+		return dst
+	case []any:
+		dst := make([]any, len(v))
+		for i, vv := range v {
+			dst[i] = deepCopyValue(vv)
+		}
+		return dst
+	case []byte:
+		dst := make([]byte, len(v))
+		copy(dst, v)
+		return dst
+	default:
+		return v
+	}
+}
+
+// propertiesSnapshot is PropertiesSnapshot's shared implementation for
+// Cmd/CmdResult: a deepCopyProps of props with this package's own
+// internally-managed keys (see isReservedPropertyKey) hidden, the same
+// way propertyKeys already hides them from PropertyKeys/PropertyKeysAt. A
+// []byte property comes back as a []byte -- deepCopyProps clones its
+// bytes rather than aliasing the original, so it's safe to read from
+// another goroutine, and it's the same type SetProperties accepts, so
+// round-tripping a snapshot through SetProperties is lossless.
+func propertiesSnapshot(props map[string]any) map[string]any {
+	dst := make(map[string]any, len(props))
+	for k, v := range props {
+		if isReservedPropertyKey(k) {
+			continue
+		}
+		dst[k] = deepCopyValue(v)
+	}
+	return dst
+}
@@ -0,0 +1,168 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"sort"
+	"strings"
+	"sync/atomic"
+)
+
+func (t *tenEnvImpl) OnPropertyChanged(path string, cb func(newValue any)) {
+	t.engine.mu.Lock()
+	defer t.engine.mu.Unlock()
+	if t.engine.propertySubs == nil {
+		t.engine.propertySubs = map[string][]func(any){}
+	}
+	t.engine.propertySubs[path] = append(t.engine.propertySubs[path], cb)
+}
+
+// propertyPathsRelated reports whether an update at updated should notify
+// a subscription registered at subscribed: either they name the same
+// property, subscribed names a property nested inside updated (so
+// replacing updated wholesale replaces subscribed's value too, e.g.
+// updating "audio" fires a subscription on "audio.gain"), or updated
+// names a property nested inside subscribed (so subscribed's own value,
+// read as a whole, has changed even though the update targeted only one
+// of its fields, e.g. updating "audio.gain" fires a subscription on
+// "audio").
+func propertyPathsRelated(subscribed, updated string) bool {
+	if subscribed == updated {
+		return true
+	}
+	return strings.HasPrefix(subscribed, updated+".") || strings.HasPrefix(updated, subscribed+".")
+}
+
+// UpdateProperty simulates the runtime pushing a single externally-changed
+// property value into the extension -- e.g. an operator editing a graph
+// config at runtime -- the same way DeliverCmd simulates it handing over
+// an incoming Cmd. See ReloadProperties for the batched counterpart, used
+// when several properties change together in one hot-reload step. It
+// stores value at path in the extension's property
+// store and then notifies every subscription registered via TenEnv.
+// OnPropertyChanged whose path is related to path (see
+// propertyPathsRelated), passing each the current value at its own
+// subscribed path rather than value itself, since a subscription on an
+// ancestor or descendant of path doesn't share path's value.
+//
+// Callbacks run synchronously on the calling goroutine, recorded as the
+// dispatch goroutine for the duration exactly like dispatchOnCmd does for
+// OnCmd, so from an extension's perspective a property-change
+// notification is just another thing that can arrive on "the" dispatch
+// goroutine, never concurrently with OnCmd or another notification. A
+// panic in one callback is recovered and logged without stopping the
+// rest from running.
+func (e *Engine) UpdateProperty(path string, value any) error {
+	e.mu.Lock()
+	if e.properties == nil {
+		e.properties = map[string]any{}
+	}
+	if err := navigateSet(e.properties, path, value); err != nil {
+		e.mu.Unlock()
+		return err
+	}
+
+	type firing struct {
+		cb    func(any)
+		value any
+	}
+	var fire []firing
+	for subPath, subs := range e.propertySubs {
+		if !propertyPathsRelated(subPath, path) {
+			continue
+		}
+		newValue, err := navigateGet(e.properties, subPath)
+		if err != nil {
+			// The subscribed path no longer resolves after this update
+			// (e.g. an ancestor was replaced with something that no
+			// longer has this child) -- nothing to notify it with.
+			continue
+		}
+		for _, cb := range subs {
+			fire = append(fire, firing{cb: cb, value: newValue})
+		}
+	}
+	e.mu.Unlock()
+
+	if len(fire) == 0 {
+		return nil
+	}
+
+	atomic.StoreInt64(&e.dispatchGoroutine, goroutineID())
+	defer atomic.StoreInt64(&e.dispatchGoroutine, 0)
+	for _, f := range fire {
+		e.invokePropertyChangeCallback(f.cb, f.value)
+	}
+	return nil
+}
+
+func (e *Engine) invokePropertyChangeCallback(cb func(any), value any) {
+	defer e.recoverInto("OnPropertyChanged", nil)
+	cb(value)
+}
+
+// ReloadProperties applies every path/value pair in updates the same way
+// UpdateProperty applies one -- each still fires any related
+// TenEnv.OnPropertyChanged subscription individually -- and then, once
+// every update in the batch has landed, calls the extension's
+// OnConfigReload exactly once with every top-level path that changed,
+// sorted for a deterministic order. It's the hot-reload counterpart to
+// UpdateProperty: an operator changing several properties (a model name
+// and a threshold, say) in one step gets one batched callback to act on
+// atomically, instead of the extension seeing them arrive one at a time
+// with no way to tell whether more are still coming. An empty updates is a
+// no-op: no property changes and no OnConfigReload call.
+func (e *Engine) ReloadProperties(updates map[string]any) error {
+	if len(updates) == 0 {
+		return nil
+	}
+
+	e.mu.Lock()
+	if e.properties == nil {
+		e.properties = map[string]any{}
+	}
+
+	type firing struct {
+		cb    func(any)
+		value any
+	}
+	var fire []firing
+	changed := make([]string, 0, len(updates))
+	for path, value := range updates {
+		if err := navigateSet(e.properties, path, value); err != nil {
+			e.mu.Unlock()
+			return err
+		}
+		changed = append(changed, path)
+		for subPath, subs := range e.propertySubs {
+			if !propertyPathsRelated(subPath, path) {
+				continue
+			}
+			newValue, err := navigateGet(e.properties, subPath)
+			if err != nil {
+				continue
+			}
+			for _, cb := range subs {
+				fire = append(fire, firing{cb: cb, value: newValue})
+			}
+		}
+	}
+	e.mu.Unlock()
+
+	sort.Strings(changed)
+
+	atomic.StoreInt64(&e.dispatchGoroutine, goroutineID())
+	defer atomic.StoreInt64(&e.dispatchGoroutine, 0)
+	for _, f := range fire {
+		e.invokePropertyChangeCallback(f.cb, f.value)
+	}
+	e.callOnConfigReload(changed)
+	return nil
+}
+
+func (e *Engine) callOnConfigReload(changed []string) {
+	defer e.recoverInto("OnConfigReload", nil)
+	e.ext.OnConfigReload(e.tenEnv, changed)
+}
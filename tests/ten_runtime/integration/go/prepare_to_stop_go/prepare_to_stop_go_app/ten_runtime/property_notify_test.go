@@ -0,0 +1,161 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import "testing"
+
+func TestTenEnv_OnPropertyChangedFiresOnExactPathUpdate(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+
+	var got any
+	engine.TenEnv().OnPropertyChanged("gain", func(newValue any) { got = newValue })
+
+	if err := engine.UpdateProperty("gain", 0.8); err != nil {
+		t.Fatalf("UpdateProperty: %v", err)
+	}
+	if got != 0.8 {
+		t.Fatalf("callback got %v, want 0.8", got)
+	}
+}
+
+func TestTenEnv_OnPropertyChangedFiresWhenAncestorReplacedWholesale(t *testing.T) {
+	engine := NewEngine(DefaultExtension{}, WithProperties(map[string]any{
+		"audio": map[string]any{"gain": 0.5},
+	}))
+
+	var got any
+	engine.TenEnv().OnPropertyChanged("audio.gain", func(newValue any) { got = newValue })
+
+	if err := engine.UpdateProperty("audio", map[string]any{"gain": 0.9}); err != nil {
+		t.Fatalf("UpdateProperty: %v", err)
+	}
+	if got != 0.9 {
+		t.Fatalf("callback got %v, want 0.9 after replacing the ancestor object", got)
+	}
+}
+
+func TestTenEnv_OnPropertyChangedFiresOnObjectWhenAFieldChanges(t *testing.T) {
+	engine := NewEngine(DefaultExtension{}, WithProperties(map[string]any{
+		"audio": map[string]any{"gain": 0.5, "muted": false},
+	}))
+
+	var got any
+	engine.TenEnv().OnPropertyChanged("audio", func(newValue any) { got = newValue })
+
+	if err := engine.UpdateProperty("audio.gain", 0.9); err != nil {
+		t.Fatalf("UpdateProperty: %v", err)
+	}
+	obj, ok := got.(map[string]any)
+	if !ok || obj["gain"] != 0.9 {
+		t.Fatalf("callback got %v, want an object reflecting the updated gain", got)
+	}
+}
+
+func TestTenEnv_OnPropertyChangedDoesNotFireForUnrelatedPath(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+
+	called := false
+	engine.TenEnv().OnPropertyChanged("gain", func(any) { called = true })
+
+	if err := engine.UpdateProperty("volume", 1); err != nil {
+		t.Fatalf("UpdateProperty: %v", err)
+	}
+	if called {
+		t.Fatal("callback fired for an unrelated property path")
+	}
+}
+
+func TestTenEnv_OnPropertyChangedSupportsMultipleSubscribersOnSamePath(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+
+	var calls int
+	engine.TenEnv().OnPropertyChanged("gain", func(any) { calls++ })
+	engine.TenEnv().OnPropertyChanged("gain", func(any) { calls++ })
+
+	if err := engine.UpdateProperty("gain", 0.8); err != nil {
+		t.Fatalf("UpdateProperty: %v", err)
+	}
+	if calls != 2 {
+		t.Fatalf("expected both subscribers to fire, got %d calls", calls)
+	}
+}
+
+func TestEngine_UpdatePropertyRecoversFromPanickingCallback(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+
+	secondCalled := false
+	engine.TenEnv().OnPropertyChanged("gain", func(any) { panic("boom") })
+	engine.TenEnv().OnPropertyChanged("gain", func(any) { secondCalled = true })
+
+	if err := engine.UpdateProperty("gain", 0.8); err != nil {
+		t.Fatalf("UpdateProperty: %v", err)
+	}
+	if !secondCalled {
+		t.Fatal("a panicking subscriber prevented a later one from running")
+	}
+}
+
+// configReloadExtension records every OnConfigReload call it receives.
+type configReloadExtension struct {
+	DefaultExtension
+	reloads [][]string
+}
+
+func (c *configReloadExtension) OnConfigReload(tenEnv TenEnv, changed []string) {
+	c.reloads = append(c.reloads, changed)
+}
+
+func TestEngine_ReloadPropertiesCallsOnConfigReloadOnceWithSortedKeys(t *testing.T) {
+	ext := &configReloadExtension{}
+	engine := NewEngine(ext)
+
+	if err := engine.ReloadProperties(map[string]any{
+		"threshold": 0.7,
+		"model":     "gpt-x",
+	}); err != nil {
+		t.Fatalf("ReloadProperties: %v", err)
+	}
+
+	if len(ext.reloads) != 1 {
+		t.Fatalf("OnConfigReload called %d times, want 1", len(ext.reloads))
+	}
+	want := []string{"model", "threshold"}
+	got := ext.reloads[0]
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Fatalf("OnConfigReload changed = %v, want %v", got, want)
+	}
+}
+
+func TestEngine_ReloadPropertiesStillFiresOnPropertyChangedPerKey(t *testing.T) {
+	ext := &configReloadExtension{}
+	engine := NewEngine(ext)
+
+	var gotModel, gotThreshold any
+	engine.TenEnv().OnPropertyChanged("model", func(v any) { gotModel = v })
+	engine.TenEnv().OnPropertyChanged("threshold", func(v any) { gotThreshold = v })
+
+	if err := engine.ReloadProperties(map[string]any{
+		"threshold": 0.7,
+		"model":     "gpt-x",
+	}); err != nil {
+		t.Fatalf("ReloadProperties: %v", err)
+	}
+
+	if gotModel != "gpt-x" || gotThreshold != 0.7 {
+		t.Fatalf("OnPropertyChanged subscribers got (%v, %v), want (gpt-x, 0.7)", gotModel, gotThreshold)
+	}
+}
+
+func TestEngine_ReloadPropertiesEmptyIsANoOp(t *testing.T) {
+	ext := &configReloadExtension{}
+	engine := NewEngine(ext)
+
+	if err := engine.ReloadProperties(nil); err != nil {
+		t.Fatalf("ReloadProperties(nil): %v", err)
+	}
+	if len(ext.reloads) != 0 {
+		t.Fatal("OnConfigReload called for an empty update batch")
+	}
+}
@@ -0,0 +1,497 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"testing"
+)
+
+func TestCmdProperty_MissingReturnsNotFound(t *testing.T) {
+	cmd, _ := NewCmd("greet")
+	if _, err := cmd.GetPropertyString("name"); !errors.Is(err, ErrPropertyNotFound) {
+		t.Fatalf("GetPropertyString(missing) = %v, want ErrPropertyNotFound", err)
+	}
+	if _, err := cmd.GetPropertyInt64("count"); !errors.Is(err, ErrPropertyNotFound) {
+		t.Fatalf("GetPropertyInt64(missing) = %v, want ErrPropertyNotFound", err)
+	}
+}
+
+func TestCmdProperty_IntWidensToFloat64ButStringDoesNot(t *testing.T) {
+	cmd, _ := NewCmd("greet")
+	if err := cmd.SetPropertyInt64("count", 5); err != nil {
+		t.Fatalf("SetPropertyInt64: %v", err)
+	}
+	f, err := cmd.GetPropertyFloat64("count")
+	if err != nil {
+		t.Fatalf("GetPropertyFloat64(int-valued) = %v, want nil", err)
+	}
+	if f != 5 {
+		t.Fatalf("GetPropertyFloat64(count) = %v, want 5", f)
+	}
+
+	if err := cmd.SetPropertyString("digits", "5"); err != nil {
+		t.Fatalf("SetPropertyString: %v", err)
+	}
+	if _, err := cmd.GetPropertyFloat64("digits"); !errors.Is(err, ErrPropertyTypeMismatch) {
+		t.Fatalf(
+			"GetPropertyFloat64(numeric-looking string) = %v, want ErrPropertyTypeMismatch",
+			err,
+		)
+	}
+	if _, err := cmd.GetPropertyInt64("digits"); !errors.Is(err, ErrPropertyTypeMismatch) {
+		t.Fatalf(
+			"GetPropertyInt64(numeric-looking string) = %v, want ErrPropertyTypeMismatch",
+			err,
+		)
+	}
+}
+
+func TestCmdResultProperty_RoundTripsBool(t *testing.T) {
+	cmd, _ := NewCmd("greet")
+	result, _ := NewCmdResult(StatusCodeOk, cmd)
+	if err := result.SetPropertyBool("ok", true); err != nil {
+		t.Fatalf("SetPropertyBool: %v", err)
+	}
+	got, err := result.GetPropertyBool("ok")
+	if err != nil {
+		t.Fatalf("GetPropertyBool: %v", err)
+	}
+	if !got {
+		t.Fatalf("GetPropertyBool(ok) = %v, want true", got)
+	}
+	if _, err := result.GetPropertyString("ok"); !errors.Is(err, ErrPropertyTypeMismatch) {
+		t.Fatalf("GetPropertyString(bool-valued) = %v, want ErrPropertyTypeMismatch", err)
+	}
+}
+
+func TestCmdProperty_DottedPathCreatesIntermediateObjects(t *testing.T) {
+	cmd, _ := NewCmd("configure")
+	if err := cmd.SetPropertyFloat64("vad.threshold", 0.5); err != nil {
+		t.Fatalf("SetPropertyFloat64(vad.threshold): %v", err)
+	}
+	got, err := cmd.GetPropertyFloat64("vad.threshold")
+	if err != nil {
+		t.Fatalf("GetPropertyFloat64(vad.threshold): %v", err)
+	}
+	if got != 0.5 {
+		t.Fatalf("GetPropertyFloat64(vad.threshold) = %v, want 0.5", got)
+	}
+}
+
+func TestCmdProperty_DottedPathMissingIntermediateNamesSegment(t *testing.T) {
+	cmd, _ := NewCmd("configure")
+	_, err := cmd.GetPropertyFloat64("vad.threshold")
+	if !errors.Is(err, ErrPropertyNotFound) {
+		t.Fatalf("GetPropertyFloat64(vad.threshold) = %v, want ErrPropertyNotFound", err)
+	}
+
+	if err := cmd.SetPropertyString("vad", "not-an-object"); err != nil {
+		t.Fatalf("SetPropertyString(vad): %v", err)
+	}
+	_, err = cmd.GetPropertyFloat64("vad.threshold")
+	if !errors.Is(err, ErrPropertyTypeMismatch) {
+		t.Fatalf(
+			"GetPropertyFloat64(vad.threshold) with non-object vad = %v, want ErrPropertyTypeMismatch",
+			err,
+		)
+	}
+}
+
+func TestCmdProperty_ArrayIndexAddressesListElement(t *testing.T) {
+	cmd, _ := NewCmd("configure")
+	impl := cmd.(*cmdImpl)
+	impl.props["stream"] = []any{
+		map[string]any{"codec": "opus"},
+		map[string]any{"codec": "pcm"},
+	}
+
+	codec, err := cmd.GetPropertyString("stream.1.codec")
+	if err != nil {
+		t.Fatalf("GetPropertyString(stream.1.codec): %v", err)
+	}
+	if codec != "pcm" {
+		t.Fatalf("GetPropertyString(stream.1.codec) = %q, want %q", codec, "pcm")
+	}
+
+	if err := cmd.SetPropertyString("stream.0.codec", "aac"); err != nil {
+		t.Fatalf("SetPropertyString(stream.0.codec): %v", err)
+	}
+	codec, _ = cmd.GetPropertyString("stream.0.codec")
+	if codec != "aac" {
+		t.Fatalf("GetPropertyString(stream.0.codec) after set = %q, want %q", codec, "aac")
+	}
+
+	if _, err := cmd.GetPropertyString("stream.5.codec"); !errors.Is(err, ErrPropertyNotFound) {
+		t.Fatalf(
+			"GetPropertyString(stream.5.codec) = %v, want ErrPropertyNotFound", err,
+		)
+	}
+}
+
+func TestCmdProperty_JSONBytesRoundTripSubtree(t *testing.T) {
+	cmd, _ := NewCmd("configure")
+	if err := cmd.SetPropertyFromJSONBytes("vad", []byte(`{"threshold":0.5,"enabled":true}`)); err != nil {
+		t.Fatalf("SetPropertyFromJSONBytes(vad): %v", err)
+	}
+
+	threshold, err := cmd.GetPropertyFloat64("vad.threshold")
+	if err != nil {
+		t.Fatalf("GetPropertyFloat64(vad.threshold): %v", err)
+	}
+	if threshold != 0.5 {
+		t.Fatalf("GetPropertyFloat64(vad.threshold) = %v, want 0.5", threshold)
+	}
+
+	data, err := cmd.GetPropertyToJSONBytes("vad")
+	if err != nil {
+		t.Fatalf("GetPropertyToJSONBytes(vad): %v", err)
+	}
+	var got map[string]any
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("json.Unmarshal(GetPropertyToJSONBytes result): %v", err)
+	}
+	if got["threshold"] != 0.5 || got["enabled"] != true {
+		t.Fatalf("GetPropertyToJSONBytes(vad) round-tripped to %v", got)
+	}
+}
+
+func TestCmdProperty_JSONBytesEmptyPathReplacesWholeBag(t *testing.T) {
+	cmd, _ := NewCmd("configure")
+	if err := cmd.SetPropertyString("stale", "value"); err != nil {
+		t.Fatalf("SetPropertyString: %v", err)
+	}
+	if err := cmd.SetPropertyFromJSONBytes("", []byte(`{"fresh":"value"}`)); err != nil {
+		t.Fatalf("SetPropertyFromJSONBytes(\"\"): %v", err)
+	}
+
+	if _, err := cmd.GetPropertyString("stale"); !errors.Is(err, ErrPropertyNotFound) {
+		t.Fatalf("GetPropertyString(stale) after whole-bag replace = %v, want ErrPropertyNotFound", err)
+	}
+	fresh, err := cmd.GetPropertyString("fresh")
+	if err != nil || fresh != "value" {
+		t.Fatalf("GetPropertyString(fresh) = %q, %v, want %q, nil", fresh, err, "value")
+	}
+}
+
+func TestCmdProperty_JSONBytesPreservesLargeIntPrecision(t *testing.T) {
+	cmd, _ := NewCmd("configure")
+	const snowflakeID = int64(9223372036854775807) // math.MaxInt64, well past 2^53.
+	if err := cmd.SetPropertyFromJSONBytes("id", []byte(strconv.FormatInt(snowflakeID, 10))); err != nil {
+		t.Fatalf("SetPropertyFromJSONBytes(id): %v", err)
+	}
+
+	got, err := cmd.GetPropertyInt64("id")
+	if err != nil {
+		t.Fatalf("GetPropertyInt64(id): %v", err)
+	}
+	if got != snowflakeID {
+		t.Fatalf("GetPropertyInt64(id) = %d, want %d (precision lost)", got, snowflakeID)
+	}
+
+	data, err := cmd.GetPropertyToJSONBytes("id")
+	if err != nil {
+		t.Fatalf("GetPropertyToJSONBytes(id): %v", err)
+	}
+	if string(data) != strconv.FormatInt(snowflakeID, 10) {
+		t.Fatalf("GetPropertyToJSONBytes(id) = %s, want a bare integer literal", data)
+	}
+}
+
+func TestCmdProperty_JSONBytesSmallIntStillWidensToFloat64Getter(t *testing.T) {
+	cmd, _ := NewCmd("configure")
+	if err := cmd.SetPropertyFromJSONBytes("count", []byte(`3`)); err != nil {
+		t.Fatalf("SetPropertyFromJSONBytes(count): %v", err)
+	}
+	got, err := cmd.GetPropertyFloat64("count")
+	if err != nil || got != 3 {
+		t.Fatalf("GetPropertyFloat64(count) = %v, %v, want 3, nil", got, err)
+	}
+}
+
+func TestCmdProperty_WithLargeIntAsStringQuotesOnlyUnsafeIntegers(t *testing.T) {
+	cmd, _ := NewCmd("configure")
+	if err := cmd.SetPropertyInt64("small", 42); err != nil {
+		t.Fatalf("SetPropertyInt64(small): %v", err)
+	}
+	const big = int64(1) << 60
+	if err := cmd.SetPropertyInt64("big", big); err != nil {
+		t.Fatalf("SetPropertyInt64(big): %v", err)
+	}
+
+	data, err := cmd.GetPropertyToJSONBytes("", WithLargeIntAsString())
+	if err != nil {
+		t.Fatalf("GetPropertyToJSONBytes: %v", err)
+	}
+	var got map[string]any
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("json.Unmarshal: %v", err)
+	}
+	if got["small"] != float64(42) {
+		t.Fatalf("small = %v (%T), want bare number 42", got["small"], got["small"])
+	}
+	if got["big"] != strconv.FormatInt(big, 10) {
+		t.Fatalf("big = %v (%T), want quoted string %q", got["big"], got["big"], strconv.FormatInt(big, 10))
+	}
+
+	withoutOpt, err := cmd.GetPropertyToJSONBytes("big")
+	if err != nil {
+		t.Fatalf("GetPropertyToJSONBytes(big): %v", err)
+	}
+	if string(withoutOpt) != strconv.FormatInt(big, 10) {
+		t.Fatalf("GetPropertyToJSONBytes(big) without the option = %s, want a bare integer literal", withoutOpt)
+	}
+}
+
+func TestCmdProperty_JSONBytesEmptyPathRejectsNonObject(t *testing.T) {
+	cmd, _ := NewCmd("configure")
+	if err := cmd.SetPropertyFromJSONBytes("", []byte(`[1,2,3]`)); !errors.Is(err, ErrPropertyTypeMismatch) {
+		t.Fatalf("SetPropertyFromJSONBytes(\"\", array) = %v, want ErrPropertyTypeMismatch", err)
+	}
+}
+
+func TestCmdProperty_PropertyKeysReturnsSortedTopLevelKeys(t *testing.T) {
+	cmd, _ := NewCmd("configure")
+	cmd.SetPropertyString("name", "vad")
+	cmd.SetPropertyBool("enabled", true)
+	cmd.SetPropertyFloat64("threshold", 0.5)
+
+	keys, err := cmd.PropertyKeys()
+	if err != nil {
+		t.Fatalf("PropertyKeys: %v", err)
+	}
+	want := []string{"enabled", "name", "threshold"}
+	if len(keys) != len(want) {
+		t.Fatalf("PropertyKeys() = %v, want %v", keys, want)
+	}
+	for i := range want {
+		if keys[i] != want[i] {
+			t.Fatalf("PropertyKeys() = %v, want %v", keys, want)
+		}
+	}
+}
+
+func TestCmdProperty_PropertyKeysAtReturnsNestedObjectKeys(t *testing.T) {
+	cmd, _ := NewCmd("configure")
+	if err := cmd.SetPropertyFromJSONBytes("vad", []byte(`{"threshold":0.5,"enabled":true}`)); err != nil {
+		t.Fatalf("SetPropertyFromJSONBytes(vad): %v", err)
+	}
+
+	keys, err := cmd.PropertyKeysAt("vad")
+	if err != nil {
+		t.Fatalf("PropertyKeysAt(vad): %v", err)
+	}
+	if len(keys) != 2 || keys[0] != "enabled" || keys[1] != "threshold" {
+		t.Fatalf("PropertyKeysAt(vad) = %v, want [enabled threshold]", keys)
+	}
+
+	if err := cmd.SetPropertyString("digits", "5"); err != nil {
+		t.Fatalf("SetPropertyString: %v", err)
+	}
+	if _, err := cmd.PropertyKeysAt("digits"); !errors.Is(err, ErrPropertyTypeMismatch) {
+		t.Fatalf("PropertyKeysAt(non-object) = %v, want ErrPropertyTypeMismatch", err)
+	}
+}
+
+func TestCmdProperty_HasPropertyReportsExistenceCorrectly(t *testing.T) {
+	cmd, _ := NewCmd("configure")
+	if cmd.HasProperty("name") {
+		t.Fatal("HasProperty(name) = true before it's set, want false")
+	}
+	cmd.SetPropertyString("name", "vad")
+	if !cmd.HasProperty("name") {
+		t.Fatal("HasProperty(name) = false after it's set, want true")
+	}
+}
+
+func TestCmdProperty_DeletePropertyRemovesKey(t *testing.T) {
+	cmd, _ := NewCmd("configure")
+	cmd.SetPropertyString("name", "vad")
+	if err := cmd.DeleteProperty("name"); err != nil {
+		t.Fatalf("DeleteProperty(name): %v", err)
+	}
+	if _, err := cmd.GetPropertyString("name"); !errors.Is(err, ErrPropertyNotFound) {
+		t.Fatalf("GetPropertyString(name) after DeleteProperty = %v, want ErrPropertyNotFound", err)
+	}
+}
+
+func TestCmdProperty_DeletePropertyOnMissingPathIsNotFound(t *testing.T) {
+	cmd, _ := NewCmd("configure")
+	if err := cmd.DeleteProperty("missing"); !errors.Is(err, ErrPropertyNotFound) {
+		t.Fatalf("DeleteProperty(missing) = %v, want ErrPropertyNotFound", err)
+	}
+}
+
+func TestCmdProperty_DeletePropertyRejectsArrayElement(t *testing.T) {
+	cmd, _ := NewCmd("configure")
+	impl := cmd.(*cmdImpl)
+	impl.props["stream"] = []any{
+		map[string]any{"codec": "opus"},
+	}
+	if err := cmd.DeleteProperty("stream.0"); !errors.Is(err, ErrPropertyTypeMismatch) {
+		t.Fatalf("DeleteProperty(array element) = %v, want ErrPropertyTypeMismatch", err)
+	}
+}
+
+func TestCmdResultProperty_KeysHasAndDeleteMirrorCmd(t *testing.T) {
+	cmd, _ := NewCmd("greet")
+	result, _ := NewCmdResult(StatusCodeOk, cmd)
+	result.SetPropertyString("detail", "done")
+	result.SetPropertyBool("ok", true)
+
+	keys, err := result.PropertyKeys()
+	if err != nil {
+		t.Fatalf("PropertyKeys: %v", err)
+	}
+	if len(keys) != 2 || keys[0] != "detail" || keys[1] != "ok" {
+		t.Fatalf("PropertyKeys() = %v, want [detail ok]", keys)
+	}
+
+	if !result.HasProperty("ok") {
+		t.Fatal("HasProperty(ok) = false, want true")
+	}
+	if err := result.DeleteProperty("ok"); err != nil {
+		t.Fatalf("DeleteProperty(ok): %v", err)
+	}
+	if result.HasProperty("ok") {
+		t.Fatal("HasProperty(ok) = true after DeleteProperty, want false")
+	}
+	if err := result.DeleteProperty("ok"); !errors.Is(err, ErrPropertyNotFound) {
+		t.Fatalf("DeleteProperty(ok) already removed = %v, want ErrPropertyNotFound", err)
+	}
+}
+
+func TestCmdProperty_GetPropertyTypeReportsEachKind(t *testing.T) {
+	cmd, _ := NewCmd("configure")
+	cmd.SetPropertyString("name", "vad")
+	cmd.SetPropertyInt64("count", 3)
+	cmd.SetPropertyFloat64("threshold", 0.5)
+	cmd.SetPropertyBool("enabled", true)
+	cmd.SetPropertyBytes("blob", []byte{1, 2, 3})
+	if err := cmd.SetPropertyFromJSONBytes("vad", []byte(`{"mode":"strict"}`)); err != nil {
+		t.Fatalf("SetPropertyFromJSONBytes(vad): %v", err)
+	}
+	if err := cmd.SetPropertyFromJSONBytes("stream", []byte(`[1,2,3]`)); err != nil {
+		t.Fatalf("SetPropertyFromJSONBytes(stream): %v", err)
+	}
+	if err := cmd.SetPropertyFromJSONBytes("nothing", []byte(`null`)); err != nil {
+		t.Fatalf("SetPropertyFromJSONBytes(nothing): %v", err)
+	}
+
+	cases := []struct {
+		path string
+		want PropertyType
+	}{
+		{"name", PropertyTypeString},
+		{"count", PropertyTypeInt64},
+		{"threshold", PropertyTypeFloat64},
+		{"enabled", PropertyTypeBool},
+		{"blob", PropertyTypeBytes},
+		{"vad", PropertyTypeObject},
+		{"stream", PropertyTypeArray},
+		{"nothing", PropertyTypeNull},
+	}
+	for _, c := range cases {
+		got, err := cmd.GetPropertyType(c.path)
+		if err != nil {
+			t.Fatalf("GetPropertyType(%q): %v", c.path, err)
+		}
+		if got != c.want {
+			t.Fatalf("GetPropertyType(%q) = %v, want %v", c.path, got, c.want)
+		}
+	}
+}
+
+func TestCmdProperty_GetPropertyTypeOnMissingPathIsNotFound(t *testing.T) {
+	cmd, _ := NewCmd("configure")
+	if _, err := cmd.GetPropertyType("missing"); !errors.Is(err, ErrPropertyNotFound) {
+		t.Fatalf("GetPropertyType(missing) = %v, want ErrPropertyNotFound", err)
+	}
+}
+
+func TestCmdResultProperty_GetPropertyTypeMirrorsCmd(t *testing.T) {
+	cmd, _ := NewCmd("greet")
+	result, _ := NewCmdResult(StatusCodeOk, cmd)
+	result.SetPropertyString("detail", "done")
+
+	got, err := result.GetPropertyType("detail")
+	if err != nil {
+		t.Fatalf("GetPropertyType(detail): %v", err)
+	}
+	if got != PropertyTypeString {
+		t.Fatalf("GetPropertyType(detail) = %v, want %v", got, PropertyTypeString)
+	}
+}
+
+func TestPropertyType_StringRendersEachKind(t *testing.T) {
+	cases := map[PropertyType]string{
+		PropertyTypeString:  "string",
+		PropertyTypeInt64:   "int64",
+		PropertyTypeFloat64: "float64",
+		PropertyTypeBool:    "bool",
+		PropertyTypeBytes:   "bytes",
+		PropertyTypeObject:  "object",
+		PropertyTypeArray:   "array",
+		PropertyTypeNull:    "null",
+	}
+	for pt, want := range cases {
+		if got := pt.String(); got != want {
+			t.Fatalf("PropertyType(%d).String() = %q, want %q", pt, got, want)
+		}
+	}
+}
+
+func TestCmdProperty_GetPropertyNumberAcceptsIntOrFloatStorage(t *testing.T) {
+	cmd, _ := NewCmd("configure")
+	cmd.SetPropertyInt64("count", 3)
+	cmd.SetPropertyFloat64("ratio", 1.5)
+
+	n, integral, err := cmd.GetPropertyNumber("count")
+	if err != nil {
+		t.Fatalf("GetPropertyNumber(count): %v", err)
+	}
+	if n != 3 || !integral {
+		t.Fatalf("GetPropertyNumber(count) = (%v, %v), want (3, true)", n, integral)
+	}
+
+	n, integral, err = cmd.GetPropertyNumber("ratio")
+	if err != nil {
+		t.Fatalf("GetPropertyNumber(ratio): %v", err)
+	}
+	if n != 1.5 || integral {
+		t.Fatalf("GetPropertyNumber(ratio) = (%v, %v), want (1.5, false)", n, integral)
+	}
+}
+
+func TestCmdProperty_GetPropertyNumberRejectsNonNumericStorage(t *testing.T) {
+	cmd, _ := NewCmd("configure")
+	cmd.SetPropertyString("name", "hi")
+	if _, _, err := cmd.GetPropertyNumber("name"); !errors.Is(err, ErrPropertyTypeMismatch) {
+		t.Fatalf("GetPropertyNumber(name) = %v, want ErrPropertyTypeMismatch", err)
+	}
+}
+
+func TestCmdProperty_GetPropertyNumberOnMissingPathIsNotFound(t *testing.T) {
+	cmd, _ := NewCmd("configure")
+	if _, _, err := cmd.GetPropertyNumber("missing"); !errors.Is(err, ErrPropertyNotFound) {
+		t.Fatalf("GetPropertyNumber(missing) = %v, want ErrPropertyNotFound", err)
+	}
+}
+
+func TestCmdResultProperty_GetPropertyNumberMirrorsCmd(t *testing.T) {
+	cmd, _ := NewCmd("greet")
+	result, _ := NewCmdResult(StatusCodeOk, cmd)
+	result.SetPropertyInt64("count", 7)
+
+	n, integral, err := result.GetPropertyNumber("count")
+	if err != nil {
+		t.Fatalf("GetPropertyNumber(count): %v", err)
+	}
+	if n != 7 || !integral {
+		t.Fatalf("GetPropertyNumber(count) = (%v, %v), want (7, true)", n, integral)
+	}
+}
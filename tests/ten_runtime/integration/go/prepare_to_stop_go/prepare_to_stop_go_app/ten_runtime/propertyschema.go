@@ -0,0 +1,193 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrPropertySchemaViolation is returned when an extension's supplied
+// properties (see WithProperties) don't satisfy the schema it registered
+// via TenEnv.SetPropertySchema.
+var ErrPropertySchemaViolation = errors.New("ten: property schema violation")
+
+// propertySchemaField describes one property's expected type, whether its
+// absence is an error, and its default value (if any), in a schema
+// registered via TenEnv.SetPropertySchema. Default is left as raw JSON
+// rather than decoded eagerly, so a schema with no "default" key can be
+// told apart from one explicitly defaulting to a zero value like 0 or "".
+type propertySchemaField struct {
+	Type     string          `json:"type"`
+	Required bool            `json:"required"`
+	Default  json.RawMessage `json:"default"`
+}
+
+// hasDefault reports whether this field's schema declared a "default".
+func (f propertySchemaField) hasDefault() bool {
+	return len(f.Default) > 0
+}
+
+// defaultValue decodes this field's declared default. It's only valid to
+// call when hasDefault reports true; parsePropertySchema has already
+// checked it decodes cleanly and matches the field's declared type.
+func (f propertySchemaField) defaultValue() any {
+	var v any
+	_ = json.Unmarshal(f.Default, &v)
+	return v
+}
+
+// propertySchema is the parsed form of the JSON an extension passes to
+// TenEnv.SetPropertySchema: an object whose "properties" field maps each
+// expected property name to its schema field, the same shape a real
+// ten_runtime manifest's property schema uses.
+type propertySchema struct {
+	Properties map[string]propertySchemaField `json:"properties"`
+}
+
+// parsePropertySchema parses schemaJSON into a propertySchema, and
+// type-checks every declared default against its own field's type at
+// registration time -- a default that wouldn't itself pass
+// validatePropertySchema is a schema author's mistake, not something that
+// should surface later as a mysterious property-schema violation once a
+// graph happens to omit that property.
+func parsePropertySchema(schemaJSON []byte) (*propertySchema, error) {
+	var schema propertySchema
+	if err := json.Unmarshal(schemaJSON, &schema); err != nil {
+		return nil, fmt.Errorf("ten: invalid property schema: %w", err)
+	}
+	for name, field := range schema.Properties {
+		if !field.hasDefault() {
+			continue
+		}
+		var v any
+		if err := json.Unmarshal(field.Default, &v); err != nil {
+			return nil, fmt.Errorf(
+				"ten: invalid default for property %q: %w", name, err,
+			)
+		}
+		if !propertyMatchesSchemaType(v, field.Type) {
+			return nil, fmt.Errorf(
+				"%w: default for property %q is %T, want type %q",
+				ErrPropertySchemaViolation, name, v, field.Type,
+			)
+		}
+	}
+	return &schema, nil
+}
+
+// applyPropertySchemaDefaults fills in schema-declared defaults for every
+// property schema names but props doesn't already hold, so a graph that
+// omits an optional property still sees it via GetPropertyString/
+// GetPropertyFloat64/etc. from OnInit onward. A nil schema is a no-op.
+// Called before validatePropertySchema, so a property that's Required but
+// has a default is satisfied by the default rather than rejected as
+// missing.
+func applyPropertySchemaDefaults(schema *propertySchema, props map[string]any) map[string]any {
+	if schema == nil {
+		return props
+	}
+	for name, field := range schema.Properties {
+		if !field.hasDefault() {
+			continue
+		}
+		if _, exists := props[name]; exists {
+			continue
+		}
+		if props == nil {
+			props = map[string]any{}
+		}
+		props[name] = field.defaultValue()
+	}
+	return props
+}
+
+// validatePropertySchema checks props against schema, returning
+// ErrPropertySchemaViolation naming the first field found missing (if
+// required) or holding a value of the wrong type. A nil schema always
+// passes, since SetPropertySchema was never called.
+func validatePropertySchema(schema *propertySchema, props map[string]any) error {
+	if schema == nil {
+		return nil
+	}
+	for name, field := range schema.Properties {
+		v, ok := props[name]
+		if !ok {
+			if field.Required {
+				return fmt.Errorf(
+					"%w: required property %q is missing", ErrPropertySchemaViolation, name,
+				)
+			}
+			continue
+		}
+		if !propertyMatchesSchemaType(v, field.Type) {
+			return fmt.Errorf(
+				"%w: property %q is %T, want type %q",
+				ErrPropertySchemaViolation, name, v, field.Type,
+			)
+		}
+	}
+	return nil
+}
+
+// mergePropertyDefaults merges defaults into props, key by key, without
+// overwriting anything props already holds: a key missing from props is
+// copied in as-is, a key present in both that's a nested object in both
+// merges recursively, and any other key present in both -- including one
+// holding an array -- is left as props already has it. See
+// TenEnv.InitPropertyFromJSON's doc comment for the rationale.
+func mergePropertyDefaults(props, defaults map[string]any) {
+	for k, defVal := range defaults {
+		curVal, exists := props[k]
+		if !exists {
+			props[k] = deepCopyValue(defVal)
+			continue
+		}
+		curMap, curIsMap := curVal.(map[string]any)
+		defMap, defIsMap := defVal.(map[string]any)
+		if curIsMap && defIsMap {
+			mergePropertyDefaults(curMap, defMap)
+		}
+	}
+}
+
+// propertyMatchesSchemaType reports whether v satisfies wantType, using the
+// same int-widens-to-float64 rule the typed property getters use. An
+// unrecognized wantType always passes -- a schema typo shouldn't itself
+// become a startup failure the extension author can't diagnose from the
+// property that's actually wrong.
+func propertyMatchesSchemaType(v any, wantType string) bool {
+	switch wantType {
+	case "string":
+		_, ok := v.(string)
+		return ok
+	case "bool":
+		_, ok := v.(bool)
+		return ok
+	case "int64":
+		switch v.(type) {
+		case int64, int:
+			return true
+		default:
+			return false
+		}
+	case "float64":
+		switch v.(type) {
+		case float64, int64, int:
+			return true
+		default:
+			return false
+		}
+	case "object":
+		_, ok := v.(map[string]any)
+		return ok
+	case "array":
+		_, ok := v.([]any)
+		return ok
+	default:
+		return true
+	}
+}
@@ -0,0 +1,235 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"errors"
+	"testing"
+)
+
+// schemaExtension registers a fixed property schema from OnConfigure, the
+// way an extension declaring its expected properties would.
+type schemaExtension struct {
+	DefaultExtension
+
+	schemaJSON []byte
+	onInitRan  bool
+}
+
+func (s *schemaExtension) OnConfigure(tenEnv TenEnv) {
+	if err := tenEnv.SetPropertySchema(s.schemaJSON); err != nil {
+		panic(err)
+	}
+	tenEnv.OnConfigureDone()
+}
+
+func (s *schemaExtension) OnInit(tenEnv TenEnv) {
+	s.onInitRan = true
+	tenEnv.OnInitDone()
+}
+
+var sampleRateSchema = []byte(`{
+	"properties": {
+		"sample_rate": {"type": "int64", "required": true},
+		"codec": {"type": "string"}
+	}
+}`)
+
+func TestEngine_InitPassesWhenPropertiesSatisfySchema(t *testing.T) {
+	ext := &schemaExtension{schemaJSON: sampleRateSchema}
+	engine := NewEngine(ext, WithProperties(map[string]any{
+		"sample_rate": int64(16000),
+		"codec":       "opus",
+	}))
+
+	if err := engine.Init(); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	if !ext.onInitRan {
+		t.Fatal("OnInit did not run after a successful schema validation")
+	}
+}
+
+func TestEngine_InitFailsWhenRequiredPropertyIsMissing(t *testing.T) {
+	ext := &schemaExtension{schemaJSON: sampleRateSchema}
+	engine := NewEngine(ext, WithProperties(map[string]any{
+		"codec": "opus",
+	}))
+
+	err := engine.Init()
+	if !errors.Is(err, ErrPropertySchemaViolation) {
+		t.Fatalf("Init() = %v, want ErrPropertySchemaViolation", err)
+	}
+	if ext.onInitRan {
+		t.Fatal("OnInit ran despite a failed schema validation")
+	}
+}
+
+func TestEngine_InitFailsWhenPropertyTypeMismatches(t *testing.T) {
+	ext := &schemaExtension{schemaJSON: sampleRateSchema}
+	engine := NewEngine(ext, WithProperties(map[string]any{
+		"sample_rate": "not-a-number",
+	}))
+
+	err := engine.Init()
+	if !errors.Is(err, ErrPropertySchemaViolation) {
+		t.Fatalf("Init() = %v, want ErrPropertySchemaViolation", err)
+	}
+	if ext.onInitRan {
+		t.Fatal("OnInit ran despite a failed schema validation")
+	}
+}
+
+func TestEngine_InitPassesWithoutAnySchemaRegistered(t *testing.T) {
+	engine := NewEngine(DefaultExtension{}, WithProperties(map[string]any{
+		"anything": "goes",
+	}))
+	if err := engine.Init(); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+}
+
+func TestTenEnv_SetPropertySchemaRejectsInvalidJSON(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+	if err := engine.TenEnv().SetPropertySchema([]byte("not json")); err == nil {
+		t.Fatal("SetPropertySchema(invalid JSON) = nil error, want an error")
+	}
+}
+
+func TestTenEnv_InitPropertyFromJSONFillsInMissingKeysOnly(t *testing.T) {
+	engine := NewEngine(DefaultExtension{}, WithProperties(map[string]any{
+		"sample_rate": int64(48000),
+	}))
+	tenEnv := engine.TenEnv()
+
+	if err := tenEnv.InitPropertyFromJSON([]byte(`{"sample_rate":16000,"codec":"opus"}`)); err != nil {
+		t.Fatalf("InitPropertyFromJSON: %v", err)
+	}
+
+	engine.mu.Lock()
+	rate := engine.properties["sample_rate"]
+	codec := engine.properties["codec"]
+	engine.mu.Unlock()
+	if rate != int64(48000) {
+		t.Fatalf("sample_rate = %v, want graph-supplied 48000 to survive", rate)
+	}
+	if codec != "opus" {
+		t.Fatalf("codec = %v, want default %q to fill in the missing key", codec, "opus")
+	}
+}
+
+func TestTenEnv_InitPropertyFromJSONMergesNestedObjectsRecursively(t *testing.T) {
+	engine := NewEngine(DefaultExtension{}, WithProperties(map[string]any{
+		"vad": map[string]any{"threshold": 0.9},
+	}))
+	tenEnv := engine.TenEnv()
+
+	if err := tenEnv.InitPropertyFromJSON([]byte(`{"vad":{"threshold":0.5,"enabled":true}}`)); err != nil {
+		t.Fatalf("InitPropertyFromJSON: %v", err)
+	}
+
+	engine.mu.Lock()
+	vad := engine.properties["vad"].(map[string]any)
+	engine.mu.Unlock()
+	if vad["threshold"] != 0.9 {
+		t.Fatalf("vad.threshold = %v, want graph-supplied 0.9 to survive", vad["threshold"])
+	}
+	if vad["enabled"] != true {
+		t.Fatalf("vad.enabled = %v, want default true to fill in the missing key", vad["enabled"])
+	}
+}
+
+func TestTenEnv_InitPropertyFromJSONReplacesArraysWholesaleNotElementwise(t *testing.T) {
+	engine := NewEngine(DefaultExtension{}, WithProperties(map[string]any{
+		"tags": []any{"custom"},
+	}))
+	tenEnv := engine.TenEnv()
+
+	if err := tenEnv.InitPropertyFromJSON([]byte(`{"tags":["default1","default2"]}`)); err != nil {
+		t.Fatalf("InitPropertyFromJSON: %v", err)
+	}
+
+	engine.mu.Lock()
+	tags := engine.properties["tags"].([]any)
+	engine.mu.Unlock()
+	if len(tags) != 1 || tags[0] != "custom" {
+		t.Fatalf("tags = %v, want the graph-supplied array left untouched, [custom]", tags)
+	}
+}
+
+var thresholdSchemaWithDefault = []byte(`{
+	"properties": {
+		"threshold": {"type": "float64", "default": 0.5},
+		"codec": {"type": "string", "required": true, "default": "opus"}
+	}
+}`)
+
+func TestTenEnv_SetPropertySchemaRejectsDefaultOfWrongType(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+	badSchema := []byte(`{"properties": {"threshold": {"type": "float64", "default": "not-a-number"}}}`)
+	if err := engine.TenEnv().SetPropertySchema(badSchema); !errors.Is(err, ErrPropertySchemaViolation) {
+		t.Fatalf("SetPropertySchema(bad default) = %v, want ErrPropertySchemaViolation", err)
+	}
+}
+
+func TestEngine_InitFillsInSchemaDefaultForMissingProperty(t *testing.T) {
+	ext := &schemaExtension{schemaJSON: thresholdSchemaWithDefault}
+	engine := NewEngine(ext, WithProperties(map[string]any{}))
+
+	if err := engine.Init(); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+
+	threshold, err := engine.TenEnv().GetPropertyFloat64("threshold")
+	if err != nil || threshold != 0.5 {
+		t.Fatalf("GetPropertyFloat64(threshold) = %v, %v, want 0.5, nil", threshold, err)
+	}
+	codec, err := engine.TenEnv().GetPropertyString("codec")
+	if err != nil || codec != "opus" {
+		t.Fatalf("GetPropertyString(codec) = %v, %v, want %q, nil", codec, err, "opus")
+	}
+}
+
+func TestEngine_InitDefaultSatisfiesRequiredProperty(t *testing.T) {
+	ext := &schemaExtension{schemaJSON: thresholdSchemaWithDefault}
+	engine := NewEngine(ext, WithProperties(nil))
+
+	if err := engine.Init(); err != nil {
+		t.Fatalf("Init() with a Required property backed only by a default = %v, want nil", err)
+	}
+	if !ext.onInitRan {
+		t.Fatal("OnInit did not run when the Required property was satisfied by its default")
+	}
+}
+
+func TestEngine_InitLeavesGraphSuppliedValueOverDefault(t *testing.T) {
+	ext := &schemaExtension{schemaJSON: thresholdSchemaWithDefault}
+	engine := NewEngine(ext, WithProperties(map[string]any{
+		"threshold": 0.9,
+		"codec":     "aac",
+	}))
+
+	if err := engine.Init(); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	threshold, _ := engine.TenEnv().GetPropertyFloat64("threshold")
+	if threshold != 0.9 {
+		t.Fatalf("GetPropertyFloat64(threshold) = %v, want graph-supplied 0.9 to win over the default", threshold)
+	}
+}
+
+func TestTenEnv_GetPropertyStringNotFoundWithoutSchemaOrValue(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+	if _, err := engine.TenEnv().GetPropertyString("missing"); !errors.Is(err, ErrPropertyNotFound) {
+		t.Fatalf("GetPropertyString(missing) = %v, want ErrPropertyNotFound", err)
+	}
+}
+
+func TestTenEnv_InitPropertyFromJSONRejectsNonObjectJSON(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+	if err := engine.TenEnv().InitPropertyFromJSON([]byte(`[1,2,3]`)); err == nil {
+		t.Fatal("InitPropertyFromJSON(array) = nil error, want an error")
+	}
+}
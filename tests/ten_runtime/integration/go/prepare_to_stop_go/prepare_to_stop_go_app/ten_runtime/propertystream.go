@@ -0,0 +1,135 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"errors"
+	"io"
+)
+
+// DefaultPropertyChunkSize is how much of a payload PropertyWriter/
+// PropertyReader move per underlying copy when no PropertyStreamOption
+// overrides it.
+const DefaultPropertyChunkSize = 64 * 1024
+
+// ErrPropertyStreamClosed is returned by a PropertyWriter's Write or
+// Close, or a PropertyReader's Read, once it has already been closed.
+var ErrPropertyStreamClosed = errors.New("ten: property stream already closed")
+
+// PropertyStreamOption configures a PropertyWriter or PropertyReader,
+// following the same functional-options convention as ReassemblerOption.
+type PropertyStreamOption func(*propertyStreamConfig)
+
+type propertyStreamConfig struct {
+	chunkSize int
+}
+
+// WithPropertyChunkSize overrides DefaultPropertyChunkSize.
+func WithPropertyChunkSize(n int) PropertyStreamOption {
+	return func(c *propertyStreamConfig) { c.chunkSize = n }
+}
+
+func resolvePropertyStreamConfig(opts []PropertyStreamOption) propertyStreamConfig {
+	cfg := propertyStreamConfig{chunkSize: DefaultPropertyChunkSize}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
+
+// propertyWriter backs Cmd/CmdResult.PropertyWriter: it stages every
+// Write into buf, at most chunkSize bytes at a time even when handed one
+// large slice, and only calls set -- committing buf as the property's one
+// and only value -- when Close is called, so the property itself never
+// observes a partial write.
+type propertyWriter struct {
+	set       func(path string, b []byte) error
+	path      string
+	chunkSize int
+	buf       []byte
+	closed    bool
+}
+
+func newPropertyWriter(set func(path string, b []byte) error, path string, opts []PropertyStreamOption) *propertyWriter {
+	cfg := resolvePropertyStreamConfig(opts)
+	return &propertyWriter{set: set, path: path, chunkSize: cfg.chunkSize}
+}
+
+// Write implements io.Writer, staging p into w.buf in pieces no larger
+// than w.chunkSize regardless of len(p), so a caller handing PropertyWriter
+// its entire multi-megabyte payload in one Write still only ever grows the
+// staging buffer a chunk at a time.
+func (w *propertyWriter) Write(p []byte) (int, error) {
+	if w.closed {
+		return 0, ErrPropertyStreamClosed
+	}
+	total := 0
+	for len(p) > 0 {
+		n := len(p)
+		if w.chunkSize > 0 && n > w.chunkSize {
+			n = w.chunkSize
+		}
+		w.buf = append(w.buf, p[:n]...)
+		p = p[n:]
+		total += n
+	}
+	return total, nil
+}
+
+// Close commits w's staged bytes to its property in a single call,
+// atomically replacing whatever was there before, and marks w closed.
+// Calling it more than once returns ErrPropertyStreamClosed.
+func (w *propertyWriter) Close() error {
+	if w.closed {
+		return ErrPropertyStreamClosed
+	}
+	w.closed = true
+	return w.set(w.path, w.buf)
+}
+
+// propertyReader backs Cmd/CmdResult.PropertyReader: it reads path's
+// current bytes property once, up front, then hands it back through Read
+// in pieces no larger than chunkSize, regardless of how large a buffer the
+// caller's io.Copy passes in.
+type propertyReader struct {
+	data      []byte
+	pos       int
+	chunkSize int
+	closed    bool
+}
+
+func newPropertyReader(get func(path string) ([]byte, error), path string, opts []PropertyStreamOption) (*propertyReader, error) {
+	data, err := get(path)
+	if err != nil {
+		return nil, err
+	}
+	cfg := resolvePropertyStreamConfig(opts)
+	return &propertyReader{data: data, chunkSize: cfg.chunkSize}, nil
+}
+
+// Read implements io.Reader, copying at most min(len(p), chunkSize) bytes
+// per call so a downstream io.Copy never pulls more than chunkSize out of
+// the property in one go, however large its own buffer is.
+func (r *propertyReader) Read(p []byte) (int, error) {
+	if r.closed {
+		return 0, ErrPropertyStreamClosed
+	}
+	if r.pos >= len(r.data) {
+		return 0, io.EOF
+	}
+	n := len(p)
+	if r.chunkSize > 0 && n > r.chunkSize {
+		n = r.chunkSize
+	}
+	n = copy(p[:n], r.data[r.pos:])
+	r.pos += n
+	return n, nil
+}
+
+// Close marks r closed; subsequent Read calls return ErrPropertyStreamClosed.
+func (r *propertyReader) Close() error {
+	r.closed = true
+	return nil
+}
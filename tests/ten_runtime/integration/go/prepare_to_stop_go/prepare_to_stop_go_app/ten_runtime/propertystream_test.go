@@ -0,0 +1,152 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestCmdProperty_PropertyWriterRoundTripsThroughPropertyReader(t *testing.T) {
+	cmd, _ := NewCmd("upload")
+	payload := bytes.Repeat([]byte("payload-chunk-"), 10000)
+
+	w, err := cmd.PropertyWriter("blob")
+	if err != nil {
+		t.Fatalf("PropertyWriter: %v", err)
+	}
+	if _, err := io.Copy(w, bytes.NewReader(payload)); err != nil {
+		t.Fatalf("io.Copy into PropertyWriter: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	r, err := cmd.PropertyReader("blob")
+	if err != nil {
+		t.Fatalf("PropertyReader: %v", err)
+	}
+	defer r.Close()
+
+	var out bytes.Buffer
+	if _, err := io.Copy(&out, r); err != nil {
+		t.Fatalf("io.Copy from PropertyReader: %v", err)
+	}
+	if !bytes.Equal(out.Bytes(), payload) {
+		t.Fatalf("round-tripped %d bytes, want %d matching payload", out.Len(), len(payload))
+	}
+}
+
+func TestCmdProperty_PropertyWriterDoesNotSetThePropertyUntilClose(t *testing.T) {
+	cmd, _ := NewCmd("upload")
+	w, err := cmd.PropertyWriter("blob")
+	if err != nil {
+		t.Fatalf("PropertyWriter: %v", err)
+	}
+	if _, err := w.Write([]byte("partial")); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	if cmd.HasProperty("blob") {
+		t.Fatal("HasProperty(blob) = true before Close, want false")
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if !cmd.HasProperty("blob") {
+		t.Fatal("HasProperty(blob) = false after Close, want true")
+	}
+}
+
+func TestCmdProperty_PropertyWriterReadWriteAfterCloseIsClosedError(t *testing.T) {
+	cmd, _ := NewCmd("upload")
+	w, _ := cmd.PropertyWriter("blob")
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	if _, err := w.Write([]byte("late")); !errors.Is(err, ErrPropertyStreamClosed) {
+		t.Fatalf("Write after Close = %v, want ErrPropertyStreamClosed", err)
+	}
+	if err := w.Close(); !errors.Is(err, ErrPropertyStreamClosed) {
+		t.Fatalf("second Close = %v, want ErrPropertyStreamClosed", err)
+	}
+}
+
+func TestCmdProperty_PropertyReaderOnMissingPathIsNotFound(t *testing.T) {
+	cmd, _ := NewCmd("upload")
+	if _, err := cmd.PropertyReader("missing"); !errors.Is(err, ErrPropertyNotFound) {
+		t.Fatalf("PropertyReader(missing) = %v, want ErrPropertyNotFound", err)
+	}
+}
+
+func TestCmdProperty_PropertyReaderAfterCloseIsClosedError(t *testing.T) {
+	cmd, _ := NewCmd("upload")
+	cmd.SetPropertyBytes("blob", []byte("hello"))
+
+	r, err := cmd.PropertyReader("blob")
+	if err != nil {
+		t.Fatalf("PropertyReader: %v", err)
+	}
+	if err := r.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if _, err := r.Read(make([]byte, 4)); !errors.Is(err, ErrPropertyStreamClosed) {
+		t.Fatalf("Read after Close = %v, want ErrPropertyStreamClosed", err)
+	}
+}
+
+func TestCmdProperty_PropertyReaderNeverReturnsMoreThanChunkSizePerRead(t *testing.T) {
+	cmd, _ := NewCmd("upload")
+	cmd.SetPropertyBytes("blob", bytes.Repeat([]byte("x"), 100))
+
+	r, err := cmd.PropertyReader("blob", WithPropertyChunkSize(10))
+	if err != nil {
+		t.Fatalf("PropertyReader: %v", err)
+	}
+	defer r.Close()
+
+	buf := make([]byte, 64)
+	n, err := r.Read(buf)
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if n != 10 {
+		t.Fatalf("Read returned %d bytes, want the configured chunk size of 10", n)
+	}
+}
+
+func TestCmdResultProperty_PropertyWriterAndReaderMirrorCmd(t *testing.T) {
+	cmd, _ := NewCmd("upload")
+	result, _ := NewCmdResult(StatusCodeOk, cmd)
+
+	w, err := result.PropertyWriter("blob")
+	if err != nil {
+		t.Fatalf("PropertyWriter: %v", err)
+	}
+	if _, err := w.Write([]byte("result payload")); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	r, err := result.PropertyReader("blob")
+	if err != nil {
+		t.Fatalf("PropertyReader: %v", err)
+	}
+	defer r.Close()
+
+	got, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+	if string(got) != "result payload" {
+		t.Fatalf("got %q, want %q", got, "result payload")
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import "fmt"
+
+// ProtoMessage is satisfied by generated protobuf message types. It mirrors
+// the minimal subset of google.golang.org/protobuf/proto.Message that
+// SetProtoBuf/GetProtoBuf need -- a stable type name plus wire
+// marshal/unmarshal -- rather than importing the real proto package: this
+// module intentionally carries zero external dependencies (see go.mod), and
+// pulling one in here would make every other package in this tree
+// unbuildable in an offline checkout for the sake of one feature. A
+// generated message wired up to marshal itself (as most protoc-gen-go
+// output already does under the hood) satisfies this interface as-is; if
+// this module ever does take on the real dependency, swapping the
+// parameter type for proto.Message is a signature-compatible change.
+type ProtoMessage interface {
+	// ProtoMessageName returns the message's fully-qualified protobuf type
+	// name (e.g. "myapp.v1.SensorReading"), used to build the type-URL
+	// SetProtoBuf stores and GetProtoBuf validates against.
+	ProtoMessageName() string
+	Marshal() ([]byte, error)
+	Unmarshal([]byte) error
+}
+
+// protoTypeURL builds the type-URL SetProtoBuf stores alongside the
+// serialized payload, following the same "type.googleapis.com/<full name>"
+// convention real protobuf Any values use, so a payload produced here reads
+// the same way once this module can depend on the real proto package.
+func protoTypeURL(m ProtoMessage) string {
+	return "type.googleapis.com/" + m.ProtoMessageName()
+}
+
+// SetProtoBuf marshals m and stores it as the Data's payload, alongside a
+// type-URL property recording m's message type for GetProtoBuf to validate
+// against. It replaces the "hand-serialize into the buffer and track the
+// type out-of-band" boilerplate directly on Data, and fails with
+// ErrDataBufLocked under the same LockBuf rules SetBuf does.
+func (d *dataImpl) SetProtoBuf(m ProtoMessage) error {
+	buf, err := m.Marshal()
+	if err != nil {
+		return fmt.Errorf("ten: marshal proto message %s: %w", m.ProtoMessageName(), err)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.locked {
+		return ErrDataBufLocked
+	}
+	if err := reserveBufferPoolDelta(len(buf) - len(d.buf)); err != nil {
+		return err
+	}
+	d.buf = buf
+	d.protoTypeURL = protoTypeURL(m)
+	return nil
+}
+
+// GetProtoBuf unmarshals the Data's payload into m, first verifying that
+// the payload was stored by SetProtoBuf for m's exact message type. A
+// mismatch (including a payload that was never set via SetProtoBuf at all)
+// returns a descriptive error instead of feeding the wrong bytes to m's
+// Unmarshal.
+func (d *dataImpl) GetProtoBuf(m ProtoMessage) error {
+	d.mu.Lock()
+	if d.locked {
+		d.mu.Unlock()
+		return ErrDataBufLocked
+	}
+	wantTypeURL := protoTypeURL(m)
+	if d.protoTypeURL == "" {
+		d.mu.Unlock()
+		return fmt.Errorf("ten: data has no protobuf payload set via SetProtoBuf (want %s)", wantTypeURL)
+	}
+	if d.protoTypeURL != wantTypeURL {
+		d.mu.Unlock()
+		return fmt.Errorf("ten: data holds a %s payload, cannot decode into %s", d.protoTypeURL, wantTypeURL)
+	}
+	buf := make([]byte, len(d.buf))
+	copy(buf, d.buf)
+	d.mu.Unlock()
+
+	if err := m.Unmarshal(buf); err != nil {
+		return fmt.Errorf("ten: unmarshal proto message %s: %w", m.ProtoMessageName(), err)
+	}
+	return nil
+}
@@ -0,0 +1,203 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"sync"
+	"time"
+)
+
+// RateLimitMode chooses what SetRateLimit does with a cmd that arrives
+// with no tokens left in its bucket.
+type RateLimitMode int
+
+const (
+	// RateLimitModeReject answers an over-limit cmd immediately with a
+	// StatusCodeError result whose detail is "rate limited", the same as
+	// if the extension itself had rejected it. This is the default.
+	RateLimitModeReject RateLimitMode = iota
+
+	// RateLimitModeQueue holds an over-limit cmd until a token frees up,
+	// instead of rejecting it outright, up to WithRateLimitQueueDepth
+	// cmds deep; once that depth is reached, further over-limit cmds are
+	// rejected exactly like RateLimitModeReject.
+	RateLimitModeQueue
+)
+
+// RateLimitOption configures SetRateLimit beyond the required perSecond
+// and burst.
+type RateLimitOption func(*rateLimiter)
+
+// WithRateLimitMode chooses the RateLimitMode SetRateLimit enforces; the
+// default, if this option isn't given, is RateLimitModeReject.
+func WithRateLimitMode(mode RateLimitMode) RateLimitOption {
+	return func(l *rateLimiter) { l.mode = mode }
+}
+
+// WithRateLimitQueueDepth bounds how many over-limit cmds
+// RateLimitModeQueue holds at once; it has no effect in
+// RateLimitModeReject. The default depth is 0, i.e. nothing is queued
+// unless this is set alongside WithRateLimitMode(RateLimitModeQueue).
+func WithRateLimitQueueDepth(depth int) RateLimitOption {
+	return func(l *rateLimiter) { l.queueDepth = depth }
+}
+
+// rateLimiter is a token-bucket limiter for one cmd name: it starts with
+// burst tokens and refills at perSecond tokens per second, never holding
+// more than burst at once.
+type rateLimiter struct {
+	mu        sync.Mutex
+	perSecond int
+	burst     int
+	tokens    float64
+	last      time.Time
+
+	mode       RateLimitMode
+	queueDepth int
+	queued     int
+}
+
+// newRateLimiter builds a limiter starting at full burst -- an extension
+// that's been idle should be able to absorb a burst immediately, not have
+// to wait for tokens to accumulate from zero. perSecond and burst below 0
+// are clamped to 0, which permits nothing: every cmd is over-limit.
+func newRateLimiter(perSecond, burst int, opts ...RateLimitOption) *rateLimiter {
+	if perSecond < 0 {
+		perSecond = 0
+	}
+	if burst < 0 {
+		burst = 0
+	}
+	l := &rateLimiter{
+		perSecond: perSecond,
+		burst:     burst,
+		tokens:    float64(burst),
+		last:      time.Now(),
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
+}
+
+// refillLocked tops up tokens based on time elapsed since the last call,
+// capped at burst. Callers must hold l.mu.
+func (l *rateLimiter) refillLocked() {
+	now := time.Now()
+	elapsed := now.Sub(l.last).Seconds()
+	l.last = now
+	l.tokens += elapsed * float64(l.perSecond)
+	if l.tokens > float64(l.burst) {
+		l.tokens = float64(l.burst)
+	}
+}
+
+// take reports whether a token is available right now, consuming one if
+// so.
+func (l *rateLimiter) take() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.refillLocked()
+	if l.tokens < 1 {
+		return false
+	}
+	l.tokens--
+	return true
+}
+
+// waitForToken returns how long until at least one token will be
+// available, assuming nothing else takes one first. A perSecond of 0
+// never refills, so it returns 0 -- there's nothing productive to wait
+// for -- letting the caller fail fast instead of blocking forever.
+func (l *rateLimiter) waitForToken() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.refillLocked()
+	if l.tokens >= 1 || l.perSecond <= 0 {
+		return 0
+	}
+	missing := 1 - l.tokens
+	return time.Duration(missing / float64(l.perSecond) * float64(time.Second))
+}
+
+// refillable reports whether tokens will ever become available again on
+// their own. A perSecond of 0 never refills, so queuing a cmd against
+// such a limiter would hold it forever; RateLimitModeQueue falls back to
+// rejecting outright in that case rather than leaking a goroutine that
+// waits for a token that's never coming.
+func (l *rateLimiter) refillable() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.perSecond > 0
+}
+
+func (l *rateLimiter) reserveQueueSlot() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.queued >= l.queueDepth {
+		return false
+	}
+	l.queued++
+	return true
+}
+
+func (l *rateLimiter) releaseQueueSlot() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.queued--
+}
+
+// admitThroughRateLimit enforces l against cmd, returning true if the
+// caller should dispatch cmd to the extension right now. If it returns
+// false, cmd has already been fully handled: either queued for delayed
+// dispatch (RateLimitModeQueue, with room in the queue) or answered with
+// a "rate limited" StatusCodeError result (RateLimitModeReject, or
+// RateLimitModeQueue with no room left).
+func (e *Engine) admitThroughRateLimit(l *rateLimiter, cmd Cmd) bool {
+	if l.take() {
+		return true
+	}
+	if l.mode == RateLimitModeQueue && l.refillable() && l.reserveQueueSlot() {
+		go func() {
+			// A single wait doesn't guarantee a token: another queued cmd
+			// (or a fresh caller taking one straight from the bucket) can
+			// win the race for the token this wait was computed for, so
+			// take() must be re-checked, and waited on again if it still
+			// fails, instead of assuming one wait implies one token.
+			for !l.take() {
+				if wait := l.waitForToken(); wait > 0 {
+					time.Sleep(wait)
+				}
+			}
+			l.releaseQueueSlot()
+			e.dispatchOnCmd(cmd)
+		}()
+		return false
+	}
+	e.tenEnv.ReturnError(cmd, "rate limited")
+	return false
+}
+
+// rateLimiterFor looks up the limiter SetRateLimit registered for cmd's
+// name, if any.
+func (e *Engine) rateLimiterFor(cmd Cmd) (*rateLimiter, bool) {
+	name, _ := cmd.GetName()
+	e.rateLimitersMu.Lock()
+	defer e.rateLimitersMu.Unlock()
+	l, ok := e.rateLimiters[name]
+	return l, ok
+}
+
+// SetRateLimit implements TenEnv.SetRateLimit; see its doc comment for
+// the contract.
+func (t *tenEnvImpl) SetRateLimit(cmdName string, perSecond, burst int, opts ...RateLimitOption) {
+	l := newRateLimiter(perSecond, burst, opts...)
+	t.engine.rateLimitersMu.Lock()
+	defer t.engine.rateLimitersMu.Unlock()
+	if t.engine.rateLimiters == nil {
+		t.engine.rateLimiters = make(map[string]*rateLimiter)
+	}
+	t.engine.rateLimiters[cmdName] = l
+}
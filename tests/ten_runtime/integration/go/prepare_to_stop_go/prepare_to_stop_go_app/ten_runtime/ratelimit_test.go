@@ -0,0 +1,200 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"testing"
+	"time"
+)
+
+// countingExtension records how many times OnCmd ran, so rate-limit tests
+// can tell an admitted cmd from a rejected one without needing to inspect
+// the CmdResult ReturnError produces (see ReturnResult's doc comment:
+// it's metrics-only, there's nothing else to observe it with).
+type countingExtension struct {
+	DefaultExtension
+	calls chan Cmd
+}
+
+func (e countingExtension) OnCmd(tenEnv TenEnv, cmd Cmd) {
+	e.calls <- cmd
+	tenEnv.ReturnOK(cmd, "")
+}
+
+func TestTenEnv_SetRateLimitRejectsOverLimitByDefault(t *testing.T) {
+	engine := NewEngine(countingExtension{calls: make(chan Cmd, 2)})
+	engine.Init()
+	engine.Start()
+	engine.TenEnv().SetRateLimit("greet", 0, 1)
+
+	first, _ := NewCmd("greet")
+	if err := engine.DeliverCmd(first); err != nil {
+		t.Fatalf("first DeliverCmd: %v", err)
+	}
+	second, _ := NewCmd("greet")
+	if err := engine.DeliverCmd(second); err != nil {
+		t.Fatalf("second DeliverCmd: %v", err)
+	}
+
+	ext := engine.tenEnv.engine.ext.(countingExtension)
+	select {
+	case <-ext.calls:
+	default:
+		t.Fatal("first cmd never reached OnCmd, want it admitted under the burst")
+	}
+	select {
+	case <-ext.calls:
+		t.Fatal("second cmd reached OnCmd, want it rejected: burst was 1 and perSecond 0 never refills")
+	default:
+	}
+}
+
+func TestTenEnv_SetRateLimitOnlyAppliesToTheNamedCmd(t *testing.T) {
+	engine := NewEngine(countingExtension{calls: make(chan Cmd, 2)})
+	engine.Init()
+	engine.Start()
+	engine.TenEnv().SetRateLimit("greet", 0, 0)
+
+	cmd, _ := NewCmd("farewell")
+	if err := engine.DeliverCmd(cmd); err != nil {
+		t.Fatalf("DeliverCmd: %v", err)
+	}
+
+	ext := engine.tenEnv.engine.ext.(countingExtension)
+	select {
+	case <-ext.calls:
+	default:
+		t.Fatal("cmd with an unrelated name was rate limited, want SetRateLimit to only affect \"greet\"")
+	}
+}
+
+func TestTenEnv_SetRateLimitRefillsOverTime(t *testing.T) {
+	engine := NewEngine(countingExtension{calls: make(chan Cmd, 2)})
+	engine.Init()
+	engine.Start()
+	engine.TenEnv().SetRateLimit("greet", 1000, 1)
+
+	first, _ := NewCmd("greet")
+	engine.DeliverCmd(first)
+	time.Sleep(20 * time.Millisecond)
+	second, _ := NewCmd("greet")
+	if err := engine.DeliverCmd(second); err != nil {
+		t.Fatalf("second DeliverCmd: %v", err)
+	}
+
+	ext := engine.tenEnv.engine.ext.(countingExtension)
+	for i := 0; i < 2; i++ {
+		select {
+		case <-ext.calls:
+		case <-time.After(time.Second):
+			t.Fatalf("only %d/2 cmds reached OnCmd, want both admitted once tokens refilled", i)
+		}
+	}
+}
+
+func TestTenEnv_SetRateLimitQueueModeDelaysInsteadOfRejecting(t *testing.T) {
+	engine := NewEngine(countingExtension{calls: make(chan Cmd, 2)})
+	engine.Init()
+	engine.Start()
+	engine.TenEnv().SetRateLimit(
+		"greet", 1000, 1,
+		WithRateLimitMode(RateLimitModeQueue),
+		WithRateLimitQueueDepth(1),
+	)
+
+	first, _ := NewCmd("greet")
+	engine.DeliverCmd(first)
+	second, _ := NewCmd("greet")
+	if err := engine.DeliverCmd(second); err != nil {
+		t.Fatalf("second DeliverCmd: %v", err)
+	}
+
+	ext := engine.tenEnv.engine.ext.(countingExtension)
+	for i := 0; i < 2; i++ {
+		select {
+		case <-ext.calls:
+		case <-time.After(time.Second):
+			t.Fatalf("only %d/2 cmds reached OnCmd, want the second queued and dispatched once refilled", i)
+		}
+	}
+}
+
+// TestTenEnv_SetRateLimitQueueModeSerializesQueuedDispatchesAgainstTheBucket
+// guards against a queued goroutine assuming its own single wait implies a
+// token was actually granted to it: with several cmds queued at once, each
+// must re-check take() (and wait again if it lost the race) instead of all
+// dispatching together the moment the first token of the batch refills.
+func TestTenEnv_SetRateLimitQueueModeSerializesQueuedDispatchesAgainstTheBucket(t *testing.T) {
+	const n = 5
+	engine := NewEngine(countingExtension{calls: make(chan Cmd, n)})
+	engine.Init()
+	engine.Start()
+	engine.TenEnv().SetRateLimit(
+		"greet", 10, 1, // one token every 100ms
+		WithRateLimitMode(RateLimitModeQueue),
+		WithRateLimitQueueDepth(n-1),
+	)
+
+	for i := 0; i < n; i++ {
+		cmd, _ := NewCmd("greet")
+		if err := engine.DeliverCmd(cmd); err != nil {
+			t.Fatalf("DeliverCmd #%d: %v", i, err)
+		}
+	}
+
+	ext := engine.tenEnv.engine.ext.(countingExtension)
+	var arrivals []time.Time
+	for i := 0; i < n; i++ {
+		select {
+		case <-ext.calls:
+			arrivals = append(arrivals, time.Now())
+		case <-time.After(3 * time.Second):
+			t.Fatalf("only %d/%d cmds reached OnCmd, want all %d eventually dispatched", i, n, n)
+		}
+	}
+
+	for i := 1; i < len(arrivals); i++ {
+		if gap := arrivals[i].Sub(arrivals[i-1]); gap < 50*time.Millisecond {
+			t.Fatalf(
+				"dispatch %d arrived only %v after dispatch %d, want each queued dispatch to wait for its own token (~100ms apart), not all fire together once the first one refills",
+				i, gap, i-1,
+			)
+		}
+	}
+}
+
+func TestTenEnv_SetRateLimitQueueModeRejectsOnceQueueIsFull(t *testing.T) {
+	engine := NewEngine(countingExtension{calls: make(chan Cmd, 3)})
+	engine.Init()
+	engine.Start()
+	engine.TenEnv().SetRateLimit(
+		"greet", 0, 1,
+		WithRateLimitMode(RateLimitModeQueue),
+		WithRateLimitQueueDepth(1),
+	)
+
+	for i := 0; i < 3; i++ {
+		cmd, _ := NewCmd("greet")
+		if err := engine.DeliverCmd(cmd); err != nil {
+			t.Fatalf("DeliverCmd #%d: %v", i, err)
+		}
+	}
+
+	// burst admits one immediately; the queue (depth 1) holds a second,
+	// but perSecond 0 means it never gets a token so it sits queued
+	// forever; the third has no room left in the queue and is rejected
+	// outright. Only the first ever reaches OnCmd.
+	ext := engine.tenEnv.engine.ext.(countingExtension)
+	select {
+	case <-ext.calls:
+	default:
+		t.Fatal("first cmd never reached OnCmd, want it admitted under the burst")
+	}
+	select {
+	case <-ext.calls:
+		t.Fatal("a second cmd reached OnCmd, want only the first admitted")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"sync"
+)
+
+// ErrCmdNotDispatched is returned by Cmd.DetachResponder for a cmd that was
+// never handed to an extension's OnCmd by an Engine -- e.g. one just built
+// via NewCmd for an outgoing SendCmd -- since there would be no Engine left
+// to eventually deliver a result to.
+var ErrCmdNotDispatched = errors.New("ten: cmd was not dispatched through an Engine")
+
+// ErrResponderTokenInvalid is returned by TenEnv.ReturnResultWithToken for a
+// token that isn't currently redeemable: it was never issued by
+// DetachResponder, or it already was redeemed once. See
+// ReturnResultWithToken's doc comment for the exactly-once contract this
+// enforces.
+var ErrResponderTokenInvalid = errors.New("ten: responder token is invalid or already used")
+
+// ResponderToken is an opaque, serializable stand-in for a Cmd's obligation
+// to eventually get a result, handed out by Cmd.DetachResponder so it can
+// ride along as a plain string -- typically a property on a further cmd --
+// to whichever extension will actually produce the answer. Its String form
+// is what actually crosses that wire; the zero value is never valid.
+type ResponderToken struct {
+	id [16]byte
+}
+
+func (tok ResponderToken) String() string { return hex.EncodeToString(tok.id[:]) }
+
+// ResponderTokenFromString parses the String form of a ResponderToken, e.g.
+// one read back out of a cmd property B received from A.
+func ResponderTokenFromString(s string) (ResponderToken, bool) {
+	var tok ResponderToken
+	b, err := hex.DecodeString(s)
+	if err != nil || len(b) != len(tok.id) {
+		return ResponderToken{}, false
+	}
+	copy(tok.id[:], b)
+	return tok, true
+}
+
+// responderEntry is what a ResponderToken actually resolves to: the Engine
+// whose ReturnResult needs calling, and which of its cmds to call it for.
+type responderEntry struct {
+	engine *Engine
+	cmd    Cmd
+}
+
+// responders is the process-wide registry backing ResponderToken, following
+// this package's usual pattern for state that must be reachable across
+// separate Engine instances -- the same shape as the addon registry in
+// dynamic.go and the log sink in logsink.go -- since the extension
+// redeeming a token is very often running against a different Engine than
+// the one that detached it.
+var (
+	respondersMu sync.Mutex
+	responders   = map[[16]byte]responderEntry{}
+)
+
+// registerResponder issues a fresh ResponderToken for cmd and files it in
+// the process-wide registry, keyed off a random 16-byte id in the same
+// style as trace.go's TraceID.
+func registerResponder(e *Engine, cmd Cmd) ResponderToken {
+	var tok ResponderToken
+	rand.Read(tok.id[:])
+
+	respondersMu.Lock()
+	responders[tok.id] = responderEntry{engine: e, cmd: cmd}
+	respondersMu.Unlock()
+
+	return tok
+}
+
+// ReturnResultWithToken implements TenEnv.ReturnResultWithToken; see its
+// doc comment. Popping the entry before calling ReturnResult, rather than
+// after, is what makes a token redeemable at most once even if two
+// goroutines race to redeem the same one: only whichever call observes the
+// entry actually gets to deliver it.
+func (t *tenEnvImpl) ReturnResultWithToken(token ResponderToken, result CmdResult) error {
+	respondersMu.Lock()
+	entry, ok := responders[token.id]
+	if ok {
+		delete(responders, token.id)
+	}
+	respondersMu.Unlock()
+
+	if !ok {
+		return ErrResponderTokenInvalid
+	}
+	return entry.engine.tenEnv.ReturnResult(result, entry.cmd)
+}
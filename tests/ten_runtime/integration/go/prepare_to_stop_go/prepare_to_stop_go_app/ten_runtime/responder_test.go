@@ -0,0 +1,115 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import "testing"
+
+// detachingExtension detaches a responder token for every cmd it receives
+// and hands it out over tokens instead of ever calling ReturnResult itself,
+// simulating extension A forwarding the obligation to answer on to B.
+type detachingExtension struct {
+	DefaultExtension
+	tokens chan ResponderToken
+}
+
+func (e *detachingExtension) OnCmd(tenEnv TenEnv, cmd Cmd) {
+	tok, err := cmd.DetachResponder()
+	if err != nil {
+		tenEnv.ReturnError(cmd, err.Error())
+		return
+	}
+	e.tokens <- tok
+}
+
+func TestTenEnv_ReturnResultWithTokenCompletesTheCmdFromAnotherEngine(t *testing.T) {
+	engineA := NewEngine(&detachingExtension{tokens: make(chan ResponderToken, 1)})
+	engineA.Init()
+	engineA.Start()
+
+	cmd, _ := NewCmd("ask")
+	go engineA.DeliverCmd(cmd)
+
+	extA := engineA.tenEnv.engine.ext.(*detachingExtension)
+	tok := <-extA.tokens
+	waitForInflightCount(t, engineA, 1)
+
+	// engineB stands in for extension B: a completely separate Engine
+	// instance from the one that received cmd, redeeming the token that
+	// crossed over from A the same way it would as a cmd property.
+	engineB := NewEngine(DefaultExtension{})
+	engineB.Init()
+	engineB.Start()
+
+	result, _ := NewCmdResult(StatusCodeOk, cmd)
+	if err := engineB.TenEnv().ReturnResultWithToken(tok, result); err != nil {
+		t.Fatalf("ReturnResultWithToken: %v", err)
+	}
+
+	waitForInflightCount(t, engineA, 0)
+}
+
+func TestTenEnv_ReturnResultWithTokenFailsOnDoubleRedemption(t *testing.T) {
+	engine := NewEngine(&detachingExtension{tokens: make(chan ResponderToken, 1)})
+	engine.Init()
+	engine.Start()
+
+	cmd, _ := NewCmd("ask")
+	go engine.DeliverCmd(cmd)
+
+	ext := engine.tenEnv.engine.ext.(*detachingExtension)
+	tok := <-ext.tokens
+	waitForInflightCount(t, engine, 1)
+
+	result, _ := NewCmdResult(StatusCodeOk, cmd)
+	if err := engine.TenEnv().ReturnResultWithToken(tok, result); err != nil {
+		t.Fatalf("first ReturnResultWithToken: %v", err)
+	}
+
+	result2, _ := NewCmdResult(StatusCodeOk, cmd)
+	if err := engine.TenEnv().ReturnResultWithToken(tok, result2); err != ErrResponderTokenInvalid {
+		t.Fatalf("second ReturnResultWithToken error = %v, want ErrResponderTokenInvalid", err)
+	}
+}
+
+func TestTenEnv_ReturnResultWithTokenFailsForAnUnknownToken(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+	engine.Init()
+	engine.Start()
+
+	var bogus ResponderToken
+	result, _ := NewCmdResult(StatusCodeOk, nil)
+	if err := engine.TenEnv().ReturnResultWithToken(bogus, result); err != ErrResponderTokenInvalid {
+		t.Fatalf("err = %v, want ErrResponderTokenInvalid", err)
+	}
+}
+
+func TestCmd_DetachResponderFailsForAnUndispatchedCmd(t *testing.T) {
+	cmd, _ := NewCmd("ask")
+	if _, err := cmd.DetachResponder(); err != ErrCmdNotDispatched {
+		t.Fatalf("err = %v, want ErrCmdNotDispatched", err)
+	}
+}
+
+func TestResponderToken_RoundTripsThroughItsStringForm(t *testing.T) {
+	engine := NewEngine(&detachingExtension{tokens: make(chan ResponderToken, 1)})
+	engine.Init()
+	engine.Start()
+
+	cmd, _ := NewCmd("ask")
+	go engine.DeliverCmd(cmd)
+
+	ext := engine.tenEnv.engine.ext.(*detachingExtension)
+	tok := <-ext.tokens
+
+	parsed, ok := ResponderTokenFromString(tok.String())
+	if !ok {
+		t.Fatalf("ResponderTokenFromString(%q) failed", tok.String())
+	}
+
+	result, _ := NewCmdResult(StatusCodeOk, cmd)
+	if err := engine.TenEnv().ReturnResultWithToken(parsed, result); err != nil {
+		t.Fatalf("ReturnResultWithToken: %v", err)
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import "fmt"
+
+// ResultStream lets an extension server-stream a sequence of interim
+// CmdResults for a single Cmd, followed by exactly one final one, without
+// juggling CmdResult.SetIsFinal itself -- e.g. a speech-to-text extension
+// emitting interim transcripts as it recognizes them. See TenEnv.
+// NewResultStream for how one is opened and its shutdown-enforcement
+// guarantee.
+type ResultStream interface {
+	// Send delivers partial as the stream's next interim (non-final)
+	// result: the caller's SendCmd/SendCmdEx callback sees it in the
+	// order Send was called, same as any other result for one Cmd.
+	// Calling Send after Close returns ErrCmdAlreadyFinal.
+	Send(partial CmdResult) error
+
+	// Close delivers final as the stream's terminal result and releases
+	// its shutdown-enforcement registration. Calling Close more than
+	// once returns ErrCmdAlreadyFinal, the same as ReturnResultEx would
+	// for a cmd that already has a final result.
+	Close(final CmdResult) error
+}
+
+// resultStream is ResultStream's only implementation. resolve is the
+// closure NewResultStream got back from registerPending, the same
+// resolve/onTimeout pairing jobHandle uses for AcceptCommand -- see
+// registerPending's doc comment in engine.go for the contract it mirrors.
+type resultStream struct {
+	tenEnv  *tenEnvImpl
+	cmd     Cmd
+	resolve func() bool
+}
+
+// NewResultStream opens a ResultStream for cmd, an alternative to calling
+// ReturnResult/ReturnResultEx (and CmdResult.SetIsFinal) directly for a
+// caller that wants to emit a series of results for one Cmd. A stream
+// that's never Closed -- because the caller forgot, or crashed first -- is
+// force-completed with an ErrShutdownTimeout result once the engine shuts
+// down, the same shutdown-forcing guarantee AcceptCommand's JobHandle gives
+// a long-running job, so cmd's originator can never be left waiting
+// forever on a dropped stream.
+func (t *tenEnvImpl) NewResultStream(cmd Cmd) (ResultStream, error) {
+	if cmd == nil {
+		return nil, fmt.Errorf("ten: NewResultStream: cmd must not be nil")
+	}
+
+	resolve, _ := t.engine.registerPending(func() {
+		// This runs from inside Stop's own shutdown-forcing path, the same
+		// as AcceptCommand's onTimeout above it -- see that comment for why
+		// this goes around ReturnResultEx instead of through it.
+		if cmd.finalized(true) {
+			return
+		}
+		result, err := NewCmdResult(StatusCodeError, cmd)
+		if err != nil {
+			return
+		}
+		_ = result.SetPropertyString("detail", ErrShutdownTimeout.Error())
+		if impl, ok := result.(*cmdResultImpl); ok {
+			impl.isFinal = true
+		}
+		t.engine.finalizeReturnedCmd(cmd)
+		t.engine.notifyCrossExtResultHandler(cmd, result)
+	})
+
+	cmd.markCompletionTokenTaken()
+	return &resultStream{tenEnv: t, cmd: cmd, resolve: resolve}, nil
+}
+
+func (s *resultStream) Send(partial CmdResult) error {
+	return s.tenEnv.ReturnResultEx(partial, s.cmd, false)
+}
+
+func (s *resultStream) Close(final CmdResult) error {
+	if !s.resolve() {
+		return ErrCmdAlreadyFinal
+	}
+	return s.tenEnv.ReturnResultEx(final, s.cmd, true)
+}
@@ -0,0 +1,110 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTenEnv_ResultStreamSendKeepsCmdOpenUntilClose(t *testing.T) {
+	engine := NewEngine(newLifecycleExtension())
+	cmd, _ := NewCmd("transcribe")
+
+	stream, err := engine.tenEnv.NewResultStream(cmd)
+	if err != nil {
+		t.Fatalf("NewResultStream: %v", err)
+	}
+
+	interim, _ := NewCmdResult(StatusCodeOk, cmd)
+	interim.SetPropertyString("text", "hel")
+	if err := stream.Send(interim); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if isFinal, _ := interim.IsFinal(); isFinal {
+		t.Fatal("Send marked its result final, want interim")
+	}
+	if cmd.finalized(false) {
+		t.Fatal("cmd already final after an interim Send")
+	}
+
+	final, _ := NewCmdResult(StatusCodeOk, cmd)
+	final.SetPropertyString("text", "hello")
+	if err := stream.Close(final); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if isFinal, _ := final.IsFinal(); !isFinal {
+		t.Fatal("Close did not mark its result final")
+	}
+	if !cmd.finalized(false) {
+		t.Fatal("cmd not final after Close")
+	}
+}
+
+func TestTenEnv_ResultStreamCloseTwiceIsErrCmdAlreadyFinal(t *testing.T) {
+	engine := NewEngine(newLifecycleExtension())
+	cmd, _ := NewCmd("transcribe")
+
+	stream, err := engine.tenEnv.NewResultStream(cmd)
+	if err != nil {
+		t.Fatalf("NewResultStream: %v", err)
+	}
+
+	final, _ := NewCmdResult(StatusCodeOk, cmd)
+	if err := stream.Close(final); err != nil {
+		t.Fatalf("first Close: %v", err)
+	}
+
+	again, _ := NewCmdResult(StatusCodeOk, cmd)
+	if err := stream.Close(again); !errors.Is(err, ErrCmdAlreadyFinal) {
+		t.Fatalf("second Close = %v, want ErrCmdAlreadyFinal", err)
+	}
+}
+
+func TestTenEnv_NewResultStreamOnNilCmdErrors(t *testing.T) {
+	engine := NewEngine(newLifecycleExtension())
+	if _, err := engine.tenEnv.NewResultStream(nil); err == nil {
+		t.Fatal("NewResultStream(nil) = nil error, want an error")
+	}
+}
+
+// TestTenEnv_ResultStreamForceCompletedOnShutdownDeadline exercises the
+// interaction the request asked to be enforced: a ResultStream whose Close
+// is never called is force-completed with ErrShutdownTimeout the same way
+// an unfinished JobHandle is -- see
+// TestTenEnv_AcceptCommandForceCompletedOnShutdownDeadline, which this
+// mirrors.
+func TestTenEnv_ResultStreamForceCompletedOnShutdownDeadline(t *testing.T) {
+	engine := NewEngine(
+		hangingStopExtension{},
+		WithShutdownDeadline(20*time.Millisecond),
+	)
+	if err := engine.Init(); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	if err := engine.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	cmd, _ := NewCmd("transcribe")
+	stream, err := engine.tenEnv.NewResultStream(cmd)
+	if err != nil {
+		t.Fatalf("NewResultStream: %v", err)
+	}
+
+	if err := engine.Stop(); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+
+	if !cmd.finalized(false) {
+		t.Fatal("cmd not force-completed by the shutdown deadline")
+	}
+
+	tooLate, _ := NewCmdResult(StatusCodeOk, cmd)
+	if err := stream.Close(tooLate); !errors.Is(err, ErrCmdAlreadyFinal) {
+		t.Fatalf("Close after shutdown force = %v, want ErrCmdAlreadyFinal", err)
+	}
+}
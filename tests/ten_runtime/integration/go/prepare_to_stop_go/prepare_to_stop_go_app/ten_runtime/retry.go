@@ -0,0 +1,102 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// RetryPolicy configures TenEnv.SendCmdWithRetry.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first.
+	// MaxAttempts <= 1 means no retries: the first failure is final.
+	MaxAttempts int
+
+	// Backoff returns how long to wait before retry number attempt (1
+	// for the first retry, i.e. the one right after the first attempt
+	// fails). A nil Backoff retries immediately, with no wait.
+	Backoff func(attempt int) time.Duration
+
+	// ShouldRetry reports whether result/err from a failed attempt
+	// warrants another try. A nil ShouldRetry retries any attempt that
+	// either returned a Go error or a CmdResult whose StatusCode is
+	// StatusCodeError, matching the common "transient failure" case.
+	ShouldRetry func(result CmdResult, err error) bool
+}
+
+func (p RetryPolicy) shouldRetry(result CmdResult, err error) bool {
+	if p.ShouldRetry != nil {
+		return p.ShouldRetry(result, err)
+	}
+	if err != nil {
+		return true
+	}
+	code, codeErr := result.StatusCode()
+	return codeErr == nil && code == StatusCodeError
+}
+
+func (t *tenEnvImpl) SendCmdWithRetry(
+	ctx context.Context, cmd Cmd, policy RetryPolicy, handler CmdResultHandler,
+) error {
+	if cmd == nil {
+		return fmt.Errorf("ten: SendCmdWithRetry: cmd must not be nil")
+	}
+	maxAttempts := policy.MaxAttempts
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+
+	var attempt func(cmd Cmd, attemptNum int) error
+	attempt = func(cmd Cmd, attemptNum int) error {
+		return t.SendCmd(cmd, func(_ TenEnv, result CmdResult, err error) {
+			if attemptNum >= maxAttempts || !policy.shouldRetry(result, err) {
+				handler(t, result, err)
+				return
+			}
+
+			select {
+			case <-ctx.Done():
+				handler(t, nil, fmt.Errorf("%w: %v", ErrContextCanceled, ctx.Err()))
+				return
+			default:
+			}
+
+			retryCmd, cloneErr := cmd.Clone()
+			if cloneErr != nil {
+				handler(t, nil, fmt.Errorf("ten: SendCmdWithRetry: cloning cmd for retry: %w", cloneErr))
+				return
+			}
+
+			retry := func() {
+				if retryErr := attempt(retryCmd, attemptNum+1); retryErr != nil {
+					handler(t, nil, retryErr)
+				}
+			}
+
+			wait := time.Duration(0)
+			if policy.Backoff != nil {
+				wait = policy.Backoff(attemptNum)
+			}
+			if wait <= 0 {
+				retry()
+				return
+			}
+
+			timer := time.NewTimer(wait)
+			go func() {
+				defer timer.Stop()
+				select {
+				case <-ctx.Done():
+					handler(t, nil, fmt.Errorf("%w: %v", ErrContextCanceled, ctx.Err()))
+				case <-timer.C:
+					retry()
+				}
+			}()
+		})
+	}
+	return attempt(cmd, 1)
+}
@@ -0,0 +1,201 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSendCmdWithRetry_SucceedsOnFirstAttemptWithoutRetrying(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+	engine.Init()
+	engine.Start()
+
+	attempts := 0
+	engine.SetSender(func(cmd Cmd, cb CmdResultHandler) {
+		attempts++
+		result, _ := NewCmdResult(StatusCodeOk, cmd)
+		cb(engine.TenEnv(), result, nil)
+	})
+
+	cmd, _ := NewCmd("greet")
+	var gotErr error
+	err := engine.TenEnv().SendCmdWithRetry(context.Background(), cmd, RetryPolicy{MaxAttempts: 3},
+		func(_ TenEnv, result CmdResult, err error) { gotErr = err })
+	if err != nil {
+		t.Fatalf("SendCmdWithRetry: %v", err)
+	}
+	if attempts != 1 {
+		t.Fatalf("expected exactly one attempt, got %d", attempts)
+	}
+	if gotErr != nil {
+		t.Fatalf("expected no error, got %v", gotErr)
+	}
+}
+
+func TestSendCmdWithRetry_RetriesOnErrorStatusUntilItSucceeds(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+	engine.Init()
+	engine.Start()
+
+	attempts := 0
+	engine.SetSender(func(cmd Cmd, cb CmdResultHandler) {
+		attempts++
+		if attempts < 3 {
+			result, _ := NewCmdResult(StatusCodeError, cmd)
+			cb(engine.TenEnv(), result, nil)
+			return
+		}
+		result, _ := NewCmdResult(StatusCodeOk, cmd)
+		cb(engine.TenEnv(), result, nil)
+	})
+
+	cmd, _ := NewCmd("greet")
+	var final CmdResult
+	err := engine.TenEnv().SendCmdWithRetry(context.Background(), cmd, RetryPolicy{MaxAttempts: 5},
+		func(_ TenEnv, result CmdResult, _ error) { final = result })
+	if err != nil {
+		t.Fatalf("SendCmdWithRetry: %v", err)
+	}
+	if attempts != 3 {
+		t.Fatalf("expected 3 attempts, got %d", attempts)
+	}
+	code, _ := final.StatusCode()
+	if code != StatusCodeOk {
+		t.Fatalf("expected the final result to be Ok, got %v", code)
+	}
+}
+
+func TestSendCmdWithRetry_GivesUpAfterMaxAttempts(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+	engine.Init()
+	engine.Start()
+
+	attempts := 0
+	engine.SetSender(func(cmd Cmd, cb CmdResultHandler) {
+		attempts++
+		result, _ := NewCmdResult(StatusCodeError, cmd)
+		cb(engine.TenEnv(), result, nil)
+	})
+
+	cmd, _ := NewCmd("greet")
+	var final CmdResult
+	err := engine.TenEnv().SendCmdWithRetry(context.Background(), cmd, RetryPolicy{MaxAttempts: 3},
+		func(_ TenEnv, result CmdResult, _ error) { final = result })
+	if err != nil {
+		t.Fatalf("SendCmdWithRetry: %v", err)
+	}
+	if attempts != 3 {
+		t.Fatalf("expected exactly MaxAttempts (3) attempts, got %d", attempts)
+	}
+	code, _ := final.StatusCode()
+	if code != StatusCodeError {
+		t.Fatalf("expected the final result to still be Error, got %v", code)
+	}
+}
+
+func TestSendCmdWithRetry_EachRetryUsesAFreshClone(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+	engine.Init()
+	engine.Start()
+
+	var seen []Cmd
+	attempts := 0
+	engine.SetSender(func(cmd Cmd, cb CmdResultHandler) {
+		attempts++
+		seen = append(seen, cmd)
+		if attempts < 2 {
+			result, _ := NewCmdResult(StatusCodeError, cmd)
+			cb(engine.TenEnv(), result, nil)
+			return
+		}
+		result, _ := NewCmdResult(StatusCodeOk, cmd)
+		cb(engine.TenEnv(), result, nil)
+	})
+
+	cmd, _ := NewCmd("greet")
+	if err := engine.TenEnv().SendCmdWithRetry(context.Background(), cmd, RetryPolicy{MaxAttempts: 3},
+		func(TenEnv, CmdResult, error) {}); err != nil {
+		t.Fatalf("SendCmdWithRetry: %v", err)
+	}
+	if len(seen) != 2 {
+		t.Fatalf("expected 2 attempts, got %d", len(seen))
+	}
+	if seen[0] == seen[1] {
+		t.Fatal("expected the retry to use a distinct, cloned Cmd rather than reusing the original")
+	}
+}
+
+func TestSendCmdWithRetry_RespectsCustomShouldRetryPredicate(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+	engine.Init()
+	engine.Start()
+
+	sentinel := errors.New("not retryable")
+	attempts := 0
+	engine.SetSender(func(cmd Cmd, cb CmdResultHandler) {
+		attempts++
+		cb(engine.TenEnv(), nil, sentinel)
+	})
+
+	cmd, _ := NewCmd("greet")
+	var gotErr error
+	policy := RetryPolicy{
+		MaxAttempts: 5,
+		ShouldRetry: func(CmdResult, error) bool { return false },
+	}
+	if err := engine.TenEnv().SendCmdWithRetry(context.Background(), cmd, policy,
+		func(_ TenEnv, _ CmdResult, err error) { gotErr = err }); err != nil {
+		t.Fatalf("SendCmdWithRetry: %v", err)
+	}
+	if attempts != 1 {
+		t.Fatalf("expected ShouldRetry=false to stop after 1 attempt, got %d", attempts)
+	}
+	if !errors.Is(gotErr, sentinel) {
+		t.Fatalf("expected the sentinel error to be reported, got %v", gotErr)
+	}
+}
+
+func TestSendCmdWithRetry_ContextCancelledDuringBackoffStopsRetrying(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+	engine.Init()
+	engine.Start()
+
+	attempts := 0
+	engine.SetSender(func(cmd Cmd, cb CmdResultHandler) {
+		attempts++
+		result, _ := NewCmdResult(StatusCodeError, cmd)
+		cb(engine.TenEnv(), result, nil)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cmd, _ := NewCmd("greet")
+	policy := RetryPolicy{
+		MaxAttempts: 5,
+		Backoff:     func(int) time.Duration { return 50 * time.Millisecond },
+	}
+
+	done := make(chan error, 1)
+	if err := engine.TenEnv().SendCmdWithRetry(ctx, cmd, policy,
+		func(_ TenEnv, _ CmdResult, err error) { done <- err }); err != nil {
+		t.Fatalf("SendCmdWithRetry: %v", err)
+	}
+	cancel()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, ErrContextCanceled) {
+			t.Fatalf("expected ErrContextCanceled, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for the cancelled context to stop the retry")
+	}
+	if attempts != 1 {
+		t.Fatalf("expected exactly 1 attempt before cancellation took effect, got %d", attempts)
+	}
+}
@@ -0,0 +1,47 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+// runtimeVersion is this package's own version, in "major.minor.patch"
+// form, bumped whenever featureSet changes. See RuntimeVersion's doc
+// comment for why it stands in for a linked C runtime's version here.
+const runtimeVersion = "0.9.0"
+
+// RuntimeVersion reports the version of the ten_runtime an extension is
+// linked against, in the same "major.minor.patch" form a deployed build
+// reports it in. This package is a Go-only simulation with no C runtime
+// underneath it to report on (see the package doc), so it reports this
+// package's own version instead -- an extension written against it can
+// still call RuntimeVersion() the same way it would in a real deployment,
+// it just tracks this simulation's capabilities rather than a linked
+// .so's.
+func RuntimeVersion() string {
+	return runtimeVersion
+}
+
+// featureSet is every capability HasFeature can report on, keyed by a
+// short, stable name unrelated to this package's own Go identifiers (so
+// renaming a Go type or function doesn't silently change what an
+// extension's capability check matches against). Add an entry here
+// alongside whatever request introduces the capability it names.
+var featureSet = map[string]bool{
+	"deadline_enforcement": true,
+	"app_signal":           true,
+	"jsonrpc_codec":        true,
+	"websocket_bridge":     true,
+	"log_buffering":        true,
+	"safe_goroutine":       true,
+}
+
+// HasFeature reports whether name is a capability this runtime supports,
+// for an extension binary shipped against multiple TEN deployments that
+// wants to branch on capability instead of guessing from RuntimeVersion
+// or from build tags. An unrecognized name reports false, the same as a
+// feature this runtime genuinely lacks -- there's no separate "unknown
+// feature" error, since a capability check unable to tell the two apart
+// is exactly as useful to the caller either way: don't rely on it.
+func HasFeature(name string) bool {
+	return featureSet[name]
+}
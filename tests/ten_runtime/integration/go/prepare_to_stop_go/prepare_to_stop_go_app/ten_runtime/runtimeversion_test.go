@@ -0,0 +1,25 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import "testing"
+
+func TestRuntimeVersion_ReportsANonEmptyVersion(t *testing.T) {
+	if v := RuntimeVersion(); v == "" {
+		t.Fatal("RuntimeVersion() is empty")
+	}
+}
+
+func TestHasFeature_ReportsTrueForAKnownFeature(t *testing.T) {
+	if !HasFeature("jsonrpc_codec") {
+		t.Fatal("HasFeature(\"jsonrpc_codec\") = false, want true")
+	}
+}
+
+func TestHasFeature_ReportsFalseForAnUnknownFeature(t *testing.T) {
+	if HasFeature("something_that_does_not_exist") {
+		t.Fatal("HasFeature(\"something_that_does_not_exist\") = true, want false")
+	}
+}
@@ -0,0 +1,42 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// Go runs fn on a new goroutine, the way the recommended pattern for a
+// long-running or blocking OnCmd handler already does, and takes care of
+// the recover/error-result bookkeeping every such handler otherwise has to
+// reimplement: recoverInto only guards the goroutine the runtime itself
+// invokes (OnCmd's own call), so a panic in a goroutine OnCmd spawns
+// itself would otherwise crash the process with nothing to catch it.
+//
+// If fn panics, Go recovers it, logs it via tenEnv.Log at LogLevelError
+// with its stack trace -- the same format recoverInto uses -- and returns
+// an error CmdResult for cmd via tenEnv.ReturnErrorf. If fn returns a
+// non-nil error instead, Go reports that error the same way, via
+// tenEnv.ReturnError. Either way, Go's own goroutine returns cmd exactly
+// one final result, consistent with cmd's finalized-once-only contract
+// (see Cmd.Clone's doc comment); a well-behaved fn should return nil after
+// already returning a result of its own via ReturnResult/ReturnOK, not via
+// a non-nil error, since the two would otherwise both try to finalize cmd.
+func Go(tenEnv TenEnv, cmd Cmd, fn func() error) {
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				tenEnv.Log(LogLevelError, fmt.Sprintf(
+					"ten: recovered panic in ten.Go: %v\n%s", r, debug.Stack(),
+				))
+				tenEnv.ReturnErrorf(cmd, "panic: %v", r)
+			}
+		}()
+		if err := fn(); err != nil {
+			tenEnv.ReturnError(cmd, err.Error())
+		}
+	}()
+}
@@ -0,0 +1,141 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// channelResultTenEnv embeds *tenEnvImpl and overrides ReturnResult to
+// publish the result on a channel, so a test can synchronize with a
+// ten.Go goroutine's asynchronous ReturnResult call instead of racing a
+// plain field the way resultCapturingTenEnv would.
+type channelResultTenEnv struct {
+	*tenEnvImpl
+	results chan CmdResult
+}
+
+func newChannelResultTenEnv(engine *Engine) *channelResultTenEnv {
+	return &channelResultTenEnv{tenEnvImpl: engine.tenEnv, results: make(chan CmdResult, 1)}
+}
+
+func (t *channelResultTenEnv) ReturnResult(result CmdResult, cmd Cmd) error {
+	t.results <- result
+	return nil
+}
+
+// ReturnError/ReturnErrorf are re-implemented rather than inherited from
+// the embedded *tenEnvImpl for the same reason error_test.go's
+// resultCapturingTenEnv does: a promoted method's body calls the embedded
+// type's ReturnResult directly, not this type's override.
+func (t *channelResultTenEnv) ReturnError(cmd Cmd, detail string) error {
+	result, err := NewCmdResult(StatusCodeError, cmd)
+	if err != nil {
+		return err
+	}
+	if err := result.SetPropertyString("detail", detail); err != nil {
+		return err
+	}
+	return t.ReturnResult(result, cmd)
+}
+
+func (t *channelResultTenEnv) ReturnErrorf(cmd Cmd, format string, args ...any) error {
+	return t.ReturnError(cmd, fmt.Sprintf(format, args...))
+}
+
+func (t *channelResultTenEnv) awaitResult(tb testing.TB) CmdResult {
+	tb.Helper()
+	select {
+	case result := <-t.results:
+		return result
+	case <-time.After(time.Second):
+		tb.Fatal("timed out waiting for ten.Go to return a result")
+		return nil
+	}
+}
+
+func TestGo_RunsFnAndReturnsNothingOnSuccess(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+	tenEnv := newChannelResultTenEnv(engine)
+	cmd, _ := NewCmd("work")
+
+	done := make(chan struct{})
+	Go(tenEnv, cmd, func() error {
+		close(done)
+		return nil
+	})
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for fn to run")
+	}
+	select {
+	case result := <-tenEnv.results:
+		t.Fatalf("Go called ReturnResult for a nil-error fn, got %+v", result)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestGo_ReturnsErrorResultWhenFnFails(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+	tenEnv := newChannelResultTenEnv(engine)
+	cmd, _ := NewCmd("work")
+
+	Go(tenEnv, cmd, func() error {
+		return errors.New("dial tcp: connection refused")
+	})
+
+	result := tenEnv.awaitResult(t)
+	status, _ := result.StatusCode()
+	if status != StatusCodeError {
+		t.Fatalf("StatusCode = %v, want StatusCodeError", status)
+	}
+	detail, _ := result.GetPropertyString("detail")
+	if detail != "dial tcp: connection refused" {
+		t.Fatalf("detail = %q, want %q", detail, "dial tcp: connection refused")
+	}
+}
+
+func TestGo_RecoversPanicAndReturnsErrorResult(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+	tenEnv := newChannelResultTenEnv(engine)
+	cmd, _ := NewCmd("work")
+
+	Go(tenEnv, cmd, func() error {
+		panic("boom")
+	})
+
+	result := tenEnv.awaitResult(t)
+	status, _ := result.StatusCode()
+	if status != StatusCodeError {
+		t.Fatalf("StatusCode = %v, want StatusCodeError", status)
+	}
+	detail, _ := result.GetPropertyString("detail")
+	if detail != "panic: boom" {
+		t.Fatalf("detail = %q, want %q", detail, "panic: boom")
+	}
+}
+
+func TestGo_LogsPanicWithStack(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+	tenEnv := newChannelResultTenEnv(engine)
+	cmd, _ := NewCmd("work")
+
+	var buf lockedBuffer
+	tenEnv.SetLogSink(&buf, LogFormatText)
+
+	Go(tenEnv, cmd, func() error {
+		panic("boom")
+	})
+	tenEnv.awaitResult(t)
+
+	if got := buf.String(); got == "" {
+		t.Fatal("Go did not log the recovered panic")
+	}
+}
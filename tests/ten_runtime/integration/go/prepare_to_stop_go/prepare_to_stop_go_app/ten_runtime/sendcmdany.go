@@ -0,0 +1,128 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrNoQuorum is returned by SendCmdQuorum when reaching n successful
+// (StatusCodeOk) results among cmds becomes impossible -- either too many
+// of them failed outright, or too many failed to send in the first place.
+var ErrNoQuorum = errors.New("ten: quorum not reached")
+
+// sendCmdRacingOutcomes fans cmds out concurrently through their own
+// SendCmdWithContext, sharing ctx so a caller can cancel every cmd still
+// outstanding -- releasing its concurrency slot immediately -- the moment
+// it has all the results it needs. It's the shared plumbing behind
+// SendCmdAny and SendCmdQuorum, neither of which, unlike SendCmdMulti,
+// waits for every cmd to finish.
+func (t *tenEnvImpl) sendCmdRacingOutcomes(
+	ctx context.Context, cmds []Cmd, namePrefix string,
+) <-chan CmdResultOrError {
+	outcomes := make(chan CmdResultOrError, len(cmds))
+	for i, cmd := range cmds {
+		if cmd == nil {
+			outcomes <- CmdResultOrError{
+				IsFinal: true,
+				Err:     fmt.Errorf("ten: %s: cmds[%d] must not be nil", namePrefix, i),
+			}
+			continue
+		}
+		cmd := cmd
+		if err := t.SendCmdWithContext(ctx, cmd, func(_ TenEnv, result CmdResult, err error) {
+			outcomes <- CmdResultOrError{Result: result, IsFinal: true, Err: err}
+		}); err != nil {
+			outcomes <- CmdResultOrError{IsFinal: true, Err: err}
+		}
+	}
+	return outcomes
+}
+
+// SendCmdAny sends every cmd in cmds concurrently and returns as soon as
+// the first one comes back with a successful (StatusCodeOk) result,
+// cancelling every cmd still outstanding so their concurrency slots are
+// released right away instead of held until each straggler eventually
+// resolves on its own -- the same hedged-request pattern SendCmdWithContext
+// gives a single caller, extended across a fan-out. If none of cmds ever
+// succeeds, the errors from all of them (including any that failed to send
+// at all) are combined with errors.Join.
+func (t *tenEnvImpl) SendCmdAny(cmds []Cmd) (CmdResult, error) {
+	if len(cmds) == 0 {
+		return nil, fmt.Errorf("ten: SendCmdAny: cmds must not be empty")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	outcomes := t.sendCmdRacingOutcomes(ctx, cmds, "SendCmdAny")
+
+	var errs []error
+	for range cmds {
+		o := <-outcomes
+		if o.Err != nil {
+			errs = append(errs, o.Err)
+			continue
+		}
+		if err := o.Result.AsError(); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		return o.Result, nil
+	}
+	return nil, errors.Join(errs...)
+}
+
+// SendCmdQuorum sends every cmd in cmds concurrently and returns as soon as
+// n of them agree, i.e. n successful (StatusCodeOk) results have come back,
+// cancelling every cmd still outstanding exactly the way SendCmdAny does.
+// The n results are returned in the order they arrived, not cmds' order --
+// unlike SendCmdMulti, which waits for all of them anyway and can afford to
+// preserve it. SendCmdQuorum gives up as soon as reaching n successes is no
+// longer possible, returning ErrNoQuorum wrapping every failure seen so
+// far, rather than waiting for stragglers that can no longer change the
+// outcome.
+func (t *tenEnvImpl) SendCmdQuorum(cmds []Cmd, n int) ([]CmdResult, error) {
+	if n <= 0 || n > len(cmds) {
+		return nil, fmt.Errorf(
+			"ten: SendCmdQuorum: n must be between 1 and len(cmds) (%d), got %d", len(cmds), n,
+		)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	outcomes := t.sendCmdRacingOutcomes(ctx, cmds, "SendCmdQuorum")
+
+	var (
+		wins      []CmdResult
+		errs      []error
+		remaining = len(cmds)
+	)
+	for remaining > 0 {
+		o := <-outcomes
+		remaining--
+
+		err := o.Err
+		if err == nil {
+			err = o.Result.AsError()
+		}
+		if err == nil {
+			wins = append(wins, o.Result)
+			if len(wins) == n {
+				return wins, nil
+			}
+			continue
+		}
+
+		errs = append(errs, err)
+		if remaining < n-len(wins) {
+			return nil, fmt.Errorf("%w: %w", ErrNoQuorum, errors.Join(errs...))
+		}
+	}
+	return nil, fmt.Errorf("%w: %w", ErrNoQuorum, errors.Join(errs...))
+}
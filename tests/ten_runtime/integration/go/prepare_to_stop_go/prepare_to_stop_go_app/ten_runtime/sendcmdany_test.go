@@ -0,0 +1,118 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestTenEnv_SendCmdAnyReturnsFirstSuccess(t *testing.T) {
+	engine := NewEngine(newLifecycleExtension())
+	engine.SetSender(func(cmd Cmd, handler CmdResultHandler) {
+		name, _ := cmd.GetName()
+		if name == "bad" {
+			handler(engine.tenEnv, nil, errors.New("downstream refused it"))
+			return
+		}
+		result, _ := NewCmdResult(StatusCodeOk, cmd)
+		result.SetPropertyString("name", name)
+		handler(engine.tenEnv, result, nil)
+	})
+
+	bad, _ := NewCmd("bad")
+	good, _ := NewCmd("good")
+
+	result, err := engine.tenEnv.SendCmdAny([]Cmd{bad, good})
+	if err != nil {
+		t.Fatalf("SendCmdAny: %v", err)
+	}
+	if got, _ := result.GetPropertyString("name"); got != "good" {
+		t.Fatalf("SendCmdAny result name = %q, want %q", got, "good")
+	}
+}
+
+func TestTenEnv_SendCmdAnyCombinesErrorsIfNoneSucceed(t *testing.T) {
+	engine := NewEngine(newLifecycleExtension())
+	failWant := errors.New("downstream refused it")
+	engine.SetSender(func(cmd Cmd, handler CmdResultHandler) {
+		handler(engine.tenEnv, nil, failWant)
+	})
+
+	a, _ := NewCmd("a")
+	b, _ := NewCmd("b")
+
+	result, err := engine.tenEnv.SendCmdAny([]Cmd{a, b})
+	if result != nil {
+		t.Fatalf("SendCmdAny result = %+v, want nil", result)
+	}
+	if !errors.Is(err, failWant) {
+		t.Fatalf("SendCmdAny err = %v, want it to wrap %v", err, failWant)
+	}
+}
+
+func TestTenEnv_SendCmdAnyRejectsEmpty(t *testing.T) {
+	engine := NewEngine(newLifecycleExtension())
+
+	if _, err := engine.tenEnv.SendCmdAny(nil); err == nil {
+		t.Fatal("SendCmdAny(nil) = nil error, want an error")
+	}
+}
+
+func TestTenEnv_SendCmdQuorumReturnsOnceNAgree(t *testing.T) {
+	engine := NewEngine(newLifecycleExtension())
+	engine.SetSender(func(cmd Cmd, handler CmdResultHandler) {
+		name, _ := cmd.GetName()
+		if name == "bad" {
+			handler(engine.tenEnv, nil, errors.New("downstream refused it"))
+			return
+		}
+		result, _ := NewCmdResult(StatusCodeOk, cmd)
+		handler(engine.tenEnv, result, nil)
+	})
+
+	bad, _ := NewCmd("bad")
+	good1, _ := NewCmd("good1")
+	good2, _ := NewCmd("good2")
+
+	results, err := engine.tenEnv.SendCmdQuorum([]Cmd{bad, good1, good2}, 2)
+	if err != nil {
+		t.Fatalf("SendCmdQuorum: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("len(results) = %d, want 2", len(results))
+	}
+}
+
+func TestTenEnv_SendCmdQuorumFailsFastOnceUnreachable(t *testing.T) {
+	engine := NewEngine(newLifecycleExtension())
+	failWant := errors.New("downstream refused it")
+	engine.SetSender(func(cmd Cmd, handler CmdResultHandler) {
+		handler(engine.tenEnv, nil, failWant)
+	})
+
+	a, _ := NewCmd("a")
+	b, _ := NewCmd("b")
+
+	_, err := engine.tenEnv.SendCmdQuorum([]Cmd{a, b}, 2)
+	if !errors.Is(err, ErrNoQuorum) {
+		t.Fatalf("SendCmdQuorum err = %v, want it to wrap ErrNoQuorum", err)
+	}
+	if !errors.Is(err, failWant) {
+		t.Fatalf("SendCmdQuorum err = %v, want it to also wrap %v", err, failWant)
+	}
+}
+
+func TestTenEnv_SendCmdQuorumRejectsInvalidN(t *testing.T) {
+	engine := NewEngine(newLifecycleExtension())
+	a, _ := NewCmd("a")
+
+	if _, err := engine.tenEnv.SendCmdQuorum([]Cmd{a}, 0); err == nil {
+		t.Fatal("SendCmdQuorum with n=0 = nil error, want an error")
+	}
+	if _, err := engine.tenEnv.SendCmdQuorum([]Cmd{a}, 2); err == nil {
+		t.Fatal("SendCmdQuorum with n > len(cmds) = nil error, want an error")
+	}
+}
@@ -0,0 +1,55 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// SendCmdMulti fans cmds out concurrently via SendCmd, waits for every
+// one's final result, and returns them in cmds' order -- not whatever
+// order their results actually arrive in. A cmd that fails to send, or
+// whose final result carries an error, doesn't stop the others from being
+// collected: its slot in the returned slice is left nil and its error is
+// combined with any others via errors.Join, so a caller gets every
+// successful result alongside one error describing everything that went
+// wrong. A nil returned error means every cmd succeeded.
+func (t *tenEnvImpl) SendCmdMulti(cmds []Cmd) ([]CmdResult, error) {
+	results := make([]CmdResult, len(cmds))
+	errs := make([]error, len(cmds))
+
+	var wg sync.WaitGroup
+	wg.Add(len(cmds))
+	for i, cmd := range cmds {
+		i, cmd := i, cmd
+		if cmd == nil {
+			errs[i] = fmt.Errorf("ten: SendCmdMulti: cmds[%d] must not be nil", i)
+			wg.Done()
+			continue
+		}
+		if err := t.SendCmd(cmd, func(_ TenEnv, result CmdResult, err error) {
+			// A sender may invoke this once per intermediate result before
+			// a final one, the same as SendCmd's own doc comment
+			// describes; only the final one counts toward wg and is kept.
+			final := true
+			if result != nil {
+				final, _ = result.IsFinal()
+			}
+			results[i] = result
+			errs[i] = err
+			if final {
+				wg.Done()
+			}
+		}); err != nil {
+			errs[i] = err
+			wg.Done()
+		}
+	}
+	wg.Wait()
+
+	return results, errors.Join(errs...)
+}
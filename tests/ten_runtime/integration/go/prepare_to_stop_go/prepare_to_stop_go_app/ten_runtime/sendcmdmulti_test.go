@@ -0,0 +1,101 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestTenEnv_SendCmdMultiReturnsResultsInOrder(t *testing.T) {
+	engine := NewEngine(newLifecycleExtension())
+	engine.SetSender(func(cmd Cmd, handler CmdResultHandler) {
+		name, _ := cmd.GetName()
+		result, _ := NewCmdResult(StatusCodeOk, cmd)
+		result.SetPropertyString("name", name)
+		handler(engine.tenEnv, result, nil)
+	})
+
+	names := []string{"a", "b", "c"}
+	cmds := make([]Cmd, len(names))
+	for i, name := range names {
+		cmds[i], _ = NewCmd(name)
+	}
+
+	results, err := engine.tenEnv.SendCmdMulti(cmds)
+	if err != nil {
+		t.Fatalf("SendCmdMulti: %v", err)
+	}
+	if len(results) != len(names) {
+		t.Fatalf("len(results) = %d, want %d", len(results), len(names))
+	}
+	for i, name := range names {
+		got, gerr := results[i].GetPropertyString("name")
+		if gerr != nil || got != name {
+			t.Fatalf("results[%d] name = %q err %v, want %q", i, got, gerr, name)
+		}
+	}
+}
+
+func TestTenEnv_SendCmdMultiCombinesErrorsButKeepsSuccesses(t *testing.T) {
+	engine := NewEngine(newLifecycleExtension())
+	failWant := errors.New("downstream refused it")
+	engine.SetSender(func(cmd Cmd, handler CmdResultHandler) {
+		name, _ := cmd.GetName()
+		if name == "bad" {
+			handler(engine.tenEnv, nil, failWant)
+			return
+		}
+		result, _ := NewCmdResult(StatusCodeOk, cmd)
+		handler(engine.tenEnv, result, nil)
+	})
+
+	good, _ := NewCmd("good")
+	bad, _ := NewCmd("bad")
+	cmds := []Cmd{good, bad}
+
+	results, err := engine.tenEnv.SendCmdMulti(cmds)
+	if !errors.Is(err, failWant) {
+		t.Fatalf("SendCmdMulti err = %v, want it to wrap %v", err, failWant)
+	}
+	if results[0] == nil {
+		t.Fatalf("results[0] = nil, want the successful cmd's result preserved")
+	}
+	if results[1] != nil {
+		t.Fatalf("results[1] = %+v, want nil for the failed cmd", results[1])
+	}
+}
+
+func TestTenEnv_SendCmdMultiEmptyReturnsNoError(t *testing.T) {
+	engine := NewEngine(newLifecycleExtension())
+
+	results, err := engine.tenEnv.SendCmdMulti(nil)
+	if err != nil {
+		t.Fatalf("SendCmdMulti(nil) err = %v, want nil", err)
+	}
+	if len(results) != 0 {
+		t.Fatalf("SendCmdMulti(nil) results = %+v, want empty", results)
+	}
+}
+
+func TestTenEnv_SendCmdMultiRejectsNilCmd(t *testing.T) {
+	engine := NewEngine(newLifecycleExtension())
+	engine.SetSender(func(cmd Cmd, handler CmdResultHandler) {
+		result, _ := NewCmdResult(StatusCodeOk, cmd)
+		handler(engine.tenEnv, result, nil)
+	})
+
+	good, _ := NewCmd("good")
+	results, err := engine.tenEnv.SendCmdMulti([]Cmd{good, nil})
+	if err == nil {
+		t.Fatalf("SendCmdMulti with a nil cmd = nil error, want an error naming the offending index")
+	}
+	if results[0] == nil {
+		t.Fatalf("results[0] = nil, want the successful cmd's result preserved")
+	}
+	if results[1] != nil {
+		t.Fatalf("results[1] = %+v, want nil for the rejected nil cmd", results[1])
+	}
+}
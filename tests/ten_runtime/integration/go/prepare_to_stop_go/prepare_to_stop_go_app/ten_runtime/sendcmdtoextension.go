@@ -0,0 +1,138 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// ErrExtensionInstanceNotFound is returned by SendCmdToExtension when
+// extName doesn't name a currently live instance within the calling
+// extension's own graph -- either it was never created via
+// CreateExtension, or it already was torn down via DestroyExtension.
+var ErrExtensionInstanceNotFound = errors.New("ten: extension instance not found in this graph")
+
+// SendCmdToExtension implements TenEnv.SendCmdToExtension; see its doc
+// comment for the overall contract. extName is resolved against
+// dynamicInstances the same way GetGraphInfo resolves it (see
+// graphinfo.go), scoped to this extension's own graph ID -- there is no
+// app URI or graph ID parameter to target a different one, since this
+// simulation's dynamicInstances registry is itself keyed only by graph ID
+// and has no concept of a remote app to address across a process
+// boundary; a real ten_runtime engine would need an actual IPC path to
+// reach either, which has no counterpart here at all.
+func (t *tenEnvImpl) SendCmdToExtension(
+	extName string, cmd Cmd, handler CmdResultHandler,
+) error {
+	if cmd == nil {
+		return fmt.Errorf("ten: SendCmdToExtension: cmd must not be nil")
+	}
+	if err := t.engine.checkSendable(); err != nil {
+		return err
+	}
+
+	_, _, graphID, _ := t.engine.identityState()
+	member, ok := dynamicInstanceSnapshot(graphID)[extName]
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrExtensionInstanceNotFound, extName)
+	}
+
+	stampCmdSource(t.engine, cmd)
+	if handler != nil {
+		member.engine.registerCrossExtResultHandler(cmd, handler)
+	}
+	if err := member.engine.DeliverCmd(cmd); err != nil {
+		if handler != nil {
+			member.engine.popCrossExtResultHandler(cmd)
+		}
+		return err
+	}
+	return nil
+}
+
+// Ping implements TenEnv.Ping; see its doc comment for the overall
+// contract. It builds a fresh PingCmdName cmd, sends it via
+// SendCmdToExtension and blocks on the same single-buffered-channel
+// pattern SendCmdAndWait uses, timing the round trip with time.Now/
+// time.Since -- Uptime measures elapsed time the same direct way, rather
+// than through the injectable Clock in clock.go, since this is a plain
+// wall-clock measurement, not a timeout or backoff a test would want to
+// fast-forward.
+func (t *tenEnvImpl) Ping(extName string) (time.Duration, error) {
+	if atomic.LoadInt64(&t.engine.dispatchGoroutine) == goroutineID() {
+		return 0, ErrPingOnDispatchGoroutine
+	}
+
+	cmd, err := NewCmd(PingCmdName)
+	if err != nil {
+		return 0, err
+	}
+
+	resultCh := make(chan CmdResultOrError, 1)
+	start := time.Now()
+	if err := t.SendCmdToExtension(extName, cmd, func(_ TenEnv, result CmdResult, err error) {
+		resultCh <- CmdResultOrError{Result: result, IsFinal: true, Err: err}
+	}); err != nil {
+		return 0, err
+	}
+
+	v := <-resultCh
+	if v.Err != nil {
+		return 0, v.Err
+	}
+	rtt := time.Since(start)
+
+	status, err := v.Result.StatusCode()
+	if err != nil {
+		return 0, err
+	}
+	if status != StatusCodeOk {
+		return 0, fmt.Errorf("ten: Ping: %q answered with status %v, want StatusCodeOk", extName, status)
+	}
+	return rtt, nil
+}
+
+// registerCrossExtResultHandler records handler to run once cmd -- which
+// is about to be handed to this Engine's own extension via DeliverCmd on
+// another extension's behalf -- gets a final CmdResult back through
+// ReturnResult or ReturnResultEx(..., true). See
+// notifyCrossExtResultHandler for the other half.
+func (e *Engine) registerCrossExtResultHandler(cmd Cmd, handler CmdResultHandler) {
+	e.crossExtMu.Lock()
+	if e.crossExtPending == nil {
+		e.crossExtPending = map[Cmd]CmdResultHandler{}
+	}
+	e.crossExtPending[cmd] = handler
+	e.crossExtMu.Unlock()
+}
+
+// popCrossExtResultHandler removes and returns cmd's registered handler,
+// if any -- used both by notifyCrossExtResultHandler on the success path
+// and by SendCmdToExtension to undo a registration when DeliverCmd itself
+// fails before the target extension ever got a chance to call
+// ReturnResult/ReturnResultEx for cmd.
+func (e *Engine) popCrossExtResultHandler(cmd Cmd) (CmdResultHandler, bool) {
+	e.crossExtMu.Lock()
+	handler, ok := e.crossExtPending[cmd]
+	if ok {
+		delete(e.crossExtPending, cmd)
+	}
+	e.crossExtMu.Unlock()
+	return handler, ok
+}
+
+// notifyCrossExtResultHandler delivers result to cmd's registered
+// SendCmdToExtension handler, if one was registered for it; it's a no-op
+// for every other cmd, which is the common case -- most cmds this Engine's
+// extension handles were delivered through the graph's normal routing,
+// not SendCmdToExtension.
+func (e *Engine) notifyCrossExtResultHandler(cmd Cmd, result CmdResult) {
+	if handler, ok := e.popCrossExtResultHandler(cmd); ok {
+		handler(e.tenEnv, result, nil)
+	}
+}
@@ -0,0 +1,165 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTenEnv_SendCmdToExtensionDeliversResultBack(t *testing.T) {
+	reg, err := RegisterAddonAsExtension("synth89_echo", NewDefaultExtensionAddon(
+		func(name string) Extension { return echoExtension{} },
+	))
+	if err != nil {
+		t.Fatalf("RegisterAddonAsExtension: %v", err)
+	}
+	defer reg.ReleaseInstance("worker-1")
+	defer reg.Unregister()
+
+	engine := NewEngine(DefaultExtension{}, WithGraphID("g-send"))
+	engine.Init()
+	engine.Start()
+
+	if _, err := engine.TenEnv().CreateExtension("synth89_echo", "worker-1"); err != nil {
+		t.Fatalf("CreateExtension: %v", err)
+	}
+
+	cmd, _ := NewCmd("ping")
+	resultCh := make(chan CmdResult, 1)
+	errCh := make(chan error, 1)
+	err = engine.TenEnv().SendCmdToExtension("worker-1", cmd, func(_ TenEnv, result CmdResult, err error) {
+		resultCh <- result
+		errCh <- err
+	})
+	if err != nil {
+		t.Fatalf("SendCmdToExtension: %v", err)
+	}
+
+	select {
+	case result := <-resultCh:
+		if err := <-errCh; err != nil {
+			t.Fatalf("handler err = %v, want nil", err)
+		}
+		status, _ := result.StatusCode()
+		if status != StatusCodeOk {
+			t.Fatalf("StatusCode() = %v, want StatusCodeOk", status)
+		}
+	default:
+		t.Fatal("handler was never invoked")
+	}
+}
+
+// TestTenEnv_SendCmdToExtensionDeliversResultOnTargetsCommandTimeout guards
+// against fireCommandTimeout finalizing a timed-out cmd without also
+// notifying the caller's crossExtPending handler: without that call, a
+// SendCmdToExtension caller whose target lets SetCommandTimeout auto-fire
+// would hang forever even though the target's own side believes it
+// auto-failed the cmd cleanly.
+func TestTenEnv_SendCmdToExtensionDeliversResultOnTargetsCommandTimeout(t *testing.T) {
+	reg, err := RegisterAddonAsExtension("synth89_never_responds", NewDefaultExtensionAddon(
+		func(name string) Extension { return &neverRespondsExtension{timeout: 5 * time.Millisecond} },
+	))
+	if err != nil {
+		t.Fatalf("RegisterAddonAsExtension: %v", err)
+	}
+	defer reg.ReleaseInstance("worker-timeout")
+	defer reg.Unregister()
+
+	engine := NewEngine(DefaultExtension{}, WithGraphID("g-send-timeout"))
+	engine.Init()
+	engine.Start()
+
+	if _, err := engine.TenEnv().CreateExtension("synth89_never_responds", "worker-timeout"); err != nil {
+		t.Fatalf("CreateExtension: %v", err)
+	}
+
+	cmd, _ := NewCmd("ask")
+	resultCh := make(chan CmdResult, 1)
+	err = engine.TenEnv().SendCmdToExtension("worker-timeout", cmd, func(_ TenEnv, result CmdResult, err error) {
+		resultCh <- result
+	})
+	if err != nil {
+		t.Fatalf("SendCmdToExtension: %v", err)
+	}
+
+	select {
+	case result := <-resultCh:
+		status, _ := result.StatusCode()
+		if status != StatusCodeError {
+			t.Fatalf("StatusCode() = %v, want StatusCodeError", status)
+		}
+		detail, _ := result.GetPropertyString("detail")
+		if detail != "command timeout" {
+			t.Fatalf("detail = %q, want %q", detail, "command timeout")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("SendCmdToExtension's handler was never invoked after the target's command timeout fired")
+	}
+}
+
+func TestTenEnv_SendCmdToExtensionFailsForUnknownInstance(t *testing.T) {
+	engine := NewEngine(DefaultExtension{}, WithGraphID("g-send-missing"))
+	engine.Init()
+	engine.Start()
+
+	cmd, _ := NewCmd("ping")
+	err := engine.TenEnv().SendCmdToExtension("no-such-worker", cmd, nil)
+	if !errors.Is(err, ErrExtensionInstanceNotFound) {
+		t.Fatalf("SendCmdToExtension(unknown) = %v, want ErrExtensionInstanceNotFound", err)
+	}
+}
+
+func TestTenEnv_SendCmdToExtensionIsScopedToItsOwnGraph(t *testing.T) {
+	reg, err := RegisterAddonAsExtension("synth89_other_graph", NewDefaultExtensionAddon(
+		func(name string) Extension { return echoExtension{} },
+	))
+	if err != nil {
+		t.Fatalf("RegisterAddonAsExtension: %v", err)
+	}
+	defer reg.ReleaseInstance("worker-2")
+	defer reg.Unregister()
+
+	engineA := NewEngine(DefaultExtension{}, WithGraphID("g-a"))
+	engineA.Init()
+	engineA.Start()
+	if _, err := engineA.TenEnv().CreateExtension("synth89_other_graph", "worker-2"); err != nil {
+		t.Fatalf("CreateExtension: %v", err)
+	}
+
+	engineB := NewEngine(DefaultExtension{}, WithGraphID("g-b"))
+	engineB.Init()
+	engineB.Start()
+
+	cmd, _ := NewCmd("ping")
+	err = engineB.TenEnv().SendCmdToExtension("worker-2", cmd, nil)
+	if !errors.Is(err, ErrExtensionInstanceNotFound) {
+		t.Fatalf("SendCmdToExtension across graphs = %v, want ErrExtensionInstanceNotFound", err)
+	}
+}
+
+func TestTenEnv_SendCmdToExtensionRejectsANilCmd(t *testing.T) {
+	engine := NewEngine(DefaultExtension{}, WithGraphID("g-send-nil"))
+	engine.Init()
+	engine.Start()
+
+	if err := engine.TenEnv().SendCmdToExtension("worker-1", nil, nil); err == nil {
+		t.Fatal("SendCmdToExtension(nil cmd) = nil error, want one")
+	}
+}
+
+func TestTenEnv_SendCmdToExtensionAfterStopIsErrStopped(t *testing.T) {
+	engine := NewEngine(DefaultExtension{}, WithGraphID("g-send-stopped"))
+	engine.Init()
+	engine.Start()
+	engine.Stop()
+
+	cmd, _ := NewCmd("ping")
+	err := engine.TenEnv().SendCmdToExtension("worker-1", cmd, nil)
+	if !errors.Is(err, ErrStopped) {
+		t.Fatalf("SendCmdToExtension after Stop = %v, want ErrStopped", err)
+	}
+}
@@ -0,0 +1,150 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrDataQueueFull is returned (via SendDataWithAck's cb, or directly,
+// depending on the destination's DataQueuePolicy) when a destination's
+// queue is at capacity and its policy is DataQueuePolicyDrop or
+// DataQueuePolicyError.
+var ErrDataQueueFull = errors.New("ten: destination's data queue is full")
+
+// DataQueuePolicy selects what SendDataWithAck does when a destination's
+// queue, bounded via SetDataQueueCapacity, is already at capacity.
+type DataQueuePolicy int
+
+const (
+	// DataQueuePolicyBlock, the default, holds the calling goroutine
+	// inside SendDataWithAck until a slot frees up -- genuine backpressure,
+	// at the cost of stalling the producer. Appropriate when the producer
+	// has nothing better to do than wait, or checks DataQueueDepth first
+	// to avoid blocking at all.
+	DataQueuePolicyBlock DataQueuePolicy = iota
+
+	// DataQueuePolicyDrop admits nothing further once full: cb fires
+	// immediately with ErrDataQueueFull and the data is never sent, but
+	// SendDataWithAck itself still returns nil, matching every other Send*
+	// method's "the call started" contract.
+	DataQueuePolicyDrop
+
+	// DataQueuePolicyError returns ErrDataQueueFull directly from
+	// SendDataWithAck instead of invoking cb at all, for a caller that
+	// wants queue-full handled as a local, synchronous error rather than
+	// an asynchronous one.
+	DataQueuePolicyError
+)
+
+// dataQueue tracks SendDataWithAck admission for one destination.
+// capacity <= 0 means unbounded: depth is still tracked (DataQueueDepth
+// stays meaningful) but admission never blocks, drops or errors.
+type dataQueue struct {
+	mu       sync.Mutex
+	cond     *sync.Cond
+	depth    int
+	capacity int
+	policy   DataQueuePolicy
+}
+
+// dataQueueFor returns destExtension's queue, creating an unbounded one on
+// first use so DataQueueDepth always has something to report against.
+func (e *Engine) dataQueueFor(destExtension string) *dataQueue {
+	e.dataQueuesMu.Lock()
+	defer e.dataQueuesMu.Unlock()
+	if e.dataQueues == nil {
+		e.dataQueues = make(map[string]*dataQueue)
+	}
+	q, ok := e.dataQueues[destExtension]
+	if !ok {
+		q = &dataQueue{}
+		q.cond = sync.NewCond(&q.mu)
+		e.dataQueues[destExtension] = q
+	}
+	return q
+}
+
+// dataDest returns the destination extension name SendDataWithAck and
+// DataQueueDepth key their queue by: data's first GetDests entry, or "" if
+// none was set.
+func dataDest(data Data) string {
+	dests, _ := data.GetDests()
+	if len(dests) == 0 {
+		return ""
+	}
+	return dests[0].ExtensionName
+}
+
+func (t *tenEnvImpl) SetDataQueueCapacity(destExtension string, capacity int, policy DataQueuePolicy) {
+	q := t.engine.dataQueueFor(destExtension)
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.capacity = capacity
+	q.policy = policy
+	q.cond.Broadcast()
+}
+
+func (t *tenEnvImpl) DataQueueDepth(destExtension string) (int, error) {
+	q := t.engine.dataQueueFor(destExtension)
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.depth, nil
+}
+
+// SendDataWithAck admits data into its destination's queue (see
+// SetDataQueueCapacity), then sends it via the Engine's dataSender; cb
+// fires once dataSender reports the destination has actually accepted it,
+// releasing the queue slot either way. See DataQueuePolicy for what happens
+// when the destination is already full.
+func (t *tenEnvImpl) SendDataWithAck(data Data, cb func(TenEnv, error)) error {
+	if data == nil {
+		return fmt.Errorf("ten: SendDataWithAck: data must not be nil")
+	}
+	if err := t.engine.checkSendable(); err != nil {
+		return err
+	}
+	stampDataSource(t.engine, data)
+	q := t.engine.dataQueueFor(dataDest(data))
+
+	q.mu.Lock()
+	for q.capacity > 0 && q.depth >= q.capacity {
+		switch q.policy {
+		case DataQueuePolicyDrop:
+			q.mu.Unlock()
+			cb(t, ErrDataQueueFull)
+			return nil
+		case DataQueuePolicyError:
+			q.mu.Unlock()
+			return ErrDataQueueFull
+		default: // DataQueuePolicyBlock
+			q.cond.Wait()
+		}
+	}
+	q.depth++
+	q.mu.Unlock()
+
+	release := func() {
+		q.mu.Lock()
+		q.depth--
+		q.cond.Broadcast()
+		q.mu.Unlock()
+	}
+
+	resolve, _ := t.engine.registerPending(func() {
+		release()
+		cb(t, ErrShutdownTimeout)
+	})
+	t.engine.dataSender(data, func(tenEnv TenEnv, err error) {
+		if !resolve() {
+			return
+		}
+		release()
+		cb(tenEnv, err)
+	})
+	return nil
+}
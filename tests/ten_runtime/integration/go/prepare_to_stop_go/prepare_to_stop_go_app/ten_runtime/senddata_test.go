@@ -0,0 +1,247 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func newStartedEngineForSendData(t *testing.T) *Engine {
+	t.Helper()
+	engine := NewEngine(DefaultExtension{})
+	engine.Init()
+	engine.Start()
+	return engine
+}
+
+// waitForDepth polls DataQueueDepth(dest) until it reaches want, failing
+// the test if it doesn't happen within a second. The Engine's dataSender
+// in these tests runs synchronously on whatever goroutine called
+// SendDataWithAck, so a blocking sender is exercised from a separate
+// goroutine and the depth change is observed here instead of assumed.
+func waitForDepth(t *testing.T, engine *Engine, dest string, want int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for {
+		if depth, _ := engine.TenEnv().DataQueueDepth(dest); depth == want {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("DataQueueDepth(%q) never reached %d", dest, want)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestTenEnv_SendDataInvokesCbOnSuccess(t *testing.T) {
+	engine := newStartedEngineForSendData(t)
+	data, _ := NewData()
+
+	done := make(chan error, 1)
+	if err := engine.TenEnv().SendData(data, func(_ TenEnv, err error) { done <- err }); err != nil {
+		t.Fatalf("SendData: %v", err)
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("SendData cb err = %v, want nil", err)
+	}
+}
+
+func TestTenEnv_SendDataWithAckAdmitsImmediatelyWhenUnbounded(t *testing.T) {
+	engine := newStartedEngineForSendData(t)
+	data, _ := NewData()
+
+	done := make(chan error, 1)
+	if err := engine.TenEnv().SendDataWithAck(data, func(_ TenEnv, err error) { done <- err }); err != nil {
+		t.Fatalf("SendDataWithAck: %v", err)
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("SendDataWithAck cb err = %v, want nil", err)
+	}
+
+	depth, err := engine.TenEnv().DataQueueDepth("")
+	if err != nil {
+		t.Fatalf("DataQueueDepth: %v", err)
+	}
+	if depth != 0 {
+		t.Fatalf("DataQueueDepth() after ack = %d, want 0", depth)
+	}
+}
+
+func TestTenEnv_DataQueueDepthReflectsInFlightSends(t *testing.T) {
+	engine := newStartedEngineForSendData(t)
+
+	release := make(chan struct{})
+	engine.SetDataSender(func(data Data, cb func(TenEnv, error)) {
+		<-release
+		cb(engine.TenEnv(), nil)
+	})
+
+	data, _ := NewData()
+	data.SetDests(Loc{ExtensionName: "sink"})
+	done := make(chan error, 1)
+	go func() {
+		engine.TenEnv().SendDataWithAck(data, func(_ TenEnv, err error) { done <- err })
+	}()
+
+	waitForDepth(t, engine, "sink", 1)
+
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatalf("SendDataWithAck cb err = %v, want nil", err)
+	}
+	waitForDepth(t, engine, "sink", 0)
+}
+
+func TestTenEnv_SetDataQueueCapacityDropPolicyRejectsWhenFull(t *testing.T) {
+	engine := newStartedEngineForSendData(t)
+	engine.TenEnv().SetDataQueueCapacity("sink", 1, DataQueuePolicyDrop)
+
+	release := make(chan struct{})
+	engine.SetDataSender(func(data Data, cb func(TenEnv, error)) {
+		<-release
+		cb(engine.TenEnv(), nil)
+	})
+
+	first, _ := NewData()
+	first.SetDests(Loc{ExtensionName: "sink"})
+	firstDone := make(chan error, 1)
+	go func() {
+		engine.TenEnv().SendDataWithAck(first, func(_ TenEnv, err error) { firstDone <- err })
+	}()
+	waitForDepth(t, engine, "sink", 1)
+
+	second, _ := NewData()
+	second.SetDests(Loc{ExtensionName: "sink"})
+	secondDone := make(chan error, 1)
+	if err := engine.TenEnv().SendDataWithAck(second, func(_ TenEnv, err error) { secondDone <- err }); err != nil {
+		t.Fatalf("SendDataWithAck (second): %v", err)
+	}
+	if err := <-secondDone; !errors.Is(err, ErrDataQueueFull) {
+		t.Fatalf("second send cb err = %v, want ErrDataQueueFull", err)
+	}
+
+	close(release)
+	if err := <-firstDone; err != nil {
+		t.Fatalf("first send cb err = %v, want nil", err)
+	}
+}
+
+func TestTenEnv_SetDataQueueCapacityErrorPolicyReturnsSynchronously(t *testing.T) {
+	engine := newStartedEngineForSendData(t)
+	engine.TenEnv().SetDataQueueCapacity("sink", 1, DataQueuePolicyError)
+
+	release := make(chan struct{})
+	engine.SetDataSender(func(data Data, cb func(TenEnv, error)) {
+		<-release
+		cb(engine.TenEnv(), nil)
+	})
+
+	first, _ := NewData()
+	first.SetDests(Loc{ExtensionName: "sink"})
+	firstDone := make(chan error, 1)
+	go func() {
+		engine.TenEnv().SendDataWithAck(first, func(_ TenEnv, err error) { firstDone <- err })
+	}()
+	waitForDepth(t, engine, "sink", 1)
+
+	second, _ := NewData()
+	second.SetDests(Loc{ExtensionName: "sink"})
+	err := engine.TenEnv().SendDataWithAck(second, func(TenEnv, error) {
+		t.Fatalf("cb should not be invoked under DataQueuePolicyError")
+	})
+	if !errors.Is(err, ErrDataQueueFull) {
+		t.Fatalf("SendDataWithAck (second) = %v, want ErrDataQueueFull", err)
+	}
+
+	close(release)
+	if err := <-firstDone; err != nil {
+		t.Fatalf("first send cb err = %v, want nil", err)
+	}
+}
+
+func TestTenEnv_SetDataQueueCapacityBlockPolicyWaitsForRoom(t *testing.T) {
+	engine := newStartedEngineForSendData(t)
+	engine.TenEnv().SetDataQueueCapacity("sink", 1, DataQueuePolicyBlock)
+
+	release := make(chan struct{})
+	engine.SetDataSender(func(data Data, cb func(TenEnv, error)) {
+		<-release
+		cb(engine.TenEnv(), nil)
+	})
+
+	first, _ := NewData()
+	first.SetDests(Loc{ExtensionName: "sink"})
+	firstDone := make(chan error, 1)
+	go func() {
+		engine.TenEnv().SendDataWithAck(first, func(_ TenEnv, err error) { firstDone <- err })
+	}()
+	waitForDepth(t, engine, "sink", 1)
+
+	second, _ := NewData()
+	second.SetDests(Loc{ExtensionName: "sink"})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	secondAdmittedAt := make(chan time.Time, 1)
+	go func() {
+		defer wg.Done()
+		engine.TenEnv().SendDataWithAck(second, func(_ TenEnv, err error) {
+			secondAdmittedAt <- time.Now()
+		})
+	}()
+
+	// Give the blocked call a moment to actually be waiting before
+	// releasing the first send, so this test can't pass by accident if
+	// SendDataWithAck stopped blocking altogether.
+	time.Sleep(20 * time.Millisecond)
+	releasedAt := time.Now()
+	close(release)
+
+	select {
+	case admittedAt := <-secondAdmittedAt:
+		if admittedAt.Before(releasedAt) {
+			t.Fatalf("second send was admitted before the first slot was released")
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("blocked SendDataWithAck never unblocked after the first slot freed up")
+	}
+	wg.Wait()
+	if err := <-firstDone; err != nil {
+		t.Fatalf("first send cb err = %v, want nil", err)
+	}
+}
+
+func TestTenEnv_SendDataWithAckDestinationsAreIndependentQueues(t *testing.T) {
+	engine := newStartedEngineForSendData(t)
+	engine.TenEnv().SetDataQueueCapacity("a", 1, DataQueuePolicyError)
+	engine.TenEnv().SetDataQueueCapacity("b", 1, DataQueuePolicyError)
+
+	release := make(chan struct{})
+	engine.SetDataSender(func(data Data, cb func(TenEnv, error)) {
+		<-release
+		cb(engine.TenEnv(), nil)
+	})
+
+	toA, _ := NewData()
+	toA.SetDests(Loc{ExtensionName: "a"})
+	go func() {
+		engine.TenEnv().SendDataWithAck(toA, func(TenEnv, error) {})
+	}()
+	waitForDepth(t, engine, "a", 1)
+
+	toB, _ := NewData()
+	toB.SetDests(Loc{ExtensionName: "b"})
+	toBErr := make(chan error, 1)
+	go func() {
+		toBErr <- engine.TenEnv().SendDataWithAck(toB, func(TenEnv, error) {})
+	}()
+
+	close(release)
+	if err := <-toBErr; err != nil {
+		t.Fatalf("SendDataWithAck to a separate destination = %v, want nil -- queues are per-destination", err)
+	}
+}
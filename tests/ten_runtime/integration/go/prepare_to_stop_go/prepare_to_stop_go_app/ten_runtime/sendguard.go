@@ -0,0 +1,29 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import "fmt"
+
+// checkSendable is the outbound counterpart to DeliverCmd's own
+// stateStopping/stateStopped rejection: SendCmd, SendCmdEx, SendData,
+// SendAudioFrame, SendVideoFrame, SendDataWithAck, ReturnResult and
+// ReturnResultEx all call it first so a caller gets the same synchronous
+// ErrStopped/ErrInvalidState pair DeliverCmd already returns, instead of
+// starting a send (or finalizing a cmd) against an extension that's
+// already shutting down or gone. TenEnv.IsStopped reports the same
+// condition proactively, for a caller that wants to check before it would
+// otherwise have to handle the error -- e.g. a goroutine spawned from
+// OnCmd that outlives OnStop.
+func (e *Engine) checkSendable() error {
+	e.mu.Lock()
+	state := e.state
+	e.mu.Unlock()
+
+	switch state {
+	case stateStopping, stateStopped:
+		return fmt.Errorf("%w: %w: extension is stopped", ErrInvalidState, ErrStopped)
+	}
+	return nil
+}
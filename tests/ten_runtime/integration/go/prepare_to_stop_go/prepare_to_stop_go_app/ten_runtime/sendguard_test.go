@@ -0,0 +1,141 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestTenEnv_SendCmdRejectsANilCmdSynchronously(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+	engine.Init()
+	engine.Start()
+
+	if err := engine.TenEnv().SendCmd(nil, func(TenEnv, CmdResult, error) {}); err == nil {
+		t.Fatal("SendCmd(nil, ...) = nil, want an error")
+	}
+}
+
+func TestTenEnv_SendCmdAfterStopIsErrStopped(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+	engine.Init()
+	engine.Start()
+	engine.Stop()
+
+	cmd, _ := NewCmd("ping")
+	err := engine.TenEnv().SendCmd(cmd, func(TenEnv, CmdResult, error) {})
+	if !errors.Is(err, ErrStopped) {
+		t.Fatalf("SendCmd after Stop = %v, want ErrStopped", err)
+	}
+}
+
+func TestTenEnv_SendCmdExAfterStopIsErrStopped(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+	engine.Init()
+	engine.Start()
+	engine.Stop()
+
+	cmd, _ := NewCmd("ping")
+	err := engine.TenEnv().SendCmdEx(cmd, func(TenEnv, CmdResult, bool, error) {})
+	if !errors.Is(err, ErrStopped) {
+		t.Fatalf("SendCmdEx after Stop = %v, want ErrStopped", err)
+	}
+}
+
+func TestTenEnv_SendCmdChanAfterStopIsErrStopped(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+	engine.Init()
+	engine.Start()
+	engine.Stop()
+
+	cmd, _ := NewCmd("ping")
+	_, err := engine.TenEnv().SendCmdChan(cmd)
+	if !errors.Is(err, ErrStopped) {
+		t.Fatalf("SendCmdChan after Stop = %v, want ErrStopped", err)
+	}
+}
+
+func TestTenEnv_SendDataAfterStopIsErrStopped(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+	engine.Init()
+	engine.Start()
+	engine.Stop()
+
+	data, _ := NewData()
+	err := engine.TenEnv().SendData(data, func(TenEnv, error) {})
+	if !errors.Is(err, ErrStopped) {
+		t.Fatalf("SendData after Stop = %v, want ErrStopped", err)
+	}
+}
+
+func TestTenEnv_SendAudioFrameAfterStopIsErrStopped(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+	engine.Init()
+	engine.Start()
+	engine.Stop()
+
+	frame, _ := NewAudioFrame("audio")
+	err := engine.TenEnv().SendAudioFrame(frame, func(TenEnv, error) {})
+	if !errors.Is(err, ErrStopped) {
+		t.Fatalf("SendAudioFrame after Stop = %v, want ErrStopped", err)
+	}
+}
+
+func TestTenEnv_SendVideoFrameAfterStopIsErrStopped(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+	engine.Init()
+	engine.Start()
+	engine.Stop()
+
+	frame, _ := NewVideoFrame("video")
+	err := engine.TenEnv().SendVideoFrame(frame, func(TenEnv, error) {})
+	if !errors.Is(err, ErrStopped) {
+		t.Fatalf("SendVideoFrame after Stop = %v, want ErrStopped", err)
+	}
+}
+
+func TestTenEnv_IsStoppedReflectsEngineLifecycle(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+	engine.Init()
+	engine.Start()
+
+	if engine.TenEnv().IsStopped() {
+		t.Fatal("IsStopped() = true before Stop, want false")
+	}
+
+	engine.Stop()
+
+	if !engine.TenEnv().IsStopped() {
+		t.Fatal("IsStopped() = false after Stop, want true")
+	}
+}
+
+func TestTenEnv_ReturnResultRejectsANilResultSynchronously(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+	engine.Init()
+	engine.Start()
+
+	cmd, _ := NewCmd("ping")
+	if err := engine.TenEnv().ReturnResult(nil, cmd); err == nil {
+		t.Fatal("ReturnResult(nil, ...) = nil, want an error")
+	}
+}
+
+func TestTenEnv_ReturnResultAfterStopIsErrStopped(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+	engine.Init()
+	engine.Start()
+
+	cmd, _ := NewCmd("ping")
+	result, _ := NewCmdResult(StatusCodeOk, cmd)
+
+	engine.Stop()
+
+	err := engine.TenEnv().ReturnResult(result, cmd)
+	if !errors.Is(err, ErrStopped) {
+		t.Fatalf("ReturnResult after Stop = %v, want ErrStopped", err)
+	}
+}
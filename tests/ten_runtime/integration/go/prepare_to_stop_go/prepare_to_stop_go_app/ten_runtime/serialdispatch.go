@@ -0,0 +1,41 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+// WithSerialDispatch opts an Engine into guaranteeing that OnCmd is never
+// invoked concurrently with another OnCmd call, or with OnStop, for this
+// extension instance -- the opposite end of the spectrum from
+// WithAsyncDispatch. It's for an extension that mutates its own state
+// from OnCmd without a lock of its own, on the assumption that nothing
+// else can be touching that state at the same time; without this option,
+// that assumption only holds if every caller happens to deliver cmds one
+// at a time, which nothing in this package enforces on its own.
+//
+// Under contention -- two cmds delivered to this extension from different
+// goroutines at close to the same time -- whichever goroutine's OnCmd
+// call reaches the front of the queue first runs first; Go's runtime
+// switches a contended sync.Mutex into a strictly FIFO handoff once a
+// waiter has been blocked for more than a millisecond, so ordering under
+// real contention is first-come-first-served, not best-effort.
+//
+// This only serializes the OnCmd call itself: a handler that wants to do
+// slow work off to the side can still spawn its own goroutine from OnCmd,
+// exactly as it would without this option, and that goroutine runs
+// unserialized, same as always. What this option prevents is the runtime
+// invoking OnCmd itself a second time before the first call returns.
+//
+// OnStop is serialized against OnCmd the same way, so an in-flight OnCmd
+// is guaranteed to have returned before OnStop is called, and no OnCmd
+// dispatched after Stop begins can run concurrently with it either.
+//
+// If both WithSerialDispatch and WithAsyncDispatch are set, serial
+// dispatch takes priority -- an extension that's asked the runtime to
+// guarantee no concurrent OnCmd calls shouldn't have that guarantee
+// silently undermined by also asking for async dispatch.
+func WithSerialDispatch(enabled bool) EngineOption {
+	return func(e *Engine) {
+		e.serialDispatch = enabled
+	}
+}
@@ -0,0 +1,126 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWithSerialDispatch_NeverRunsOverlappingOnCmdCalls(t *testing.T) {
+	var inFlight int32
+	var sawOverlap int32
+
+	ext := &blockingSendExtension{onCmd: func(tenEnv TenEnv, cmd Cmd) {
+		if atomic.AddInt32(&inFlight, 1) > 1 {
+			atomic.StoreInt32(&sawOverlap, 1)
+		}
+		time.Sleep(5 * time.Millisecond)
+		atomic.AddInt32(&inFlight, -1)
+		result, _ := NewCmdResult(StatusCodeOk, cmd)
+		tenEnv.ReturnResult(result, cmd)
+	}}
+	engine := NewEngine(ext, WithSerialDispatch(true))
+	if err := engine.Init(); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	if err := engine.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			cmd, _ := NewCmd("greet")
+			if err := engine.DeliverCmd(cmd); err != nil {
+				t.Errorf("DeliverCmd: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if atomic.LoadInt32(&sawOverlap) != 0 {
+		t.Fatal("two OnCmd calls ran concurrently despite WithSerialDispatch(true)")
+	}
+}
+
+func TestWithSerialDispatch_OnStopWaitsOutInFlightOnCmd(t *testing.T) {
+	onCmdEntered := make(chan struct{})
+	release := make(chan struct{})
+
+	blockingExt := &blockingSendExtension{onCmd: func(tenEnv TenEnv, cmd Cmd) {
+		close(onCmdEntered)
+		<-release
+		result, _ := NewCmdResult(StatusCodeOk, cmd)
+		tenEnv.ReturnResult(result, cmd)
+	}}
+	engine := NewEngine(blockingExt, WithSerialDispatch(true))
+	if err := engine.Init(); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	if err := engine.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	cmd, _ := NewCmd("greet")
+	go engine.DeliverCmd(cmd)
+
+	select {
+	case <-onCmdEntered:
+	case <-time.After(time.Second):
+		t.Fatal("OnCmd never entered")
+	}
+
+	stopReturned := make(chan error, 1)
+	go func() { stopReturned <- engine.Stop() }()
+
+	select {
+	case <-stopReturned:
+		t.Fatal("Stop returned while OnCmd was still blocked in-flight")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	close(release)
+
+	select {
+	case err := <-stopReturned:
+		if err != nil {
+			t.Fatalf("Stop: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Stop never returned after the in-flight OnCmd released")
+	}
+}
+
+func TestWithSerialDispatch_HandlerCanStillSpawnItsOwnGoroutine(t *testing.T) {
+	bgDone := make(chan struct{})
+	ext := &blockingSendExtension{onCmd: func(tenEnv TenEnv, cmd Cmd) {
+		go func() { close(bgDone) }()
+		result, _ := NewCmdResult(StatusCodeOk, cmd)
+		tenEnv.ReturnResult(result, cmd)
+	}}
+	engine := NewEngine(ext, WithSerialDispatch(true))
+	if err := engine.Init(); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	if err := engine.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	cmd, _ := NewCmd("greet")
+	if err := engine.DeliverCmd(cmd); err != nil {
+		t.Fatalf("DeliverCmd: %v", err)
+	}
+
+	select {
+	case <-bgDone:
+	case <-time.After(time.Second):
+		t.Fatal("OnCmd's own spawned goroutine never ran")
+	}
+}
@@ -0,0 +1,385 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// hangingStopExtension never signals OnStopDone on its own, simulating an
+// OnStop that's waiting on a peer that has crashed.
+type hangingStopExtension struct {
+	DefaultExtension
+}
+
+func (hangingStopExtension) OnStop(tenEnv TenEnv) {}
+
+func TestEngineStop_DeadlineForcesOnStopDone(t *testing.T) {
+	engine := NewEngine(
+		hangingStopExtension{},
+		WithShutdownDeadline(20*time.Millisecond),
+	)
+
+	if err := engine.Init(); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	if err := engine.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- engine.Stop() }()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Stop: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Stop did not return after the shutdown deadline expired")
+	}
+}
+
+func TestEngineStop_CancelsInFlightSendCmdOnTimeout(t *testing.T) {
+	block := make(chan struct{})
+	senderStarted := make(chan struct{})
+	engine := NewEngine(
+		hangingStopExtension{},
+		WithShutdownDeadline(20*time.Millisecond),
+	)
+	engine.SetSender(func(cmd Cmd, handler CmdResultHandler) {
+		// A downstream responder that never answers -- the deadline must
+		// still unblock the caller.
+		close(senderStarted)
+		<-block
+	})
+
+	if err := engine.Init(); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	if err := engine.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	cmd, _ := NewCmd("long-running")
+	gotErr := make(chan error, 1)
+	go func() {
+		// Real extensions call SendCmd from their own goroutine (see
+		// bExtension.OnCmd); the downstream responder here blocks until
+		// the test closes block, simulating a peer that never replies.
+		engine.tenEnv.SendCmd(
+			cmd,
+			func(tenEnv TenEnv, result CmdResult, err error) {
+				gotErr <- err
+			},
+		)
+	}()
+	<-senderStarted
+
+	if err := engine.Stop(); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+	close(block)
+
+	select {
+	case err := <-gotErr:
+		if !errors.Is(err, ErrShutdownTimeout) {
+			t.Fatalf("SendCmd callback err = %v, want ErrShutdownTimeout", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("SendCmd callback was never invoked")
+	}
+}
+
+func TestEngineStop_WithPropertiesSetsDeadline(t *testing.T) {
+	engine := NewEngine(
+		hangingStopExtension{},
+		WithProperties(map[string]any{
+			PropertyGracefulShutdownTimeoutMs: 20,
+		}),
+	)
+
+	if err := engine.Init(); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	if err := engine.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- engine.Stop() }()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Stop: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Stop did not return after the property-configured deadline expired")
+	}
+}
+
+// stopTimeoutExtension sets its stop timeout at runtime, from OnInit,
+// rather than at Engine construction -- the pattern SetStopTimeout exists
+// for.
+type stopTimeoutExtension struct {
+	DefaultExtension
+
+	timeout time.Duration
+}
+
+func (s stopTimeoutExtension) OnInit(tenEnv TenEnv) {
+	tenEnv.SetStopTimeout(s.timeout)
+	tenEnv.OnInitDone()
+}
+
+func (stopTimeoutExtension) OnStop(tenEnv TenEnv) {}
+
+func TestTenEnv_SetStopTimeoutForcesOnStopDone(t *testing.T) {
+	engine := NewEngine(stopTimeoutExtension{timeout: 20 * time.Millisecond})
+
+	if err := engine.Init(); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	if err := engine.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- engine.Stop() }()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Stop: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Stop did not return after the runtime-configured timeout expired")
+	}
+}
+
+func TestTenEnv_SetStopTimeoutCancelsShutdownContext(t *testing.T) {
+	cancelled := make(chan struct{})
+	ext := &stopContextExtension{cancelled: cancelled}
+	engine := NewEngine(ext)
+	engine.tenEnv.SetStopTimeout(20 * time.Millisecond)
+
+	if err := engine.Init(); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	if err := engine.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- engine.Stop() }()
+
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Fatal("ShutdownContext was never cancelled after the stop timeout expired")
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+}
+
+// stopContextExtension waits on tenEnv.ShutdownContext() instead of on its
+// own channel, so it observes the timeout SetStopTimeout configures rather
+// than waiting forever for an OnStopDone it never calls itself.
+type stopContextExtension struct {
+	DefaultExtension
+
+	cancelled chan struct{}
+}
+
+func (s *stopContextExtension) OnStop(tenEnv TenEnv) {
+	go func() {
+		<-tenEnv.ShutdownContext().Done()
+		close(s.cancelled)
+	}()
+}
+
+func TestTenEnv_ContextIsSameInstanceAcrossCalls(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+	if engine.tenEnv.Context() != engine.tenEnv.Context() {
+		t.Fatal("Context() returned a different instance on a second call")
+	}
+}
+
+func TestTenEnv_ContextIsCancelledAtStartOfOnStop(t *testing.T) {
+	cancelledDuringOnStop := make(chan bool, 1)
+	ext := &lifeContextExtension{cancelledDuringOnStop: cancelledDuringOnStop}
+	engine := NewEngine(ext)
+
+	if err := engine.Init(); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	if err := engine.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if err := engine.tenEnv.Context().Err(); err != nil {
+		t.Fatalf("Context().Err() = %v before Stop, want nil", err)
+	}
+
+	if err := engine.Stop(); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+
+	select {
+	case cancelled := <-cancelledDuringOnStop:
+		if !cancelled {
+			t.Fatal("Context() was not yet cancelled when OnStop ran")
+		}
+	default:
+		t.Fatal("OnStop never ran")
+	}
+	if engine.tenEnv.Context().Err() == nil {
+		t.Fatal("Context().Err() = nil after Stop, want context.Canceled")
+	}
+}
+
+// lifeContextExtension records, from inside OnStop itself, whether
+// tenEnv.Context() is already cancelled -- proving cancellation happens
+// before OnStop runs, not merely by the time Stop returns.
+type lifeContextExtension struct {
+	DefaultExtension
+
+	cancelledDuringOnStop chan bool
+}
+
+func (l *lifeContextExtension) OnStop(tenEnv TenEnv) {
+	l.cancelledDuringOnStop <- (tenEnv.Context().Err() != nil)
+	tenEnv.OnStopDone()
+}
+
+func TestEngineStop_NoDeadlineWaitsIndefinitely(t *testing.T) {
+	ext := newLifecycleExtension()
+	engine := NewEngine(ext)
+
+	if err := engine.Init(); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	if err := engine.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- engine.Stop() }()
+
+	select {
+	case <-done:
+		t.Fatal("Stop returned before OnStopDone, with no deadline set")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(ext.stopChan)
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Stop: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Stop did not return after OnStopDone")
+	}
+}
+
+func TestEngineStop_StopWatchdogLogsWithoutForcing(t *testing.T) {
+	SetStopWatchdog(20 * time.Millisecond)
+	defer SetStopWatchdog(0)
+
+	ext := newLifecycleExtension()
+	engine := NewEngine(ext, WithExtensionName("watched-ext"))
+	if err := engine.Init(); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	if err := engine.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	var sink lockedBuffer
+	engine.TenEnv().SetLogSink(&sink, LogFormatText)
+
+	done := make(chan error, 1)
+	go func() { done <- engine.Stop() }()
+
+	select {
+	case <-done:
+		t.Fatal("Stop returned before OnStopDone, with no deadline and a log-only watchdog")
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	if got := sink.String(); !strings.Contains(got, "watched-ext") || !strings.Contains(got, "stop watchdog") {
+		t.Fatalf("log sink content = %q, want it to name the extension and mention the stop watchdog", got)
+	}
+
+	close(ext.stopChan)
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Stop: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Stop did not return after OnStopDone, even though the watchdog is log-only")
+	}
+}
+
+func TestEngineStop_StopWatchdogForceAfterForcesOnStopDone(t *testing.T) {
+	SetStopWatchdog(10*time.Millisecond, WithStopWatchdogForceAfter(10*time.Millisecond))
+	defer SetStopWatchdog(0)
+
+	engine := NewEngine(hangingStopExtension{})
+	if err := engine.Init(); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	if err := engine.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- engine.Stop() }()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Stop: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Stop did not return after the watchdog's force-after delay expired")
+	}
+}
+
+func TestEngineStop_NoStopWatchdogConfiguredWaitsIndefinitely(t *testing.T) {
+	ext := newLifecycleExtension()
+	engine := NewEngine(ext)
+	if err := engine.Init(); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	if err := engine.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- engine.Stop() }()
+
+	select {
+	case <-done:
+		t.Fatal("Stop returned before OnStopDone, with no watchdog and no deadline configured")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	close(ext.stopChan)
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Stop: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Stop did not return after OnStopDone")
+	}
+}
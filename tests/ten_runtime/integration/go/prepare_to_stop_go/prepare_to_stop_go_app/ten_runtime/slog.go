@@ -0,0 +1,151 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+// slogHandler adapts a TenEnv into an slog.Handler, so code already
+// written against log/slog -- slog.New(ten.NewSlogHandler(tenEnv)) --
+// runs inside an extension unchanged and its output flows through the
+// same TenEnv.LogFields pipeline as everything else the extension logs.
+// attrs and groups accumulate across WithAttrs/WithGroup exactly like the
+// slog.Handler contract requires: each call returns a new handler with
+// its own copy, leaving the receiver (and any other handler derived from
+// it) unaffected.
+type slogHandler struct {
+	tenEnv TenEnv
+	attrs  []slog.Attr
+	groups []string
+}
+
+// NewSlogHandler returns an slog.Handler that forwards every record to
+// tenEnv.LogFields: r.Level maps to the nearest LogLevel (see
+// levelFromSlog), r.Message becomes the Field msg, and every attribute --
+// the handler's own accumulated ones from WithAttrs, then the record's
+// own -- becomes a Field, dot-prefixed with whatever WithGroup groups are
+// currently open (so slog's nested-group attributes survive as flat,
+// dotted field keys rather than being lost or double-encoded). Field
+// rendering itself is LogFields'/formatFields' concern, same as every
+// other structured logging call in this package: with the default
+// LogFormatText it becomes "key=value" text appended to the message.
+func NewSlogHandler(tenEnv TenEnv) slog.Handler {
+	return &slogHandler{tenEnv: tenEnv}
+}
+
+// levelFromSlog maps l to whichever LogLevel its value is closest to,
+// using the same relative spacing slog itself uses between its four
+// standard levels (Debug=-4, Info=0, Warn=4, Error=8) extended one step
+// in each direction to cover LogLevelVerbose and LogLevelFatal, so a
+// custom slog.Level below Debug or above Error still lands somewhere
+// sensible instead of clamping to Debug or Error.
+func levelFromSlog(l slog.Level) LogLevel {
+	switch {
+	case l < slog.LevelDebug-2:
+		return LogLevelVerbose
+	case l < slog.LevelInfo-2:
+		return LogLevelDebug
+	case l < slog.LevelWarn-2:
+		return LogLevelInfo
+	case l < slog.LevelError-2:
+		return LogLevelWarn
+	case l < slog.LevelError+2:
+		return LogLevelError
+	default:
+		return LogLevelFatal
+	}
+}
+
+func (h *slogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return h.tenEnv.IsLogLevelEnabled(levelFromSlog(level))
+}
+
+func (h *slogHandler) Handle(_ context.Context, r slog.Record) error {
+	fields := make([]Field, 0, len(h.attrs)+r.NumAttrs())
+	for _, a := range h.attrs {
+		fields = appendSlogAttr(fields, h.groups, a)
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		fields = appendSlogAttr(fields, h.groups, a)
+		return true
+	})
+	h.tenEnv.LogFields(levelFromSlog(r.Level), r.Message, fields...)
+	return nil
+}
+
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	next := *h
+	next.attrs = append(append([]slog.Attr(nil), h.attrs...), attrs...)
+	return &next
+}
+
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	next := *h
+	next.groups = append(append([]string(nil), h.groups...), name)
+	return &next
+}
+
+// appendSlogAttr flattens a into one or more Fields onto fields, dotting
+// in prefix (the open WithGroup groups) ahead of a's own key. A
+// slog.KindGroup attr recurses with its own name appended to prefix --
+// slog's own convention for representing nested groups -- rather than
+// becoming a single Field carrying an opaque group value; an empty-named
+// group (slog.Group("", attrs...), used to splice attrs in without a
+// nesting level) recurses with prefix unchanged instead of adding a
+// leading dot.
+func appendSlogAttr(fields []Field, prefix []string, a slog.Attr) []Field {
+	a.Value = a.Value.Resolve()
+	if a.Value.Kind() == slog.KindGroup {
+		groupPrefix := prefix
+		if a.Key != "" {
+			groupPrefix = append(append([]string(nil), prefix...), a.Key)
+		}
+		for _, sub := range a.Value.Group() {
+			fields = appendSlogAttr(fields, groupPrefix, sub)
+		}
+		return fields
+	}
+
+	key := a.Key
+	if len(prefix) > 0 {
+		key = strings.Join(prefix, ".") + "." + key
+	}
+	return append(fields, Field{Key: key, Value: slogAttrValue(a.Value)})
+}
+
+// slogAttrValue converts v to whatever Go value formatFields knows how to
+// render: slog's own concrete kinds pass through as their natural Go
+// type, and anything else (slog.KindAny wrapping an arbitrary value, or a
+// slog.LogValuer that resolved to one) falls back to its String() form.
+func slogAttrValue(v slog.Value) any {
+	switch v.Kind() {
+	case slog.KindString:
+		return v.String()
+	case slog.KindInt64:
+		return v.Int64()
+	case slog.KindUint64:
+		return v.Uint64()
+	case slog.KindFloat64:
+		return v.Float64()
+	case slog.KindBool:
+		return v.Bool()
+	case slog.KindDuration:
+		return v.Duration().String()
+	case slog.KindTime:
+		return v.Time().String()
+	default:
+		return fmt.Sprint(v.Any())
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+)
+
+func TestNewSlogHandler_MapsMessageLevelAndAttrs(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+	engine.Init()
+	engine.Start()
+
+	var buf bytes.Buffer
+	engine.TenEnv().SetLogSink(&buf, LogFormatJSON)
+
+	logger := slog.New(NewSlogHandler(engine.TenEnv()))
+	logger.Warn("disk almost full", "host", "db.internal", "attempt", 3)
+
+	var decoded struct {
+		Msg   string `json:"msg"`
+		Level int    `json:"level"`
+	}
+	if err := json.Unmarshal(bytes.TrimSpace(buf.Bytes()), &decoded); err != nil {
+		t.Fatalf("sink content isn't valid JSON: %v (%q)", err, buf.String())
+	}
+	if decoded.Level != int(LogLevelWarn) {
+		t.Fatalf("decoded = %+v, want level %d", decoded, LogLevelWarn)
+	}
+	for _, want := range []string{"disk almost full", "host=db.internal", "attempt=3"} {
+		if !bytes.Contains([]byte(decoded.Msg), []byte(want)) {
+			t.Fatalf("decoded.Msg = %q, want it to contain %q", decoded.Msg, want)
+		}
+	}
+}
+
+func TestNewSlogHandler_WithGroupNestsAttrsAsDottedKeys(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+	engine.Init()
+	engine.Start()
+
+	var buf bytes.Buffer
+	engine.TenEnv().SetLogSink(&buf, LogFormatJSON)
+
+	logger := slog.New(NewSlogHandler(engine.TenEnv()))
+	logger.WithGroup("request").With("id", "abc123").Info("handled")
+
+	var decoded struct {
+		Msg string `json:"msg"`
+	}
+	if err := json.Unmarshal(bytes.TrimSpace(buf.Bytes()), &decoded); err != nil {
+		t.Fatalf("sink content isn't valid JSON: %v (%q)", err, buf.String())
+	}
+	if !bytes.Contains([]byte(decoded.Msg), []byte("request.id=abc123")) {
+		t.Fatalf("decoded.Msg = %q, want it to contain %q", decoded.Msg, "request.id=abc123")
+	}
+}
+
+func TestNewSlogHandler_WithAttrsIsIndependentAcrossDerivedHandlers(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+	engine.Init()
+	engine.Start()
+
+	var buf bytes.Buffer
+	engine.TenEnv().SetLogSink(&buf, LogFormatJSON)
+
+	base := slog.New(NewSlogHandler(engine.TenEnv()))
+	withService := base.With("service", "billing")
+
+	base.Info("base line")
+	if got := buf.String(); bytes.Contains([]byte(got), []byte("service")) {
+		t.Fatalf("base logger output = %q, want it unaffected by a sibling With call", got)
+	}
+	buf.Reset()
+
+	withService.Info("scoped line")
+	var decoded struct {
+		Msg string `json:"msg"`
+	}
+	if err := json.Unmarshal(bytes.TrimSpace(buf.Bytes()), &decoded); err != nil {
+		t.Fatalf("sink content isn't valid JSON: %v (%q)", err, buf.String())
+	}
+	if !bytes.Contains([]byte(decoded.Msg), []byte("service=billing")) {
+		t.Fatalf("decoded.Msg = %q, want it to contain %q", decoded.Msg, "service=billing")
+	}
+}
+
+func TestNewSlogHandler_EnabledMatchesTenEnvLogLevel(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+	engine.Init()
+	engine.Start()
+	engine.SetLogLevel(LogLevelWarn)
+
+	handler := NewSlogHandler(engine.TenEnv())
+	ctx := context.Background()
+	if handler.Enabled(ctx, slog.LevelInfo) {
+		t.Fatal("Enabled(LevelInfo) = true, want false once the threshold is LogLevelWarn")
+	}
+	if !handler.Enabled(ctx, slog.LevelWarn) {
+		t.Fatal("Enabled(LevelWarn) = false, want true")
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// SetSlowHandlerThreshold implements TenEnv.SetSlowHandlerThreshold; see
+// its doc comment.
+func (t *tenEnvImpl) SetSlowHandlerThreshold(d time.Duration) {
+	atomic.StoreInt64(&t.engine.slowHandlerThreshold, int64(d))
+}
+
+// MarkHandlerComplete implements TenEnv.MarkHandlerComplete; see its doc
+// comment.
+func (t *tenEnvImpl) MarkHandlerComplete(cmd Cmd) {
+	t.engine.finishHandlerTiming(cmd)
+}
+
+// startHandlerTiming records cmd's dispatch time so finishHandlerTiming
+// can later warn if it ran long. It's a no-op while slow-handler warnings
+// are disabled (the zero-threshold default), so an extension that never
+// opts in pays no bookkeeping cost.
+func (e *Engine) startHandlerTiming(cmd Cmd) {
+	if atomic.LoadInt64(&e.slowHandlerThreshold) <= 0 {
+		return
+	}
+	e.handlerStartMu.Lock()
+	defer e.handlerStartMu.Unlock()
+	if e.handlerStart == nil {
+		e.handlerStart = map[Cmd]time.Time{}
+	}
+	e.handlerStart[cmd] = time.Now()
+}
+
+// finishHandlerTiming is the slow-handler timer's single finalization
+// point, reached from MarkHandlerComplete when an extension calls it
+// explicitly, or from ReturnResult/ReturnResultEx(..., true) as the
+// fallback for a handler that never does -- whichever happens first. It's
+// a no-op if cmd was never timed (threshold disabled, or already
+// finalized once), so calling it from both places for the same cmd only
+// ever logs once.
+func (e *Engine) finishHandlerTiming(cmd Cmd) {
+	e.handlerStartMu.Lock()
+	start, ok := e.handlerStart[cmd]
+	if ok {
+		delete(e.handlerStart, cmd)
+	}
+	e.handlerStartMu.Unlock()
+	if !ok {
+		return
+	}
+
+	elapsed := time.Since(start)
+	threshold := time.Duration(atomic.LoadInt64(&e.slowHandlerThreshold))
+	if threshold <= 0 || elapsed < threshold {
+		return
+	}
+
+	name, _ := cmd.GetName()
+	e.tenEnv.LogFields(LogLevelWarn, "slow handler",
+		String("cmd", name),
+		String("duration", elapsed.String()),
+		String("extension", e.extensionName()),
+	)
+}
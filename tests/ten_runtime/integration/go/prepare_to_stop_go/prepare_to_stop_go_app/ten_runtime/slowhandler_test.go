@@ -0,0 +1,148 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+// sleepingExtension sleeps for delay before returning OK, so a test can
+// exercise the slow-handler timer's synchronous, OnCmd-returns path.
+type sleepingExtension struct {
+	DefaultExtension
+	delay time.Duration
+}
+
+func (e *sleepingExtension) OnCmd(tenEnv TenEnv, cmd Cmd) {
+	time.Sleep(e.delay)
+	tenEnv.ReturnOK(cmd, "")
+}
+
+func TestTenEnv_SlowHandlerThresholdWarnsWhenOnCmdRunsLong(t *testing.T) {
+	engine := NewEngine(&sleepingExtension{delay: 20 * time.Millisecond}, WithExtensionName("laggy"))
+	engine.Init()
+	engine.Start()
+
+	var buf bytes.Buffer
+	engine.TenEnv().SetLogSink(&buf, LogFormatText)
+	engine.TenEnv().SetSlowHandlerThreshold(5 * time.Millisecond)
+
+	cmd, _ := NewCmd("slow")
+	if err := engine.DeliverCmd(cmd); err != nil {
+		t.Fatalf("DeliverCmd: %v", err)
+	}
+
+	got := buf.String()
+	if !strings.Contains(got, "slow handler") || !strings.Contains(got, "slow") || !strings.Contains(got, "laggy") {
+		t.Fatalf("sink content = %q, want a slow-handler warning naming the cmd and extension", got)
+	}
+}
+
+func TestTenEnv_SlowHandlerThresholdSilentWhenFast(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+	engine.Init()
+	engine.Start()
+
+	var buf bytes.Buffer
+	engine.TenEnv().SetLogSink(&buf, LogFormatText)
+	engine.TenEnv().SetSlowHandlerThreshold(time.Second)
+
+	cmd, _ := NewCmd(HealthCheckCmdName)
+	if err := engine.DeliverCmd(cmd); err != nil {
+		t.Fatalf("DeliverCmd: %v", err)
+	}
+
+	if got := buf.String(); strings.Contains(got, "slow handler") {
+		t.Fatalf("sink content = %q, did not expect a slow-handler warning for a fast handler", got)
+	}
+}
+
+func TestTenEnv_SlowHandlerThresholdDisabledByDefault(t *testing.T) {
+	engine := NewEngine(&sleepingExtension{delay: 10 * time.Millisecond})
+	engine.Init()
+	engine.Start()
+
+	var buf bytes.Buffer
+	engine.TenEnv().SetLogSink(&buf, LogFormatText)
+
+	cmd, _ := NewCmd("slow")
+	if err := engine.DeliverCmd(cmd); err != nil {
+		t.Fatalf("DeliverCmd: %v", err)
+	}
+
+	if got := buf.String(); strings.Contains(got, "slow handler") {
+		t.Fatalf("sink content = %q, did not expect a slow-handler warning with the default zero threshold", got)
+	}
+}
+
+// deferredCompletionExtension does its timed work in a goroutine spawned
+// from OnCmd -- which returns immediately -- and calls MarkHandlerComplete
+// the moment that work finishes, before it gets around to sending the
+// actual CmdResult, so a test can observe the timer stopping there rather
+// than at the (later, and irrelevant to what's being timed) final result.
+type deferredCompletionExtension struct {
+	DefaultExtension
+	delay chan struct{}
+	done  chan struct{}
+}
+
+func (e *deferredCompletionExtension) OnCmd(tenEnv TenEnv, cmd Cmd) {
+	go func() {
+		<-e.delay
+		tenEnv.MarkHandlerComplete(cmd)
+		tenEnv.ReturnOK(cmd, "")
+		close(e.done)
+	}()
+}
+
+func TestTenEnv_MarkHandlerCompleteStopsTimingBeforeTheFinalResult(t *testing.T) {
+	ext := &deferredCompletionExtension{delay: make(chan struct{}), done: make(chan struct{})}
+	engine := NewEngine(ext, WithExtensionName("async-worker"))
+	engine.Init()
+	engine.Start()
+
+	var buf bytes.Buffer
+	engine.TenEnv().SetLogSink(&buf, LogFormatText)
+	engine.TenEnv().SetSlowHandlerThreshold(5 * time.Millisecond)
+
+	cmd, _ := NewCmd("kickoff")
+	if err := engine.DeliverCmd(cmd); err != nil {
+		t.Fatalf("DeliverCmd: %v", err)
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	close(ext.delay)
+	<-ext.done
+
+	got := buf.String()
+	if !strings.Contains(got, "slow handler") || !strings.Contains(got, "async-worker") {
+		t.Fatalf("sink content = %q, want a slow-handler warning once MarkHandlerComplete ran", got)
+	}
+}
+
+func TestTenEnv_MarkHandlerCompleteIsNoOpAfterTheFinalResultAlreadyFinalized(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+	engine.Init()
+	engine.Start()
+
+	var buf bytes.Buffer
+	engine.TenEnv().SetLogSink(&buf, LogFormatText)
+	engine.TenEnv().SetSlowHandlerThreshold(time.Hour)
+
+	cmd, _ := NewCmd(HealthCheckCmdName)
+	if err := engine.DeliverCmd(cmd); err != nil {
+		t.Fatalf("DeliverCmd: %v", err)
+	}
+
+	// The cmd already finalized via ReturnResult; calling
+	// MarkHandlerComplete again must not panic or log a second warning.
+	engine.TenEnv().MarkHandlerComplete(cmd)
+	if strings.Contains(buf.String(), "slow handler") {
+		t.Fatalf("sink content = %q, did not expect a warning for a fast handler", buf.String())
+	}
+}
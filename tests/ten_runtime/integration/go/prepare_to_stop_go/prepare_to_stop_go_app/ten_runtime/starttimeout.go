@@ -0,0 +1,22 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrStartTimeout is the error Start returns when SetStartTimeout's
+// deadline expires before OnStartDone is called.
+var ErrStartTimeout = errors.New("ten: start deadline exceeded before OnStartDone")
+
+func newStartContext(deadline time.Duration) (context.Context, context.CancelFunc) {
+	if deadline <= 0 {
+		return context.WithCancel(context.Background())
+	}
+	return context.WithTimeout(context.Background(), deadline)
+}
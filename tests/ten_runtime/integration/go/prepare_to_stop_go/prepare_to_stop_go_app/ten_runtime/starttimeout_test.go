@@ -0,0 +1,107 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// hangingStartExtension never signals OnStartDone on its own, simulating
+// an OnStart stuck on a dependency (e.g. a database) that never answers.
+type hangingStartExtension struct {
+	DefaultExtension
+}
+
+func (hangingStartExtension) OnStart(tenEnv TenEnv) {}
+
+func TestEngineStart_TimeoutFailsGraphStartup(t *testing.T) {
+	engine := NewEngine(hangingStartExtension{})
+	engine.tenEnv.SetStartTimeout(20 * time.Millisecond)
+
+	if err := engine.Init(); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- engine.Start() }()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, ErrStartTimeout) {
+			t.Fatalf("Start err = %v, want ErrStartTimeout", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Start did not return after the start timeout expired")
+	}
+}
+
+func TestEngineStart_NoTimeoutWaitsIndefinitely(t *testing.T) {
+	ext := newDeferredStartExtension()
+	engine := NewEngine(ext)
+
+	if err := engine.Init(); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- engine.Start() }()
+
+	select {
+	case <-done:
+		t.Fatal("Start returned before OnStartDone, with no start timeout configured")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(ext.startChan)
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Start: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Start did not return after OnStartDone")
+	}
+}
+
+// startContextExtension waits on tenEnv.StartContext() instead of on its
+// own channel, so it observes the timeout SetStartTimeout configures
+// rather than waiting forever for an OnStartDone it never calls itself.
+type startContextExtension struct {
+	DefaultExtension
+
+	cancelled chan struct{}
+}
+
+func (s *startContextExtension) OnStart(tenEnv TenEnv) {
+	go func() {
+		<-tenEnv.StartContext().Done()
+		close(s.cancelled)
+	}()
+}
+
+func TestTenEnv_SetStartTimeoutCancelsStartContext(t *testing.T) {
+	cancelled := make(chan struct{})
+	ext := &startContextExtension{cancelled: cancelled}
+	engine := NewEngine(ext)
+	engine.tenEnv.SetStartTimeout(20 * time.Millisecond)
+
+	if err := engine.Init(); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- engine.Start() }()
+
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Fatal("StartContext was never cancelled after the start timeout expired")
+	}
+	if err := <-done; !errors.Is(err, ErrStartTimeout) {
+		t.Fatalf("Start err = %v, want ErrStartTimeout", err)
+	}
+}
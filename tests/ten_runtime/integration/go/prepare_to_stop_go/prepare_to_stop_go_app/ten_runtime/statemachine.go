@@ -0,0 +1,113 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrIllegalStateTransition is returned by StateMachine.Transition when the
+// requested transition was never declared via AllowTransition.
+var ErrIllegalStateTransition = errors.New("ten: illegal state transition")
+
+// StateHandler is run by StateMachine.Transition once it has moved into
+// state to, after logging the transition -- e.g. an extension entering
+// "streaming" might start a goroutine here, and one entering "draining"
+// might flush a buffer.
+type StateHandler[State any] func(tenEnv TenEnv, from, to State)
+
+// StateMachine is a small embeddable helper for an extension whose OnCmd
+// logic is really a state machine (idle -> connecting -> streaming ->
+// draining, say) tangled inside a switch. An extension declares its legal
+// transitions up front with AllowTransition and, optionally, a handler
+// per destination state with OnEnter, then drives the machine from OnCmd
+// with Transition -- which rejects anything not declared, and logs every
+// transition it makes through the TenEnv it's given, tagged with the
+// extension's own name via TenEnv.GetExtensionName.
+//
+// StateMachine has no notion of Cmd, TenEnv lifecycle, or the extension
+// interface beyond what Transition needs to log and to call a
+// StateHandler; it's a general enough helper to also drive, say, a
+// connection's own state independent of the extension hosting it.
+type StateMachine[State comparable] struct {
+	mu       sync.Mutex
+	current  State
+	allowed  map[State]map[State]bool
+	handlers map[State]StateHandler[State]
+}
+
+// NewStateMachine returns a StateMachine starting in initial, with no
+// transitions allowed yet -- every one an extension wants to make must be
+// declared with AllowTransition first.
+func NewStateMachine[State comparable](initial State) *StateMachine[State] {
+	return &StateMachine[State]{
+		current:  initial,
+		allowed:  map[State]map[State]bool{},
+		handlers: map[State]StateHandler[State]{},
+	}
+}
+
+// AllowTransition declares that Transition may move the machine from from
+// to to. It returns sm so a full set of transitions can be declared in one
+// chained expression. Transitions not declared here are illegal.
+func (sm *StateMachine[State]) AllowTransition(from, to State) *StateMachine[State] {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	if sm.allowed[from] == nil {
+		sm.allowed[from] = map[State]bool{}
+	}
+	sm.allowed[from][to] = true
+	return sm
+}
+
+// OnEnter registers handler to run every time Transition moves the
+// machine into state. Only one handler per state is kept; registering
+// again for the same state replaces it.
+func (sm *StateMachine[State]) OnEnter(state State, handler StateHandler[State]) *StateMachine[State] {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	sm.handlers[state] = handler
+	return sm
+}
+
+// Current returns the machine's current state.
+func (sm *StateMachine[State]) Current() State {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	return sm.current
+}
+
+// Transition moves the machine from its current state to to, provided
+// that exact transition was declared with AllowTransition. On success, it
+// logs the transition through tenEnv at LogLevelInfo -- tagged with the
+// extension's name (see TenEnv.GetExtensionName), its "from" and "to"
+// states -- and then, if one was registered, runs to's OnEnter handler.
+// On an undeclared transition it returns ErrIllegalStateTransition and
+// leaves the machine's state, and everything else, untouched.
+func (sm *StateMachine[State]) Transition(tenEnv TenEnv, to State) error {
+	sm.mu.Lock()
+	from := sm.current
+	if !sm.allowed[from][to] {
+		sm.mu.Unlock()
+		return fmt.Errorf("%w: %v -> %v", ErrIllegalStateTransition, from, to)
+	}
+	sm.current = to
+	handler := sm.handlers[to]
+	sm.mu.Unlock()
+
+	name, _ := tenEnv.GetExtensionName()
+	tenEnv.LogFields(LogLevelInfo, "state transition",
+		String("extension", name),
+		Field{Key: "from", Value: from},
+		Field{Key: "to", Value: to},
+	)
+
+	if handler != nil {
+		handler(tenEnv, from, to)
+	}
+	return nil
+}
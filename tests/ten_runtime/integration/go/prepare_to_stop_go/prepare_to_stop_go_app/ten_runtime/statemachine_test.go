@@ -0,0 +1,113 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type connState string
+
+const (
+	connIdle       connState = "idle"
+	connConnecting connState = "connecting"
+	connStreaming  connState = "streaming"
+	connDraining   connState = "draining"
+)
+
+func newConnStateMachine() *StateMachine[connState] {
+	return NewStateMachine(connIdle).
+		AllowTransition(connIdle, connConnecting).
+		AllowTransition(connConnecting, connStreaming).
+		AllowTransition(connStreaming, connDraining).
+		AllowTransition(connDraining, connIdle)
+}
+
+func TestStateMachine_TransitionMovesCurrentStateOnSuccess(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+	engine.Init()
+	engine.Start()
+
+	sm := newConnStateMachine()
+	if err := sm.Transition(engine.TenEnv(), connConnecting); err != nil {
+		t.Fatalf("Transition: %v", err)
+	}
+	if got := sm.Current(); got != connConnecting {
+		t.Fatalf("Current() = %v, want %v", got, connConnecting)
+	}
+}
+
+func TestStateMachine_TransitionRejectsUndeclaredMove(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+	engine.Init()
+	engine.Start()
+
+	sm := newConnStateMachine()
+	err := sm.Transition(engine.TenEnv(), connStreaming)
+	if !errors.Is(err, ErrIllegalStateTransition) {
+		t.Fatalf("Transition(idle->streaming) = %v, want ErrIllegalStateTransition", err)
+	}
+	if got := sm.Current(); got != connIdle {
+		t.Fatalf("Current() = %v, want idle to be left untouched by a rejected transition", got)
+	}
+}
+
+func TestStateMachine_TransitionRunsOnEnterHandlerForDestination(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+	engine.Init()
+	engine.Start()
+
+	var gotFrom, gotTo connState
+	sm := newConnStateMachine()
+	sm.OnEnter(connConnecting, func(_ TenEnv, from, to connState) {
+		gotFrom, gotTo = from, to
+	})
+
+	if err := sm.Transition(engine.TenEnv(), connConnecting); err != nil {
+		t.Fatalf("Transition: %v", err)
+	}
+	if gotFrom != connIdle || gotTo != connConnecting {
+		t.Fatalf("OnEnter saw (%v, %v), want (%v, %v)", gotFrom, gotTo, connIdle, connConnecting)
+	}
+}
+
+func TestStateMachine_TransitionDoesNotRunHandlerOnRejectedMove(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+	engine.Init()
+	engine.Start()
+
+	ran := false
+	sm := newConnStateMachine()
+	sm.OnEnter(connStreaming, func(_ TenEnv, _, _ connState) { ran = true })
+
+	sm.Transition(engine.TenEnv(), connStreaming)
+	if ran {
+		t.Fatal("OnEnter handler ran despite an illegal transition")
+	}
+}
+
+func TestStateMachine_TransitionLogsExtensionNameAndStates(t *testing.T) {
+	engine := NewEngine(DefaultExtension{}, WithExtensionName("gateway"))
+	engine.Init()
+	engine.Start()
+
+	var buf bytes.Buffer
+	engine.TenEnv().SetLogSink(&buf, LogFormatText)
+
+	sm := newConnStateMachine()
+	if err := sm.Transition(engine.TenEnv(), connConnecting); err != nil {
+		t.Fatalf("Transition: %v", err)
+	}
+
+	got := buf.String()
+	for _, want := range []string{"gateway", "idle", "connecting"} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("logged transition = %q, want it to contain %q", got, want)
+		}
+	}
+}
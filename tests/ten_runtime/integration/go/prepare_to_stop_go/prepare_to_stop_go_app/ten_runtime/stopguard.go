@@ -0,0 +1,84 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import "sync"
+
+// StopGuard tracks an OnStop's outstanding in-flight commands and
+// goroutines, the way a sync.WaitGroup tracks outstanding work, and calls
+// the guarded TenEnv's OnStopDone exactly once, automatically, as soon as
+// OnStop has finished registering that work (see Arm) and the count of
+// outstanding work drops to zero. It replaces hand-rolled stopChan
+// bookkeeping in an OnStop like:
+//
+//	func (e *myExtension) OnStop(tenEnv ten.TenEnv) {
+//		guard := ten.NewStopGuard(tenEnv)
+//		defer guard.Arm()
+//
+//		guard.Add(1)
+//		go func() {
+//			defer guard.Done()
+//			<-e.stopChan
+//		}()
+//	}
+//
+// which used to hang the whole graph's shutdown whenever a goroutine
+// forgot to signal completion, or OnStopDone was called before every
+// registered goroutine had actually finished. A StopGuard makes both
+// mistakes impossible: OnStopDone only ever fires after Arm has been
+// called and every Add has a matching Done.
+type StopGuard struct {
+	tenEnv TenEnv
+
+	mu      sync.Mutex
+	pending int
+	armed   bool
+	done    bool
+}
+
+// NewStopGuard creates a StopGuard that will call tenEnv.OnStopDone once
+// armed and drained. It's typically created at the top of OnStop.
+func NewStopGuard(tenEnv TenEnv) *StopGuard {
+	return &StopGuard{tenEnv: tenEnv}
+}
+
+// Add registers delta outstanding operations, mirroring
+// sync.WaitGroup.Add. Call it before starting the goroutine or command it
+// accounts for; a negative delta is equivalent to that many calls to
+// Done.
+func (g *StopGuard) Add(delta int) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.pending += delta
+	g.maybeFinishLocked()
+}
+
+// Done marks one outstanding operation as finished, mirroring
+// sync.WaitGroup.Done.
+func (g *StopGuard) Done() {
+	g.Add(-1)
+}
+
+// Arm marks that OnStop has finished registering all the outstanding
+// work it knows about up front. Once armed, the guard calls OnStopDone
+// as soon as the pending count reaches zero -- immediately, if no work
+// was ever registered, or later as goroutines call Done. Calling Arm
+// before every Add would race the guard into finishing early, so Add
+// calls that describe work known at OnStop's entry should happen before
+// Arm; work discovered afterward can still Add/Done normally.
+func (g *StopGuard) Arm() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.armed = true
+	g.maybeFinishLocked()
+}
+
+func (g *StopGuard) maybeFinishLocked() {
+	if g.done || !g.armed || g.pending > 0 {
+		return
+	}
+	g.done = true
+	g.tenEnv.OnStopDone()
+}
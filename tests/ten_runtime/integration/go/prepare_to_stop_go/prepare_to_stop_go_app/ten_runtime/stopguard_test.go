@@ -0,0 +1,150 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStopGuard_FinishesImmediatelyWhenArmedWithNothingOutstanding(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+	engine.Init()
+	engine.Start()
+
+	guard := NewStopGuard(engine.TenEnv())
+	guard.Arm()
+
+	select {
+	case <-engine.stopDone:
+	case <-time.After(time.Second):
+		t.Fatal("Arm with no outstanding work never called OnStopDone")
+	}
+}
+
+func TestStopGuard_WaitsForOutstandingWorkBeforeFinishing(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+	engine.Init()
+	engine.Start()
+
+	guard := NewStopGuard(engine.TenEnv())
+	guard.Add(1)
+	guard.Arm()
+
+	select {
+	case <-engine.stopDone:
+		t.Fatal("OnStopDone fired before the outstanding Add(1) called Done")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	guard.Done()
+
+	select {
+	case <-engine.stopDone:
+	case <-time.After(time.Second):
+		t.Fatal("OnStopDone never fired after outstanding work called Done")
+	}
+}
+
+func TestStopGuard_OnlyCallsOnStopDoneOnce(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+	engine.Init()
+	engine.Start()
+
+	guard := NewStopGuard(engine.TenEnv())
+	guard.Add(2)
+	guard.Arm()
+	guard.Done()
+	guard.Done()
+	guard.Done() // extra Done beyond the registered Add(2) must not panic or double-fire.
+
+	select {
+	case <-engine.stopDone:
+	case <-time.After(time.Second):
+		t.Fatal("OnStopDone never fired")
+	}
+}
+
+// stopGuardExtension is a stand-in for extension_b's pattern: OnStop starts
+// a goroutine that waits on an external signal before finishing, tracked
+// through a StopGuard instead of a hand-rolled stopChan.
+type stopGuardExtension struct {
+	DefaultExtension
+
+	release chan struct{}
+}
+
+func (s *stopGuardExtension) OnStop(tenEnv TenEnv) {
+	guard := NewStopGuard(tenEnv)
+	guard.Add(1)
+	go func() {
+		defer guard.Done()
+		<-s.release
+	}()
+	guard.Arm()
+}
+
+func TestStopGuard_DrivesEngineStopEndToEnd(t *testing.T) {
+	ext := &stopGuardExtension{release: make(chan struct{})}
+	engine := NewEngine(ext)
+	engine.Init()
+	engine.Start()
+
+	stopErr := make(chan error, 1)
+	go func() { stopErr <- engine.Stop() }()
+
+	select {
+	case err := <-stopErr:
+		t.Fatalf("Stop() returned early (%v) before the guarded goroutine finished", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(ext.release)
+
+	select {
+	case err := <-stopErr:
+		if err != nil {
+			t.Fatalf("Stop() = %v, want nil", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Stop() never returned after the guarded goroutine finished")
+	}
+}
+
+func TestDeferStopDone_CallsOnStopDoneOnFirstInvocationOnly(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+	engine.Init()
+	engine.Start()
+
+	done := engine.TenEnv().DeferStopDone()
+	done()
+	done() // a second call must be a no-op, not a second OnStopDone signal.
+
+	select {
+	case <-engine.stopDone:
+	case <-time.After(time.Second):
+		t.Fatal("DeferStopDone's returned func never called OnStopDone")
+	}
+}
+
+// deferStopDoneExtension shows the documented defer tenEnv.DeferStopDone()()
+// pattern for an OnStop with no outstanding work to wait on.
+type deferStopDoneExtension struct {
+	DefaultExtension
+}
+
+func (deferStopDoneExtension) OnStop(tenEnv TenEnv) {
+	defer tenEnv.DeferStopDone()()
+}
+
+func TestDeferStopDone_DrivesEngineStopEndToEnd(t *testing.T) {
+	engine := NewEngine(deferStopDoneExtension{})
+	engine.Init()
+	engine.Start()
+
+	if err := engine.Stop(); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+}
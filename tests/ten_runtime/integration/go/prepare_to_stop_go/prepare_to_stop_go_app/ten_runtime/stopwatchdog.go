@@ -0,0 +1,90 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+	"time"
+)
+
+var (
+	stopWatchdogMu         sync.Mutex
+	stopWatchdogWindow     time.Duration
+	stopWatchdogForceAfter time.Duration
+)
+
+// StopWatchdogOption configures SetStopWatchdog beyond the required
+// window.
+type StopWatchdogOption func()
+
+// WithStopWatchdogForceAfter makes the watchdog escalate to a forced
+// teardown -- the same cancelPending-and-signalStopDone forcing
+// WithShutdownDeadline's own expiry does -- if OnStopDone still hasn't
+// been called d after the watchdog first logged. The default, if this
+// option isn't given, is 0: the watchdog only logs, it never forces
+// anything on its own, leaving that to WithShutdownDeadline (if
+// configured) or an operator watching for the log line.
+func WithStopWatchdogForceAfter(d time.Duration) StopWatchdogOption {
+	return func() { stopWatchdogForceAfter = d }
+}
+
+// SetStopWatchdog opt-in arms a process-wide watchdog: if an extension
+// enters OnStop and doesn't call OnStopDone within d, Engine.Stop logs a
+// LogLevelError line naming the extension and including every live
+// goroutine's stack, the same diagnostic an operator would otherwise have
+// to attach a debugger to get. A d of 0 (the default) disables it. See
+// WithStopWatchdogForceAfter for having it also force a teardown instead
+// of only logging.
+//
+// This mirrors SetLogSink's process-wide-plus-lock shape rather than
+// WithShutdownDeadline's per-Engine option, since a watchdog is a
+// debugging aid an operator reaches for globally -- typically from an
+// init() or main() during an incident -- not something tuned per
+// extension instance.
+func SetStopWatchdog(d time.Duration, opts ...StopWatchdogOption) {
+	stopWatchdogMu.Lock()
+	defer stopWatchdogMu.Unlock()
+	stopWatchdogWindow = d
+	stopWatchdogForceAfter = 0
+	for _, opt := range opts {
+		opt()
+	}
+}
+
+// getStopWatchdog returns the currently configured watchdog window and
+// force-after delay.
+func getStopWatchdog() (window, forceAfter time.Duration) {
+	stopWatchdogMu.Lock()
+	defer stopWatchdogMu.Unlock()
+	return stopWatchdogWindow, stopWatchdogForceAfter
+}
+
+// allGoroutineStacks dumps every live goroutine's stack, growing the
+// buffer until the dump fits -- runtime.Stack silently truncates instead
+// of reporting that it did, so there's no other way to tell it needs more
+// room.
+func allGoroutineStacks() string {
+	buf := make([]byte, 64*1024)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			return string(buf[:n])
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+}
+
+// logStopWatchdogTripped logs the loud, named diagnostic SetStopWatchdog's
+// doc comment describes.
+func (e *Engine) logStopWatchdogTripped(window time.Duration) {
+	e.tenEnv.Log(LogLevelError, fmt.Sprintf(
+		"ten: extension %q has not called OnStopDone within the stop "+
+			"watchdog window (%s) -- it may be deadlocked in OnStop. "+
+			"Live goroutine stacks follow:\n%s",
+		e.extensionName(), window, allGoroutineStacks(),
+	))
+}
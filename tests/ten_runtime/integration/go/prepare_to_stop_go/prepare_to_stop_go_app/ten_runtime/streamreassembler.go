@@ -0,0 +1,139 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrStreamReassemblyTimedOut is returned by StreamReassembler.Push when a
+// gap in the sequence (a chunk that never arrived) has stood open longer
+// than the reassembler's configured gap timeout. The reassembler drops
+// everything it had buffered for that stream and starts over from whatever
+// chunk triggered the error, so one dropped chunk can't hang reassembly
+// forever.
+var ErrStreamReassemblyTimedOut = errors.New("ten: stream reassembly timed out waiting for a missing chunk")
+
+// ReassemblerOption configures a StreamReassembler constructed via
+// NewStreamReassembler, following the same functional-options convention as
+// WithRateLimitMode and WithLogFormat.
+type ReassemblerOption func(*StreamReassembler)
+
+// WithReassemblerGapTimeout bounds how long StreamReassembler will wait for
+// a missing sequence number before giving up on the stream and returning
+// ErrStreamReassemblyTimedOut. The zero value (the default, if this option
+// isn't given) disables the timeout -- Push then waits indefinitely for
+// gaps to close, which is only appropriate when the caller enforces its own
+// timeout externally.
+func WithReassemblerGapTimeout(d time.Duration) ReassemblerOption {
+	return func(r *StreamReassembler) { r.gapTimeout = d }
+}
+
+// WithReassemblerOnComplete registers fn to be called with the fully
+// reassembled payload as soon as it's available, in addition to Push
+// returning it directly. Use this when chunks are pushed from several
+// goroutines and no single Push call is guaranteed to be the one that
+// completes the stream.
+func WithReassemblerOnComplete(fn func([]byte)) ReassemblerOption {
+	return func(r *StreamReassembler) { r.onComplete = fn }
+}
+
+// StreamReassembler buffers Data chunks carrying out-of-order SetSeq values
+// and reassembles them, in order, into one contiguous payload once the
+// chunk marked via SetEndOfStream arrives and every sequence number up to
+// it has been filled in. It is safe for concurrent use.
+type StreamReassembler struct {
+	mu sync.Mutex
+
+	gapTimeout time.Duration
+	onComplete func([]byte)
+
+	pending      map[uint64][]byte
+	nextSeq      uint64
+	assembled    []byte
+	haveEnd      bool
+	endSeq       uint64
+	lastActivity time.Time
+}
+
+// NewStreamReassembler creates an empty StreamReassembler expecting chunks
+// starting at sequence 0.
+func NewStreamReassembler(opts ...ReassemblerOption) *StreamReassembler {
+	r := &StreamReassembler{pending: make(map[uint64][]byte)}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Push feeds one chunk into the reassembler. It returns the complete
+// payload, with ok true, once every chunk up to (and including) the one
+// marked via SetEndOfStream has been pushed; otherwise it returns (nil,
+// false, nil) to mean "buffered, keep going". If the gap since the last
+// chunk it was actually missing (not this one) has stood open longer than
+// the configured WithReassemblerGapTimeout, Push drops everything buffered
+// -- including chunk -- and returns ErrStreamReassemblyTimedOut; the caller
+// must push chunk again to start a fresh stream from it.
+func (r *StreamReassembler) Push(chunk Data) (complete []byte, ok bool, err error) {
+	buf, err := chunk.GetBuf()
+	if err != nil {
+		return nil, false, err
+	}
+	seq := chunk.GetSeq()
+	eos := chunk.IsEndOfStream()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.gapTimeout > 0 && !r.lastActivity.IsZero() && seq != r.nextSeq &&
+		time.Since(r.lastActivity) > r.gapTimeout {
+		// The gap has stood open too long; drop everything buffered for
+		// the stalled stream and report it instead of silently hanging.
+		// This chunk is dropped along with the rest -- the caller sees
+		// the error and must push it again to start the next stream.
+		r.resetLocked(seq)
+		return nil, false, ErrStreamReassemblyTimedOut
+	}
+	r.lastActivity = time.Now()
+
+	r.pending[seq] = buf
+	if eos {
+		r.haveEnd = true
+		r.endSeq = seq
+	}
+
+	for {
+		next, buffered := r.pending[r.nextSeq]
+		if !buffered {
+			break
+		}
+		r.assembled = append(r.assembled, next...)
+		delete(r.pending, r.nextSeq)
+
+		if r.haveEnd && r.nextSeq == r.endSeq {
+			complete = r.assembled
+			r.resetLocked(0)
+			if r.onComplete != nil {
+				r.onComplete(complete)
+			}
+			return complete, true, nil
+		}
+		r.nextSeq++
+	}
+	return nil, false, nil
+}
+
+// resetLocked clears all buffered state and resumes expecting startSeq
+// next. Callers must hold r.mu.
+func (r *StreamReassembler) resetLocked(startSeq uint64) {
+	r.pending = make(map[uint64][]byte)
+	r.nextSeq = startSeq
+	r.assembled = nil
+	r.haveEnd = false
+	r.endSeq = 0
+	r.lastActivity = time.Time{}
+}
@@ -0,0 +1,132 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func chunkAt(t *testing.T, seq uint64, payload string, eos bool) Data {
+	t.Helper()
+	d, _ := NewData()
+	d.SetBuf([]byte(payload))
+	d.SetSeq(seq)
+	d.SetEndOfStream(eos)
+	return d
+}
+
+func TestStreamReassembler_InOrderChunksCompleteOnEndOfStream(t *testing.T) {
+	r := NewStreamReassembler()
+
+	if _, ok, err := r.Push(chunkAt(t, 0, "hel", false)); ok || err != nil {
+		t.Fatalf("Push(0) = ok %v err %v, want incomplete", ok, err)
+	}
+	if _, ok, err := r.Push(chunkAt(t, 1, "lo ", false)); ok || err != nil {
+		t.Fatalf("Push(1) = ok %v err %v, want incomplete", ok, err)
+	}
+	complete, ok, err := r.Push(chunkAt(t, 2, "world", true))
+	if err != nil {
+		t.Fatalf("Push(2): %v", err)
+	}
+	if !ok {
+		t.Fatalf("Push(2) ok = false, want true once end-of-stream chunk fills the last gap")
+	}
+	if string(complete) != "hello world" {
+		t.Fatalf("complete = %q, want %q", complete, "hello world")
+	}
+}
+
+func TestStreamReassembler_OutOfOrderChunksStillReassembleInOrder(t *testing.T) {
+	r := NewStreamReassembler()
+
+	if _, ok, err := r.Push(chunkAt(t, 2, "world", true)); ok || err != nil {
+		t.Fatalf("Push(2) = ok %v err %v, want buffered", ok, err)
+	}
+	if _, ok, err := r.Push(chunkAt(t, 0, "hel", false)); ok || err != nil {
+		t.Fatalf("Push(0) = ok %v err %v, want incomplete", ok, err)
+	}
+	complete, ok, err := r.Push(chunkAt(t, 1, "lo ", false))
+	if err != nil {
+		t.Fatalf("Push(1): %v", err)
+	}
+	if !ok {
+		t.Fatalf("Push(1) ok = false, want true once the gap to the buffered end-of-stream chunk closes")
+	}
+	if string(complete) != "hello world" {
+		t.Fatalf("complete = %q, want %q", complete, "hello world")
+	}
+}
+
+func TestStreamReassembler_OnCompleteCallbackFires(t *testing.T) {
+	var got []byte
+	r := NewStreamReassembler(WithReassemblerOnComplete(func(b []byte) { got = b }))
+
+	if _, _, err := r.Push(chunkAt(t, 0, "hi", true)); err != nil {
+		t.Fatalf("Push: %v", err)
+	}
+	if string(got) != "hi" {
+		t.Fatalf("onComplete payload = %q, want %q", got, "hi")
+	}
+}
+
+func TestStreamReassembler_ResetsAndStartsFreshAfterCompletion(t *testing.T) {
+	r := NewStreamReassembler()
+	if _, ok, err := r.Push(chunkAt(t, 0, "first", true)); !ok || err != nil {
+		t.Fatalf("Push(0) = ok %v err %v, want complete", ok, err)
+	}
+
+	complete, ok, err := r.Push(chunkAt(t, 0, "second", true))
+	if err != nil {
+		t.Fatalf("Push after completion: %v", err)
+	}
+	if !ok || string(complete) != "second" {
+		t.Fatalf("Push after completion = %q ok %v, want a fresh stream starting at seq 0", complete, ok)
+	}
+}
+
+func TestStreamReassembler_GapTimeoutDropsStalledStream(t *testing.T) {
+	r := NewStreamReassembler(WithReassemblerGapTimeout(10 * time.Millisecond))
+
+	if _, ok, err := r.Push(chunkAt(t, 0, "hel", false)); ok || err != nil {
+		t.Fatalf("Push(0) = ok %v err %v, want buffered", ok, err)
+	}
+	// Chunk 1 never arrives; simulate the gap standing open past the
+	// timeout before the next chunk (seq 2) shows up.
+	time.Sleep(20 * time.Millisecond)
+
+	_, ok, err := r.Push(chunkAt(t, 2, "world", true))
+	if !errors.Is(err, ErrStreamReassemblyTimedOut) {
+		t.Fatalf("Push after a stale gap = %v, want ErrStreamReassemblyTimedOut", err)
+	}
+	if ok {
+		t.Fatalf("Push after a stale gap ok = true, want false -- the dropped chunk must be re-pushed")
+	}
+
+	// Pushing the same chunk again starts a fresh stream from it: chunk 0
+	// was dropped along with the gap, so seq 2 is now the expected first
+	// chunk and, since it's also marked end-of-stream, completes alone.
+	complete, ok, err := r.Push(chunkAt(t, 2, "world", true))
+	if err != nil {
+		t.Fatalf("Push retry after timeout: %v", err)
+	}
+	if !ok || string(complete) != "world" {
+		t.Fatalf("Push retry after timeout = %q ok %v, want a fresh stream starting at seq 2", complete, ok)
+	}
+}
+
+func TestStreamReassembler_NoGapTimeoutWaitsIndefinitely(t *testing.T) {
+	r := NewStreamReassembler()
+
+	if _, ok, err := r.Push(chunkAt(t, 0, "hel", false)); ok || err != nil {
+		t.Fatalf("Push(0) = ok %v err %v, want buffered", ok, err)
+	}
+	time.Sleep(20 * time.Millisecond)
+
+	if _, ok, err := r.Push(chunkAt(t, 2, "world", true)); ok || err != nil {
+		t.Fatalf("Push(2) with no gap timeout configured = ok %v err %v, want it to keep waiting on seq 1", ok, err)
+	}
+}
@@ -0,0 +1,255 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ErrUnmarshalTarget is returned by Cmd.Unmarshal when v is not a non-nil
+// pointer to a struct.
+var ErrUnmarshalTarget = errors.New("ten: Unmarshal target must be a non-nil pointer to a struct")
+
+// ErrMarshalSource is returned by Cmd.Marshal when v is not a struct, or a
+// pointer to one.
+var ErrMarshalSource = errors.New("ten: Marshal source must be a struct or a pointer to one")
+
+// ErrMissingRequiredProperty is returned by Cmd.Unmarshal when a field
+// tagged `ten:"...,required"` has no matching property.
+var ErrMissingRequiredProperty = errors.New("ten: missing required property")
+
+// tagInfo is a parsed `ten:"name,option,..."` struct tag.
+type tagInfo struct {
+	name     string
+	required bool
+	skip     bool
+}
+
+// parseTag reads field's `ten` tag, defaulting to the field's own name when
+// the tag is absent, mirroring encoding/json's fallback. A tag of "-" skips
+// the field entirely.
+func parseTag(field reflect.StructField) tagInfo {
+	tag, ok := field.Tag.Lookup("ten")
+	if !ok {
+		return tagInfo{name: field.Name}
+	}
+	if tag == "-" {
+		return tagInfo{skip: true}
+	}
+	parts := strings.Split(tag, ",")
+	info := tagInfo{name: parts[0]}
+	if info.name == "" {
+		info.name = field.Name
+	}
+	for _, opt := range parts[1:] {
+		if opt == "required" {
+			info.required = true
+		}
+	}
+	return info
+}
+
+// unmarshalStruct populates the struct pointed to by v from props, per
+// field's `ten` tag. See Cmd.Unmarshal's doc comment for the full contract.
+func unmarshalStruct(props map[string]any, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return ErrUnmarshalTarget
+	}
+	return unmarshalStructValue(props, rv.Elem())
+}
+
+func unmarshalStructValue(props map[string]any, sv reflect.Value) error {
+	st := sv.Type()
+	for i := 0; i < st.NumField(); i++ {
+		field := st.Field(i)
+		if field.PkgPath != "" {
+			continue // unexported
+		}
+		info := parseTag(field)
+		if info.skip {
+			continue
+		}
+		raw, ok := props[info.name]
+		if !ok {
+			if info.required {
+				return fmt.Errorf("%w: %q", ErrMissingRequiredProperty, info.name)
+			}
+			continue
+		}
+		if err := assignField(sv.Field(i), raw, info.name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func assignField(fv reflect.Value, raw any, name string) error {
+	switch fv.Kind() {
+	case reflect.String:
+		s, ok := raw.(string)
+		if !ok {
+			return fmt.Errorf("%w: property %q is %T, not a string", ErrPropertyTypeMismatch, name, raw)
+		}
+		fv.SetString(s)
+	case reflect.Bool:
+		b, ok := raw.(bool)
+		if !ok {
+			return fmt.Errorf("%w: property %q is %T, not a bool", ErrPropertyTypeMismatch, name, raw)
+		}
+		fv.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, ok := asInt64(raw)
+		if !ok {
+			return fmt.Errorf("%w: property %q is %T, not an integer", ErrPropertyTypeMismatch, name, raw)
+		}
+		fv.SetInt(n)
+	case reflect.Float32, reflect.Float64:
+		f, ok := asFloat64(raw)
+		if !ok {
+			return fmt.Errorf("%w: property %q is %T, not a number", ErrPropertyTypeMismatch, name, raw)
+		}
+		fv.SetFloat(f)
+	case reflect.Struct:
+		m, ok := raw.(map[string]any)
+		if !ok {
+			return fmt.Errorf("%w: property %q is %T, not an object", ErrPropertyTypeMismatch, name, raw)
+		}
+		if err := unmarshalStructValue(m, fv); err != nil {
+			return err
+		}
+	case reflect.Slice:
+		list, ok := raw.([]any)
+		if !ok {
+			return fmt.Errorf("%w: property %q is %T, not an array", ErrPropertyTypeMismatch, name, raw)
+		}
+		out := reflect.MakeSlice(fv.Type(), len(list), len(list))
+		for i, elem := range list {
+			if err := assignField(out.Index(i), elem, fmt.Sprintf("%s.%d", name, i)); err != nil {
+				return err
+			}
+		}
+		fv.Set(out)
+	case reflect.Pointer:
+		elem := reflect.New(fv.Type().Elem())
+		if err := assignField(elem.Elem(), raw, name); err != nil {
+			return err
+		}
+		fv.Set(elem)
+	default:
+		return fmt.Errorf("%w: property %q has unsupported field kind %s", ErrPropertyTypeMismatch, name, fv.Kind())
+	}
+	return nil
+}
+
+func asInt64(raw any) (int64, bool) {
+	switch n := raw.(type) {
+	case int64:
+		return n, true
+	case int:
+		return int64(n), true
+	default:
+		return 0, false
+	}
+}
+
+func asFloat64(raw any) (float64, bool) {
+	switch n := raw.(type) {
+	case float64:
+		return n, true
+	case int64:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	default:
+		return 0, false
+	}
+}
+
+// marshalStruct replaces props's contents with the fields of v, per field's
+// `ten` tag. See Cmd.Marshal's doc comment for the full contract.
+func marshalStruct(props map[string]any, v any) error {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return ErrMarshalSource
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return ErrMarshalSource
+	}
+	m, err := marshalStructValue(rv)
+	if err != nil {
+		return err
+	}
+	for k := range props {
+		delete(props, k)
+	}
+	for k, vv := range m {
+		props[k] = vv
+	}
+	return nil
+}
+
+func marshalStructValue(sv reflect.Value) (map[string]any, error) {
+	st := sv.Type()
+	out := make(map[string]any, st.NumField())
+	for i := 0; i < st.NumField(); i++ {
+		field := st.Field(i)
+		if field.PkgPath != "" {
+			continue // unexported
+		}
+		info := parseTag(field)
+		if info.skip {
+			continue
+		}
+		val, err := marshalValue(sv.Field(i))
+		if err != nil {
+			return nil, fmt.Errorf("field %q: %w", info.name, err)
+		}
+		out[info.name] = val
+	}
+	return out, nil
+}
+
+func marshalValue(fv reflect.Value) (any, error) {
+	switch fv.Kind() {
+	case reflect.String:
+		return fv.String(), nil
+	case reflect.Bool:
+		return fv.Bool(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return fv.Int(), nil
+	case reflect.Float32, reflect.Float64:
+		return fv.Float(), nil
+	case reflect.Struct:
+		m, err := marshalStructValue(fv)
+		if err != nil {
+			return nil, err
+		}
+		return m, nil
+	case reflect.Slice:
+		out := make([]any, fv.Len())
+		for i := 0; i < fv.Len(); i++ {
+			v, err := marshalValue(fv.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			out[i] = v
+		}
+		return out, nil
+	case reflect.Pointer:
+		if fv.IsNil() {
+			return nil, nil
+		}
+		return marshalValue(fv.Elem())
+	default:
+		return nil, fmt.Errorf("%w: unsupported field kind %s", ErrPropertyTypeMismatch, fv.Kind())
+	}
+}
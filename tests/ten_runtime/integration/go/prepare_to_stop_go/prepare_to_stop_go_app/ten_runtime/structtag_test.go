@@ -0,0 +1,167 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"errors"
+	"testing"
+)
+
+type vadConfig struct {
+	Enabled   bool    `ten:"enabled"`
+	Threshold float64 `ten:"threshold"`
+}
+
+type audioParams struct {
+	SampleRate int64     `ten:"sample_rate,required"`
+	Channels   int64     `ten:"channels"`
+	Codec      string    `ten:"codec"`
+	Ignored    string    `ten:"-"`
+	Vad        vadConfig `ten:"vad"`
+	Tags       []string  `ten:"tags"`
+}
+
+func TestCmd_UnmarshalPopulatesFieldsFromProperties(t *testing.T) {
+	cmd, _ := NewCmd("configure")
+	cmd.SetPropertyInt64("sample_rate", 16000)
+	cmd.SetPropertyInt64("channels", 2)
+	cmd.SetPropertyString("codec", "opus")
+	cmd.SetPropertyFromJSONBytes("vad", []byte(`{"enabled":true,"threshold":0.5}`))
+	cmd.SetPropertyFromJSONBytes("tags", []byte(`["a","b"]`))
+
+	var params audioParams
+	if err := cmd.Unmarshal(&params); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if params.SampleRate != 16000 || params.Channels != 2 || params.Codec != "opus" {
+		t.Fatalf("params = %+v, want SampleRate 16000, Channels 2, Codec opus", params)
+	}
+	if !params.Vad.Enabled || params.Vad.Threshold != 0.5 {
+		t.Fatalf("params.Vad = %+v, want {true 0.5}", params.Vad)
+	}
+	if len(params.Tags) != 2 || params.Tags[0] != "a" || params.Tags[1] != "b" {
+		t.Fatalf("params.Tags = %v, want [a b]", params.Tags)
+	}
+}
+
+func TestCmd_UnmarshalUsesFieldNameWhenUntagged(t *testing.T) {
+	type plain struct {
+		Name string
+	}
+	cmd, _ := NewCmd("configure")
+	cmd.SetPropertyString("Name", "vad")
+
+	var p plain
+	if err := cmd.Unmarshal(&p); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if p.Name != "vad" {
+		t.Fatalf("p.Name = %q, want %q", p.Name, "vad")
+	}
+}
+
+func TestCmd_UnmarshalMissingRequiredFieldIsError(t *testing.T) {
+	cmd, _ := NewCmd("configure")
+	cmd.SetPropertyString("codec", "opus")
+
+	var params audioParams
+	err := cmd.Unmarshal(&params)
+	if !errors.Is(err, ErrMissingRequiredProperty) {
+		t.Fatalf("Unmarshal(missing required) = %v, want ErrMissingRequiredProperty", err)
+	}
+}
+
+func TestCmd_UnmarshalIgnoresUnknownProperties(t *testing.T) {
+	cmd, _ := NewCmd("configure")
+	cmd.SetPropertyInt64("sample_rate", 16000)
+	cmd.SetPropertyString("unrelated", "value")
+
+	var params audioParams
+	if err := cmd.Unmarshal(&params); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if params.SampleRate != 16000 {
+		t.Fatalf("params.SampleRate = %d, want 16000", params.SampleRate)
+	}
+}
+
+func TestCmd_UnmarshalRejectsNonPointerTarget(t *testing.T) {
+	cmd, _ := NewCmd("configure")
+	var params audioParams
+	if err := cmd.Unmarshal(params); !errors.Is(err, ErrUnmarshalTarget) {
+		t.Fatalf("Unmarshal(non-pointer) = %v, want ErrUnmarshalTarget", err)
+	}
+}
+
+func TestCmd_UnmarshalTypeMismatchNamesTheProperty(t *testing.T) {
+	cmd, _ := NewCmd("configure")
+	cmd.SetPropertyString("sample_rate", "not-a-number")
+
+	var params audioParams
+	if err := cmd.Unmarshal(&params); !errors.Is(err, ErrPropertyTypeMismatch) {
+		t.Fatalf("Unmarshal(sample_rate=string) = %v, want ErrPropertyTypeMismatch", err)
+	}
+}
+
+func TestCmd_MarshalPopulatesPropertiesFromStruct(t *testing.T) {
+	cmd, _ := NewCmd("configure")
+	params := audioParams{
+		SampleRate: 48000,
+		Channels:   1,
+		Codec:      "pcm",
+		Vad:        vadConfig{Enabled: true, Threshold: 0.25},
+		Tags:       []string{"x", "y"},
+	}
+	if err := cmd.Marshal(&params); err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	rate, err := cmd.GetPropertyInt64("sample_rate")
+	if err != nil || rate != 48000 {
+		t.Fatalf("GetPropertyInt64(sample_rate) = %d, %v, want 48000, nil", rate, err)
+	}
+	enabled, err := cmd.GetPropertyBool("vad.enabled")
+	if err != nil || !enabled {
+		t.Fatalf("GetPropertyBool(vad.enabled) = %v, %v, want true, nil", enabled, err)
+	}
+	keys, _ := cmd.PropertyKeysAt("")
+	if cmd.HasProperty("Ignored") {
+		t.Fatalf("PropertyKeys = %v, want no key for the \"-\"-tagged field", keys)
+	}
+}
+
+func TestCmd_MarshalRejectsNonStructSource(t *testing.T) {
+	cmd, _ := NewCmd("configure")
+	if err := cmd.Marshal("not-a-struct"); !errors.Is(err, ErrMarshalSource) {
+		t.Fatalf("Marshal(non-struct) = %v, want ErrMarshalSource", err)
+	}
+}
+
+func TestCmdResult_UnmarshalPopulatesFieldsFromProperties(t *testing.T) {
+	cmd, _ := NewCmd("start")
+	result, _ := NewCmdResult(StatusCodeOk, cmd)
+	result.SetPropertyInt64("sample_rate", 44100)
+	result.SetPropertyString("codec", "aac")
+
+	type startResp struct {
+		SampleRate int64  `ten:"sample_rate"`
+		Codec      string `ten:"codec"`
+	}
+	var resp startResp
+	if err := result.Unmarshal(&resp); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if resp.SampleRate != 44100 || resp.Codec != "aac" {
+		t.Fatalf("resp = %+v, want SampleRate 44100, Codec aac", resp)
+	}
+}
+
+func TestCmdResult_UnmarshalRejectsNonPointerTarget(t *testing.T) {
+	cmd, _ := NewCmd("start")
+	result, _ := NewCmdResult(StatusCodeOk, cmd)
+	if err := result.Unmarshal(audioParams{}); err == nil {
+		t.Fatal("Unmarshal(non-pointer) = nil error, want an error")
+	}
+}
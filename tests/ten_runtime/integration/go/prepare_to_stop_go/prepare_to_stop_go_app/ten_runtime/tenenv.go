@@ -0,0 +1,1229 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// CmdResultHandler is invoked once for every CmdResult a sent Cmd produces.
+// err reports a send failure (the cmd never reached its destination);
+// result.StatusCode() classifies an outcome that did reach it. The tenEnv
+// handed to the callback may not carry the outer OnCmd's WithCommandLogging
+// correlation ID; call ForCommand(cmd) on it to get that back.
+type CmdResultHandler func(tenEnv TenEnv, result CmdResult, err error)
+
+// CmdResultExHandler is invoked once per CmdResult a SendCmdEx'd Cmd
+// produces, isFinal marking the terminal one. Exactly one call with
+// isFinal == true is guaranteed for a given Cmd.
+type CmdResultExHandler func(
+	tenEnv TenEnv, result CmdResult, isFinal bool, err error,
+)
+
+// TenEnv is the handle an Extension uses to talk back to the runtime: to
+// log, to send commands on to the rest of the graph, to return results for
+// commands it was itself handed, and to acknowledge lifecycle callbacks.
+type TenEnv interface {
+	Log(level LogLevel, msg string)
+
+	// LogFields is Log with structured key-value fields attached (see
+	// String/Int/Err), rendered per the Engine's LogFormat (see
+	// WithLogFormat) before being handed to Log -- so LogFields output
+	// is always a normal Log call under the hood.
+	LogFields(level LogLevel, msg string, fields ...Field)
+
+	// IsLogLevelEnabled reports whether level is enabled for this
+	// extension's Engine (see WithLogLevel), so a caller can skip
+	// expensive message construction for a level that would be discarded
+	// anyway. LogLazy already does this for the common case.
+	IsLogLevelEnabled(level LogLevel) bool
+
+	// GetLogLevel returns the minimum level currently enabled for this
+	// extension (lower is more verbose, matching the LogLevel constants'
+	// ordering). It reflects Engine.SetLogLevel's current value, so a
+	// caller that reads it more than once may see it change between
+	// calls if the level is adjusted at runtime.
+	GetLogLevel() LogLevel
+
+	// LogLazy calls fn and logs its result at level, but only if
+	// IsLogLevelEnabled(level) -- so a caller can defer an expensive
+	// fmt.Sprintf-built message to a closure without paying for it when
+	// the level is disabled. fn must be side-effect free: it may never
+	// run.
+	LogLazy(level LogLevel, fn func() string)
+
+	// SetLogSink routes this extension's Log/LogFields output to w as
+	// well, taking precedence over the process-wide sink SetLogSink (the
+	// package-level function) configures. See that function's doc
+	// comment for the format argument and the interleaving guarantee. A
+	// nil w reverts to the process-wide sink, if any.
+	SetLogSink(w io.Writer, format LogFormat)
+
+	// SetLogBuffering switches this extension's Log/LogFields/LogLazy
+	// calls from writing immediately to accumulating on the Go side and
+	// flushing in batches, trading a little log latency for far fewer
+	// crossings into the native logger under high-frequency logging. See
+	// its doc comment in logbuffering.go for the exact flush triggers and
+	// ordering guarantee.
+	SetLogBuffering(maxBatch int, maxDelay time.Duration)
+
+	// Metrics returns a MetricsRecorder for this extension to record its
+	// own counters and latency observations against. If the Engine wasn't
+	// constructed with WithMetricsRegistry, it returns a no-op recorder --
+	// metrics collection is opt-in, so an extension that calls Metrics()
+	// unconditionally never has to check whether anyone is scraping it.
+	Metrics() MetricsRecorder
+
+	// StartSpan opens a Span for cmd, continuing whatever trace cmd
+	// already carries in its reserved trace properties (see the Span doc
+	// comment) or starting a fresh one if it carries none, and writes the
+	// resulting trace/span IDs back onto cmd so that sending cmd (or a
+	// Clone of it) via SendCmd/SendCmdEx/SendCmdChan carries them to a
+	// downstream extension, whose own StartSpan call then continues the
+	// same trace. This package has no go.opentelemetry.io/otel dependency
+	// (see go.mod's empty require block); Span exposes just the
+	// TraceID/SpanID/End surface an OTel bridge would need to seed a real
+	// otel.Tracer's span from, without this package importing otel itself.
+	StartSpan(cmd Cmd) (context.Context, *Span)
+
+	// WithCommandLogging returns a TenEnv identical to this one except
+	// that every Log/LogFields/LogLazy call made through it also carries
+	// a "correlation_id" field identifying the request cmd is part of --
+	// so every log line touched by handling one command, across every
+	// extension it's forwarded to via SendCmd, can be grepped back
+	// together. The correlation ID is the same value StartSpan uses as a
+	// trace ID (see trace.go): if cmd already carries one, from an
+	// upstream SendCmd, it's reused; otherwise a fresh one is generated
+	// and stamped onto cmd so it propagates onward the same way a trace
+	// does. Calling StartSpan and WithCommandLogging on the same cmd
+	// therefore shares one ID between tracing and logging, rather than
+	// minting two unrelated identifiers for the same request.
+	WithCommandLogging(cmd Cmd) TenEnv
+
+	// ForCommand returns the TenEnv code should use for operations scoped
+	// to cmd -- currently identical to WithCommandLogging(cmd) -- so a
+	// callback that only wants "the right env for this cmd" has one name
+	// to reach for regardless of whether what it's about to do is
+	// ReturnResult (where, per CmdResultHandler's doc comment, any TenEnv
+	// sharing this one's Engine already works) or something env-specific
+	// like Log. Safe to call on any TenEnv for the same cmd, including one
+	// received from inside a CmdResultHandler/CmdResultExHandler that
+	// wasn't the original caller's own.
+	ForCommand(cmd Cmd) TenEnv
+
+	SendCmd(cmd Cmd, handler CmdResultHandler) error
+	ReturnResult(result CmdResult, cmd Cmd) error
+
+	// CmdContext returns the context cmd was delivered with, if it arrived
+	// via Engine.DeliverCmdWithContext -- Done() closes when the
+	// originating caller cancels or its deadline expires, so OnCmd (or a
+	// goroutine it spawns to keep working after OnCmd returns) can stop
+	// early instead of finishing work nobody wants the result of anymore,
+	// e.g. an LLM extension abandoning mid-generation when the user hangs
+	// up. A cmd delivered via plain DeliverCmd, which carries no caller
+	// context, gets context.Background(): Done() never closes.
+	CmdContext(cmd Cmd) context.Context
+
+	// SetLocalValue/GetLocalValue attach an arbitrary Go value bag to cmd
+	// for a downstream in-process extension that also holds cmd -- a
+	// *sql.Tx, an open connection, anything request-scoped that can't be
+	// serialized into a property. They are a pure in-process side channel:
+	// a value stored this way never crosses a process or app boundary and
+	// is silently dropped if cmd is ever serialized, unlike a property.
+	// The bag is released once cmd's final result is returned, so
+	// GetLocalValue's second return is false both for a key that was never
+	// set and for one on a cmd that has already completed.
+	SetLocalValue(cmd Cmd, key any, val any)
+	GetLocalValue(cmd Cmd, key any) (any, bool)
+
+	// SendAudioFrame/SendVideoFrame send a media frame built via
+	// NewAudioFrame/NewVideoFrame downstream. cb reports a send failure;
+	// there is no result to deliver on success, unlike SendCmd.
+	SendAudioFrame(frame AudioFrame, cb func(TenEnv, error)) error
+	SendVideoFrame(frame VideoFrame, cb func(TenEnv, error)) error
+
+	// SendData sends a Data message downstream, fire-and-forget: cb
+	// reports a send failure the same way SendAudioFrame's does, but
+	// firing it only means the local send call completed, not that
+	// whatever is downstream has room for it. SendDataWithAck and
+	// DataQueueDepth, declared in senddata.go, are for a producer that
+	// needs to know the difference.
+	SendData(data Data, cb func(TenEnv, error)) error
+
+	// ReturnError builds a final, StatusCodeError CmdResult for cmd with
+	// its "detail" property set to detail, and returns it via
+	// ReturnResult -- the create/set/return sequence extensions otherwise
+	// repeat by hand. Unlike that hand-written version, it doesn't
+	// silently discard NewCmdResult's error. ReturnErrorf is the same
+	// with a format string. ReturnOK is the StatusCodeOk counterpart for
+	// the success path.
+	ReturnError(cmd Cmd, detail string) error
+	ReturnErrorf(cmd Cmd, format string, args ...any) error
+	ReturnOK(cmd Cmd, detail string) error
+
+	// SendCmdWithContext is like SendCmd, except cancellation or deadline
+	// expiry of ctx invokes handler exactly once with ErrContextCanceled;
+	// a real result arriving after that is dropped, and a real result
+	// winning the race suppresses the later ctx cancellation. Enforced
+	// entirely on the Go side, independent of any runtime-level timeout.
+	SendCmdWithContext(
+		ctx context.Context, cmd Cmd, handler CmdResultHandler,
+	) error
+
+	// SendCmdCancelable is like SendCmd, except it also returns a
+	// CmdHandle whose Cancel method aborts this specific cmd: handler
+	// fires exactly once, with ErrCanceled, and a real result arriving
+	// afterward is dropped. It's finer-grained than SendCmdWithContext,
+	// which cancels through a whole context, and composes with it --
+	// whichever of a Cancel call, a ctx expiring or a real result comes
+	// first is the one handler sees. Cancelling a cmd that has already
+	// completed, or calling Cancel twice, is a harmless no-op. See
+	// cmdhandle.go.
+	SendCmdCancelable(cmd Cmd, handler CmdResultHandler) (CmdHandle, error)
+
+	// SendCmdWithRetry sends cmd, and for a result or error policy's
+	// ShouldRetry judges retryable, re-sends up to policy.MaxAttempts
+	// times before finally invoking handler. Each retry is a fresh send
+	// of a Clone of cmd, never the original or a previous attempt's Cmd,
+	// since finalized's "exactly one final result" bookkeeping is
+	// per-Cmd (see Clone's doc comment). policy.Backoff, if set, is
+	// consulted between attempts; ctx being cancelled or expiring, either
+	// before an attempt starts or during a backoff wait, invokes handler
+	// with ErrContextCanceled instead of retrying further, the same way
+	// SendCmdWithContext reports it.
+	SendCmdWithRetry(
+		ctx context.Context, cmd Cmd, policy RetryPolicy, handler CmdResultHandler,
+	) error
+
+	// SendCmdAndWait is a blocking variant of SendCmd for the common case
+	// of a command with exactly one result: it blocks the calling
+	// goroutine until that result (or a send/shutdown error) is available.
+	// It must be called from a goroutine the extension itself spawned, not
+	// synchronously from OnCmd's own dispatch goroutine -- doing so returns
+	// ErrSendCmdAndWaitOnDispatchGoroutine instead of hanging.
+	SendCmdAndWait(cmd Cmd) (CmdResult, error)
+
+	// SendCmdEx is like SendCmd but the callback may be invoked more than
+	// once: zero or more non-final progress results followed by exactly
+	// one final result.
+	SendCmdEx(cmd Cmd, handler CmdResultExHandler) error
+
+	// ReturnResultEx is like ReturnResult but tags result with an is_final
+	// bit instead of always closing the cmd. It returns ErrCmdAlreadyFinal
+	// if a final result was already returned for cmd.
+	ReturnResultEx(result CmdResult, cmd Cmd, isFinal bool) error
+
+	// AcceptCommand is for a cmd representing a long-running job: it sends
+	// an immediate non-final "accepted" ack (the same ack ReturnResultEx(
+	// ..., false) would send) and hands back a JobHandle whose Complete
+	// delivers cmd's real, terminal result whenever the job actually
+	// finishes -- possibly long after OnCmd itself has returned, from a
+	// goroutine the extension spawned for the work. It keeps cmd alive
+	// across that gap by registering it with the engine's pending-call
+	// tracking, the same mechanism SendCmd/SendCmdEx use: a JobHandle still
+	// outstanding when the engine's shutdown deadline or stop watchdog
+	// fires is force-completed with an ErrShutdownTimeout result, so a job
+	// an extension never finishes can't wedge Stop forever. See jobhandle.go.
+	AcceptCommand(cmd Cmd) (JobHandle, error)
+
+	// NewResultStream opens a ResultStream for cmd, letting a caller emit
+	// a series of interim results followed by one final one without
+	// juggling CmdResult.SetIsFinal itself; see ResultStream and
+	// resultstream.go for the full contract, including what happens if
+	// the stream is never Closed.
+	NewResultStream(cmd Cmd) (ResultStream, error)
+
+	// SendCmdChan is a channel-based variant of SendCmdEx for callers who
+	// would rather range over results than nest callbacks. See
+	// CmdResultOrError for what's delivered on the channel.
+	SendCmdChan(cmd Cmd) (<-chan CmdResultOrError, error)
+
+	// SendCmdMulti fans cmds out concurrently and gathers every one's
+	// final result, so a scatter/gather caller doesn't have to hand-roll
+	// its own WaitGroup and result slice around repeated SendCmd calls.
+	// See sendcmdmulti.go for the ordering and partial-failure contract.
+	SendCmdMulti(cmds []Cmd) ([]CmdResult, error)
+
+	// SendCmdAny fans cmds out concurrently and returns the first
+	// successful (StatusCodeOk) result, cancelling every cmd still
+	// outstanding so it releases its concurrency slot right away -- a
+	// hedged-request pattern for a caller that only needs one of several
+	// equivalent cmds to come back. See sendcmdany.go for the full
+	// cancellation and error-aggregation contract.
+	SendCmdAny(cmds []Cmd) (CmdResult, error)
+
+	// SendCmdQuorum is SendCmdAny generalized to n agreeing results
+	// instead of one, for a caller that wants majority (or any other
+	// fixed-count) agreement before acting. It returns ErrNoQuorum once
+	// reaching n successes is no longer possible. See sendcmdany.go.
+	SendCmdQuorum(cmds []Cmd, n int) ([]CmdResult, error)
+
+	// SetPropertySchema registers the property schema an extension expects
+	// its supplied properties (see WithProperties) to satisfy, as a JSON
+	// object shaped like {"properties": {"sample_rate": {"type": "int64",
+	// "required": true}}}. It's meant to be called from OnConfigure: Init
+	// validates the properties against whatever schema is registered by
+	// the time OnConfigure calls OnConfigureDone, before OnInit ever runs,
+	// failing with ErrPropertySchemaViolation if a required property is
+	// missing or a present one doesn't match its declared type. Calling it
+	// more than once replaces the previously registered schema.
+	SetPropertySchema(schemaJSON []byte) error
+
+	// GetPropertyString, GetPropertyInt64, GetPropertyFloat64 and
+	// GetPropertyBool read from the same property store WithProperties
+	// seeds, TenEnv.EnableEnvInterpolation expands and
+	// TenEnv.SetPropertySchema's declared defaults fill in -- by the time
+	// OnInit runs, a property with a schema default is already present
+	// even if the graph never supplied it, so an extension reading it
+	// doesn't need its own "if err != nil { v = 0.5 }" fallback. They
+	// follow the same not-found/type-mismatch rules as Cmd's identically
+	// named getters. Engine.UpdateProperty is how a value at path changes
+	// after Init; see also OnPropertyChanged for observing that.
+	GetPropertyString(path string) (string, error)
+	GetPropertyInt64(path string) (int64, error)
+	GetPropertyFloat64(path string) (float64, error)
+	GetPropertyBool(path string) (bool, error)
+
+	// EnableEnvInterpolation opts an extension's properties (see
+	// WithProperties) into environment-variable expansion: once enabled,
+	// every string property value is scanned for ${VAR} and
+	// ${VAR:-default} references, which are expanded against the process
+	// environment before Init validates properties against the schema (if
+	// any) or calls OnInit -- so a graph JSON can reference a secret like
+	// ${OPENAI_API_KEY} instead of baking it in. A literal "$" is written
+	// as "$$". It's meant to be called from OnConfigure, disabled by
+	// default so a graph relying on a literal "${...}"-shaped string isn't
+	// silently reinterpreted. If a referenced variable is unset and no
+	// default was given, Init fails with ErrEnvVarNotSet instead of
+	// letting OnInit see a half-expanded value.
+	EnableEnvInterpolation(enabled bool)
+
+	// InitPropertyFromJSON merges jsonBytes into the extension's property
+	// store (the same store WithProperties seeds and SetPropertySchema
+	// validates), without overwriting anything already present -- so an
+	// extension can call it from OnConfigure or OnStart to ship sane
+	// defaults while letting a deployer's graph config, loaded first via
+	// WithProperties, take precedence. A nested object merges recursively,
+	// key by key; any other value, including an array, is left alone
+	// entirely if the property already exists (arrays are never merged
+	// element-wise) and copied in as-is if it doesn't. It returns an error
+	// if jsonBytes isn't a JSON object.
+	InitPropertyFromJSON(jsonBytes []byte) error
+
+	// OnPropertyChanged registers cb to be called whenever path's value
+	// changes via Engine.UpdateProperty, e.g. an operator tweaking a
+	// "gain" property at runtime instead of only at start. cb runs on the
+	// extension's dispatch goroutine -- the same serialization OnCmd gets
+	// -- so it never races an in-flight OnCmd or another notification.
+	// Subscribing to a nested path (like "audio.gain") also fires when an
+	// ancestor object ("audio") is replaced wholesale, and subscribing to
+	// an object fires when any of its fields changes individually; see
+	// UpdateProperty's doc comment for the exact rule. Calling it more
+	// than once for the same path adds another independent subscriber
+	// rather than replacing the previous one.
+	OnPropertyChanged(path string, cb func(newValue any))
+
+	// OnAppSignal registers cb to run whenever the app hosting this
+	// extension's graph reports a lifecycle event -- see AppSignal's
+	// constants and appsignal.go for the full ordering guarantee relative
+	// to OnStop. Calling it more than once adds another independent
+	// callback rather than replacing the previous one, the same as
+	// OnPropertyChanged.
+	OnAppSignal(cb func(sig AppSignal))
+
+	// GetExtensionName, GetGraphID and GetAppURI report this extension
+	// instance's identity within its graph: the name a deployer's graph
+	// config gave it (see WithExtensionName), the ID of the graph it was
+	// loaded into (see WithGraphID), and the URI of the app it runs
+	// inside (see WithAppURI). All three are read-only and only
+	// meaningful from OnStart onward -- calling any of them earlier
+	// returns ErrExtensionIdentityNotYetAvailable, matching a real
+	// ten_runtime engine not finishing an extension's placement into its
+	// graph until then.
+	GetExtensionName() (string, error)
+	GetGraphID() (string, error)
+	GetAppURI() (string, error)
+
+	// GetGraphInfo reports this extension instance's own graph -- the
+	// caller's own entry plus every instance created within it via
+	// CreateExtension, each with its current lifecycle state -- as a
+	// read-only, point-in-time snapshot: nothing else in this package
+	// enforces the ConnectionSpecs it reports (see ConnectionSpec's doc
+	// comment), so Connections describes intent, not traffic actually
+	// observed. Safe to call from any extension in the graph, at any
+	// time from OnStart onward; like GetExtensionName/GetGraphID/GetAppURI
+	// it returns ErrExtensionIdentityNotYetAvailable before that, since a
+	// real ten_runtime engine hasn't placed this extension into a graph
+	// yet either. See graphinfo.go.
+	GetGraphInfo() (*GraphInfo, error)
+
+	// CreateExtension and DestroyExtension let an extension spin up (and
+	// later tear down) another extension instance at runtime instead of
+	// only ever the ones declared in the graph's JSON -- e.g. one per
+	// active call session. See CreateExtension/DestroyExtension and
+	// ExtensionHandle/ConnectionSpec in dynamic.go for the full contract.
+	CreateExtension(addonName, instanceName string, conns ...ConnectionSpec) (ExtensionHandle, error)
+	DestroyExtension(handle ExtensionHandle) error
+
+	// SendCmdToExtension sends cmd straight to the instance named extName
+	// within this extension's own graph, overriding connection-based
+	// routing -- e.g. a supervisor addressing a session-specific worker it
+	// created itself via CreateExtension, whose name it already knows,
+	// rather than relying on a ConnectionSpec it never declared. handler
+	// is invoked exactly once with cmd's final CmdResult, the same
+	// contract SendCmd's handler has; pass a nil handler to fire cmd
+	// without waiting for one. It returns ErrExtensionInstanceNotFound if
+	// extName doesn't name a currently live instance. See
+	// sendcmdtoextension.go for why this can only ever address an
+	// instance in the calling extension's own graph, never a different
+	// graph or a different app.
+	SendCmdToExtension(extName string, cmd Cmd, handler CmdResultHandler) error
+
+	// Ping measures round-trip time to extName within this extension's
+	// own graph: it sends the reserved PingCmdName cmd via
+	// SendCmdToExtension and blocks until extName's DefaultExtension-
+	// provided pong answers it, returning the elapsed time. extName is
+	// resolved the same way SendCmdToExtension resolves it, so the same
+	// ErrExtensionInstanceNotFound applies. Like SendCmdAndWait, it must
+	// be called from a goroutine the extension itself spawned, not
+	// synchronously from OnCmd's own dispatch goroutine -- doing so
+	// returns ErrPingOnDispatchGoroutine instead of hanging.
+	Ping(extName string) (time.Duration, error)
+
+	// LookupExtension resolves name the same way SendCmdToExtension
+	// resolves extName, and, if the resolved instance opted in via
+	// WithDirectCallable(true), returns a DirectRef to it -- an advanced,
+	// opt-in, in-process bypass of the ordinary message-passing path for
+	// two tightly coupled extensions. See DirectRef.Call for its
+	// threading and reentrancy caveats before reaching for it.
+	LookupExtension(name string) (DirectRef, error)
+
+	// SetRateLimit caps how many cmds named cmdName DeliverCmd passes
+	// through to OnCmd per second, via a token bucket that starts full
+	// (burst tokens) and refills at perSecond tokens/second.
+	//
+	// By default an over-limit cmd gets a StatusCodeError result whose
+	// detail is "rate limited"; pass WithRateLimitMode(RateLimitModeQueue)
+	// to hold it for a token instead. Calling SetRateLimit again for the
+	// same cmdName replaces the previous limiter. See ratelimit.go.
+	SetRateLimit(cmdName string, perSecond, burst int, opts ...RateLimitOption)
+
+	// SetCmdFilter narrows the cmd names DeliverCmd dispatches to OnCmd:
+	// once at least one filter is registered, only a cmd whose name
+	// matches pattern (a glob per path.Match) reaches OnCmd at all.
+	// Registering more than once adds patterns rather than replacing the
+	// last one. A non-matching cmd gets ErrCmdFilterNoMatch instead of
+	// being dispatched. See cmdfilter.go.
+	SetCmdFilter(pattern string)
+
+	// ConnectionStats reports point-in-time depth and drop bookkeeping for
+	// this instance's internal message queues, for tuning
+	// WithPendingCmdQueueCapacity.
+	ConnectionStats() ([]ConnStat, error)
+
+	// BeginDrain flips the extension into drain mode: every cmd
+	// DeliverCmd receives afterward gets a StatusCodeError result whose
+	// detail is "draining" instead of reaching OnCmd. Cmds already
+	// dispatched keep running; see InflightCount to know when they've all
+	// finished. There's no EndDrain. See drain.go.
+	BeginDrain()
+
+	// InflightCount reports how many cmds this extension is currently
+	// processing: dispatched to OnCmd but not yet given a final result.
+	InflightCount() int
+
+	// SetSlowHandlerThreshold turns on slow-handler warnings: once set to
+	// a positive duration, every cmd dispatched to OnCmd is timed until
+	// it's finalized (MarkHandlerComplete, or else its final result). If
+	// that took longer than d, a LogLevelWarn line names the cmd, the
+	// elapsed duration and this extension. Zero, the default, disables
+	// timing altogether.
+	SetSlowHandlerThreshold(d time.Duration)
+
+	// MarkHandlerComplete marks the moment a handler's timed work actually
+	// finished, independent of when it returns a formal result. A handler
+	// that never calls it is still timed: ReturnResult and
+	// ReturnResultEx(..., true) call it themselves as a fallback. Calling it
+	// again for an already-finalized cmd is a harmless no-op.
+	MarkHandlerComplete(cmd Cmd)
+
+	// ReturnResultWithToken completes the cmd Cmd.DetachResponder issued
+	// token for, from whichever extension actually computed the answer --
+	// the redeeming half of a detach-and-forward handoff, where extension A
+	// passes the token to B instead of blocking on its own SendCmd.
+	//
+	// A token may be redeemed at most once; a second redemption, or one for
+	// an invalid token, fails with ErrResponderTokenInvalid. A token that's
+	// never redeemed leaves cmd unfinalized indefinitely -- set your own
+	// deadline (see SetDeadline) before detaching if that's a concern.
+	ReturnResultWithToken(token ResponderToken, result CmdResult) error
+
+	// SetCommandTimeout arms a watchdog for cmd, called from within OnCmd:
+	// if this extension hasn't given cmd a final result via ReturnResult
+	// or ReturnResultEx(..., true) within d, the runtime does it instead
+	// -- auto-returning a StatusCodeError result whose detail is "command
+	// timeout" -- so one buggy extension that forgets to respond can't
+	// wedge an otherwise-healthy graph waiting on it forever. Whichever
+	// of the auto-return and a racing manual return actually claims cmd
+	// first wins; the other is discarded, with a LogLevelWarn line naming
+	// the cmd, rather than both running and finalizing the same cmd
+	// twice. Calling it again for the same cmd replaces the previous
+	// deadline, discarding its timer, the same way SetRateLimit replaces
+	// a cmd name's previous limiter.
+	SetCommandTimeout(cmd Cmd, d time.Duration)
+
+	// SetClock overrides the Clock this extension's Engine uses for its
+	// internal timeout/backoff logic -- SetCommandTimeout today -- in
+	// place of the real time package NewEngine defaults to. Intended for
+	// tests: pass a tentest.FakeClock so a SetCommandTimeout watchdog can
+	// be made to fire by advancing it, instead of a real duration
+	// elapsing. See also the WithClock EngineOption, for setting it at
+	// construction time rather than from within a running extension.
+	SetClock(clock Clock)
+
+	// SetCmdConcurrencyLimit caps how many SendCmd/SendCmdEx calls may be
+	// outstanding at once -- waiting on a downstream final result -- so an
+	// extension can bound how hard it hits a downstream service without
+	// hand-rolling its own semaphore around every send. By default
+	// (CmdConcurrencyModeBlock) a call past the limit blocks until a slot
+	// frees up; pass WithCmdConcurrencyMode(CmdConcurrencyModeNonBlocking)
+	// to return ErrWouldBlock immediately instead. A limit <= 0, the
+	// default, means unbounded. See cmdconcurrency.go for how
+	// SendCmdWithContext releases its slot immediately on cancellation
+	// instead of waiting for whatever real result shows up afterward.
+	SetCmdConcurrencyLimit(n int, opts ...CmdConcurrencyOption)
+
+	// SendDataWithAck sends data downstream the same way SendData does,
+	// except cb only fires once it's actually been accepted into the
+	// destination's queue (data.GetDests()'s first entry, or "" if none
+	// was set), not merely handed to the local sender. Combined with
+	// DataQueueDepth, a fast producer can check how backed up a
+	// destination is, or rely on SetDataQueueCapacity's policy to slow
+	// it down automatically instead of growing memory without bound. See
+	// senddata.go.
+	SendDataWithAck(data Data, cb func(TenEnv, error)) error
+
+	// DataQueueDepth reports how many SendDataWithAck calls are currently
+	// admitted to destExtension's queue and not yet acknowledged by its
+	// dataSender. A destination that's never had SetDataQueueCapacity or
+	// SendDataWithAck called for it reports 0.
+	DataQueueDepth(destExtension string) (int, error)
+
+	// SetDataQueueCapacity bounds destExtension's SendDataWithAck queue
+	// depth and selects what happens once it's full: see DataQueuePolicy.
+	// capacity <= 0 means unbounded (the default for any destination this
+	// is never called for), so SendDataWithAck always admits immediately
+	// and policy is never consulted. Calling it again for the same
+	// destExtension replaces the previous capacity/policy but not
+	// whatever is already admitted and waiting on acknowledgement.
+	SetDataQueueCapacity(destExtension string, capacity int, policy DataQueuePolicy)
+
+	OnConfigureDone()
+	OnInitDone()
+	OnStartDone()
+	OnStopDone()
+	OnDeinitDone()
+
+	// DeferStopDone returns a func that calls OnStopDone the first time
+	// it's invoked, meant to be used as defer tenEnv.DeferStopDone()() at
+	// the top of OnStop so a stray early return can't forget to call
+	// OnStopDone and hang the graph's shutdown. It's for an OnStop with no
+	// outstanding goroutines or commands to wait on; an OnStop that needs
+	// to wait for in-flight work to finish first should use a StopGuard
+	// instead, which calls OnStopDone itself once that work reaches zero.
+	DeferStopDone() func()
+
+	// OnPauseDone/OnResumeDone acknowledge OnPause/OnResume, letting the
+	// runtime complete the pause/resume transition it is waiting on.
+	OnPauseDone()
+	OnResumeDone()
+
+	// ShutdownContext is Done() once OnStop's graceful-shutdown deadline
+	// (see WithShutdownDeadline) expires, so idiomatic Go code can write
+	// select { case <-myChan: case <-tenEnv.ShutdownContext().Done(): }.
+	// Before OnStop has been called it never fires.
+	ShutdownContext() context.Context
+
+	// Context returns a context.Context tied to the extension's lifetime:
+	// live from construction (so it's always safe to pass into an
+	// outbound HTTP/gRPC call made from OnStart onward) and cancelled as
+	// the very first thing Stop does, before OnStop is even invoked -- so
+	// a goroutine spawned from OnCmd can select on ctx.Done() instead of
+	// inventing its own stopChan. It's distinct from ShutdownContext,
+	// which stays live for the whole graceful-shutdown window and is only
+	// cancelled once OnStop's deadline actually expires; Context answers
+	// "is the extension stopping at all", ShutdownContext answers "has
+	// this extension overstayed its shutdown budget". Every call returns
+	// the same Context instance for a given extension.
+	Context() context.Context
+
+	// IsStopped reports whether this extension has already been stopped
+	// (or is in the middle of stopping): the same state SendCmd, SendCmdEx,
+	// SendData, SendAudioFrame, SendVideoFrame and ReturnResult/
+	// ReturnResultEx already check before doing anything, returning an
+	// error wrapping ErrStopped instead of a crash or a silent no-op. It's
+	// for a goroutine spawned from OnCmd that outlives OnStop -- e.g. one
+	// blocked on a channel that might not wake up until well after
+	// shutdown -- to check proactively, either instead of or in addition
+	// to selecting on Context().Done(), before touching tenEnv again.
+	IsStopped() bool
+
+	// Uptime reports how long this extension's Engine has existed,
+	// measured from NewEngine -- not from OnStart, since there's no
+	// reliable "OnStart finished" timestamp to record without giving
+	// DefaultExtension's OnStart a pointer receiver, which would stop
+	// DefaultExtension{} being usable by value (see the many tests and
+	// extensions that embed and pass it that way). In practice the two
+	// only differ by however long OnConfigure/OnInit took to run.
+	Uptime() time.Duration
+
+	// FlushLogs blocks until every Log/LogFields/LogLazy call submitted
+	// before it returns has been durably written, so the final messages
+	// before a crash or a deliberate os.Exit aren't lost to a buffer that
+	// never got flushed. Stop calls it automatically right after
+	// OnStopDone (or after the graceful-shutdown deadline forces it), so
+	// most extensions never need to call it themselves; it's exposed for
+	// the case where an extension is about to os.Exit on its own, outside
+	// the normal Stop path, and wants the same guarantee first. Log
+	// writes in this package are already synchronous (see Log's
+	// implementation), so FlushLogs never actually has to wait -- it
+	// exists so the call site is correct regardless of that detail, the
+	// same way it would be against a real ten_runtime log subsystem whose
+	// C-side buffer is asynchronous.
+	FlushLogs() error
+
+	// SetStopTimeout sets or overrides the graceful-shutdown deadline
+	// WithShutdownDeadline configures at construction time: once OnStop is
+	// invoked, Stop() waits at most d for OnStopDone before logging a
+	// warning, cancelling ShutdownContext, and forcing OnStopDone on the
+	// extension's behalf so the app is guaranteed to shut down instead of
+	// hanging on a wedged dependency. Call it any time before OnStop runs
+	// -- typically from OnConfigure or OnInit -- for a deadline that isn't
+	// known until runtime. A d of 0 means wait indefinitely.
+	SetStopTimeout(d time.Duration)
+
+	// SetStartTimeout bounds how long Start() waits for OnStartDone: once
+	// it expires, Start cancels StartContext, logs an error naming the
+	// extension, and returns ErrStartTimeout instead of hanging the whole
+	// graph's startup on a wedged dependency (e.g. a database that never
+	// answers). Call it from OnConfigure or OnInit, before OnStart runs.
+	// A d of 0 (the default) means wait indefinitely, mirroring
+	// SetStopTimeout's default. Unlike SetStopTimeout's forced
+	// OnStopDone, there is no equivalent forced OnStartDone: a start that
+	// timed out is reported as a failure, not silently treated as having
+	// succeeded.
+	SetStartTimeout(d time.Duration)
+
+	// StartContext is Done() once SetStartTimeout's deadline expires
+	// without OnStartDone having been called. Before Start is called, or
+	// once it has already returned successfully, it never fires. An
+	// OnStart doing async setup can select on it the same way an OnStop
+	// selects on ShutdownContext, to abandon work that's about to be
+	// reported as a start failure anyway.
+	StartContext() context.Context
+}
+
+// tenEnvImpl is the Engine-backed TenEnv handed to an Extension instance.
+type tenEnvImpl struct {
+	engine *Engine
+}
+
+func newTenEnv(engine *Engine) *tenEnvImpl {
+	return &tenEnvImpl{engine: engine}
+}
+
+func (t *tenEnvImpl) Log(level LogLevel, msg string) {
+	t.engine.logWG.Add(1)
+	defer t.engine.logWG.Done()
+	if t.engine.tryBufferLog(level, msg) {
+		return
+	}
+	t.engine.writeLogRecord(level, msg)
+}
+
+// FlushLogs waits for every Log call already in flight to finish writing,
+// and drains whatever SetLogBuffering currently has buffered. Log writes
+// in this package are otherwise already synchronous (see Log's
+// implementation), so absent buffering FlushLogs never actually has to
+// wait -- it exists so the call site is correct regardless of that detail,
+// the same way it would be against a real ten_runtime log subsystem whose
+// C-side buffer is asynchronous.
+func (t *tenEnvImpl) FlushLogs() error {
+	t.engine.flushLogBuffer()
+	t.engine.logWG.Wait()
+	return nil
+}
+
+func (t *tenEnvImpl) LogFields(level LogLevel, msg string, fields ...Field) {
+	t.Log(level, formatFields(t.engine.logFormat, msg, fields))
+}
+
+func (t *tenEnvImpl) IsLogLevelEnabled(level LogLevel) bool {
+	return level >= t.engine.getLogLevel()
+}
+
+func (t *tenEnvImpl) GetLogLevel() LogLevel {
+	return t.engine.getLogLevel()
+}
+
+func (t *tenEnvImpl) LogLazy(level LogLevel, fn func() string) {
+	if !t.IsLogLevelEnabled(level) {
+		return
+	}
+	t.Log(level, fn())
+}
+
+func (t *tenEnvImpl) Metrics() MetricsRecorder {
+	t.engine.mu.Lock()
+	registry := t.engine.metrics
+	t.engine.mu.Unlock()
+	if registry == nil {
+		return nopMetricsRecorder{}
+	}
+	return registry
+}
+
+func (t *tenEnvImpl) StartSpan(cmd Cmd) (context.Context, *Span) {
+	span := newSpan(cmd)
+	ctx := context.WithValue(t.Context(), spanContextKey{}, span)
+	return ctx, span
+}
+
+func (t *tenEnvImpl) WithCommandLogging(cmd Cmd) TenEnv {
+	return &commandLoggingTenEnv{TenEnv: t, correlationID: correlationIDFor(cmd)}
+}
+
+func (t *tenEnvImpl) ForCommand(cmd Cmd) TenEnv {
+	return t.WithCommandLogging(cmd)
+}
+
+func (t *tenEnvImpl) SendCmd(cmd Cmd, handler CmdResultHandler) error {
+	if cmd == nil {
+		return fmt.Errorf("ten: SendCmd: cmd must not be nil")
+	}
+	if err := t.engine.checkSendable(); err != nil {
+		return err
+	}
+	release, err := t.engine.acquireCmdSlot()
+	if err != nil {
+		return err
+	}
+	_, err = t.sendCmdWithRelease(cmd, release, handler)
+	return err
+}
+
+// SendCmdCancelable implements TenEnv.SendCmdCancelable; see its doc
+// comment for the overall contract. It shares SendCmd's own body,
+// sendCmdWithRelease, just keeping the CmdHandle that body already builds
+// instead of discarding it.
+func (t *tenEnvImpl) SendCmdCancelable(cmd Cmd, handler CmdResultHandler) (CmdHandle, error) {
+	if cmd == nil {
+		return nil, fmt.Errorf("ten: SendCmdCancelable: cmd must not be nil")
+	}
+	if err := t.engine.checkSendable(); err != nil {
+		return nil, err
+	}
+	release, err := t.engine.acquireCmdSlot()
+	if err != nil {
+		return nil, err
+	}
+	return t.sendCmdWithRelease(cmd, release, handler)
+}
+
+// sendCmdWithRelease is SendCmd's (and SendCmdCancelable's) actual body,
+// taking an already-acquired concurrency slot's release func instead of
+// acquiring its own -- so SendCmdWithContext can acquire a slot up front
+// and release it itself the instant ctx is cancelled, rather than only
+// once a real result arrives through this method's normal path. release
+// is idempotent, so whichever of the two fires first is harmless. The
+// returned CmdHandle shares its resolve/release/handler with the send
+// itself, so a caller that ignores it (as plain SendCmd does) and one
+// that calls Cancel on it are both just racing to resolve the same
+// pending-call entry -- see CmdHandle.Cancel's doc comment.
+func (t *tenEnvImpl) sendCmdWithRelease(cmd Cmd, release func(), handler CmdResultHandler) (CmdHandle, error) {
+	stampCmdSource(t.engine, cmd)
+	resolve, peek := t.engine.registerPending(func() {
+		release()
+		handler(t, nil, ErrShutdownTimeout)
+	})
+	t.engine.sender(cmd, func(tenEnv TenEnv, result CmdResult, err error) {
+		// A sender may invoke handler once per intermediate result before
+		// a final one, the same way exSender does for SendCmdEx; a result
+		// says which via IsFinal (nil, e.g. on error, counts as final).
+		final := true
+		if result != nil {
+			final, _ = result.IsFinal()
+		}
+		if final {
+			if !resolve() {
+				// Already force-cancelled by a graceful-shutdown timeout or
+				// a CmdHandle.Cancel call.
+				return
+			}
+			release()
+		} else if !peek() {
+			return
+		}
+		handler(tenEnv, result, err)
+	})
+	return cmdHandle{resolve: resolve, release: release, tenEnv: t, handler: handler}, nil
+}
+
+func (t *tenEnvImpl) ReturnResult(result CmdResult, cmd Cmd) error {
+	if result == nil {
+		return fmt.Errorf("ten: ReturnResult: result must not be nil")
+	}
+	if err := t.engine.checkSendable(); err != nil {
+		return err
+	}
+	if !t.engine.claimCommandTimeout(cmd) {
+		t.engine.logIgnoredLateCommandTimeoutResult(cmd)
+		return nil
+	}
+	t.engine.finalizeReturnedCmd(cmd)
+	t.engine.notifyCrossExtResultHandler(cmd, result)
+	return nil
+}
+
+func (t *tenEnvImpl) SendAudioFrame(frame AudioFrame, cb func(TenEnv, error)) error {
+	if frame == nil {
+		return fmt.Errorf("ten: SendAudioFrame: frame must not be nil")
+	}
+	if err := t.engine.checkSendable(); err != nil {
+		return err
+	}
+	stampAudioFrameSource(t.engine, frame)
+	resolve, _ := t.engine.registerPending(func() {
+		cb(t, ErrShutdownTimeout)
+	})
+	t.engine.audioFrameSender(frame, func(tenEnv TenEnv, err error) {
+		if !resolve() {
+			return
+		}
+		cb(tenEnv, err)
+	})
+	return nil
+}
+
+func (t *tenEnvImpl) SendVideoFrame(frame VideoFrame, cb func(TenEnv, error)) error {
+	if frame == nil {
+		return fmt.Errorf("ten: SendVideoFrame: frame must not be nil")
+	}
+	if err := t.engine.checkSendable(); err != nil {
+		return err
+	}
+	stampVideoFrameSource(t.engine, frame)
+	resolve, _ := t.engine.registerPending(func() {
+		cb(t, ErrShutdownTimeout)
+	})
+	t.engine.videoFrameSender(frame, func(tenEnv TenEnv, err error) {
+		if !resolve() {
+			return
+		}
+		cb(tenEnv, err)
+	})
+	return nil
+}
+
+func (t *tenEnvImpl) SendData(data Data, cb func(TenEnv, error)) error {
+	if data == nil {
+		return fmt.Errorf("ten: SendData: data must not be nil")
+	}
+	if err := t.engine.checkSendable(); err != nil {
+		return err
+	}
+	stampDataSource(t.engine, data)
+	resolve, _ := t.engine.registerPending(func() {
+		cb(t, ErrShutdownTimeout)
+	})
+	t.engine.dataSender(data, func(tenEnv TenEnv, err error) {
+		if !resolve() {
+			return
+		}
+		cb(tenEnv, err)
+	})
+	return nil
+}
+
+func (t *tenEnvImpl) ReturnError(cmd Cmd, detail string) error {
+	result, err := NewCmdResult(StatusCodeError, cmd)
+	if err != nil {
+		return err
+	}
+	if err := result.SetPropertyString("detail", detail); err != nil {
+		return err
+	}
+	return t.ReturnResult(result, cmd)
+}
+
+func (t *tenEnvImpl) ReturnErrorf(cmd Cmd, format string, args ...any) error {
+	return t.ReturnError(cmd, fmt.Sprintf(format, args...))
+}
+
+func (t *tenEnvImpl) ReturnOK(cmd Cmd, detail string) error {
+	result, err := NewCmdResult(StatusCodeOk, cmd)
+	if err != nil {
+		return err
+	}
+	if err := result.SetPropertyString("detail", detail); err != nil {
+		return err
+	}
+	return t.ReturnResult(result, cmd)
+}
+
+// ErrContextCanceled is the error handed to a SendCmdWithContext handler
+// when its context is cancelled or its deadline expires before a real
+// result arrives.
+var ErrContextCanceled = errors.New("ten: cmd context canceled")
+
+func (t *tenEnvImpl) SendCmdWithContext(
+	ctx context.Context, cmd Cmd, handler CmdResultHandler,
+) error {
+	if cmd == nil {
+		return fmt.Errorf("ten: SendCmdWithContext: cmd must not be nil")
+	}
+	release, err := t.engine.acquireCmdSlot()
+	if err != nil {
+		return err
+	}
+
+	var once sync.Once
+	resolved := make(chan struct{})
+	fire := func(result CmdResult, err error) {
+		once.Do(func() {
+			close(resolved)
+			handler(t, result, err)
+		})
+	}
+
+	if _, err := t.sendCmdWithRelease(cmd, release, func(_ TenEnv, result CmdResult, err error) {
+		fire(result, err)
+	}); err != nil {
+		release()
+		return err
+	}
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			// Release this slot the instant ctx is cancelled, not whenever
+			// (if ever) the real result shows up -- release is idempotent,
+			// so it's harmless if the real result's own release call, from
+			// inside sendCmdWithRelease, still fires later.
+			release()
+			fire(nil, fmt.Errorf("%w: %v", ErrContextCanceled, ctx.Err()))
+		case <-resolved:
+		}
+	}()
+	return nil
+}
+
+func (t *tenEnvImpl) SendCmdAndWait(cmd Cmd) (CmdResult, error) {
+	if cmd == nil {
+		return nil, fmt.Errorf("ten: SendCmdAndWait: cmd must not be nil")
+	}
+	if atomic.LoadInt64(&t.engine.dispatchGoroutine) == goroutineID() {
+		return nil, ErrSendCmdAndWaitOnDispatchGoroutine
+	}
+
+	resultCh := make(chan CmdResultOrError, 1)
+	if err := t.SendCmd(cmd, func(_ TenEnv, result CmdResult, err error) {
+		resultCh <- CmdResultOrError{Result: result, IsFinal: true, Err: err}
+	}); err != nil {
+		return nil, err
+	}
+	v := <-resultCh
+	return v.Result, v.Err
+}
+
+func (t *tenEnvImpl) SendCmdEx(cmd Cmd, handler CmdResultExHandler) error {
+	if cmd == nil {
+		return fmt.Errorf("ten: SendCmdEx: cmd must not be nil")
+	}
+	if err := t.engine.checkSendable(); err != nil {
+		return err
+	}
+	release, err := t.engine.acquireCmdSlot()
+	if err != nil {
+		return err
+	}
+	stampCmdSource(t.engine, cmd)
+	resolve, peek := t.engine.registerPending(func() {
+		release()
+		handler(t, nil, true, ErrShutdownTimeout)
+	})
+	t.engine.exSender(
+		cmd,
+		func(tenEnv TenEnv, result CmdResult, isFinal bool, err error) {
+			if isFinal {
+				if !resolve() {
+					return
+				}
+				release()
+			} else if !peek() {
+				return
+			}
+			handler(tenEnv, result, isFinal, err)
+		},
+	)
+	return nil
+}
+
+// CmdResultOrError is delivered on the channel returned by SendCmdChan: one
+// value per CmdResult a SendCmdEx'd Cmd produces, or a single terminal value
+// with Err set (and Result nil) if the send itself fails asynchronously.
+type CmdResultOrError struct {
+	Result  CmdResult
+	IsFinal bool
+	Err     error
+}
+
+// SendCmdChan sends cmd and returns a channel that yields a CmdResultOrError
+// for every result SendCmdEx would have delivered to a callback, closing the
+// channel once the final one has been sent. The synchronous error return
+// covers immediate send failures (a nil cmd) so callers can bail out before
+// ranging over the channel.
+//
+// Results are queued on an unbounded internal buffer before being forwarded
+// to the channel, so a synchronous exSender that delivers every result
+// before the caller starts reading can never deadlock the send side.
+func (t *tenEnvImpl) SendCmdChan(cmd Cmd) (<-chan CmdResultOrError, error) {
+	if cmd == nil {
+		return nil, fmt.Errorf("ten: SendCmdChan: cmd must not be nil")
+	}
+
+	var (
+		mu     sync.Mutex
+		cond   = sync.NewCond(&mu)
+		queue  []CmdResultOrError
+		closed bool
+	)
+	push := func(v CmdResultOrError) {
+		mu.Lock()
+		queue = append(queue, v)
+		if v.IsFinal {
+			closed = true
+		}
+		mu.Unlock()
+		cond.Signal()
+	}
+
+	err := t.SendCmdEx(
+		cmd,
+		func(_ TenEnv, result CmdResult, isFinal bool, err error) {
+			push(CmdResultOrError{Result: result, IsFinal: isFinal, Err: err})
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only start the reader goroutine once SendCmdEx has actually
+	// accepted cmd -- starting it unconditionally would leak it forever
+	// on a synchronous error, since nothing would ever push to queue or
+	// set closed to wake it out of cond.Wait.
+	ch := make(chan CmdResultOrError)
+	go func() {
+		defer close(ch)
+		for {
+			mu.Lock()
+			for len(queue) == 0 && !closed {
+				cond.Wait()
+			}
+			if len(queue) == 0 {
+				mu.Unlock()
+				return
+			}
+			v := queue[0]
+			queue = queue[1:]
+			mu.Unlock()
+			ch <- v
+		}
+	}()
+	return ch, nil
+}
+
+func (t *tenEnvImpl) ReturnResultEx(
+	result CmdResult, cmd Cmd, isFinal bool,
+) error {
+	if result == nil {
+		return fmt.Errorf("ten: ReturnResultEx: result must not be nil")
+	}
+	if err := t.engine.checkSendable(); err != nil {
+		return err
+	}
+	if isFinal && !t.engine.claimCommandTimeout(cmd) {
+		t.engine.logIgnoredLateCommandTimeoutResult(cmd)
+		return ErrCmdAlreadyFinal
+	}
+	// cmd.finalized(isFinal) is the single atomic check-and-mark: calling
+	// it once, rather than peeking with finalized(false) and separately
+	// marking with finalized(true), closes the window where two
+	// concurrent final ReturnResultEx calls could both observe
+	// "not yet final" before either one marks it.
+	if cmd.finalized(isFinal) {
+		return ErrCmdAlreadyFinal
+	}
+	if impl, ok := result.(*cmdResultImpl); ok {
+		impl.isFinal = isFinal
+	}
+	if isFinal {
+		t.engine.finalizeReturnedCmd(cmd)
+		t.engine.notifyCrossExtResultHandler(cmd, result)
+	}
+	return nil
+}
+
+func (t *tenEnvImpl) SetPropertySchema(schemaJSON []byte) error {
+	schema, err := parsePropertySchema(schemaJSON)
+	if err != nil {
+		return err
+	}
+	t.engine.mu.Lock()
+	t.engine.propertySchema = schema
+	t.engine.mu.Unlock()
+	return nil
+}
+
+func (t *tenEnvImpl) GetPropertyString(path string) (string, error) {
+	t.engine.mu.Lock()
+	defer t.engine.mu.Unlock()
+	return propertyGetString(t.engine.properties, path)
+}
+
+func (t *tenEnvImpl) GetPropertyInt64(path string) (int64, error) {
+	t.engine.mu.Lock()
+	defer t.engine.mu.Unlock()
+	return propertyGetInt64(t.engine.properties, path)
+}
+
+func (t *tenEnvImpl) GetPropertyFloat64(path string) (float64, error) {
+	t.engine.mu.Lock()
+	defer t.engine.mu.Unlock()
+	return propertyGetFloat64(t.engine.properties, path)
+}
+
+func (t *tenEnvImpl) GetPropertyBool(path string) (bool, error) {
+	t.engine.mu.Lock()
+	defer t.engine.mu.Unlock()
+	return propertyGetBool(t.engine.properties, path)
+}
+
+func (t *tenEnvImpl) EnableEnvInterpolation(enabled bool) {
+	t.engine.mu.Lock()
+	t.engine.envInterpolationEnabled = enabled
+	t.engine.mu.Unlock()
+}
+
+func (t *tenEnvImpl) InitPropertyFromJSON(jsonBytes []byte) error {
+	var defaults map[string]any
+	if err := json.Unmarshal(jsonBytes, &defaults); err != nil {
+		return fmt.Errorf("ten: invalid JSON for InitPropertyFromJSON: %w", err)
+	}
+	t.engine.mu.Lock()
+	defer t.engine.mu.Unlock()
+	if t.engine.properties == nil {
+		t.engine.properties = map[string]any{}
+	}
+	mergePropertyDefaults(t.engine.properties, defaults)
+	return nil
+}
+
+// OnConfigureDone, OnInitDone, OnStartDone and OnStopDone are all
+// idempotent: the first call closes the matching *Done channel and lets
+// whichever Init/Start/Stop call is waiting on it proceed; any later call
+// -- an extension calling it twice, say, or two goroutines racing to
+// signal the same completion -- is a harmless no-op that logs a debug
+// line naming the callback, rather than panicking on a double close.
+func (t *tenEnvImpl) OnConfigureDone() {
+	if !signalDoneOnce(&t.engine.configureDoneOnce, t.engine.configureDone) {
+		t.engine.logRedundantDone("OnConfigureDone")
+	}
+}
+
+func (t *tenEnvImpl) OnInitDone() {
+	if !signalDoneOnce(&t.engine.initDoneOnce, t.engine.initDone) {
+		t.engine.logRedundantDone("OnInitDone")
+	}
+}
+
+func (t *tenEnvImpl) OnStartDone() {
+	if !signalDoneOnce(&t.engine.startDoneOnce, t.engine.startDone) {
+		t.engine.logRedundantDone("OnStartDone")
+	}
+}
+
+func (t *tenEnvImpl) OnStopDone() {
+	if !t.engine.signalStopDone() {
+		t.engine.logRedundantDone("OnStopDone")
+	}
+}
+
+func (t *tenEnvImpl) DeferStopDone() func() {
+	var once sync.Once
+	return func() { once.Do(t.OnStopDone) }
+}
+func (t *tenEnvImpl) OnDeinitDone() {}
+
+func (t *tenEnvImpl) ShutdownContext() context.Context {
+	t.engine.mu.Lock()
+	defer t.engine.mu.Unlock()
+	return t.engine.shutdownCtx
+}
+
+func (t *tenEnvImpl) Context() context.Context {
+	return t.engine.lifeCtx
+}
+
+func (t *tenEnvImpl) IsStopped() bool {
+	return t.engine.checkSendable() != nil
+}
+
+func (t *tenEnvImpl) Uptime() time.Duration {
+	return time.Since(t.engine.startedAt)
+}
+
+func (t *tenEnvImpl) SetStopTimeout(d time.Duration) {
+	t.engine.mu.Lock()
+	defer t.engine.mu.Unlock()
+	t.engine.shutdownDeadline = d
+}
+
+func (t *tenEnvImpl) SetStartTimeout(d time.Duration) {
+	t.engine.mu.Lock()
+	defer t.engine.mu.Unlock()
+	t.engine.startTimeout = d
+}
+
+func (t *tenEnvImpl) StartContext() context.Context {
+	t.engine.mu.Lock()
+	defer t.engine.mu.Unlock()
+	return t.engine.startCtx
+}
+
+func (t *tenEnvImpl) OnPauseDone() {
+	t.engine.mu.Lock()
+	once, done := &t.engine.pauseDoneOnce, t.engine.pauseDone
+	t.engine.mu.Unlock()
+	if !signalDoneOnce(once, done) {
+		t.engine.logRedundantDone("OnPauseDone")
+	}
+}
+
+func (t *tenEnvImpl) OnResumeDone() {
+	t.engine.mu.Lock()
+	once, done := &t.engine.resumeDoneOnce, t.engine.resumeDone
+	t.engine.mu.Unlock()
+	if !signalDoneOnce(once, done) {
+		t.engine.logRedundantDone("OnResumeDone")
+	}
+}
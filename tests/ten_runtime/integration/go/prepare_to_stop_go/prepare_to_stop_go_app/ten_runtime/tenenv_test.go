@@ -0,0 +1,225 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestSendCmdChan_StreamsResultsThenCloses(t *testing.T) {
+	engine := NewEngine(newLifecycleExtension())
+	engine.SetExSender(func(cmd Cmd, handler CmdResultExHandler) {
+		for i := 0; i < 2; i++ {
+			partial, _ := NewCmdResult(StatusCodeOk, cmd)
+			partial.SetPropertyInt64("progress", int64(i))
+			handler(engine.tenEnv, partial, false, nil)
+		}
+		final, _ := NewCmdResult(StatusCodeOk, cmd)
+		final.SetPropertyString("output", "done")
+		handler(engine.tenEnv, final, true, nil)
+	})
+
+	cmd, _ := NewCmd("generate")
+	ch, err := engine.tenEnv.SendCmdChan(cmd)
+	if err != nil {
+		t.Fatalf("SendCmdChan: %v", err)
+	}
+
+	var got []CmdResultOrError
+	for v := range ch {
+		got = append(got, v)
+	}
+
+	if len(got) != 3 {
+		t.Fatalf("got %d results, want 3", len(got))
+	}
+	if got[0].IsFinal || got[1].IsFinal || !got[2].IsFinal {
+		t.Fatalf("IsFinal sequence = %v, %v, %v, want false, false, true",
+			got[0].IsFinal, got[1].IsFinal, got[2].IsFinal)
+	}
+	output, _ := got[2].Result.GetPropertyString("output")
+	if output != "done" {
+		t.Fatalf("final result output = %q, want %q", output, "done")
+	}
+}
+
+func TestSendCmdChan_NilCmdReturnsError(t *testing.T) {
+	engine := NewEngine(newLifecycleExtension())
+	if _, err := engine.tenEnv.SendCmdChan(nil); err == nil {
+		t.Fatal("SendCmdChan(nil): got nil error, want non-nil")
+	}
+}
+
+// TestSendCmdChan_SynchronousErrorDoesNotLeakTheReaderGoroutine guards
+// against starting the reader goroutine before SendCmdEx has actually
+// accepted cmd: doing so left it parked in cond.Wait forever whenever
+// SendCmdEx failed synchronously, since nothing would ever push a value
+// or set closed to wake it.
+func TestSendCmdChan_SynchronousErrorDoesNotLeakTheReaderGoroutine(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+	engine.Init()
+	engine.Start()
+	engine.Stop()
+
+	before := runtime.NumGoroutine()
+
+	cmd, _ := NewCmd("ping")
+	ch, err := engine.tenEnv.SendCmdChan(cmd)
+	if !errors.Is(err, ErrStopped) {
+		t.Fatalf("SendCmdChan after Stop = %v, want ErrStopped", err)
+	}
+	if ch != nil {
+		t.Fatalf("SendCmdChan after Stop returned a non-nil channel")
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > before && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if got := runtime.NumGoroutine(); got > before {
+		t.Fatalf("goroutine count after SendCmdChan's synchronous error = %d, want <= %d (before)", got, before)
+	}
+}
+
+func TestSendCmdAndWait_ReturnsSingleResult(t *testing.T) {
+	engine := NewEngine(newLifecycleExtension())
+	engine.SetSender(func(cmd Cmd, handler CmdResultHandler) {
+		result, _ := NewCmdResult(StatusCodeOk, cmd)
+		result.SetPropertyString("output", "done")
+		handler(engine.tenEnv, result, nil)
+	})
+
+	cmd, _ := NewCmd("greet")
+	result, err := engine.tenEnv.SendCmdAndWait(cmd)
+	if err != nil {
+		t.Fatalf("SendCmdAndWait: %v", err)
+	}
+	output, _ := result.GetPropertyString("output")
+	if output != "done" {
+		t.Fatalf("output = %q, want %q", output, "done")
+	}
+}
+
+func TestSendCmdAndWait_OnDispatchGoroutineReturnsError(t *testing.T) {
+	engine := NewEngine(newLifecycleExtension())
+	engine.SetSender(func(cmd Cmd, handler CmdResultHandler) {
+		result, _ := NewCmdResult(StatusCodeOk, cmd)
+		handler(engine.tenEnv, result, nil)
+	})
+
+	var gotErr error
+	blockingExt := &blockingSendExtension{
+		onCmd: func(tenEnv TenEnv, cmd Cmd) {
+			_, gotErr = tenEnv.SendCmdAndWait(cmd)
+		},
+	}
+	engine2 := NewEngine(blockingExt, WithShutdownDeadline(0))
+	engine2.SetSender(engine.sender)
+	if err := engine2.Init(); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	if err := engine2.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	cmd, _ := NewCmd("greet")
+	if err := engine2.DeliverCmd(cmd); err != nil {
+		t.Fatalf("DeliverCmd: %v", err)
+	}
+	if !errors.Is(gotErr, ErrSendCmdAndWaitOnDispatchGoroutine) {
+		t.Fatalf(
+			"SendCmdAndWait from OnCmd = %v, want ErrSendCmdAndWaitOnDispatchGoroutine",
+			gotErr,
+		)
+	}
+}
+
+// blockingSendExtension calls onCmd synchronously from OnCmd, i.e. without
+// spawning its own goroutine first, to exercise the dispatch-goroutine
+// detection in SendCmdAndWait.
+type blockingSendExtension struct {
+	DefaultExtension
+	onCmd func(tenEnv TenEnv, cmd Cmd)
+}
+
+func (b *blockingSendExtension) OnCmd(tenEnv TenEnv, cmd Cmd) {
+	b.onCmd(tenEnv, cmd)
+}
+
+func TestSendCmdWithContext_TimeoutFiresContextCanceled(t *testing.T) {
+	block := make(chan struct{})
+	engine := NewEngine(newLifecycleExtension())
+	engine.SetSender(func(cmd Cmd, handler CmdResultHandler) {
+		// A downstream responder that never answers within the test's
+		// context deadline below -- SendCmdWithContext must fire the
+		// timeout regardless.
+		go func() {
+			<-block
+			result, _ := NewCmdResult(StatusCodeOk, cmd)
+			handler(engine.tenEnv, result, nil)
+		}()
+	})
+	defer close(block)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	cmd, _ := NewCmd("slow")
+	gotErr := make(chan error, 1)
+	if err := engine.tenEnv.SendCmdWithContext(
+		ctx, cmd,
+		func(_ TenEnv, _ CmdResult, err error) { gotErr <- err },
+	); err != nil {
+		t.Fatalf("SendCmdWithContext: %v", err)
+	}
+
+	select {
+	case err := <-gotErr:
+		if !errors.Is(err, ErrContextCanceled) {
+			t.Fatalf("err = %v, want ErrContextCanceled", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("handler was never invoked after the context deadline expired")
+	}
+}
+
+func TestSendCmdWithContext_RealResultWinsOverCancellation(t *testing.T) {
+	engine := NewEngine(newLifecycleExtension())
+	engine.SetSender(func(cmd Cmd, handler CmdResultHandler) {
+		result, _ := NewCmdResult(StatusCodeOk, cmd)
+		handler(engine.tenEnv, result, nil)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cmd, _ := NewCmd("fast")
+	calls := 0
+	var lastErr error
+	if err := engine.tenEnv.SendCmdWithContext(
+		ctx, cmd,
+		func(_ TenEnv, _ CmdResult, err error) {
+			calls++
+			lastErr = err
+		},
+	); err != nil {
+		t.Fatalf("SendCmdWithContext: %v", err)
+	}
+	cancel()
+
+	// Give a wrongly-firing cancellation goroutine a chance to (incorrectly)
+	// invoke the handler a second time.
+	time.Sleep(10 * time.Millisecond)
+	if calls != 1 {
+		t.Fatalf("handler invoked %d times, want exactly 1", calls)
+	}
+	if lastErr != nil {
+		t.Fatalf("err = %v, want nil (the real result should win)", lastErr)
+	}
+}
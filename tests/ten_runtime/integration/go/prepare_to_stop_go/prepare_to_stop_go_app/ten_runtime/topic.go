@@ -0,0 +1,114 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"errors"
+	"sort"
+	"sync"
+)
+
+// Topic centralizes the publish/subscribe fan-out that would otherwise be
+// duplicated by hand for every extension that broadcasts the same Data to
+// a dynamic set of downstream extensions -- an audio mixer feeding N
+// listeners, say, where N changes at runtime. It builds directly on
+// TenEnv.SendData and Data.Clone: Publish clones data once per current
+// subscriber and sends each clone on to it.
+type Topic struct {
+	tenEnv TenEnv
+	name   string
+
+	mu          sync.Mutex
+	subscribers map[string]struct{}
+}
+
+// NewTopic creates a Topic named name, publishing through tenEnv. The name
+// is not otherwise interpreted -- it exists so a log line or a panic
+// naming Topic can say which one, and so an extension juggling several
+// topics can tell them apart.
+func NewTopic(tenEnv TenEnv, name string) *Topic {
+	return &Topic{
+		tenEnv:      tenEnv,
+		name:        name,
+		subscribers: map[string]struct{}{},
+	}
+}
+
+// Name returns the name this Topic was created with.
+func (top *Topic) Name() string {
+	return top.name
+}
+
+// Subscribe adds extName to this Topic's subscriber set. Subscribing an
+// already-subscribed extName is a no-op.
+func (top *Topic) Subscribe(extName string) {
+	top.mu.Lock()
+	defer top.mu.Unlock()
+	top.subscribers[extName] = struct{}{}
+}
+
+// Unsubscribe removes extName from this Topic's subscriber set.
+// Unsubscribing an extName that was never subscribed is a no-op.
+func (top *Topic) Unsubscribe(extName string) {
+	top.mu.Lock()
+	defer top.mu.Unlock()
+	delete(top.subscribers, extName)
+}
+
+// Subscribers returns the currently subscribed extension names, sorted for
+// a deterministic order.
+func (top *Topic) Subscribers() []string {
+	top.mu.Lock()
+	defer top.mu.Unlock()
+	names := make([]string, 0, len(top.subscribers))
+	for name := range top.subscribers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Publish clones data once per current subscriber -- in sorted subscriber
+// order, so a test observing the sends sees a deterministic sequence --
+// and sends each clone to that subscriber via TenEnv.SendData. Each
+// clone's buffer is separately accounted against SetBufferPool, the same
+// as any other SetBuf-driven allocation, since Data.Clone itself doesn't
+// go through that accounting (there's no caller to charge it to until
+// now). A subscriber whose clone, buffer-pool reservation, or SendData
+// call fails doesn't stop the rest from being attempted; every error is
+// combined via errors.Join. A nil returned error means every subscriber
+// was sent to successfully. Publish itself does not wait for any
+// subscriber's SendData to be acknowledged.
+func (top *Topic) Publish(data Data) error {
+	if data == nil {
+		return errors.New("ten: Topic.Publish: data must not be nil")
+	}
+
+	var errs []error
+	for _, name := range top.Subscribers() {
+		clone, err := data.Clone()
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		buf, err := clone.GetBuf()
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if err := reserveBufferPoolDelta(len(buf)); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if err := clone.SetDests(Loc{ExtensionName: name}); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if err := top.tenEnv.SendData(clone, func(TenEnv, error) {}); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
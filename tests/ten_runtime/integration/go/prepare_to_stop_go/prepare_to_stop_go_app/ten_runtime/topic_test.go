@@ -0,0 +1,146 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestTopic_PublishSendsAClonedCopyToEachSubscriber(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+	engine.Init()
+	engine.Start()
+
+	var mu sync.Mutex
+	var gotDests []string
+	engine.SetDataSender(func(data Data, cb func(TenEnv, error)) {
+		dests, _ := data.GetDests()
+		mu.Lock()
+		gotDests = append(gotDests, dests[0].ExtensionName)
+		mu.Unlock()
+		cb(engine.tenEnv, nil)
+	})
+
+	top := NewTopic(engine.TenEnv(), "mix")
+	top.Subscribe("listener-a")
+	top.Subscribe("listener-b")
+
+	src, _ := NewData()
+	if err := src.SetBuf([]byte("hello")); err != nil {
+		t.Fatalf("SetBuf: %v", err)
+	}
+	if err := top.Publish(src); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+
+	if want := []string{"listener-a", "listener-b"}; !reflect.DeepEqual(gotDests, want) {
+		t.Fatalf("gotDests = %v, want %v", gotDests, want)
+	}
+}
+
+func TestTopic_PublishClonesRatherThanAliasingTheSourceBuffer(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+	engine.Init()
+	engine.Start()
+
+	var got Data
+	engine.SetDataSender(func(data Data, cb func(TenEnv, error)) {
+		got = data
+		cb(engine.tenEnv, nil)
+	})
+
+	top := NewTopic(engine.TenEnv(), "mix")
+	top.Subscribe("listener-a")
+
+	src, _ := NewData()
+	if err := src.SetBuf([]byte("hello")); err != nil {
+		t.Fatalf("SetBuf: %v", err)
+	}
+	if err := top.Publish(src); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+
+	if err := src.SetBuf([]byte("changed")); err != nil {
+		t.Fatalf("SetBuf: %v", err)
+	}
+	buf, _ := got.GetBuf()
+	if string(buf) != "hello" {
+		t.Fatalf("subscriber's buf = %q, want %q (unaffected by a later change to the source)", buf, "hello")
+	}
+}
+
+func TestTopic_UnsubscribeStopsFurtherPublishes(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+	engine.Init()
+	engine.Start()
+
+	var count int
+	engine.SetDataSender(func(data Data, cb func(TenEnv, error)) {
+		count++
+		cb(engine.tenEnv, nil)
+	})
+
+	top := NewTopic(engine.TenEnv(), "mix")
+	top.Subscribe("listener-a")
+	top.Unsubscribe("listener-a")
+
+	src, _ := NewData()
+	if err := top.Publish(src); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	if count != 0 {
+		t.Fatalf("count = %d, want 0 after unsubscribing the only subscriber", count)
+	}
+}
+
+func TestTopic_SubscribersReturnsSortedNames(t *testing.T) {
+	top := NewTopic(NewEngine(DefaultExtension{}).TenEnv(), "mix")
+	top.Subscribe("zeta")
+	top.Subscribe("alpha")
+	top.Subscribe("mu")
+
+	if want := []string{"alpha", "mu", "zeta"}; !reflect.DeepEqual(top.Subscribers(), want) {
+		t.Fatalf("Subscribers() = %v, want %v", top.Subscribers(), want)
+	}
+}
+
+func TestTopic_PublishReportsABufferPoolExhaustedCloneAndStillReachesOtherSubscribers(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+	engine.Init()
+	engine.Start()
+
+	var mu sync.Mutex
+	var gotDests []string
+	engine.SetDataSender(func(data Data, cb func(TenEnv, error)) {
+		dests, _ := data.GetDests()
+		mu.Lock()
+		gotDests = append(gotDests, dests[0].ExtensionName)
+		mu.Unlock()
+		cb(engine.tenEnv, nil)
+	})
+
+	top := NewTopic(engine.TenEnv(), "mix")
+	top.Subscribe("listener-a")
+	top.Subscribe("listener-b")
+
+	src, _ := NewData()
+	if err := src.SetBuf(make([]byte, 5)); err != nil {
+		t.Fatalf("SetBuf: %v", err)
+	}
+
+	// Only room enough for one subscriber's 5-byte clone.
+	SetBufferPool(5)
+	defer SetBufferPool(0)
+
+	err := top.Publish(src)
+	if err == nil {
+		t.Fatal("Publish err = nil, want an error once the buffer pool cap is exceeded")
+	}
+	if want := []string{"listener-a"}; !reflect.DeepEqual(gotDests, want) {
+		t.Fatalf("gotDests = %v, want %v (the second subscriber's clone should have been rejected, not skipped silently)", gotDests, want)
+	}
+}
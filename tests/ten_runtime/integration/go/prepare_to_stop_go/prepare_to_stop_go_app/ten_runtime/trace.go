@@ -0,0 +1,132 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// TraceID and SpanID identify a distributed trace and one of its spans.
+// They're hex-encoded the same way go.opentelemetry.io/otel/trace
+// represents them (16 and 8 bytes respectively), so the reserved Cmd
+// properties they ride in (see StartSpan) can be handed straight to a
+// real OTel exporter without any translation.
+type TraceID [16]byte
+type SpanID [8]byte
+
+func (id TraceID) String() string { return hex.EncodeToString(id[:]) }
+func (id SpanID) String() string  { return hex.EncodeToString(id[:]) }
+
+func newTraceID() TraceID {
+	var id TraceID
+	rand.Read(id[:])
+	return id
+}
+
+func newSpanID() SpanID {
+	var id SpanID
+	rand.Read(id[:])
+	return id
+}
+
+func traceIDFromHex(s string) (TraceID, bool) {
+	var id TraceID
+	b, err := hex.DecodeString(s)
+	if err != nil || len(b) != len(id) {
+		return TraceID{}, false
+	}
+	copy(id[:], b)
+	return id, true
+}
+
+// tracePropTraceID and tracePropSpanID are the reserved Cmd properties
+// StartSpan reads and writes to propagate a trace across SendCmd. They're
+// excluded from PropertyKeys/PropertyKeysAt (see isReservedPropertyKey)
+// so a generic logging/forwarding extension never sees them mixed in
+// with user-visible properties, the same way an HTTP client's
+// traceparent header rides alongside, but isn't part of, the request
+// body it's tracing.
+const (
+	tracePropTraceID = "_ten_trace_id"
+	tracePropSpanID  = "_ten_span_id"
+)
+
+// cmdPropDeadline is the reserved Cmd property Cmd.SetDeadline/GetDeadline
+// read and write; see deadline.go.
+const cmdPropDeadline = "_ten_deadline"
+
+// jsonrpcIDProperty is the reserved Cmd property JSONRPCCodec.ToCmd stows
+// a JSON-RPC request's id under; see jsonrpc.go.
+const jsonrpcIDProperty = "_ten_jsonrpc_id"
+
+// isReservedPropertyKey reports whether key is one this package manages
+// internally -- trace propagation, the end-to-end deadline and the
+// JSON-RPC codec's request id -- and therefore hides from PropertyKeys/
+// PropertyKeysAt.
+func isReservedPropertyKey(key string) bool {
+	switch key {
+	case tracePropTraceID, tracePropSpanID, cmdPropDeadline, jsonrpcIDProperty:
+		return true
+	default:
+		return false
+	}
+}
+
+// Span is a single unit of work within a trace, opened by
+// TenEnv.StartSpan for the lifetime of one OnCmd invocation.
+//
+// This package has no go.opentelemetry.io/otel dependency (see go.mod's
+// empty require block) and doesn't try to simulate one; Span models just
+// enough of otel/trace's Span -- a TraceID/SpanID pair and an End method
+// -- for a caller to bridge it into a real otel.Tracer's span themselves,
+// e.g. by seeding trace.NewSpanContext from TraceID()/SpanID() before
+// doing its own work. What StartSpan actually guarantees, independent of
+// whichever tracing backend either end is wired to, is the propagation
+// contract: the trace/span IDs always ride along in cmd's reserved
+// properties, so a downstream extension's own StartSpan call continues
+// the same trace.
+type Span struct {
+	traceID TraceID
+	spanID  SpanID
+}
+
+func (s *Span) TraceID() TraceID { return s.traceID }
+func (s *Span) SpanID() SpanID   { return s.spanID }
+
+// End marks the span finished. It's a no-op today -- there's no exporter
+// in this package for it to flush to -- but callers should still call it
+// symmetrically with StartSpan so an OTel bridge has a place to hook in
+// span-end reporting later without every call site changing.
+func (s *Span) End() {}
+
+// newSpan opens a Span for cmd: if cmd already carries a trace ID in its
+// reserved properties (it arrived from an upstream StartSpan/SendCmd),
+// the new span continues that trace with a fresh SpanID; otherwise it
+// starts a new trace. Either way cmd's reserved trace properties are
+// (re)written to the span's IDs, so sending cmd onward propagates them.
+func newSpan(cmd Cmd) *Span {
+	span := &Span{traceID: newTraceID(), spanID: newSpanID()}
+	if cmd != nil {
+		if s, err := cmd.GetPropertyString(tracePropTraceID); err == nil {
+			if traceID, ok := traceIDFromHex(s); ok {
+				span.traceID = traceID
+			}
+		}
+		cmd.SetPropertyString(tracePropTraceID, span.traceID.String())
+		cmd.SetPropertyString(tracePropSpanID, span.spanID.String())
+	}
+	return span
+}
+
+type spanContextKey struct{}
+
+// SpanFromContext returns the Span StartSpan placed into ctx, or nil if
+// ctx carries none.
+func SpanFromContext(ctx context.Context) *Span {
+	span, _ := ctx.Value(spanContextKey{}).(*Span)
+	return span
+}
@@ -0,0 +1,91 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import "testing"
+
+func TestTenEnv_StartSpanStartsNewTraceWhenCmdCarriesNone(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+	cmd, _ := NewCmd("greet")
+
+	_, span := engine.TenEnv().StartSpan(cmd)
+
+	if span.TraceID() == (TraceID{}) {
+		t.Fatalf("expected a non-zero trace ID")
+	}
+	traceID, err := cmd.GetPropertyString(tracePropTraceID)
+	if err != nil {
+		t.Fatalf("GetPropertyString(%q): %v", tracePropTraceID, err)
+	}
+	if traceID != span.TraceID().String() {
+		t.Fatalf("cmd's trace property %q doesn't match span's trace ID %q", traceID, span.TraceID())
+	}
+}
+
+func TestTenEnv_StartSpanContinuesTraceCarriedByCmd(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+	cmd, _ := NewCmd("greet")
+
+	_, upstream := engine.TenEnv().StartSpan(cmd)
+	_, downstream := engine.TenEnv().StartSpan(cmd)
+
+	if downstream.TraceID() != upstream.TraceID() {
+		t.Fatalf("downstream span's trace ID %q doesn't match upstream's %q",
+			downstream.TraceID(), upstream.TraceID())
+	}
+	if downstream.SpanID() == upstream.SpanID() {
+		t.Fatalf("downstream span should get its own span ID, not reuse upstream's")
+	}
+}
+
+func TestTenEnv_StartSpanPropertiesAreHiddenFromPropertyKeys(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+	cmd, _ := NewCmd("greet")
+	cmd.SetPropertyString("user_id", "abc123")
+
+	engine.TenEnv().StartSpan(cmd)
+
+	keys, err := cmd.PropertyKeys()
+	if err != nil {
+		t.Fatalf("PropertyKeys: %v", err)
+	}
+	for _, k := range keys {
+		if isReservedPropertyKey(k) {
+			t.Fatalf("PropertyKeys leaked reserved key %q: %v", k, keys)
+		}
+	}
+	if len(keys) != 1 || keys[0] != "user_id" {
+		t.Fatalf("expected PropertyKeys to only report user_id, got %v", keys)
+	}
+
+	// Still reachable directly, since only iteration hides them.
+	if _, err := cmd.GetPropertyString(tracePropTraceID); err != nil {
+		t.Fatalf("GetPropertyString(%q) after StartSpan: %v", tracePropTraceID, err)
+	}
+}
+
+func TestTenEnv_StartSpanPropagatesAcrossSendCmd(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+	engine.Init()
+	engine.Start()
+
+	cmd, _ := NewCmd("greet")
+	_, upstream := engine.TenEnv().StartSpan(cmd)
+
+	var downstreamTraceID string
+	engine.SetSender(func(cmd Cmd, cb CmdResultHandler) {
+		_, downstream := engine.TenEnv().StartSpan(cmd)
+		downstreamTraceID = downstream.TraceID().String()
+		result, _ := NewCmdResult(StatusCodeOk, cmd)
+		cb(engine.TenEnv(), result, nil)
+	})
+
+	if err := engine.TenEnv().SendCmd(cmd, func(TenEnv, CmdResult, error) {}); err != nil {
+		t.Fatalf("SendCmd: %v", err)
+	}
+	if downstreamTraceID != upstream.TraceID().String() {
+		t.Fatalf("downstream trace ID %q doesn't match upstream %q", downstreamTraceID, upstream.TraceID())
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import "fmt"
+
+// UnhandledCmdPolicy controls what dispatchOnCmdSync does when an
+// extension's OnCmd returns without a result ever having been produced for
+// cmd -- a bug, or a switch-on-cmd-name handler with a forgotten default
+// case -- instead of silently letting cmd hang forever. See
+// WithUnhandledCmdPolicy.
+type UnhandledCmdPolicy int
+
+const (
+	// UnhandledCmdHang is the default: OnCmd returning without a result
+	// leaves cmd exactly as unresolved as it always has in this package,
+	// waiting on whatever eventually calls ReturnResult/ReturnResultEx (or
+	// the engine's own shutdown-forcing, if one is registered).
+	UnhandledCmdHang UnhandledCmdPolicy = iota
+
+	// UnhandledCmdAutoError finalizes cmd with a StatusCodeError result
+	// naming it as unhandled, right after OnCmd returns, so a forgotten
+	// case fails loudly and immediately instead of hanging.
+	UnhandledCmdAutoError
+
+	// UnhandledCmdLog logs the omission at LogLevelWarn and otherwise
+	// behaves like UnhandledCmdHang -- cmd is left unresolved, but the bug
+	// is at least visible.
+	UnhandledCmdLog
+)
+
+// WithUnhandledCmdPolicy configures what happens when OnCmd returns without
+// having produced any result for the Cmd it was handed -- neither a final
+// one via ReturnResult/ReturnResultEx, nor an async handoff via
+// AcceptCommand, NewResultStream, or DetachResponder, any of which counts
+// as OnCmd having taken a completion token for cmd and is exempt from the
+// policy regardless of how long the actual work behind it takes. Without
+// this option, the default is UnhandledCmdHang, this package's
+// long-standing behavior.
+func WithUnhandledCmdPolicy(policy UnhandledCmdPolicy) EngineOption {
+	return func(e *Engine) {
+		e.unhandledCmdPolicy = policy
+	}
+}
+
+// enforceUnhandledCmdPolicy runs immediately after OnCmd returns, from
+// dispatchOnCmdSync. It's a no-op if cmd was already finalized, or if an
+// async handler took a completion token for it (see
+// WithUnhandledCmdPolicy's doc comment for what counts).
+func (e *Engine) enforceUnhandledCmdPolicy(cmd Cmd) {
+	if e.unhandledCmdPolicy == UnhandledCmdHang {
+		return
+	}
+	if cmd.finalized(false) || cmd.tookCompletionToken() {
+		return
+	}
+
+	name, _ := cmd.GetName()
+	switch e.unhandledCmdPolicy {
+	case UnhandledCmdAutoError:
+		result, err := NewCmdResult(StatusCodeError, cmd)
+		if err != nil {
+			return
+		}
+		_ = result.SetPropertyString(
+			"detail", fmt.Sprintf("unhandled command %q", name),
+		)
+		// ReturnResultEx, not ReturnResult, so this goes through the same
+		// finalized exactly-once mark ReturnResultEx's own callers rely on
+		// -- ReturnResult doesn't touch it.
+		_ = e.tenEnv.ReturnResultEx(result, cmd, true)
+	case UnhandledCmdLog:
+		e.tenEnv.Log(
+			LogLevelWarn,
+			fmt.Sprintf(
+				"ten: extension %q's OnCmd returned without a result for "+
+					"command %q", e.extensionName(), name,
+			),
+		)
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"strings"
+	"testing"
+)
+
+// silentExtension's OnCmd does nothing at all -- no ReturnResult, no
+// AcceptCommand, no NewResultStream, no DetachResponder -- the bug
+// WithUnhandledCmdPolicy exists to make loud instead of a silent hang.
+type silentExtension struct {
+	DefaultExtension
+}
+
+func (silentExtension) OnCmd(tenEnv TenEnv, cmd Cmd) {}
+
+func TestUnhandledCmdPolicy_HangIsTheDefault(t *testing.T) {
+	engine := NewEngine(silentExtension{})
+	engine.Init()
+	engine.Start()
+
+	cmd, _ := NewCmd("greet")
+	if err := engine.DeliverCmd(cmd); err != nil {
+		t.Fatalf("DeliverCmd: %v", err)
+	}
+
+	if cmd.finalized(false) {
+		t.Fatal("cmd finalized under the default (Hang) policy, want left open")
+	}
+}
+
+func TestUnhandledCmdPolicy_AutoErrorFinalizesWithAnErrorResult(t *testing.T) {
+	engine := NewEngine(silentExtension{}, WithUnhandledCmdPolicy(UnhandledCmdAutoError))
+	engine.Init()
+	engine.Start()
+
+	cmd, _ := NewCmd("greet")
+	if err := engine.DeliverCmd(cmd); err != nil {
+		t.Fatalf("DeliverCmd: %v", err)
+	}
+
+	if !cmd.finalized(false) {
+		t.Fatal("cmd not finalized under UnhandledCmdAutoError")
+	}
+}
+
+func TestUnhandledCmdPolicy_LogLogsButLeavesCmdOpen(t *testing.T) {
+	engine := NewEngine(silentExtension{}, WithUnhandledCmdPolicy(UnhandledCmdLog))
+	engine.Init()
+	engine.Start()
+
+	buf := &lockedBuffer{}
+	engine.TenEnv().SetLogSink(buf, LogFormatText)
+
+	cmd, _ := NewCmd("greet")
+	if err := engine.DeliverCmd(cmd); err != nil {
+		t.Fatalf("DeliverCmd: %v", err)
+	}
+
+	if cmd.finalized(false) {
+		t.Fatal("cmd finalized under UnhandledCmdLog, want left open")
+	}
+	if !strings.Contains(buf.String(), "greet") {
+		t.Fatalf("log output = %q, want it to mention the unhandled command", buf.String())
+	}
+}
+
+func TestUnhandledCmdPolicy_DoesNotFireWhenAcceptCommandTookOver(t *testing.T) {
+	var handle JobHandle
+	ext := &blockingSendExtension{onCmd: func(tenEnv TenEnv, cmd Cmd) {
+		var err error
+		handle, err = tenEnv.AcceptCommand(cmd)
+		if err != nil {
+			t.Errorf("AcceptCommand: %v", err)
+		}
+	}}
+
+	engine := NewEngine(ext, WithUnhandledCmdPolicy(UnhandledCmdAutoError))
+	engine.Init()
+	engine.Start()
+
+	cmd, _ := NewCmd("greet")
+	if err := engine.DeliverCmd(cmd); err != nil {
+		t.Fatalf("DeliverCmd: %v", err)
+	}
+
+	if cmd.finalized(false) {
+		t.Fatal("cmd finalized by the policy despite AcceptCommand taking a completion token")
+	}
+
+	result, _ := NewCmdResult(StatusCodeOk, cmd)
+	if err := handle.Complete(result); err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+}
@@ -0,0 +1,57 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+// Value wraps a property value with its intended stored type, for a
+// SetProperties caller that wants to spell out int64 vs. float64 vs.
+// []byte explicitly instead of relying on the plain Go type of a map
+// literal -- which SetProperties already refuses to guess at: a bare
+// `int` is ErrUnsupportedPropertyValueType, not silently widened to
+// int64, precisely so a typo like `"attempt": 3` (int) instead of
+// `"attempt": int64(3)` fails loudly instead of stored as a type
+// GetPropertyInt64 later can't read back. Int64/Float64/Bytes/Bool build
+// one; because their parameters are already concretely typed, an
+// untyped constant like Int64(5) needs no separate int64(5) cast the way
+// a bare map literal would.
+type Value struct {
+	v any
+}
+
+// Int64 builds an int64-valued Value.
+func Int64(v int64) Value { return Value{v: v} }
+
+// Float64 builds a float64-valued Value.
+func Float64(v float64) Value { return Value{v: v} }
+
+// Bytes builds a []byte-valued Value.
+func Bytes(v []byte) Value { return Value{v: v} }
+
+// Bool builds a bool-valued Value.
+func Bool(v bool) Value { return Value{v: v} }
+
+// resolvePropertyValue unwraps a Value (recursively, inside any nested
+// map[string]any/[]any) into the plain concrete type validatePropertyValue
+// and navigateSet already know how to handle. A value containing no Value
+// anywhere is returned as-is.
+func resolvePropertyValue(v any) any {
+	switch vv := v.(type) {
+	case Value:
+		return resolvePropertyValue(vv.v)
+	case map[string]any:
+		out := make(map[string]any, len(vv))
+		for k, sub := range vv {
+			out[k] = resolvePropertyValue(sub)
+		}
+		return out
+	case []any:
+		out := make([]any, len(vv))
+		for i, sub := range vv {
+			out[i] = resolvePropertyValue(sub)
+		}
+		return out
+	default:
+		return v
+	}
+}
@@ -0,0 +1,214 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"errors"
+	"sync"
+)
+
+// PixelFormat identifies a VideoFrame's pixel layout.
+type PixelFormat int
+
+const (
+	PixelFormatUnspecified PixelFormat = iota
+	PixelFormatRGBA
+	PixelFormatI420
+	PixelFormatNV12
+)
+
+// ErrVideoFramePlaneIndexOutOfRange is returned by GetPlaneData when index
+// is negative or beyond the number of planes the current pixel format has
+// (1 for RGBA, 2 for NV12, 3 for I420).
+var ErrVideoFramePlaneIndexOutOfRange = errors.New("ten: video frame plane index out of range")
+
+// VideoFrame represents a single video frame flowing through a graph,
+// either delivered to an extension or built via NewVideoFrame for
+// TenEnv.SendVideoFrame. See AudioFrame's doc comment for the buffer
+// thread-safety contract LockBuf/UnlockBuf follow.
+type VideoFrame interface {
+	Msg
+
+	// SetBuf, LockBuf, UnlockBuf and GetBufSize implement frameBuf's
+	// buffer contract; see its doc comments for the copy-vs-alias
+	// semantics of each.
+	SetBuf(buf []byte) error
+	LockBuf() ([]byte, error)
+	UnlockBuf(buf []byte) error
+	GetBufSize() (int, error)
+
+	SetWidth(width int32) error
+	GetWidth() (int32, error)
+
+	SetHeight(height int32) error
+	GetHeight() (int32, error)
+
+	SetPixelFormat(format PixelFormat) error
+	GetPixelFormat() (PixelFormat, error)
+
+	SetTimestamp(timestamp int64) error
+	GetTimestamp() (int64, error)
+
+	// GetSource follows the same semantics as Cmd.GetSource: the Loc this
+	// VideoFrame was sent from, stamped by SendVideoFrame just before
+	// handing it to the sender; a frame built via NewVideoFrame that's
+	// never been sent carries a zero Loc.
+	GetSource() (Loc, error)
+
+	// GetPlaneData returns plane index's raw data and its stride in bytes
+	// (the byte distance between the start of consecutive rows, which may
+	// exceed the row's pixel width if the native buffer pads rows). The
+	// returned slice aliases the frame's internal storage, the same as
+	// LockBuf; it stays valid as long as the frame's dimensions and pixel
+	// format are unchanged. Plane count and layout follow PixelFormat: 1
+	// plane for RGBA, 2 (Y, interleaved UV) for NV12, 3 (Y, U, V) for
+	// I420, with chroma planes subsampled by 2 in both dimensions.
+	GetPlaneData(index int) (data []byte, stride int, err error)
+
+	// Clone returns a deep copy of this VideoFrame: same dimensions,
+	// pixel format and timestamp, freshly recomputed (not shared) planes,
+	// plus a copy of the current buffer independent of this frame's own
+	// lock state, as a new, unlocked, independently owned VideoFrame. It's
+	// for broadcasting the same frame to several destinations without them
+	// fighting over one buffer via LockBuf or one plane via GetPlaneData.
+	// The clone's source is reset to a zero Loc rather than copied, the
+	// same as Cmd.Clone, since it hasn't been sent anywhere yet.
+	Clone() (VideoFrame, error)
+}
+
+type videoFrameImpl struct {
+	frameBuf
+	name        string
+	width       int32
+	height      int32
+	pixelFormat PixelFormat
+	timestamp   int64
+
+	// source is stamped by stampVideoFrameSource; see its doc comment in
+	// loc.go.
+	source Loc
+
+	planesMu sync.Mutex
+	planes   [][]byte
+	strides  []int
+}
+
+// NewVideoFrame creates a new, empty VideoFrame named name.
+func NewVideoFrame(name string) (VideoFrame, error) {
+	return &videoFrameImpl{name: name}, nil
+}
+
+func (v *videoFrameImpl) GetName() (string, error) {
+	return v.name, nil
+}
+
+func (v *videoFrameImpl) GetType() (MsgType, error) {
+	return MsgTypeVideoFrame, nil
+}
+
+func (v *videoFrameImpl) SetWidth(width int32) error {
+	v.width = width
+	v.recomputePlanes()
+	return nil
+}
+
+func (v *videoFrameImpl) GetWidth() (int32, error) {
+	return v.width, nil
+}
+
+func (v *videoFrameImpl) SetHeight(height int32) error {
+	v.height = height
+	v.recomputePlanes()
+	return nil
+}
+
+func (v *videoFrameImpl) GetHeight() (int32, error) {
+	return v.height, nil
+}
+
+func (v *videoFrameImpl) SetPixelFormat(format PixelFormat) error {
+	v.pixelFormat = format
+	v.recomputePlanes()
+	return nil
+}
+
+func (v *videoFrameImpl) GetPixelFormat() (PixelFormat, error) {
+	return v.pixelFormat, nil
+}
+
+func (v *videoFrameImpl) SetTimestamp(timestamp int64) error {
+	v.timestamp = timestamp
+	return nil
+}
+
+func (v *videoFrameImpl) GetTimestamp() (int64, error) {
+	return v.timestamp, nil
+}
+
+func (v *videoFrameImpl) GetSource() (Loc, error) {
+	return v.source, nil
+}
+
+// recomputePlanes (re)allocates v.planes/v.strides for the frame's current
+// width, height and pixel format, called after every SetWidth/SetHeight/
+// SetPixelFormat so a frame built via NewVideoFrame+setters ends up with
+// correctly sized planes without a separate allocation step. It's a no-op
+// until both dimensions are positive and a concrete pixel format is set.
+func (v *videoFrameImpl) recomputePlanes() {
+	v.planesMu.Lock()
+	defer v.planesMu.Unlock()
+
+	if v.width <= 0 || v.height <= 0 {
+		v.planes, v.strides = nil, nil
+		return
+	}
+
+	w, h := int(v.width), int(v.height)
+	chromaW, chromaH := (w+1)/2, (h+1)/2
+
+	switch v.pixelFormat {
+	case PixelFormatI420:
+		v.strides = []int{w, chromaW, chromaW}
+		v.planes = [][]byte{
+			make([]byte, w*h),
+			make([]byte, chromaW*chromaH),
+			make([]byte, chromaW*chromaH),
+		}
+	case PixelFormatNV12:
+		v.strides = []int{w, w}
+		v.planes = [][]byte{
+			make([]byte, w*h),
+			make([]byte, w*chromaH),
+		}
+	case PixelFormatRGBA:
+		stride := w * 4
+		v.strides = []int{stride}
+		v.planes = [][]byte{make([]byte, stride*h)}
+	default:
+		v.planes, v.strides = nil, nil
+	}
+}
+
+func (v *videoFrameImpl) Clone() (VideoFrame, error) {
+	clone := &videoFrameImpl{
+		name:        v.name,
+		width:       v.width,
+		height:      v.height,
+		pixelFormat: v.pixelFormat,
+		timestamp:   v.timestamp,
+	}
+	clone.buf = v.cloneBuf()
+	clone.recomputePlanes()
+	return clone, nil
+}
+
+func (v *videoFrameImpl) GetPlaneData(index int) ([]byte, int, error) {
+	v.planesMu.Lock()
+	defer v.planesMu.Unlock()
+	if index < 0 || index >= len(v.planes) {
+		return nil, 0, ErrVideoFramePlaneIndexOutOfRange
+	}
+	return v.planes[index], v.strides[index], nil
+}
@@ -0,0 +1,176 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestVideoFrame_SettersRoundTrip(t *testing.T) {
+	frame, _ := NewVideoFrame("overlay")
+	frame.SetWidth(1920)
+	frame.SetHeight(1080)
+	frame.SetPixelFormat(PixelFormatRGBA)
+	frame.SetTimestamp(6789)
+
+	name, _ := frame.GetName()
+	width, _ := frame.GetWidth()
+	height, _ := frame.GetHeight()
+	format, _ := frame.GetPixelFormat()
+	ts, _ := frame.GetTimestamp()
+	if name != "overlay" || width != 1920 || height != 1080 ||
+		format != PixelFormatRGBA || ts != 6789 {
+		t.Fatalf(
+			"got %q, %d, %d, %v, %d, want %q, 1920, 1080, PixelFormatRGBA, 6789",
+			name, width, height, format, ts, "overlay",
+		)
+	}
+}
+
+func TestVideoFrame_SetBufCopiesSoCallerCanReuseSlice(t *testing.T) {
+	frame, _ := NewVideoFrame("overlay")
+	buf := []byte{1, 2, 3}
+	frame.SetBuf(buf)
+	buf[0] = 9
+
+	locked, _ := frame.LockBuf()
+	if locked[0] != 1 {
+		t.Fatalf("buffer after mutating caller's slice = %v, want [1 2 3]", locked)
+	}
+}
+
+func TestTenEnv_SendVideoFrameInvokesDefaultSenderSuccessfully(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+	frame, _ := NewVideoFrame("overlay")
+
+	done := make(chan error, 1)
+	if err := engine.TenEnv().SendVideoFrame(frame, func(_ TenEnv, err error) {
+		done <- err
+	}); err != nil {
+		t.Fatalf("SendVideoFrame: %v", err)
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("SendVideoFrame callback err = %v, want nil", err)
+	}
+}
+
+func TestTenEnv_SendVideoFrameRejectsNilFrame(t *testing.T) {
+	engine := NewEngine(DefaultExtension{})
+	if err := engine.TenEnv().SendVideoFrame(nil, func(TenEnv, error) {}); err == nil {
+		t.Fatal("SendVideoFrame(nil, ...) = nil error, want an error")
+	}
+}
+
+func TestVideoFrame_LockBufWithoutUnlockThenSetBufIsError(t *testing.T) {
+	frame, _ := NewVideoFrame("overlay")
+	if _, err := frame.LockBuf(); err != nil {
+		t.Fatalf("LockBuf: %v", err)
+	}
+	if err := frame.SetBuf([]byte{1}); !errors.Is(err, ErrFrameBufLocked) {
+		t.Fatalf("SetBuf() while locked = %v, want ErrFrameBufLocked", err)
+	}
+}
+
+func TestVideoFrame_I420PlanesAreCorrectlySizedAndStrided(t *testing.T) {
+	frame, _ := NewVideoFrame("cam")
+	frame.SetWidth(4)
+	frame.SetHeight(2)
+	frame.SetPixelFormat(PixelFormatI420)
+
+	y, yStride, err := frame.GetPlaneData(0)
+	if err != nil {
+		t.Fatalf("GetPlaneData(0): %v", err)
+	}
+	if len(y) != 8 || yStride != 4 {
+		t.Fatalf("Y plane = %d bytes, stride %d, want 8 bytes, stride 4", len(y), yStride)
+	}
+
+	u, uStride, err := frame.GetPlaneData(1)
+	if err != nil {
+		t.Fatalf("GetPlaneData(1): %v", err)
+	}
+	if len(u) != 2 || uStride != 2 {
+		t.Fatalf("U plane = %d bytes, stride %d, want 2 bytes, stride 2", len(u), uStride)
+	}
+
+	if _, _, err := frame.GetPlaneData(3); !errors.Is(err, ErrVideoFramePlaneIndexOutOfRange) {
+		t.Fatalf("GetPlaneData(3) = %v, want ErrVideoFramePlaneIndexOutOfRange", err)
+	}
+}
+
+func TestVideoFrame_NV12HasTwoPlanes(t *testing.T) {
+	frame, _ := NewVideoFrame("cam")
+	frame.SetWidth(4)
+	frame.SetHeight(2)
+	frame.SetPixelFormat(PixelFormatNV12)
+
+	y, yStride, _ := frame.GetPlaneData(0)
+	uv, uvStride, err := frame.GetPlaneData(1)
+	if err != nil {
+		t.Fatalf("GetPlaneData(1): %v", err)
+	}
+	if len(y) != 8 || yStride != 4 || len(uv) != 4 || uvStride != 4 {
+		t.Fatalf(
+			"Y = %d bytes/stride %d, UV = %d bytes/stride %d, want 8/4, 4/4",
+			len(y), yStride, len(uv), uvStride,
+		)
+	}
+	if _, _, err := frame.GetPlaneData(2); !errors.Is(err, ErrVideoFramePlaneIndexOutOfRange) {
+		t.Fatalf("GetPlaneData(2) = %v, want ErrVideoFramePlaneIndexOutOfRange", err)
+	}
+}
+
+func TestVideoFrame_CloneCopiesFieldsBufferAndPlanesIndependently(t *testing.T) {
+	frame, _ := NewVideoFrame("cam")
+	frame.SetWidth(4)
+	frame.SetHeight(2)
+	frame.SetPixelFormat(PixelFormatI420)
+	frame.SetTimestamp(42)
+	frame.SetBuf([]byte{1, 2, 3})
+
+	clone, err := frame.Clone()
+	if err != nil {
+		t.Fatalf("Clone: %v", err)
+	}
+	clone.SetBuf([]byte{9, 9, 9})
+
+	name, _ := clone.GetName()
+	width, _ := clone.GetWidth()
+	height, _ := clone.GetHeight()
+	format, _ := clone.GetPixelFormat()
+	ts, _ := clone.GetTimestamp()
+	if name != "cam" || width != 4 || height != 2 || format != PixelFormatI420 || ts != 42 {
+		t.Fatalf(
+			"clone fields = %q, %d, %d, %v, %d, want %q, 4, 2, PixelFormatI420, 42",
+			name, width, height, format, ts, "cam",
+		)
+	}
+
+	y, yStride, err := clone.GetPlaneData(0)
+	if err != nil || len(y) != 8 || yStride != 4 {
+		t.Fatalf("clone Y plane = %d bytes, stride %d, err %v, want 8 bytes, stride 4, nil", len(y), yStride, err)
+	}
+
+	original, _ := frame.LockBuf()
+	if len(original) != 3 || original[0] != 1 {
+		t.Fatalf("original buf = %v after mutating clone, want unchanged [1 2 3]", original)
+	}
+}
+
+func TestVideoFrame_PlanesAreAliasedNotCopied(t *testing.T) {
+	frame, _ := NewVideoFrame("cam")
+	frame.SetWidth(2)
+	frame.SetHeight(2)
+	frame.SetPixelFormat(PixelFormatRGBA)
+
+	plane, _, _ := frame.GetPlaneData(0)
+	plane[0] = 42
+
+	plane2, _, _ := frame.GetPlaneData(0)
+	if plane2[0] != 42 {
+		t.Fatal("GetPlaneData returned a copy, want an alias of the frame's storage")
+	}
+}
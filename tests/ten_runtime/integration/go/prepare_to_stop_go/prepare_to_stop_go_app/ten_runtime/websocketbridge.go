@@ -0,0 +1,337 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+)
+
+// wsHandshakeGUID is RFC 6455's fixed magic string, concatenated onto a
+// client's Sec-WebSocket-Key before hashing to produce
+// Sec-WebSocket-Accept.
+const wsHandshakeGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+func wsAcceptKey(clientKey string) string {
+	h := sha1.New()
+	h.Write([]byte(clientKey + wsHandshakeGUID))
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+// WebSocketBridge is an Extension that fronts a TEN graph with a
+// WebSocket endpoint: each inbound JSON-RPC request frame (see
+// JSONRPCCodec) becomes a Cmd sent into the graph via SendCmdEx, and
+// every CmdResult that produces streams straight back to the same
+// connection as a JSON-RPC response frame -- so a frontend can talk to a
+// graph the same way it would talk to any other JSON-RPC-over-WebSocket
+// service, including a streaming one.
+//
+// It's registered like any other Extension -- ten.NewEngine(ten.
+// NewWebSocketBridge(...)) -- and manages its own listener and
+// connections around the extension lifecycle: OnStart begins listening,
+// and OnStop closes the listener and every open connection and waits for
+// their goroutines to actually exit before calling OnStopDone, so the
+// graph's shutdown sequence never leaves a socket or a goroutine behind.
+// A connection with more than WithMaxInFlightPerConnection requests
+// already outstanding stops being read from until one finishes, applying
+// backpressure straight to that client's TCP connection instead of
+// letting an unbounded queue build up in memory -- that lifecycle and
+// backpressure integration with the runtime's async model is this type's
+// reason for existing, not the WebSocket wire protocol itself, which is
+// deliberately minimal: single-frame messages only (see wsframe.go), no
+// permessage-deflate, and no Origin/subprotocol negotiation.
+type WebSocketBridge struct {
+	DefaultExtension
+
+	addr               string
+	maxInFlightPerConn int
+
+	tenEnv   TenEnv
+	codec    JSONRPCCodec
+	listener net.Listener
+	server   *http.Server
+	wg       sync.WaitGroup
+
+	mu     sync.Mutex
+	closed bool
+	nextID uint64
+	conns  map[uint64]*wsConn
+}
+
+// WebSocketBridgeOption configures a WebSocketBridge constructed via
+// NewWebSocketBridge.
+type WebSocketBridgeOption func(*WebSocketBridge)
+
+// WithAddr sets the address WebSocketBridge listens on, e.g. ":8080", or
+// the default "127.0.0.1:0" to let the OS pick a free port -- see Addr to
+// read back which one it chose.
+func WithAddr(addr string) WebSocketBridgeOption {
+	return func(b *WebSocketBridge) { b.addr = addr }
+}
+
+// WithMaxInFlightPerConnection bounds how many requests a single
+// WebSocket connection may have outstanding at once. The default is 8;
+// see WebSocketBridge's doc comment for what exceeding it does to that
+// connection's read loop.
+func WithMaxInFlightPerConnection(n int) WebSocketBridgeOption {
+	return func(b *WebSocketBridge) { b.maxInFlightPerConn = n }
+}
+
+// NewWebSocketBridge creates a WebSocketBridge with the given options
+// applied over the defaults (WithAddr("127.0.0.1:0"),
+// WithMaxInFlightPerConnection(8)). It doesn't start listening until
+// OnStart runs.
+func NewWebSocketBridge(opts ...WebSocketBridgeOption) *WebSocketBridge {
+	b := &WebSocketBridge{
+		addr:               "127.0.0.1:0",
+		maxInFlightPerConn: 8,
+		conns:              map[uint64]*wsConn{},
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	return b
+}
+
+// Addr reports the address the bridge is actually listening on, once
+// OnStart has run; it returns "" beforehand or if OnStart failed to bind.
+func (b *WebSocketBridge) Addr() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.listener == nil {
+		return ""
+	}
+	return b.listener.Addr().String()
+}
+
+func (b *WebSocketBridge) OnStart(tenEnv TenEnv) {
+	b.tenEnv = tenEnv
+
+	listener, err := net.Listen("tcp", b.addr)
+	if err != nil {
+		tenEnv.Log(LogLevelError, fmt.Sprintf("ten: WebSocketBridge: listen: %v", err))
+		tenEnv.OnStartDone()
+		return
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", b.handleUpgrade)
+
+	b.mu.Lock()
+	b.listener = listener
+	b.mu.Unlock()
+	b.server = &http.Server{Addr: listener.Addr().String(), Handler: mux}
+
+	go b.server.Serve(listener)
+	tenEnv.OnStartDone()
+}
+
+// OnStop stops accepting new connections, closes every connection
+// currently open, and waits for their read loops (and whatever
+// SendCmdEx-backed requests they still had outstanding) to finish before
+// acknowledging the stop -- mirroring WorkerPool.Drain's
+// close-then-wait shape for the same reason: a graph must never be torn
+// down out from under a goroutine still using its TenEnv.
+func (b *WebSocketBridge) OnStop(tenEnv TenEnv) {
+	b.mu.Lock()
+	b.closed = true
+	if b.listener != nil {
+		b.listener.Close()
+	}
+	conns := make([]*wsConn, 0, len(b.conns))
+	for _, c := range b.conns {
+		conns = append(conns, c)
+	}
+	b.mu.Unlock()
+
+	for _, c := range conns {
+		c.close()
+	}
+	b.wg.Wait()
+
+	if b.server != nil {
+		b.server.Close()
+	}
+	tenEnv.OnStopDone()
+}
+
+func (b *WebSocketBridge) handleUpgrade(w http.ResponseWriter, req *http.Request) {
+	key := req.Header.Get("Sec-WebSocket-Key")
+	if key == "" || req.Header.Get("Upgrade") != "websocket" {
+		http.Error(w, "expected a WebSocket upgrade request", http.StatusBadRequest)
+		return
+	}
+
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "connection does not support hijacking", http.StatusInternalServerError)
+		return
+	}
+	netConn, rw, err := hijacker.Hijack()
+	if err != nil {
+		return
+	}
+
+	handshake := "HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + wsAcceptKey(key) + "\r\n\r\n"
+	if _, err := rw.WriteString(handshake); err != nil || rw.Flush() != nil {
+		netConn.Close()
+		return
+	}
+
+	conn := &wsConn{
+		id:      atomic.AddUint64(&b.nextID, 1),
+		netConn: netConn,
+		reader:  rw.Reader,
+		sem:     make(chan struct{}, b.maxInFlightPerConn),
+		done:    make(chan struct{}),
+	}
+
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		netConn.Close()
+		return
+	}
+	b.conns[conn.id] = conn
+	b.mu.Unlock()
+
+	b.wg.Add(1)
+	go b.serveConn(conn)
+}
+
+func (b *WebSocketBridge) forgetConn(conn *wsConn) {
+	b.mu.Lock()
+	delete(b.conns, conn.id)
+	b.mu.Unlock()
+}
+
+func (b *WebSocketBridge) serveConn(conn *wsConn) {
+	defer b.wg.Done()
+	defer b.forgetConn(conn)
+	defer conn.close()
+
+	for {
+		frame, err := readWSFrame(conn.reader)
+		if err != nil {
+			return
+		}
+		switch frame.opcode {
+		case wsOpClose:
+			return
+		case wsOpPing:
+			conn.writeFrame(wsOpPong, frame.payload)
+		case wsOpText:
+			select {
+			case conn.sem <- struct{}{}:
+				b.wg.Add(1)
+				go b.handleRequest(conn, frame.payload)
+			case <-conn.done:
+				return
+			}
+		default:
+			// Binary, pong and continuation frames aren't part of this
+			// bridge's JSON-RPC protocol; ignore them.
+		}
+	}
+}
+
+// handleRequest decodes raw as a JSONRPCRequest and sends it into the
+// graph via SendCmdEx, streaming every CmdResult it produces back to
+// conn as a JSON-RPC response frame carrying the same id, and releasing
+// conn's in-flight slot once the terminal one arrives. It's tracked in
+// b.wg the same as serveConn, so OnStop's wait actually covers the
+// SendCmdEx call this goroutine makes into tenEnv, not just the
+// connection's read loop.
+func (b *WebSocketBridge) handleRequest(conn *wsConn, raw []byte) {
+	defer b.wg.Done()
+
+	var req JSONRPCRequest
+	if err := json.Unmarshal(raw, &req); err != nil {
+		conn.writeJSON(JSONRPCResponse{Error: &JSONRPCError{Code: -32700, Message: "parse error"}})
+		<-conn.sem
+		return
+	}
+
+	cmd, err := b.codec.ToCmd(req)
+	if err != nil {
+		conn.writeJSON(JSONRPCResponse{ID: req.ID, Error: &JSONRPCError{Code: -32600, Message: err.Error()}})
+		<-conn.sem
+		return
+	}
+
+	err = b.tenEnv.SendCmdEx(cmd, func(_ TenEnv, result CmdResult, isFinal bool, sendErr error) {
+		if isFinal || sendErr != nil {
+			defer func() { <-conn.sem }()
+		}
+
+		if sendErr != nil {
+			conn.writeJSON(JSONRPCResponse{ID: req.ID, Error: &JSONRPCError{Code: -32000, Message: sendErr.Error()}})
+			return
+		}
+		resp, err := b.codec.FromResult(result)
+		if err != nil {
+			conn.writeJSON(JSONRPCResponse{ID: req.ID, Error: &JSONRPCError{Code: -32000, Message: err.Error()}})
+			return
+		}
+		resp.ID = req.ID
+		conn.writeJSON(resp)
+	})
+	if err != nil {
+		conn.writeJSON(JSONRPCResponse{ID: req.ID, Error: &JSONRPCError{Code: -32000, Message: err.Error()}})
+		<-conn.sem
+	}
+}
+
+// wsConn is one accepted WebSocket connection: netConn/reader are the
+// hijacked HTTP connection's raw socket and its already-buffered reader,
+// writeMu serializes writes since more than one in-flight request's
+// SendCmdEx callback can be writing a response at once, sem bounds how
+// many requests this connection may have outstanding (see
+// WithMaxInFlightPerConnection), and done is closed by close so a read
+// loop blocked trying to acquire sem wakes up and exits instead of
+// leaking past OnStop.
+type wsConn struct {
+	id      uint64
+	netConn net.Conn
+	reader  *bufio.Reader
+
+	writeMu sync.Mutex
+
+	sem  chan struct{}
+	done chan struct{}
+
+	closeOnce sync.Once
+}
+
+func (c *wsConn) writeFrame(opcode byte, payload []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	return writeWSFrame(c.netConn, opcode, payload)
+}
+
+func (c *wsConn) writeJSON(v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return c.writeFrame(wsOpText, data)
+}
+
+func (c *wsConn) close() {
+	c.closeOnce.Do(func() {
+		close(c.done)
+		c.netConn.Close()
+	})
+}
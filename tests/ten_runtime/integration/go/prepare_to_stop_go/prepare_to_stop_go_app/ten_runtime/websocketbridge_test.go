@@ -0,0 +1,254 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/json"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// writeMaskedWSFrame writes a client->server frame: the spec requires the
+// client to mask every frame it sends, unlike writeWSFrame's server-side
+// unmasked frames.
+func writeMaskedWSFrame(conn net.Conn, opcode byte, payload []byte) error {
+	var mask [4]byte
+	rand.Read(mask[:])
+
+	masked := make([]byte, len(payload))
+	for i, b := range payload {
+		masked[i] = b ^ mask[i%4]
+	}
+
+	length := len(masked)
+	var head []byte
+	switch {
+	case length <= 125:
+		head = []byte{0x80 | opcode, 0x80 | byte(length)}
+	default:
+		head = make([]byte, 4)
+		head[0] = 0x80 | opcode
+		head[1] = 0x80 | 126
+		binary.BigEndian.PutUint16(head[2:], uint16(length))
+	}
+	if _, err := conn.Write(head); err != nil {
+		return err
+	}
+	if _, err := conn.Write(mask[:]); err != nil {
+		return err
+	}
+	_, err := conn.Write(masked)
+	return err
+}
+
+// dialWSTestClient performs the WebSocket handshake against addr's
+// root path and returns the raw connection plus a buffered reader over
+// it, so the test can read/write frames directly with wsframe.go's
+// helpers (readWSFrame works unchanged: it dispatches on the frame's own
+// mask bit, and a server's frames are always unmasked).
+func dialWSTestClient(t *testing.T, addr string) (net.Conn, *bufio.Reader) {
+	t.Helper()
+
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatalf("Dial: %v", err)
+	}
+	req, err := http.NewRequest(http.MethodGet, "http://"+addr+"/", nil)
+	if err != nil {
+		t.Fatalf("NewRequest: %v", err)
+	}
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Sec-WebSocket-Key", "dGhlIHNhbXBsZSBub25jZQ==")
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	if err := req.Write(conn); err != nil {
+		t.Fatalf("Write handshake request: %v", err)
+	}
+
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, req)
+	if err != nil {
+		t.Fatalf("ReadResponse: %v", err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("handshake status = %d, want 101", resp.StatusCode)
+	}
+	return conn, reader
+}
+
+func TestWebSocketBridge_RoundTripsAJSONRPCRequestThroughTheGraph(t *testing.T) {
+	engine := NewEngine(NewWebSocketBridge(WithAddr("127.0.0.1:0")))
+	engine.SetExSender(func(cmd Cmd, handler CmdResultExHandler) {
+		result, _ := NewCmdResult(StatusCodeOk, cmd)
+		name, _ := cmd.GetName()
+		result.SetPropertyString("echo", name)
+		handler(engine.TenEnv(), result, true, nil)
+	})
+	engine.Init()
+	engine.Start()
+	defer engine.Stop()
+
+	bridge := engine.tenEnv.engine.ext.(*WebSocketBridge)
+	waitForAddr(t, bridge)
+
+	conn, reader := dialWSTestClient(t, bridge.Addr())
+	defer conn.Close()
+
+	reqBody, _ := json.Marshal(JSONRPCRequest{ID: []byte(`1`), Method: "greet"})
+	if err := writeMaskedWSFrame(conn, wsOpText, reqBody); err != nil {
+		t.Fatalf("writeMaskedWSFrame: %v", err)
+	}
+
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	frame, err := readWSFrame(reader)
+	if err != nil {
+		t.Fatalf("readWSFrame: %v", err)
+	}
+
+	var resp JSONRPCResponse
+	if err := json.Unmarshal(frame.payload, &resp); err != nil {
+		t.Fatalf("Unmarshal response: %v", err)
+	}
+	if resp.Error != nil {
+		t.Fatalf("Error = %v, want nil", resp.Error)
+	}
+	props, ok := resp.Result.(map[string]any)
+	if !ok || props["echo"] != "greet" {
+		t.Fatalf("Result = %v, want a map with echo=greet", resp.Result)
+	}
+	if !bytes.Equal(resp.ID, []byte(`1`)) {
+		t.Fatalf("ID = %s, want 1", resp.ID)
+	}
+}
+
+func TestWebSocketBridge_OnStopClosesOpenConnections(t *testing.T) {
+	engine := NewEngine(NewWebSocketBridge(WithAddr("127.0.0.1:0")))
+	engine.Init()
+	engine.Start()
+
+	bridge := engine.tenEnv.engine.ext.(*WebSocketBridge)
+	waitForAddr(t, bridge)
+
+	conn, reader := dialWSTestClient(t, bridge.Addr())
+	defer conn.Close()
+
+	if err := engine.Stop(); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	if _, err := readWSFrame(reader); err == nil {
+		t.Fatal("readWSFrame succeeded after Stop, want the connection closed")
+	}
+}
+
+func TestWebSocketBridge_MaxInFlightPerConnectionThrottlesTheReadLoop(t *testing.T) {
+	release := make(chan struct{})
+	engine := NewEngine(NewWebSocketBridge(WithAddr("127.0.0.1:0"), WithMaxInFlightPerConnection(1)))
+	engine.SetExSender(func(cmd Cmd, handler CmdResultExHandler) {
+		<-release
+		result, _ := NewCmdResult(StatusCodeOk, cmd)
+		handler(engine.TenEnv(), result, true, nil)
+	})
+	engine.Init()
+	engine.Start()
+	defer func() {
+		close(release)
+		engine.Stop()
+	}()
+
+	bridge := engine.tenEnv.engine.ext.(*WebSocketBridge)
+	waitForAddr(t, bridge)
+
+	conn, reader := dialWSTestClient(t, bridge.Addr())
+	defer conn.Close()
+
+	first, _ := json.Marshal(JSONRPCRequest{ID: []byte(`1`), Method: "slow"})
+	second, _ := json.Marshal(JSONRPCRequest{ID: []byte(`2`), Method: "slow"})
+	writeMaskedWSFrame(conn, wsOpText, first)
+	writeMaskedWSFrame(conn, wsOpText, second)
+
+	conn.SetReadDeadline(time.Now().Add(200 * time.Millisecond))
+	if _, err := readWSFrame(reader); err == nil {
+		t.Fatal("got a response before release, want the second request throttled behind the first")
+	}
+}
+
+// TestWebSocketBridge_OnStopWaitsForAnInFlightHandleRequest guards against
+// handleRequest's goroutine going untracked by b.wg: without it, OnStop
+// could call tenEnv.OnStopDone() while a handleRequest goroutine is still
+// live inside its own SendCmdEx call, tearing the graph down out from
+// under it.
+func TestWebSocketBridge_OnStopWaitsForAnInFlightHandleRequest(t *testing.T) {
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	engine := NewEngine(NewWebSocketBridge(WithAddr("127.0.0.1:0")))
+	engine.SetExSender(func(cmd Cmd, handler CmdResultExHandler) {
+		close(entered)
+		<-release
+		result, _ := NewCmdResult(StatusCodeOk, cmd)
+		handler(engine.TenEnv(), result, true, nil)
+	})
+	engine.Init()
+	engine.Start()
+
+	bridge := engine.tenEnv.engine.ext.(*WebSocketBridge)
+	waitForAddr(t, bridge)
+
+	conn, _ := dialWSTestClient(t, bridge.Addr())
+	defer conn.Close()
+
+	reqBody, _ := json.Marshal(JSONRPCRequest{ID: []byte(`1`), Method: "slow"})
+	if err := writeMaskedWSFrame(conn, wsOpText, reqBody); err != nil {
+		t.Fatalf("writeMaskedWSFrame: %v", err)
+	}
+
+	select {
+	case <-entered:
+	case <-time.After(5 * time.Second):
+		t.Fatal("handleRequest's SendCmdEx was never reached")
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		engine.Stop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		t.Fatal("Stop() returned while handleRequest was still inside SendCmdEx")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Stop() never returned after the in-flight handleRequest finished")
+	}
+}
+
+// waitForAddr polls until OnStart has bound a listener, since Start
+// returns once OnStartDone fires but the listener is assigned a moment
+// before that on the same goroutine.
+func waitForAddr(t *testing.T, bridge *WebSocketBridge) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if bridge.Addr() != "" {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatal("WebSocketBridge never bound a listener")
+}
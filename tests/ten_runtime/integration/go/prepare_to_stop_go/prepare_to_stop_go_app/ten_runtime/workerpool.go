@@ -0,0 +1,83 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"errors"
+	"sync"
+)
+
+// ErrWorkerPoolClosed is returned by WorkerPool.Submit once Drain has been
+// called; fn is not run in that case.
+var ErrWorkerPoolClosed = errors.New("ten: worker pool is closed")
+
+// WorkerPool bounds the number of goroutines concurrently running work an
+// extension submits to it, for an extension that would otherwise spawn a
+// fresh goroutine per Cmd (see extension_b's OnCmd) with no upper bound
+// under load. An extension typically embeds one and Submits its per-Cmd
+// work to it instead of calling go func() directly.
+type WorkerPool struct {
+	tasks chan func()
+	wg    sync.WaitGroup
+
+	closeOnce sync.Once
+	closed    chan struct{}
+}
+
+// NewWorkerPool starts a WorkerPool with size fixed worker goroutines,
+// each pulling from a shared queue of submitted tasks; size below 1 is
+// treated as 1.
+func NewWorkerPool(size int) *WorkerPool {
+	if size < 1 {
+		size = 1
+	}
+	p := &WorkerPool{
+		tasks:  make(chan func()),
+		closed: make(chan struct{}),
+	}
+	p.wg.Add(size)
+	for i := 0; i < size; i++ {
+		go p.worker()
+	}
+	return p
+}
+
+func (p *WorkerPool) worker() {
+	defer p.wg.Done()
+	for {
+		select {
+		case task := <-p.tasks:
+			task()
+		case <-p.closed:
+			return
+		}
+	}
+}
+
+// Submit queues fn to run on one of the pool's fixed workers, blocking
+// until a worker is free to accept it. It returns ErrWorkerPoolClosed,
+// without running fn, once Drain has been called.
+func (p *WorkerPool) Submit(fn func()) error {
+	select {
+	case p.tasks <- fn:
+		return nil
+	case <-p.closed:
+		return ErrWorkerPoolClosed
+	}
+}
+
+// Drain stops the pool from accepting new work and blocks until every
+// task already handed to a worker has finished running, then returns.
+// An extension's OnStop should call Drain before calling OnStopDone, so
+// the runtime never tears the extension down while a pool worker is still
+// mid-task; it's safe to call OnStopDone immediately after Drain returns.
+// Drain is idempotent -- calling it more than once is safe, and the second
+// call returns as soon as the first one's wait completes.
+func (p *WorkerPool) Drain() {
+	p.closeOnce.Do(func() {
+		close(p.closed)
+	})
+	p.wg.Wait()
+}
@@ -0,0 +1,128 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWorkerPool_SubmitRunsTask(t *testing.T) {
+	pool := NewWorkerPool(2)
+	defer pool.Drain()
+
+	done := make(chan struct{})
+	if err := pool.Submit(func() { close(done) }); err != nil {
+		t.Fatalf("Submit: %v", err)
+	}
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("task submitted to pool never ran")
+	}
+}
+
+func TestWorkerPool_BoundsConcurrency(t *testing.T) {
+	const size = 3
+	pool := NewWorkerPool(size)
+	defer pool.Drain()
+
+	var current, max int64
+	release := make(chan struct{})
+	started := make(chan struct{}, size*2)
+
+	for i := 0; i < size*2; i++ {
+		go func() {
+			if err := pool.Submit(func() {
+				n := atomic.AddInt64(&current, 1)
+				for {
+					old := atomic.LoadInt64(&max)
+					if n <= old || atomic.CompareAndSwapInt64(&max, old, n) {
+						break
+					}
+				}
+				started <- struct{}{}
+				<-release
+				atomic.AddInt64(&current, -1)
+			}); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+
+	for i := 0; i < size; i++ {
+		select {
+		case <-started:
+		case <-time.After(time.Second):
+			t.Fatal("fewer than size workers picked up work concurrently")
+		}
+	}
+	close(release)
+
+	for i := 0; i < size; i++ {
+		select {
+		case <-started:
+		case <-time.After(time.Second):
+			t.Fatal("remaining submitted tasks never ran after release")
+		}
+	}
+
+	if got := atomic.LoadInt64(&max); got > size {
+		t.Fatalf("observed %d tasks running at once, want at most %d", got, size)
+	}
+}
+
+func TestWorkerPool_DrainWaitsForOutstandingTasks(t *testing.T) {
+	pool := NewWorkerPool(1)
+
+	var ran int32
+	release := make(chan struct{})
+	if err := pool.Submit(func() {
+		<-release
+		atomic.StoreInt32(&ran, 1)
+	}); err != nil {
+		t.Fatalf("Submit: %v", err)
+	}
+
+	drained := make(chan struct{})
+	go func() {
+		pool.Drain()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+		t.Fatal("Drain returned before its outstanding task finished")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	select {
+	case <-drained:
+	case <-time.After(time.Second):
+		t.Fatal("Drain never returned after its outstanding task finished")
+	}
+	if atomic.LoadInt32(&ran) != 1 {
+		t.Fatal("Drain returned without the submitted task having run")
+	}
+}
+
+func TestWorkerPool_SubmitAfterDrainIsRejected(t *testing.T) {
+	pool := NewWorkerPool(1)
+	pool.Drain()
+
+	if err := pool.Submit(func() {}); !errors.Is(err, ErrWorkerPoolClosed) {
+		t.Fatalf("Submit after Drain = %v, want ErrWorkerPoolClosed", err)
+	}
+}
+
+func TestWorkerPool_DrainIsIdempotent(t *testing.T) {
+	pool := NewWorkerPool(1)
+	pool.Drain()
+	pool.Drain()
+}
@@ -0,0 +1,108 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package ten
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+const (
+	wsOpText  byte = 0x1
+	wsOpClose byte = 0x8
+	wsOpPing  byte = 0x9
+	wsOpPong  byte = 0xA
+)
+
+// wsFrame is one parsed WebSocket frame.
+type wsFrame struct {
+	opcode  byte
+	payload []byte
+}
+
+// errWSFragmented is returned by readWSFrame for a frame with FIN unset.
+var errWSFragmented = errors.New("ten: fragmented WebSocket frames are not supported")
+
+// readWSFrame reads one RFC 6455 frame from r. Only single-frame messages
+// (FIN=1) are supported: WebSocketBridge's own JSON-RPC payloads are
+// always small enough to fit in one frame, and reassembling a message an
+// arbitrary client chose to fragment is outside this bridge's scope (see
+// WebSocketBridge's doc comment: the engineering here is lifecycle and
+// backpressure, not the wire protocol itself).
+func readWSFrame(r *bufio.Reader) (wsFrame, error) {
+	head := make([]byte, 2)
+	if _, err := io.ReadFull(r, head); err != nil {
+		return wsFrame{}, err
+	}
+	fin := head[0]&0x80 != 0
+	if !fin {
+		return wsFrame{}, errWSFragmented
+	}
+	opcode := head[0] & 0x0f
+	masked := head[1]&0x80 != 0
+	length := uint64(head[1] & 0x7f)
+
+	switch length {
+	case 126:
+		ext := make([]byte, 2)
+		if _, err := io.ReadFull(r, ext); err != nil {
+			return wsFrame{}, err
+		}
+		length = uint64(binary.BigEndian.Uint16(ext))
+	case 127:
+		ext := make([]byte, 8)
+		if _, err := io.ReadFull(r, ext); err != nil {
+			return wsFrame{}, err
+		}
+		length = binary.BigEndian.Uint64(ext)
+	}
+
+	var maskKey [4]byte
+	if masked {
+		if _, err := io.ReadFull(r, maskKey[:]); err != nil {
+			return wsFrame{}, err
+		}
+	}
+
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return wsFrame{}, err
+	}
+	if masked {
+		for i := range payload {
+			payload[i] ^= maskKey[i%4]
+		}
+	}
+	return wsFrame{opcode: opcode, payload: payload}, nil
+}
+
+// writeWSFrame writes one unmasked, single-frame RFC 6455 message to w.
+// It's unmasked because the spec requires masking only from client to
+// server, and WebSocketBridge only ever writes as the server side.
+func writeWSFrame(w io.Writer, opcode byte, payload []byte) error {
+	var head []byte
+	length := len(payload)
+	switch {
+	case length <= 125:
+		head = []byte{0x80 | opcode, byte(length)}
+	case length <= 0xffff:
+		head = make([]byte, 4)
+		head[0] = 0x80 | opcode
+		head[1] = 126
+		binary.BigEndian.PutUint16(head[2:], uint16(length))
+	default:
+		head = make([]byte, 10)
+		head[0] = 0x80 | opcode
+		head[1] = 127
+		binary.BigEndian.PutUint64(head[2:], uint64(length))
+	}
+	if _, err := w.Write(head); err != nil {
+		return err
+	}
+	_, err := w.Write(payload)
+	return err
+}
@@ -0,0 +1,103 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package tentest
+
+import (
+	"sync"
+	"time"
+
+	ten "ten_framework/ten_runtime"
+)
+
+// FakeClock is a ten.Clock a test drives by hand: Now never moves and no
+// AfterFunc callback ever fires until a call to Advance says so, which is
+// what lets a test exercise e.g. a SetCommandTimeout watchdog
+// deterministically instead of arming a real short duration and sleeping
+// past it. Install one via ten.WithClock or TenEnv.SetClock.
+type FakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []*fakeClockTimer
+}
+
+// NewFakeClock creates a FakeClock whose Now starts at start.
+func NewFakeClock(start time.Time) *FakeClock {
+	return &FakeClock{now: start}
+}
+
+// Now implements ten.Clock.
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// AfterFunc implements ten.Clock: f runs, in its own goroutine, the first
+// time Advance moves Now to or past d after this call.
+func (c *FakeClock) AfterFunc(d time.Duration, f func()) ten.ClockTimer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	timer := &fakeClockTimer{deadline: c.now.Add(d), f: f}
+	c.timers = append(c.timers, timer)
+	return timer
+}
+
+// Advance moves Now forward by d, then runs -- each in its own goroutine,
+// the same way a real time.AfterFunc callback would -- every timer whose
+// deadline it reached or passed and that hasn't already been stopped.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	c.now = c.now.Add(d)
+	now := c.now
+
+	var due []*fakeClockTimer
+	remaining := c.timers[:0]
+	for _, timer := range c.timers {
+		if !now.Before(timer.deadline) {
+			due = append(due, timer)
+		} else {
+			remaining = append(remaining, timer)
+		}
+	}
+	c.timers = remaining
+	c.mu.Unlock()
+
+	for _, timer := range due {
+		if timer.claim() {
+			go timer.f()
+		}
+	}
+}
+
+// fakeClockTimer is the ten.ClockTimer FakeClock.AfterFunc returns.
+// firing/stopping are the same single-CAS-style race as
+// (*time.Timer).Stop's own documented ambiguity, guarded here with a
+// plain mutex since a FakeClock's timers only ever fire from Advance,
+// never concurrently with each other.
+type fakeClockTimer struct {
+	mu       sync.Mutex
+	deadline time.Time
+	f        func()
+	resolved bool
+}
+
+// claim reports whether this call is the one that gets to run f -- either
+// Advance finding it due, or losing to a Stop that already claimed it.
+func (t *fakeClockTimer) claim() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.resolved {
+		return false
+	}
+	t.resolved = true
+	return true
+}
+
+// Stop implements ten.ClockTimer. It reports whether it beat Advance to
+// claiming this timer -- false if Advance already ran (or is about to
+// run) f first.
+func (t *fakeClockTimer) Stop() bool {
+	return t.claim()
+}
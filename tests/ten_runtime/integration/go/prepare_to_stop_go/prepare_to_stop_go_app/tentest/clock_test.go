@@ -0,0 +1,131 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package tentest
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	ten "ten_framework/ten_runtime"
+)
+
+// lockedClockTestBuffer is an io.Writer safe for the concurrent
+// writes-from-the-log-goroutine, reads-from-the-test-goroutine pattern
+// TestFakeClock_DrivesSetCommandTimeoutWithoutSleeping polls with.
+type lockedClockTestBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedClockTestBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedClockTestBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestFakeClock_NowStartsAtTheGivenTimeAndOnlyAdvanceMovesIt(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+
+	if got := clock.Now(); !got.Equal(start) {
+		t.Fatalf("Now() = %v, want %v", got, start)
+	}
+	clock.Advance(time.Minute)
+	if got := clock.Now(); !got.Equal(start.Add(time.Minute)) {
+		t.Fatalf("Now() = %v, want %v", got, start.Add(time.Minute))
+	}
+}
+
+func TestFakeClock_AfterFuncFiresOnlyOnceAdvancePassesItsDeadline(t *testing.T) {
+	clock := NewFakeClock(time.Unix(0, 0))
+	fired := make(chan struct{}, 1)
+	clock.AfterFunc(time.Second, func() { fired <- struct{}{} })
+
+	clock.Advance(500 * time.Millisecond)
+	select {
+	case <-fired:
+		t.Fatal("AfterFunc fired before its deadline")
+	default:
+	}
+
+	clock.Advance(500 * time.Millisecond)
+	select {
+	case <-fired:
+	case <-time.After(time.Second):
+		t.Fatal("AfterFunc never fired once Advance passed its deadline")
+	}
+}
+
+func TestFakeClock_StopBeforeItsDueSuppressesTheCallback(t *testing.T) {
+	clock := NewFakeClock(time.Unix(0, 0))
+	fired := make(chan struct{}, 1)
+	timer := clock.AfterFunc(time.Second, func() { fired <- struct{}{} })
+
+	if ok := timer.Stop(); !ok {
+		t.Fatal("Stop() = false, want true for a timer stopped before its deadline")
+	}
+	clock.Advance(time.Hour)
+	select {
+	case <-fired:
+		t.Fatal("AfterFunc fired despite being stopped first")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+// neverRespondsExtension mirrors ten_runtime's own cmdtimeout_test.go
+// fixture of the same name: it arms SetCommandTimeout and then never
+// calls ReturnResult itself.
+type neverRespondsExtension struct {
+	ten.DefaultExtension
+	timeout time.Duration
+}
+
+func (e *neverRespondsExtension) OnCmd(tenEnv ten.TenEnv, cmd ten.Cmd) {
+	tenEnv.SetCommandTimeout(cmd, e.timeout)
+}
+
+// TestFakeClock_DrivesSetCommandTimeoutWithoutSleeping is the demonstration
+// tentest.FakeClock exists for: a SetCommandTimeout watchdog armed against
+// a fake clock fires exactly when the test advances it, with no real
+// duration ever elapsing.
+func TestFakeClock_DrivesSetCommandTimeoutWithoutSleeping(t *testing.T) {
+	clock := NewFakeClock(time.Unix(0, 0))
+	engine := ten.NewEngine(&neverRespondsExtension{timeout: time.Hour}, ten.WithClock(clock))
+	engine.Init()
+	engine.Start()
+
+	buf := &lockedClockTestBuffer{}
+	engine.TenEnv().SetLogSink(buf, ten.LogFormatText)
+
+	cmd, _ := ten.NewCmd("ask")
+	if err := engine.DeliverCmd(cmd); err != nil {
+		t.Fatalf("DeliverCmd: %v", err)
+	}
+
+	// The real hour-long deadline never elapses in wall-clock time; only
+	// advancing the fake clock past it does.
+	clock.Advance(59 * time.Minute)
+	if got := buf.String(); strings.Contains(got, "command timeout") {
+		t.Fatalf("sink content = %q, did not expect the watchdog to have fired yet", got)
+	}
+
+	clock.Advance(2 * time.Minute)
+	deadline := time.Now().Add(time.Second)
+	for !strings.Contains(buf.String(), "command timeout") && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if got := buf.String(); !strings.Contains(got, "command timeout: auto-returning") {
+		t.Fatalf("sink content = %q, want a command-timeout auto-return warning", got)
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package tentest
+
+import (
+	ten "ten_framework/ten_runtime"
+)
+
+// MockCmd builds a ten.Cmd with a name and properties set up front,
+// instead of the usual NewCmd-then-SetProperty* sequence, for tests that
+// want the whole thing in one expression.
+type MockCmd struct {
+	name    string
+	setters []func(cmd ten.Cmd) error
+}
+
+// NewMockCmd starts building a Cmd named name.
+func NewMockCmd(name string) *MockCmd {
+	return &MockCmd{name: name}
+}
+
+// WithString queues path to be set to value when Build is called.
+func (b *MockCmd) WithString(path string, value string) *MockCmd {
+	b.setters = append(b.setters, func(cmd ten.Cmd) error {
+		return cmd.SetPropertyString(path, value)
+	})
+	return b
+}
+
+// WithInt64 queues path to be set to value when Build is called.
+func (b *MockCmd) WithInt64(path string, value int64) *MockCmd {
+	b.setters = append(b.setters, func(cmd ten.Cmd) error {
+		return cmd.SetPropertyInt64(path, value)
+	})
+	return b
+}
+
+// WithFloat64 queues path to be set to value when Build is called.
+func (b *MockCmd) WithFloat64(path string, value float64) *MockCmd {
+	b.setters = append(b.setters, func(cmd ten.Cmd) error {
+		return cmd.SetPropertyFloat64(path, value)
+	})
+	return b
+}
+
+// WithBool queues path to be set to value when Build is called.
+func (b *MockCmd) WithBool(path string, value bool) *MockCmd {
+	b.setters = append(b.setters, func(cmd ten.Cmd) error {
+		return cmd.SetPropertyBool(path, value)
+	})
+	return b
+}
+
+// Build creates the underlying ten.Cmd and applies every queued property
+// setter to it, in the order they were added, stopping at the first
+// error.
+func (b *MockCmd) Build() (ten.Cmd, error) {
+	cmd, err := ten.NewCmd(b.name)
+	if err != nil {
+		return nil, err
+	}
+	for _, set := range b.setters {
+		if err := set(cmd); err != nil {
+			return nil, err
+		}
+	}
+	return cmd, nil
+}
@@ -0,0 +1,125 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package tentest
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	ten "ten_framework/ten_runtime"
+)
+
+// ResultsEqual compares a and b's status code and properties -- binary
+// properties and nested objects/arrays included, since PropertiesSnapshot
+// already decodes them into plain Go values instead of leaving anything
+// as raw JSON -- and reports whether they match. When they don't, the
+// second return is a human-readable diff naming exactly what differs, so
+// a test can just do:
+//
+//	if ok, diff := tentest.ResultsEqual(want, got); !ok {
+//		t.Fatal(diff)
+//	}
+func ResultsEqual(a, b ten.CmdResult) (bool, string) {
+	statusA, err := a.StatusCode()
+	if err != nil {
+		return false, fmt.Sprintf("a.StatusCode(): %v", err)
+	}
+	statusB, err := b.StatusCode()
+	if err != nil {
+		return false, fmt.Sprintf("b.StatusCode(): %v", err)
+	}
+
+	var lines []string
+	if statusA != statusB {
+		lines = append(lines, fmt.Sprintf("StatusCode: %v != %v", statusA, statusB))
+	}
+
+	propsA, err := a.PropertiesSnapshot()
+	if err != nil {
+		return false, fmt.Sprintf("a.PropertiesSnapshot(): %v", err)
+	}
+	propsB, err := b.PropertiesSnapshot()
+	if err != nil {
+		return false, fmt.Sprintf("b.PropertiesSnapshot(): %v", err)
+	}
+	lines = append(lines, diffPropertyLines(propsA, propsB)...)
+
+	if len(lines) == 0 {
+		return true, ""
+	}
+	return false, strings.Join(lines, "\n")
+}
+
+// CmdsEqual is ResultsEqual's counterpart for Cmd: it compares name and
+// properties the same way, returning a diff naming any mismatch.
+func CmdsEqual(a, b ten.Cmd) (bool, string) {
+	nameA, err := a.GetName()
+	if err != nil {
+		return false, fmt.Sprintf("a.GetName(): %v", err)
+	}
+	nameB, err := b.GetName()
+	if err != nil {
+		return false, fmt.Sprintf("b.GetName(): %v", err)
+	}
+
+	var lines []string
+	if nameA != nameB {
+		lines = append(lines, fmt.Sprintf("Name: %q != %q", nameA, nameB))
+	}
+
+	propsA, err := a.PropertiesSnapshot()
+	if err != nil {
+		return false, fmt.Sprintf("a.PropertiesSnapshot(): %v", err)
+	}
+	propsB, err := b.PropertiesSnapshot()
+	if err != nil {
+		return false, fmt.Sprintf("b.PropertiesSnapshot(): %v", err)
+	}
+	lines = append(lines, diffPropertyLines(propsA, propsB)...)
+
+	if len(lines) == 0 {
+		return true, ""
+	}
+	return false, strings.Join(lines, "\n")
+}
+
+// diffPropertyLines compares two PropertiesSnapshot results key by key,
+// in sorted order for a stable, diffable report, and returns one line per
+// key present on only one side or holding an unequal value on both.
+// reflect.DeepEqual is what makes this correct for nested map[string]any/
+// []any values and []byte properties alike -- PropertiesSnapshot already
+// decoded everything into those plain Go shapes, so there's no JSON or
+// base64 layer left to compare through.
+func diffPropertyLines(a, b map[string]any) []string {
+	keys := make(map[string]struct{}, len(a)+len(b))
+	for k := range a {
+		keys[k] = struct{}{}
+	}
+	for k := range b {
+		keys[k] = struct{}{}
+	}
+	sorted := make([]string, 0, len(keys))
+	for k := range keys {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+
+	var lines []string
+	for _, k := range sorted {
+		va, inA := a[k]
+		vb, inB := b[k]
+		switch {
+		case !inB:
+			lines = append(lines, fmt.Sprintf("%s: %v (only in a)", k, va))
+		case !inA:
+			lines = append(lines, fmt.Sprintf("%s: %v (only in b)", k, vb))
+		case !reflect.DeepEqual(va, vb):
+			lines = append(lines, fmt.Sprintf("%s: %v != %v", k, va, vb))
+		}
+	}
+	return lines
+}
@@ -0,0 +1,85 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package tentest
+
+import (
+	"strings"
+	"testing"
+
+	ten "ten_framework/ten_runtime"
+)
+
+func TestResultsEqual_TrueForMatchingStatusAndProperties(t *testing.T) {
+	a, _ := ten.NewCmdResult(ten.StatusCodeOk, nil)
+	a.SetPropertyString("output", "hi")
+	a.SetPropertyBytes("blob", []byte{1, 2, 3})
+	a.SetPropertyFromJSONBytes("nested", []byte(`{"k":"v"}`))
+
+	b, _ := ten.NewCmdResult(ten.StatusCodeOk, nil)
+	b.SetPropertyString("output", "hi")
+	b.SetPropertyBytes("blob", []byte{1, 2, 3})
+	b.SetPropertyFromJSONBytes("nested", []byte(`{"k":"v"}`))
+
+	ok, diff := ResultsEqual(a, b)
+	if !ok {
+		t.Fatalf("ResultsEqual = false, want true; diff:\n%s", diff)
+	}
+}
+
+func TestResultsEqual_ReportsStatusCodeMismatch(t *testing.T) {
+	a, _ := ten.NewCmdResult(ten.StatusCodeOk, nil)
+	b, _ := ten.NewCmdResult(ten.StatusCodeError, nil)
+
+	ok, diff := ResultsEqual(a, b)
+	if ok {
+		t.Fatal("ResultsEqual = true, want false")
+	}
+	if !strings.Contains(diff, "StatusCode") {
+		t.Fatalf("diff = %q, want it to mention StatusCode", diff)
+	}
+}
+
+func TestResultsEqual_ReportsPropertyMismatchesAndOneSidedKeys(t *testing.T) {
+	a, _ := ten.NewCmdResult(ten.StatusCodeOk, nil)
+	a.SetPropertyString("output", "hi")
+	a.SetPropertyBytes("blob", []byte{1, 2, 3})
+
+	b, _ := ten.NewCmdResult(ten.StatusCodeOk, nil)
+	b.SetPropertyString("output", "bye")
+	b.SetPropertyString("extra", "surprise")
+
+	ok, diff := ResultsEqual(a, b)
+	if ok {
+		t.Fatal("ResultsEqual = true, want false")
+	}
+	for _, want := range []string{"output", "blob", "extra"} {
+		if !strings.Contains(diff, want) {
+			t.Fatalf("diff = %q, want it to mention %q", diff, want)
+		}
+	}
+}
+
+func TestCmdsEqual_TrueForMatchingNameAndProperties(t *testing.T) {
+	a, _ := NewMockCmd("greet").WithString("name", "world").Build()
+	b, _ := NewMockCmd("greet").WithString("name", "world").Build()
+
+	ok, diff := CmdsEqual(a, b)
+	if !ok {
+		t.Fatalf("CmdsEqual = false, want true; diff:\n%s", diff)
+	}
+}
+
+func TestCmdsEqual_ReportsNameMismatch(t *testing.T) {
+	a, _ := NewMockCmd("greet").Build()
+	b, _ := NewMockCmd("farewell").Build()
+
+	ok, diff := CmdsEqual(a, b)
+	if ok {
+		t.Fatal("CmdsEqual = true, want false")
+	}
+	if !strings.Contains(diff, "greet") || !strings.Contains(diff, "farewell") {
+		t.Fatalf("diff = %q, want it to mention both cmd names", diff)
+	}
+}
@@ -0,0 +1,220 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package tentest
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	ten "ten_framework/ten_runtime"
+)
+
+// RecordedEvent is one line of a Recorder's output file: a single inbound
+// Cmd/Data/AudioFrame/VideoFrame, with just enough recorded to reconstruct
+// and feed it back through Replayer.
+type RecordedEvent struct {
+	Seq        int       `json:"seq"`
+	Kind       string    `json:"kind"`
+	Name       string    `json:"name"`
+	Timestamp  int64     `json:"timestamp"`
+	RecordedAt time.Time `json:"recorded_at"`
+
+	// Properties is only ever non-empty for a Cmd -- Data, AudioFrame and
+	// VideoFrame carry no property bag (see ten.Data's doc comment).
+	Properties map[string]any `json:"properties,omitempty"`
+
+	// PayloadSize is always recorded, even when the payload itself was
+	// spilled to a sidecar file or dropped.
+	PayloadSize int `json:"payload_size"`
+
+	// Payload holds the raw bytes inline, base64-encoded by
+	// encoding/json, for a payload at or under the Recorder's
+	// InlinePayloadLimit. Exactly one of Payload and PayloadFile is set
+	// for an event with PayloadSize > 0.
+	Payload []byte `json:"payload,omitempty"`
+
+	// PayloadFile, when set, names a file under the recording's sidecar
+	// directory (see Recorder's doc comment) holding the raw payload,
+	// for a payload over the Recorder's InlinePayloadLimit.
+	PayloadFile string `json:"payload_file,omitempty"`
+}
+
+// DefaultInlinePayloadLimit is the payload size, in bytes, at or under
+// which Recorder inlines a Data/AudioFrame/VideoFrame's payload in the
+// recording itself; see WithInlinePayloadLimit.
+const DefaultInlinePayloadLimit = 4096
+
+// RecorderOption configures a Recorder created via NewRecorder.
+type RecorderOption func(*Recorder)
+
+// WithInlinePayloadLimit overrides DefaultInlinePayloadLimit: a payload at
+// or under limit bytes is stored inline in the recording; anything larger
+// is written to its own file under the recording's sidecar directory
+// instead, keeping a session with a few large frames from ballooning the
+// main recording file. A non-positive limit spills every non-empty
+// payload to a sidecar file.
+func WithInlinePayloadLimit(limit int) RecorderOption {
+	return func(r *Recorder) { r.inlineLimit = limit }
+}
+
+// WithRecorderClock overrides the clock RecordedAt is stamped from,
+// the same way ten.WithClock overrides an Engine's -- e.g. to pair a
+// Recorder with a tentest.FakeClock so a test's recording has
+// deterministic, controllable timestamps instead of wall-clock ones.
+func WithRecorderClock(now func() time.Time) RecorderOption {
+	return func(r *Recorder) { r.now = now }
+}
+
+// Recorder wraps an Extension and logs every Cmd/Data/AudioFrame/
+// VideoFrame delivered to it -- name, properties (Cmd only), payload size,
+// the message's own opaque timestamp, and a RecordedAt this Recorder's own
+// clock stamps -- as newline-delimited JSON, one RecordedEvent per line,
+// before forwarding the call on to the wrapped Extension unchanged. It
+// never alters what the wrapped Extension sees or does; a bug that only
+// reproduces in the recording path itself would be this type's own bug,
+// not the extension under test's.
+//
+// Pair it with Replayer to feed a captured session back through an
+// extension under a MockTenEnv at original or accelerated timing, e.g. to
+// reproduce a field bug locally without the production graph that
+// triggered it. See NewRecorder for how a recording (and its sidecar
+// payload directory, if it ends up needing one) is laid out on disk.
+type Recorder struct {
+	ten.Extension
+
+	mu          sync.Mutex
+	file        *os.File
+	enc         *json.Encoder
+	seq         int
+	inlineLimit int
+	now         func() time.Time
+	sidecarDir  string
+}
+
+// NewRecorder creates a Recorder wrapping ext that writes its recording to
+// path, creating it (truncating an existing file of the same name). Any
+// payload too large to inline, per InlinePayloadLimit, is written under
+// path+".payloads", created lazily the first time it's needed -- a
+// recording with no oversized payloads never creates that directory.
+func NewRecorder(ext ten.Extension, path string, opts ...RecorderOption) (*Recorder, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("ten: NewRecorder: %w", err)
+	}
+	r := &Recorder{
+		Extension:   ext,
+		file:        file,
+		enc:         json.NewEncoder(file),
+		inlineLimit: DefaultInlinePayloadLimit,
+		now:         time.Now,
+		sidecarDir:  path + ".payloads",
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r, nil
+}
+
+// Close flushes and closes the underlying recording file. It does not
+// touch any sidecar payload files, each of which is written and closed
+// independently as it's created.
+func (r *Recorder) Close() error {
+	return r.file.Close()
+}
+
+// record appends one event for msg to the recording. Errors writing the
+// event or a sidecar payload are swallowed rather than propagated: a
+// Recorder observes an extension's traffic, it doesn't get to fail the
+// callback the traffic arrived through.
+func (r *Recorder) record(msg ten.Msg, properties map[string]any, payload []byte) {
+	kind, _ := msg.GetType()
+	name, _ := msg.GetName()
+	timestamp, _ := msg.GetTimestamp()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.seq++
+	event := RecordedEvent{
+		Seq:         r.seq,
+		Kind:        kind.String(),
+		Name:        name,
+		Timestamp:   timestamp,
+		RecordedAt:  r.now(),
+		Properties:  properties,
+		PayloadSize: len(payload),
+	}
+	if len(payload) > 0 {
+		if len(payload) <= r.inlineLimit {
+			event.Payload = payload
+		} else if file, err := r.writeSidecarLocked(payload); err == nil {
+			event.PayloadFile = file
+		}
+	}
+	_ = r.enc.Encode(event)
+}
+
+// writeSidecarLocked writes payload to a fresh file under r.sidecarDir and
+// returns its path relative to the recording file's own directory, the
+// form Replayer expects in RecordedEvent.PayloadFile. Callers must hold
+// r.mu.
+func (r *Recorder) writeSidecarLocked(payload []byte) (string, error) {
+	if err := os.MkdirAll(r.sidecarDir, 0o755); err != nil {
+		return "", err
+	}
+	name := fmt.Sprintf("%d.bin", r.seq)
+	if err := os.WriteFile(filepath.Join(r.sidecarDir, name), payload, 0o644); err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Base(r.sidecarDir), name), nil
+}
+
+func (r *Recorder) OnCmd(tenEnv ten.TenEnv, cmd ten.Cmd) {
+	properties, _ := cmd.PropertiesSnapshot()
+	r.record(cmd, properties, nil)
+	r.Extension.OnCmd(tenEnv, cmd)
+}
+
+func (r *Recorder) OnData(tenEnv ten.TenEnv, data ten.Data) {
+	payload, _ := data.GetBuf()
+	r.record(data, nil, payload)
+	r.Extension.OnData(tenEnv, data)
+}
+
+func (r *Recorder) OnAudioFrame(tenEnv ten.TenEnv, frame ten.AudioFrame) {
+	r.record(frame, nil, lockAndCopyBuf(frame))
+	r.Extension.OnAudioFrame(tenEnv, frame)
+}
+
+func (r *Recorder) OnVideoFrame(tenEnv ten.TenEnv, frame ten.VideoFrame) {
+	r.record(frame, nil, lockAndCopyBuf(frame))
+	r.Extension.OnVideoFrame(tenEnv, frame)
+}
+
+// frameBuf is the LockBuf/UnlockBuf half of AudioFrame and VideoFrame --
+// both satisfy it, unlike Data, which exposes a plain copying GetBuf
+// instead (see ten.Data's doc comment for why).
+type frameBuf interface {
+	LockBuf() ([]byte, error)
+	UnlockBuf(buf []byte) error
+}
+
+// lockAndCopyBuf reads a frame's buffer through the LockBuf/UnlockBuf pair
+// its zero-copy contract requires, copying it out before unlocking so the
+// copy stays valid after this call returns. It returns nil if the buffer
+// is already locked by something else -- a Recorder skips that payload
+// rather than blocking or erroring OnAudioFrame/OnVideoFrame over it.
+func lockAndCopyBuf(f frameBuf) []byte {
+	buf, err := f.LockBuf()
+	if err != nil {
+		return nil
+	}
+	defer f.UnlockBuf(buf)
+	return append([]byte(nil), buf...)
+}
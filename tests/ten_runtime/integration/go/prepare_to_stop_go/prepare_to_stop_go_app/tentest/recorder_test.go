@@ -0,0 +1,137 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package tentest
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	ten "ten_framework/ten_runtime"
+)
+
+func readEvents(t *testing.T, path string) []RecordedEvent {
+	t.Helper()
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("open %s: %v", path, err)
+	}
+	defer file.Close()
+
+	var events []RecordedEvent
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var event RecordedEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			t.Fatalf("unmarshal event: %v", err)
+		}
+		events = append(events, event)
+	}
+	return events
+}
+
+func TestRecorder_LogsCmdNameAndProperties(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+	rec, err := NewRecorder(ten.DefaultExtension{}, path)
+	if err != nil {
+		t.Fatalf("NewRecorder: %v", err)
+	}
+
+	env := NewMockTenEnv()
+	cmd, _ := NewMockCmd("greet").WithString("name", "ferris").Build()
+	rec.OnCmd(env, cmd)
+	if err := rec.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	events := readEvents(t, path)
+	if len(events) != 1 {
+		t.Fatalf("recorded %d events, want 1", len(events))
+	}
+	if events[0].Kind != "cmd" || events[0].Name != "greet" {
+		t.Fatalf("event = %+v, want kind=cmd name=greet", events[0])
+	}
+	if got := events[0].Properties["name"]; got != "ferris" {
+		t.Fatalf("event.Properties[name] = %v, want ferris", got)
+	}
+}
+
+func TestRecorder_ForwardsTheCallToTheWrappedExtension(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+	inner := &countingRecorderExtension{}
+	rec, err := NewRecorder(inner, path)
+	if err != nil {
+		t.Fatalf("NewRecorder: %v", err)
+	}
+	defer rec.Close()
+
+	env := NewMockTenEnv()
+	cmd, _ := NewMockCmd("ping").Build()
+	rec.OnCmd(env, cmd)
+
+	if inner.cmdCalls != 1 {
+		t.Fatalf("wrapped extension's OnCmd calls = %d, want 1", inner.cmdCalls)
+	}
+}
+
+func TestRecorder_InlinesASmallPayloadButSpillsALargeOneToASidecar(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "session.jsonl")
+	rec, err := NewRecorder(ten.DefaultExtension{}, path, WithInlinePayloadLimit(4))
+	if err != nil {
+		t.Fatalf("NewRecorder: %v", err)
+	}
+
+	env := NewMockTenEnv()
+	small, _ := ten.NewData()
+	small.SetName("small")
+	small.SetBuf([]byte("hi"))
+	rec.OnData(env, small)
+
+	large, _ := ten.NewData()
+	large.SetName("large")
+	large.SetBuf([]byte("this payload is over the 4-byte limit"))
+	rec.OnData(env, large)
+
+	if err := rec.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	events := readEvents(t, path)
+	if len(events) != 2 {
+		t.Fatalf("recorded %d events, want 2", len(events))
+	}
+	if len(events[0].Payload) != 2 || events[0].PayloadFile != "" {
+		t.Fatalf("small payload event = %+v, want inline Payload of length 2", events[0])
+	}
+	if events[1].Payload != nil || events[1].PayloadFile == "" {
+		t.Fatalf("large payload event = %+v, want a PayloadFile, no inline Payload", events[1])
+	}
+	if events[1].PayloadSize != len("this payload is over the 4-byte limit") {
+		t.Fatalf("large payload event PayloadSize = %d, want %d", events[1].PayloadSize, len("this payload is over the 4-byte limit"))
+	}
+
+	sidecar, err := os.ReadFile(filepath.Join(dir, events[1].PayloadFile))
+	if err != nil {
+		t.Fatalf("reading sidecar file: %v", err)
+	}
+	if string(sidecar) != "this payload is over the 4-byte limit" {
+		t.Fatalf("sidecar contents = %q, want the large payload", sidecar)
+	}
+}
+
+// countingRecorderExtension counts OnCmd calls, to prove Recorder forwards
+// to the extension it wraps instead of swallowing the call itself.
+type countingRecorderExtension struct {
+	ten.DefaultExtension
+	cmdCalls int
+}
+
+func (e *countingRecorderExtension) OnCmd(tenEnv ten.TenEnv, cmd ten.Cmd) {
+	e.cmdCalls++
+	tenEnv.ReturnOK(cmd, "")
+}
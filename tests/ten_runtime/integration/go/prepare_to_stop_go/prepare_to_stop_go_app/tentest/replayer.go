@@ -0,0 +1,218 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package tentest
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	ten "ten_framework/ten_runtime"
+)
+
+// Replayer feeds a session captured by Recorder back through an extension,
+// one RecordedEvent at a time, reconstructing each as the Cmd/Data/
+// AudioFrame/VideoFrame it originally was and calling the matching OnCmd/
+// OnData/OnAudioFrame/OnVideoFrame method directly -- there is no Engine
+// or graph involved, so it's meant to be paired with a MockTenEnv the same
+// way a hand-written unit test would drive an extension's callbacks.
+type Replayer struct {
+	dir    string
+	events []RecordedEvent
+}
+
+// NewReplayer reads every RecordedEvent from path, a file Recorder wrote,
+// in the order Recorder wrote them. Sidecar payload files are read lazily
+// by Replay, relative to path's own directory, so a recording and its
+// ".payloads" directory can be moved together without NewReplayer needing
+// to touch them up front.
+func NewReplayer(path string) (*Replayer, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("ten: NewReplayer: %w", err)
+	}
+	defer file.Close()
+
+	var events []RecordedEvent
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, 64<<20)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var event RecordedEvent
+		if err := json.Unmarshal(line, &event); err != nil {
+			return nil, fmt.Errorf("ten: NewReplayer: %s: %w", path, err)
+		}
+		events = append(events, event)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("ten: NewReplayer: %s: %w", path, err)
+	}
+
+	return &Replayer{dir: filepath.Dir(path), events: events}, nil
+}
+
+// Events returns every RecordedEvent read by NewReplayer, in recorded
+// order, for a caller that wants to inspect or filter the session before
+// (or instead of) calling Replay.
+func (rp *Replayer) Events() []RecordedEvent {
+	return append([]RecordedEvent(nil), rp.events...)
+}
+
+// ReplayOption configures a Replay call.
+type ReplayOption func(*replayConfig)
+
+type replayConfig struct {
+	speed float64
+	sleep func(time.Duration)
+}
+
+// WithReplaySpeed scales the gaps between events' RecordedAt timestamps:
+// 1 (the default) reproduces the original session's pacing, 2 replays
+// twice as fast, 0.5 half as fast. speed <= 0 replays as fast as possible,
+// with no delay between events at all.
+func WithReplaySpeed(speed float64) ReplayOption {
+	return func(c *replayConfig) { c.speed = speed }
+}
+
+// withReplaySleep overrides the delay function Replay uses between
+// events, letting a test swap in an instrumented or non-blocking stand-in
+// for time.Sleep instead of actually waiting out a slowed-down replay.
+func withReplaySleep(sleep func(time.Duration)) ReplayOption {
+	return func(c *replayConfig) { c.sleep = sleep }
+}
+
+// Replay feeds every event, in order, to ext through tenEnv, waiting
+// between events for the (speed-scaled) gap between their original
+// RecordedAt timestamps -- so a caller that wants the original timing
+// gets it via WithReplaySpeed(1) (the default), and one that just wants
+// the sequence reproduced as fast as possible uses a speed <= 0. It
+// returns the first error building a recorded message back into a Cmd/
+// Data/AudioFrame/VideoFrame, stopping the replay at that point rather
+// than skipping the bad event and continuing.
+func (rp *Replayer) Replay(ext ten.Extension, tenEnv ten.TenEnv, opts ...ReplayOption) error {
+	cfg := replayConfig{speed: 1, sleep: time.Sleep}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	var previous *RecordedEvent
+	for i := range rp.events {
+		event := rp.events[i]
+		if previous != nil && cfg.speed > 0 {
+			gap := event.RecordedAt.Sub(previous.RecordedAt)
+			if gap > 0 {
+				cfg.sleep(time.Duration(float64(gap) / cfg.speed))
+			}
+		}
+		previous = &rp.events[i]
+
+		if err := rp.deliver(ext, tenEnv, event); err != nil {
+			return fmt.Errorf("ten: Replay: event %d (%s %q): %w", event.Seq, event.Kind, event.Name, err)
+		}
+	}
+	return nil
+}
+
+func (rp *Replayer) deliver(ext ten.Extension, tenEnv ten.TenEnv, event RecordedEvent) error {
+	switch event.Kind {
+	case ten.MsgTypeCmd.String():
+		cmd, err := ten.NewCmd(event.Name)
+		if err != nil {
+			return err
+		}
+		if len(event.Properties) > 0 {
+			if err := cmd.SetProperties(event.Properties); err != nil {
+				return err
+			}
+		}
+		if err := cmd.SetTimestamp(event.Timestamp); err != nil {
+			return err
+		}
+		ext.OnCmd(tenEnv, cmd)
+
+	case ten.MsgTypeData.String():
+		payload, err := rp.payload(event)
+		if err != nil {
+			return err
+		}
+		data, err := ten.NewData()
+		if err != nil {
+			return err
+		}
+		if err := data.SetName(event.Name); err != nil {
+			return err
+		}
+		if err := data.SetTimestamp(event.Timestamp); err != nil {
+			return err
+		}
+		if len(payload) > 0 {
+			if err := data.SetBuf(payload); err != nil {
+				return err
+			}
+		}
+		ext.OnData(tenEnv, data)
+
+	case ten.MsgTypeAudioFrame.String():
+		payload, err := rp.payload(event)
+		if err != nil {
+			return err
+		}
+		frame, err := ten.NewAudioFrame(event.Name)
+		if err != nil {
+			return err
+		}
+		if err := frame.SetTimestamp(event.Timestamp); err != nil {
+			return err
+		}
+		if len(payload) > 0 {
+			if err := frame.SetBuf(payload); err != nil {
+				return err
+			}
+		}
+		ext.OnAudioFrame(tenEnv, frame)
+
+	case ten.MsgTypeVideoFrame.String():
+		payload, err := rp.payload(event)
+		if err != nil {
+			return err
+		}
+		frame, err := ten.NewVideoFrame(event.Name)
+		if err != nil {
+			return err
+		}
+		if err := frame.SetTimestamp(event.Timestamp); err != nil {
+			return err
+		}
+		if len(payload) > 0 {
+			if err := frame.SetBuf(payload); err != nil {
+				return err
+			}
+		}
+		ext.OnVideoFrame(tenEnv, frame)
+
+	default:
+		return fmt.Errorf("unrecognized event kind %q", event.Kind)
+	}
+	return nil
+}
+
+// payload resolves event's payload, whether it was stored inline or
+// spilled to a sidecar file.
+func (rp *Replayer) payload(event RecordedEvent) ([]byte, error) {
+	if event.PayloadFile == "" {
+		return event.Payload, nil
+	}
+	buf, err := os.ReadFile(filepath.Join(rp.dir, event.PayloadFile))
+	if err != nil {
+		return nil, fmt.Errorf("reading sidecar payload: %w", err)
+	}
+	return buf, nil
+}
@@ -0,0 +1,156 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package tentest
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	ten "ten_framework/ten_runtime"
+)
+
+// replayerSpyExtension records every message Replay hands it, in order.
+type replayerSpyExtension struct {
+	ten.DefaultExtension
+	cmds  []string
+	datas []string
+	bufs  [][]byte
+}
+
+func (e *replayerSpyExtension) OnCmd(tenEnv ten.TenEnv, cmd ten.Cmd) {
+	name, _ := cmd.GetName()
+	e.cmds = append(e.cmds, name)
+	tenEnv.ReturnOK(cmd, "")
+}
+
+func (e *replayerSpyExtension) OnData(tenEnv ten.TenEnv, data ten.Data) {
+	name, _ := data.GetName()
+	buf, _ := data.GetBuf()
+	e.datas = append(e.datas, name)
+	e.bufs = append(e.bufs, buf)
+}
+
+func TestReplayer_ReplaysRecordedCmdsAndDataInOrder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+	rec, err := NewRecorder(ten.DefaultExtension{}, path)
+	if err != nil {
+		t.Fatalf("NewRecorder: %v", err)
+	}
+
+	env := NewMockTenEnv()
+	first, _ := NewMockCmd("start").WithString("mode", "fast").Build()
+	rec.OnCmd(env, first)
+
+	data, _ := ten.NewData()
+	data.SetName("chunk")
+	data.SetBuf([]byte("payload"))
+	rec.OnData(env, data)
+
+	second, _ := NewMockCmd("stop").Build()
+	rec.OnCmd(env, second)
+
+	if err := rec.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	replayer, err := NewReplayer(path)
+	if err != nil {
+		t.Fatalf("NewReplayer: %v", err)
+	}
+	if len(replayer.Events()) != 3 {
+		t.Fatalf("Events() = %d, want 3", len(replayer.Events()))
+	}
+
+	spy := &replayerSpyExtension{}
+	replayEnv := NewMockTenEnv()
+	if err := replayer.Replay(spy, replayEnv, withReplaySleep(func(time.Duration) {})); err != nil {
+		t.Fatalf("Replay: %v", err)
+	}
+
+	if got := spy.cmds; len(got) != 2 || got[0] != "start" || got[1] != "stop" {
+		t.Fatalf("replayed cmds = %v, want [start stop]", got)
+	}
+	if got := spy.datas; len(got) != 1 || got[0] != "chunk" {
+		t.Fatalf("replayed data names = %v, want [chunk]", got)
+	}
+	if string(spy.bufs[0]) != "payload" {
+		t.Fatalf("replayed data payload = %q, want %q", spy.bufs[0], "payload")
+	}
+}
+
+func TestReplayer_RestoresACmdsPropertiesAndASidecaredPayload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+	rec, err := NewRecorder(ten.DefaultExtension{}, path, WithInlinePayloadLimit(0))
+	if err != nil {
+		t.Fatalf("NewRecorder: %v", err)
+	}
+
+	env := NewMockTenEnv()
+	cmd, _ := NewMockCmd("configure").WithInt64("retries", 3).Build()
+	rec.OnCmd(env, cmd)
+
+	data, _ := ten.NewData()
+	data.SetName("frame")
+	data.SetBuf([]byte("bytes-that-get-sidecared"))
+	rec.OnData(env, data)
+
+	if err := rec.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	replayer, err := NewReplayer(path)
+	if err != nil {
+		t.Fatalf("NewReplayer: %v", err)
+	}
+
+	spy := &replayerSpyExtension{}
+	replayEnv := NewMockTenEnv()
+	if err := replayer.Replay(spy, replayEnv, withReplaySleep(func(time.Duration) {})); err != nil {
+		t.Fatalf("Replay: %v", err)
+	}
+
+	if len(spy.cmds) != 1 || spy.cmds[0] != "configure" {
+		t.Fatalf("replayed cmds = %v, want [configure]", spy.cmds)
+	}
+	if string(spy.bufs[0]) != "bytes-that-get-sidecared" {
+		t.Fatalf("replayed sidecared payload = %q, want the original bytes", spy.bufs[0])
+	}
+}
+
+func TestReplayer_WithReplaySpeedZeroSkipsAllDelay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+	rec, err := NewRecorder(ten.DefaultExtension{}, path)
+	if err != nil {
+		t.Fatalf("NewRecorder: %v", err)
+	}
+	env := NewMockTenEnv()
+	cmd1, _ := NewMockCmd("a").Build()
+	cmd2, _ := NewMockCmd("b").Build()
+	rec.OnCmd(env, cmd1)
+	rec.OnCmd(env, cmd2)
+	if err := rec.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	replayer, err := NewReplayer(path)
+	if err != nil {
+		t.Fatalf("NewReplayer: %v", err)
+	}
+
+	var slept bool
+	spy := &replayerSpyExtension{}
+	replayEnv := NewMockTenEnv()
+	err = replayer.Replay(spy, replayEnv,
+		WithReplaySpeed(0),
+		withReplaySleep(func(time.Duration) { slept = true }),
+	)
+	if err != nil {
+		t.Fatalf("Replay: %v", err)
+	}
+	if slept {
+		t.Fatal("Replay slept between events with WithReplaySpeed(0), want no delay at all")
+	}
+}
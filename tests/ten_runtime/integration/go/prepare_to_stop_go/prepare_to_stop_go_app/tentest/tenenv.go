@@ -0,0 +1,195 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+
+// Package tentest provides a mock ten.TenEnv for unit-testing an
+// Extension's callbacks -- e.g. bExtension.OnCmd -- without standing up a
+// real graph. Construct a MockTenEnv, hand it directly to the callback
+// under test, and assert on the interactions it recorded.
+package tentest
+
+import (
+	"fmt"
+	"sync"
+
+	ten "ten_framework/ten_runtime"
+)
+
+// LoggedMessage records a single Log or LogFields call made through a
+// MockTenEnv. Fields is nil for a plain Log call.
+type LoggedMessage struct {
+	Level   ten.LogLevel
+	Message string
+	Fields  []ten.Field
+}
+
+// SentCmd records a single SendCmd call made through a MockTenEnv.
+type SentCmd struct {
+	Name string
+	Cmd  ten.Cmd
+}
+
+// ReturnedResult records a single ReturnResult call made through a
+// MockTenEnv.
+type ReturnedResult struct {
+	Cmd    ten.Cmd
+	Result ten.CmdResult
+}
+
+// MockTenEnv is a ten.TenEnv that records every Log and SendCmd call made
+// through it, plus every result an extension hands back via ReturnResult,
+// so a test can assert on them afterwards. A test supplies canned
+// CmdResults for outgoing commands via RespondTo; SendCmd for any other
+// cmd name gets a default StatusCodeOk result.
+//
+// Everything else -- the SendCmdEx/SendCmdChan/SendCmdAndWait/
+// SendCmdWithContext family, the lifecycle Done() callbacks,
+// ShutdownContext -- is delegated to a real Engine underneath, so those
+// behave exactly as they would against the runtime.
+type MockTenEnv struct {
+	ten.TenEnv
+	engine *ten.Engine
+
+	mu       sync.Mutex
+	logs     []LoggedMessage
+	sentCmds []SentCmd
+	returned []ReturnedResult
+	canned   map[string]ten.CmdResult
+}
+
+// NewMockTenEnv creates a MockTenEnv ready to be passed to an Extension
+// callback under test, e.g. someExtension.OnCmd(mockTenEnv, cmd).
+func NewMockTenEnv() *MockTenEnv {
+	engine := ten.NewEngine(ten.DefaultExtension{})
+	m := &MockTenEnv{
+		TenEnv: engine.TenEnv(),
+		engine: engine,
+		canned: map[string]ten.CmdResult{},
+	}
+	engine.SetSender(m.dispatchSend)
+	return m
+}
+
+func (m *MockTenEnv) dispatchSend(cmd ten.Cmd, handler ten.CmdResultHandler) {
+	name, _ := cmd.GetName()
+
+	m.mu.Lock()
+	result, ok := m.canned[name]
+	m.mu.Unlock()
+
+	if !ok {
+		result, _ = ten.NewCmdResult(ten.StatusCodeOk, cmd)
+	}
+	handler(m, result, nil)
+}
+
+// Log records msg without printing it.
+func (m *MockTenEnv) Log(level ten.LogLevel, msg string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.logs = append(m.logs, LoggedMessage{Level: level, Message: msg})
+}
+
+// LogFields records msg and fields without printing them or formatting
+// them into a single string, so a test can assert on the fields directly
+// instead of parsing rendered output.
+func (m *MockTenEnv) LogFields(level ten.LogLevel, msg string, fields ...ten.Field) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.logs = append(m.logs, LoggedMessage{Level: level, Message: msg, Fields: fields})
+}
+
+// SendCmd records cmd, then answers handler with the CmdResult registered
+// for cmd's name via RespondTo, or a default StatusCodeOk result if none
+// was registered.
+func (m *MockTenEnv) SendCmd(cmd ten.Cmd, handler ten.CmdResultHandler) error {
+	name, _ := cmd.GetName()
+	m.mu.Lock()
+	m.sentCmds = append(m.sentCmds, SentCmd{Name: name, Cmd: cmd})
+	m.mu.Unlock()
+	return m.TenEnv.SendCmd(cmd, handler)
+}
+
+// ReturnResult records the (result, cmd) pair. Unlike the real
+// implementation, it never forwards result anywhere -- there is no
+// downstream to forward it to.
+func (m *MockTenEnv) ReturnResult(result ten.CmdResult, cmd ten.Cmd) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.returned = append(m.returned, ReturnedResult{Cmd: cmd, Result: result})
+	return nil
+}
+
+// ReturnError builds a final, StatusCodeError CmdResult with its "detail"
+// property set to detail and records it via ReturnResult, the same way
+// the real implementation does -- overridden here (rather than left to
+// the embedded TenEnv) so the built result reaches this MockTenEnv's own
+// ReturnResult, not the underlying Engine's no-op one.
+func (m *MockTenEnv) ReturnError(cmd ten.Cmd, detail string) error {
+	result, err := ten.NewCmdResult(ten.StatusCodeError, cmd)
+	if err != nil {
+		return err
+	}
+	if err := result.SetPropertyString("detail", detail); err != nil {
+		return err
+	}
+	return m.ReturnResult(result, cmd)
+}
+
+// ReturnErrorf is ReturnError with a format string.
+func (m *MockTenEnv) ReturnErrorf(cmd ten.Cmd, format string, args ...any) error {
+	return m.ReturnError(cmd, fmt.Sprintf(format, args...))
+}
+
+// ReturnOK is ReturnError's StatusCodeOk counterpart for the success path.
+func (m *MockTenEnv) ReturnOK(cmd ten.Cmd, detail string) error {
+	result, err := ten.NewCmdResult(ten.StatusCodeOk, cmd)
+	if err != nil {
+		return err
+	}
+	if err := result.SetPropertyString("detail", detail); err != nil {
+		return err
+	}
+	return m.ReturnResult(result, cmd)
+}
+
+// LogLazy calls fn and records its result, but only if
+// IsLogLevelEnabled(level) -- delegated to the underlying Engine, so a
+// MockTenEnv obeys the same WithLogLevel threshold the real runtime would.
+func (m *MockTenEnv) LogLazy(level ten.LogLevel, fn func() string) {
+	if !m.IsLogLevelEnabled(level) {
+		return
+	}
+	m.Log(level, fn())
+}
+
+// RespondTo registers the CmdResult that a subsequent SendCmd for a cmd
+// named cmdName should be answered with.
+func (m *MockTenEnv) RespondTo(cmdName string, result ten.CmdResult) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.canned[cmdName] = result
+}
+
+// SentCmds returns every Cmd passed to SendCmd so far, in call order.
+func (m *MockTenEnv) SentCmds() []SentCmd {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]SentCmd(nil), m.sentCmds...)
+}
+
+// Logs returns every message passed to Log so far, in call order.
+func (m *MockTenEnv) Logs() []LoggedMessage {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]LoggedMessage(nil), m.logs...)
+}
+
+// ReturnedResults returns every (result, cmd) pair passed to ReturnResult
+// so far, in call order.
+func (m *MockTenEnv) ReturnedResults() []ReturnedResult {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]ReturnedResult(nil), m.returned...)
+}
@@ -0,0 +1,146 @@
+// Copyright © 2025 Agora
+// This file is part of TEN Framework, an open source project.
+// Licensed under the Apache License, Version 2.0, with certain conditions.
+// Refer to the "LICENSE" file in the root directory for more information.
+package tentest
+
+import (
+	"testing"
+	"time"
+
+	ten "ten_framework/ten_runtime"
+)
+
+func TestMockTenEnv_RecordsLogAndReturnResult(t *testing.T) {
+	env := NewMockTenEnv()
+	cmd, err := NewMockCmd("unhandled").Build()
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+
+	ten.DefaultExtension{}.OnCmd(env, cmd)
+
+	returned := env.ReturnedResults()
+	if len(returned) != 1 {
+		t.Fatalf("ReturnedResults() = %d entries, want 1", len(returned))
+	}
+	if err := returned[0].Result.AsError(); err == nil {
+		t.Fatal("ReturnedResults()[0].Result.AsError() = nil, want the unknown-cmd error")
+	}
+}
+
+func TestMockTenEnv_RespondToSuppliesCannedResult(t *testing.T) {
+	env := NewMockTenEnv()
+	canned, _ := ten.NewCmdResult(ten.StatusCodeOk, nil)
+	canned.SetPropertyString("output", "canned")
+	env.RespondTo("generate", canned)
+
+	cmd, _ := NewMockCmd("generate").WithString("prompt", "hi").Build()
+
+	gotCh := make(chan ten.CmdResult, 1)
+	if err := env.SendCmd(cmd, func(_ ten.TenEnv, result ten.CmdResult, _ error) {
+		gotCh <- result
+	}); err != nil {
+		t.Fatalf("SendCmd: %v", err)
+	}
+
+	select {
+	case result := <-gotCh:
+		output, _ := result.GetPropertyString("output")
+		if output != "canned" {
+			t.Fatalf("output = %q, want %q", output, "canned")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("SendCmd handler was never invoked")
+	}
+
+	sent := env.SentCmds()
+	if len(sent) != 1 || sent[0].Name != "generate" {
+		t.Fatalf("SentCmds() = %+v, want a single \"generate\" entry", sent)
+	}
+}
+
+func TestMockTenEnv_LogsRecordsMessages(t *testing.T) {
+	env := NewMockTenEnv()
+	env.Log(ten.LogLevelInfo, "hello")
+
+	logs := env.Logs()
+	if len(logs) != 1 || logs[0].Message != "hello" {
+		t.Fatalf("Logs() = %+v, want a single \"hello\" entry", logs)
+	}
+}
+
+func TestMockTenEnv_ReturnErrorRecordsErrorResult(t *testing.T) {
+	env := NewMockTenEnv()
+	cmd, _ := NewMockCmd("connect").Build()
+
+	if err := env.ReturnError(cmd, "dial tcp: connection refused"); err != nil {
+		t.Fatalf("ReturnError: %v", err)
+	}
+
+	returned := env.ReturnedResults()
+	if len(returned) != 1 {
+		t.Fatalf("ReturnedResults() = %d entries, want 1", len(returned))
+	}
+	status, _ := returned[0].Result.StatusCode()
+	if status != ten.StatusCodeError {
+		t.Fatalf("StatusCode = %v, want StatusCodeError", status)
+	}
+	detail, _ := returned[0].Result.GetPropertyString("detail")
+	if detail != "dial tcp: connection refused" {
+		t.Fatalf("detail = %q, want %q", detail, "dial tcp: connection refused")
+	}
+}
+
+func TestMockTenEnv_ReturnOKRecordsOkResult(t *testing.T) {
+	env := NewMockTenEnv()
+	cmd, _ := NewMockCmd("greet").Build()
+
+	if err := env.ReturnOK(cmd, "done"); err != nil {
+		t.Fatalf("ReturnOK: %v", err)
+	}
+	returned := env.ReturnedResults()
+	status, _ := returned[0].Result.StatusCode()
+	if status != ten.StatusCodeOk {
+		t.Fatalf("StatusCode = %v, want StatusCodeOk", status)
+	}
+}
+
+func TestMockTenEnv_LogLazySkipsFnWhenLevelDisabled(t *testing.T) {
+	env := NewMockTenEnv()
+
+	called := false
+	env.LogLazy(ten.LogLevelVerbose, func() string {
+		called = true
+		return "unused"
+	})
+	if !called {
+		t.Fatal("LogLazy did not invoke fn: MockTenEnv defaults to every level enabled")
+	}
+	if logs := env.Logs(); len(logs) != 1 || logs[0].Message != "unused" {
+		t.Fatalf("Logs() = %+v, want a single \"unused\" entry", logs)
+	}
+}
+
+func TestMockCmd_BuildAppliesProperties(t *testing.T) {
+	cmd, err := NewMockCmd("greet").
+		WithString("name", "world").
+		WithInt64("count", 3).
+		WithFloat64("threshold", 0.5).
+		WithBool("urgent", true).
+		Build()
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+
+	name, _ := cmd.GetPropertyString("name")
+	count, _ := cmd.GetPropertyInt64("count")
+	threshold, _ := cmd.GetPropertyFloat64("threshold")
+	urgent, _ := cmd.GetPropertyBool("urgent")
+	if name != "world" || count != 3 || threshold != 0.5 || !urgent {
+		t.Fatalf(
+			"Build() properties = %q, %d, %v, %v, want %q, 3, 0.5, true",
+			name, count, threshold, urgent, "world",
+		)
+	}
+}